@@ -0,0 +1,57 @@
+// Command auditverify walks the hash chains of a deck audit
+// log and reports the first broken link.
+//
+// Usage:
+//
+//	auditverify -file audit.log
+//	auditverify -sqlite audit.db
+//
+// It exits with status 1 when the log was tampered with.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+func main() {
+	file := flag.String("file", "", "path of a JSON lines audit log")
+	sqlite := flag.String("sqlite", "", "path of a SQLite audit database")
+	flag.Parse()
+
+	records, err := readRecords(*file, *sqlite)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "auditverify:", err)
+		os.Exit(2)
+	}
+
+	report := usecase.VerifyAudit(records)
+	if report.Broken != nil {
+		fmt.Printf("BROKEN: deck %s record %d: %s (%d records verified before it)\n",
+			report.Broken.DeckID, report.Broken.Seq, report.Broken.Reason, report.Records)
+		os.Exit(1)
+	}
+
+	fmt.Printf("OK: %d records over %d decks\n", report.Records, report.Decks)
+}
+
+func readRecords(file, sqlite string) ([]entity.AuditRecord, error) {
+	switch {
+	case file != "" && sqlite == "":
+		return repo.ReadAuditFile(file)
+	case sqlite != "" && file == "":
+		db, err := repo.NewAuditSQLite(sqlite)
+		if err != nil {
+			return nil, err
+		}
+		defer db.Close()
+		return db.Records()
+	default:
+		return nil, fmt.Errorf("exactly one of -file or -sqlite is needed")
+	}
+}
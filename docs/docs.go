@@ -16,71 +16,341 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/decks": {
+        "/admin/keys": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists the API keys, from the configuration or created through the API, without their secrets.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Lists the API keys.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.apiKeysResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Creates a new deck with cards.",
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates an API key. Its secret is only ever shown in this response.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Creates a new deck.",
+                "summary": "Creates an API key.",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ci",
+                        "description": "Key name, owning the decks created with it",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "boolean",
                         "default": false,
-                        "description": "Activate or deactivate cards shuffling.",
-                        "name": "shuffle",
+                        "description": "Whether the key reaches every deck of its tenant, and the admin routes without a tenant",
+                        "name": "admin",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "AS,2S",
-                        "description": "Comma separated card codes to create a custom deck. If not sent, the regular 52 cards deck will be created.",
-                        "name": "cards",
+                        "example": "acme",
+                        "description": "Tenant the key works in, the default one if not sent",
+                        "name": "tenant",
                         "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/v1.newDeckResponse"
+                            "$ref": "#/definitions/v1.newAPIKeyResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
                         }
                     }
                 }
             }
         },
-        "/decks/withdrawals/{id}": {
-            "get": {
-                "description": "Draw an amount of cards given a deck.",
+        "/admin/keys/{name}": {
+            "delete": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revokes an API key created through the API. Keys of the configuration are removed from it instead.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Draw cards from a deck.",
+                "summary": "Revokes an API key.",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Deck id",
-                        "name": "id",
+                        "description": "Key name",
+                        "name": "name",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/snapshots": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
                     },
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Amount of cards to draw",
-                        "name": "amount",
-                        "in": "query"
+                        "Bearer": []
+                    }
+                ],
+                "description": "Writes every deck and deck event to the snapshot file, replacing the previous snapshot.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Snapshots the deck store.",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.snapshotResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tenants": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
                     }
                 ],
+                "description": "Lists the tenants and their limits, by name.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Lists the tenants.",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.drawCardsResp"
+                            "$ref": "#/definitions/v1.tenantsResp"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates a tenant, whose decks are kept apart from the others'. Limits left out or 0 don't bound the tenant.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Creates a tenant.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "acme",
+                        "description": "Tenant name: lowercase letters, digits and dashes",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Decks the tenant can keep",
+                        "name": "max_decks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cards a deck of the tenant can be created with",
+                        "name": "max_cards",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Requests the tenant can make each minute, to each instance",
+                        "name": "requests_per_minute",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/response.Error"
                         }
@@ -94,18 +364,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/decks/{id}": {
+        "/admin/tenants/{name}/usage": {
             "get": {
-                "description": "Opens a deck, showing all its cards.",
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Shows the decks a tenant keeps and the requests it made this minute, to the instance answering, next to its limits.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Opens a deck.",
+                "summary": "Shows what a tenant uses.",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Deck id",
-                        "name": "id",
+                        "description": "Tenant name",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     }
@@ -114,7 +392,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Deck"
+                            "$ref": "#/definitions/entity.TenantUsage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
                         }
                     },
                     "404": {
@@ -131,33 +421,2743 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "entity.Card": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "suit": {
-                    "type": "string"
-                },
-                "value": {
-                    "type": "string"
-                }
-            }
         },
-        "entity.Deck": {
-            "type": "object",
-            "properties": {
-                "cards": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/entity.Card"
-                    }
-                },
-                "deck_id": {
+        "/decks": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates a new deck with cards. A tenant over its decks, or asking for more cards than its decks can have, gets a 403.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Creates a new deck.",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Activate or deactivate cards shuffling.",
+                        "name": "shuffle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "AS,2S",
+                        "description": "Comma separated card codes to create a custom deck. If not sent, the regular 52 cards deck will be created.",
+                        "name": "cards",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.newDeckResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/decks/withdrawals/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Draw an amount of cards given a deck.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Draw cards from a deck.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Deck id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Amount of cards to draw",
+                        "name": "amount",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.drawCardsResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/decks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Opens a deck, showing all its cards. Only the hand of the player owning the token is shown, the others show their size.\nWith at_version the deck is shown as it was right after that event; only its owner sees the cards left in it.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Opens a deck.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Deck id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Event version to rebuild the deck at",
+                        "name": "at_version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Player token",
+                        "name": "X-Player-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.deckResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a deck. Its events are kept, so its history can still be read. Tokens need the decks:delete scope.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Deletes a deck.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Deck id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/decks/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists every operation done on a deck, oldest first. Created and shuffled decks show how many cards they hold, not their order. Only the cards dealt to the player owning the token are shown, the other deals show how many cards they moved.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Lists the events of a deck.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Deck id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Player token",
+                        "name": "X-Player-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.deckEventsResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/decks/{id}/hands": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deals an amount of cards to each player in turn into hands kept with the deck, returning a token for each player getting a first hand.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Deals hands from a deck.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Deck id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "alice,bob",
+                        "description": "Comma separated player ids",
+                        "name": "players",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Cards dealt to each player",
+                        "name": "amount",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.dealResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/decks/{id}/returns": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Puts cards drawn from a deck back at its bottom.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns cards to a deck.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Deck id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "AS,2S",
+                        "description": "Comma separated card codes",
+                        "name": "cards",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.deckResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/blackjack": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates a blackjack table bound to a shuffled multi-deck shoe.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Creates a blackjack table.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 6,
+                        "description": "Number of decks in the shoe.",
+                        "name": "decks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.75,
+                        "description": "Fraction of the shoe dealt before the cut card.",
+                        "name": "penetration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Dealer hits soft 17.",
+                        "name": "h17",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "Player chips.",
+                        "name": "bankroll",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.blackjackTableResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/blackjack/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Shows a blackjack table. The dealer hole card is hidden during a round.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Shows a blackjack table.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.blackjackTableResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/blackjack/{id}/deals": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Takes the bet and deals a new round, reshuffling when the cut card was reached.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Deals a blackjack round.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Bet amount",
+                        "name": "bet",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.blackjackTableResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/blackjack/{id}/{action}": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Plays hit, stand, double, split or surrender on the active hand.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Plays a blackjack decision.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "hits",
+                            "stands",
+                            "doubles",
+                            "splits",
+                            "surrenders"
+                        ],
+                        "type": "string",
+                        "description": "Decision",
+                        "name": "action",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.blackjackTableResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/bridge": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deals 13 cards to each seat, with the dealer and vulnerability of the board. Constraints are met by dealing again until they hold, giving up with a 422 after 10000 deals.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Deals a bridge board.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Board number",
+                        "name": "board",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Seat constraint as SEAT:MIN-MAX or SEAT:MIN-MAX:balanced, e.g. N:15-17:balanced. Can be repeated.",
+                        "name": "constraint",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.bridgeDealResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/bridge/pbn": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Imports the deals of a Portable Bridge Notation file sent as the request body.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Imports bridge boards.",
+                "parameters": [
+                    {
+                        "description": "PBN file",
+                        "name": "pbn",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.bridgeDealResp"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/bridge/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Shows the hands of a bridge board with their HCP and distribution.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Shows a bridge board.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Deal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.bridgeDealResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/bridge/{id}/pbn": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Exports a bridge board in Portable Bridge Notation.",
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Exports a bridge board.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Deal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/holdem": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates an empty no-limit Texas Hold'em table.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Creates a Hold'em table.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Small blind",
+                        "name": "small_blind",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Big blind",
+                        "name": "big_blind",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.holdemTableResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/holdem/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Shows a Hold'em table, hiding other players' hole cards until showdown.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Shows a Hold'em table.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token received when sitting down",
+                        "name": "X-Player-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.holdemTableResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/holdem/{id}/actions": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Plays an action for the player owning the token. Bet and raise amounts are the total to raise to.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Plays a Hold'em action.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token received when sitting down",
+                        "name": "X-Player-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "fold",
+                            "check",
+                            "call",
+                            "bet",
+                            "raise",
+                            "allin"
+                        ],
+                        "type": "string",
+                        "description": "Action",
+                        "name": "action",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Amount to raise to",
+                        "name": "amount",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.holdemTableResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/holdem/{id}/hands": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Moves the button, posts blinds and deals hole cards from a new deck.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Starts a Hold'em hand.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token received when sitting down",
+                        "name": "X-Player-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.holdemTableResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/holdem/{id}/seats": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sits a player with a buy-in and returns the token used to act.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Sits a player at a Hold'em table.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Player id",
+                        "name": "player_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Chips to sit with",
+                        "name": "buy_in",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.holdemSitResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/klondike": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deals a Klondike game. The same seed always deals the same game.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Deals a Klondike game.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Deal seed, random when missing.",
+                        "name": "seed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Cards turned from the stock, 1 or 3.",
+                        "name": "draw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.klondikeGameResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/klondike/daily": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Shows the seed of the day, proven winnable by the solver. A search that can't end within 20 seconds gets a 503.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Shows the daily Klondike deal.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Day as YYYY-MM-DD, today when missing.",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Cards turned from the stock, 1 or 3.",
+                        "name": "draw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.klondikeDailyResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/klondike/solutions": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Tells whether the deal of a seed is winnable, unwinnable or unknown within the solver budget.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Solves a Klondike deal.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Deal seed",
+                        "name": "seed",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Cards turned from the stock, 1 or 3.",
+                        "name": "draw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.KlondikeSolution"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/klondike/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Shows a Klondike game. The stock and the face down cards are hidden.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Shows a Klondike game.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.klondikeGameResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/klondike/{id}/moves": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Draws from the stock or moves cards between the waste, tableau and foundation piles.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Plays a Klondike move.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "draw",
+                            "move"
+                        ],
+                        "type": "string",
+                        "description": "Move action",
+                        "name": "action",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "waste",
+                            "tableau",
+                            "foundation"
+                        ],
+                        "type": "string",
+                        "description": "Source pile",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Source pile index",
+                        "name": "from_index",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "tableau",
+                            "foundation"
+                        ],
+                        "type": "string",
+                        "description": "Target pile",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Target pile index",
+                        "name": "to_index",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Cards moved from a tableau pile.",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.klondikeGameResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/klondike/{id}/undos": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Takes back the last move of a Klondike game.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Takes back a Klondike move.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.klondikeGameResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/{game}": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deals a new game of War, Go Fish or Crazy Eights and returns a token per player.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Creates a casual card game.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "war",
+                            "gofish",
+                            "crazyeights"
+                        ],
+                        "type": "string",
+                        "description": "Game",
+                        "name": "game",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "alice,bob",
+                        "description": "Comma separated player ids",
+                        "name": "players",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.createdCasualGameResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/{game}/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Shows a game, with only the hand of the player owning the token.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Shows a casual card game.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "war",
+                            "gofish",
+                            "crazyeights"
+                        ],
+                        "type": "string",
+                        "description": "Game",
+                        "name": "game",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token received when the game was created",
+                        "name": "X-Player-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.casualGameResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/{game}/{id}/bots": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lets a bot make the move of the player whose turn it is.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Lets a bot move.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "war",
+                            "gofish",
+                            "crazyeights"
+                        ],
+                        "type": "string",
+                        "description": "Game",
+                        "name": "game",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.casualGameResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/{game}/{id}/moves": {
+            "get": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists the moves the player owning the token can make now.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Lists legal moves.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "war",
+                            "gofish",
+                            "crazyeights"
+                        ],
+                        "type": "string",
+                        "description": "Game",
+                        "name": "game",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token received when the game was created",
+                        "name": "X-Player-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.casualMovesResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    },
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Validates and plays a move for the player owning the token.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Plays a move.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "war",
+                            "gofish",
+                            "crazyeights"
+                        ],
+                        "type": "string",
+                        "description": "Game",
+                        "name": "game",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token received when the game was created",
+                        "name": "X-Player-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "flip",
+                            "ask",
+                            "play",
+                            "draw",
+                            "pass"
+                        ],
+                        "type": "string",
+                        "description": "Move",
+                        "name": "action",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Player asked in Go Fish",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "KING",
+                        "description": "Value asked in Go Fish",
+                        "name": "value",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "8S",
+                        "description": "Card played in Crazy Eights",
+                        "name": "card",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "HEARTS",
+                        "description": "Suit named with an eight in Crazy Eights",
+                        "name": "suit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.casualGameResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entity.Card": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "suit": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CasualMove": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "card": {
+                    "type": "string"
+                },
+                "player_id": {
+                    "type": "string"
+                },
+                "suit": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.HoldemPot": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "eligible": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "entity.HoldemWinner": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "hand": {
+                    "type": "string"
+                },
+                "player_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.KlondikeMove": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "from_index": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "to_index": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.Tenant": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "string"
+                },
+                "limits": {
+                    "$ref": "#/definitions/entity.TenantLimits"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.TenantLimits": {
+            "type": "object",
+            "properties": {
+                "max_cards": {
+                    "description": "MaxCards is how many cards a deck of the tenant can\nbe created with.",
+                    "type": "integer"
+                },
+                "max_decks": {
+                    "description": "MaxDecks is how many decks the tenant can keep.",
+                    "type": "integer"
+                },
+                "requests_per_minute": {
+                    "description": "RequestsPerMinute is how many requests the tenant\ncan make each minute.",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.TenantUsage": {
+            "type": "object",
+            "properties": {
+                "decks": {
+                    "type": "integer"
+                },
+                "requests": {
+                    "description": "Requests is how many requests the tenant made in\nthe current minute.",
+                    "type": "integer"
+                },
+                "tenant": {
+                    "$ref": "#/definitions/entity.Tenant"
+                }
+            }
+        },
+        "response.Error": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.KlondikeSolution": {
+            "type": "object",
+            "properties": {
+                "draw_count": {
+                    "type": "integer"
+                },
+                "moves": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.KlondikeMove"
+                    }
+                },
+                "result": {
+                    "type": "string"
+                },
+                "seed": {
+                    "type": "integer"
+                },
+                "states": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.apiKeyResp": {
+            "type": "object",
+            "properties": {
+                "admin": {
+                    "type": "boolean"
+                },
+                "created": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.apiKeysResp": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.apiKeyResp"
+                    }
+                }
+            }
+        },
+        "v1.blackjackHandResp": {
+            "type": "object",
+            "properties": {
+                "bet": {
+                    "type": "integer"
+                },
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Card"
+                    }
+                },
+                "doubled": {
+                    "type": "boolean"
+                },
+                "payout": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.blackjackTableResp": {
+            "type": "object",
+            "properties": {
+                "active_hand": {
+                    "type": "integer"
+                },
+                "bankroll": {
+                    "type": "integer"
+                },
+                "dealer": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Card"
+                    }
+                },
+                "dealer_hits_soft_17": {
+                    "type": "boolean"
+                },
+                "dealer_score": {
+                    "type": "integer"
+                },
+                "decks": {
+                    "type": "integer"
+                },
+                "hands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.blackjackHandResp"
+                    }
+                },
+                "shoe_remaining": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "table_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.bridgeDealResp": {
+            "type": "object",
+            "properties": {
+                "board": {
+                    "type": "integer"
+                },
+                "deal_id": {
+                    "type": "string"
+                },
+                "dealer": {
+                    "type": "string"
+                },
+                "deck_id": {
+                    "type": "string"
+                },
+                "hands": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/v1.bridgeHandResp"
+                    }
+                },
+                "vulnerable": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.bridgeHandResp": {
+            "type": "object",
+            "properties": {
+                "balanced": {
+                    "type": "boolean"
+                },
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Card"
+                    }
+                },
+                "hcp": {
+                    "type": "integer"
+                },
+                "lengths": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "shape": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.casualGameResp": {
+            "type": "object",
+            "properties": {
+                "finished": {
+                    "type": "boolean"
+                },
+                "game_id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "pile": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Card"
+                    }
+                },
+                "players": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.casualPlayerResp"
+                    }
+                },
+                "round": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "suit": {
+                    "type": "string"
+                },
+                "turn": {
+                    "type": "integer"
+                },
+                "winner": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.casualMovesResp": {
+            "type": "object",
+            "properties": {
+                "moves": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CasualMove"
+                    }
+                }
+            }
+        },
+        "v1.casualPlayerResp": {
+            "type": "object",
+            "properties": {
+                "books": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "hand": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Card"
+                    }
+                },
+                "hand_count": {
+                    "type": "integer"
+                },
+                "player_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.createdCasualGameResp": {
+            "type": "object",
+            "properties": {
+                "game": {
+                    "$ref": "#/definitions/v1.casualGameResp"
+                },
+                "tokens": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v1.dealResp": {
+            "type": "object",
+            "properties": {
+                "deck": {
+                    "$ref": "#/definitions/v1.deckResp"
+                },
+                "tokens": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v1.deckEventResp": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Card"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "deck_id": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "player_id": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.deckEventsResp": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.deckEventResp"
+                    }
+                }
+            }
+        },
+        "v1.deckResp": {
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Card"
+                    }
+                },
+                "deck_id": {
+                    "type": "string"
+                },
+                "hands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.handResp"
+                    }
+                },
+                "owner": {
                     "type": "string"
                 },
                 "remaining": {
@@ -165,18 +3165,201 @@ const docTemplate = `{
                 },
                 "shuffled": {
                     "type": "boolean"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "response.Error": {
+        "v1.drawCardsResp": {
             "type": "object",
             "properties": {
-                "message": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Card"
+                    }
+                }
+            }
+        },
+        "v1.handResp": {
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Card"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "player_id": {
                     "type": "string"
                 }
             }
         },
-        "v1.drawCardsResp": {
+        "v1.holdemSeatResp": {
+            "type": "object",
+            "properties": {
+                "all_in": {
+                    "type": "boolean"
+                },
+                "bet": {
+                    "type": "integer"
+                },
+                "folded": {
+                    "type": "boolean"
+                },
+                "hole": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Card"
+                    }
+                },
+                "hole_count": {
+                    "type": "integer"
+                },
+                "in_hand": {
+                    "type": "boolean"
+                },
+                "player_id": {
+                    "type": "string"
+                },
+                "stack": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.holdemSitResp": {
+            "type": "object",
+            "properties": {
+                "table": {
+                    "$ref": "#/definitions/v1.holdemTableResp"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.holdemTableResp": {
+            "type": "object",
+            "properties": {
+                "big_blind": {
+                    "type": "integer"
+                },
+                "board": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Card"
+                    }
+                },
+                "button": {
+                    "type": "integer"
+                },
+                "current_bet": {
+                    "type": "integer"
+                },
+                "hand_number": {
+                    "type": "integer"
+                },
+                "min_raise": {
+                    "type": "integer"
+                },
+                "pots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.HoldemPot"
+                    }
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.holdemSeatResp"
+                    }
+                },
+                "small_blind": {
+                    "type": "integer"
+                },
+                "street": {
+                    "type": "string"
+                },
+                "table_id": {
+                    "type": "string"
+                },
+                "to_act": {
+                    "type": "integer"
+                },
+                "winners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.HoldemWinner"
+                    }
+                }
+            }
+        },
+        "v1.klondikeDailyResp": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "draw_count": {
+                    "type": "integer"
+                },
+                "seed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.klondikeGameResp": {
+            "type": "object",
+            "properties": {
+                "can_undo": {
+                    "type": "boolean"
+                },
+                "draw_count": {
+                    "type": "integer"
+                },
+                "foundations": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/entity.Card"
+                        }
+                    }
+                },
+                "game_id": {
+                    "type": "string"
+                },
+                "moves": {
+                    "type": "integer"
+                },
+                "seed": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "tableau": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.klondikeStackResp"
+                    }
+                },
+                "waste": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Card"
+                    }
+                },
+                "won": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.klondikeStackResp": {
             "type": "object",
             "properties": {
                 "cards": {
@@ -184,6 +3367,29 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/entity.Card"
                     }
+                },
+                "face_down": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.newAPIKeyResp": {
+            "type": "object",
+            "properties": {
+                "admin": {
+                    "type": "boolean"
+                },
+                "created": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "type": "string"
                 }
             }
         },
@@ -200,6 +3406,44 @@ const docTemplate = `{
                     "type": "boolean"
                 }
             }
+        },
+        "v1.snapshotResp": {
+            "type": "object",
+            "properties": {
+                "decks": {
+                    "type": "integer"
+                },
+                "events": {
+                    "type": "integer"
+                },
+                "taken": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.tenantsResp": {
+            "type": "object",
+            "properties": {
+                "tenants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Tenant"
+                    }
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "APIKey": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "Bearer": {
+            "description": "A JWT as \"Bearer \u003ctoken\u003e\".",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -1,27 +1,434 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"io"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/lualfe/card-game/internal/config"
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/tracing"
 	"github.com/lualfe/card-game/internal/usecase"
 	"github.com/lualfe/card-game/internal/usecase/repo"
 
+	"github.com/lualfe/card-game/internal/controller/http/middleware"
 	v1 "github.com/lualfe/card-game/internal/controller/http/v1"
 )
 
+// tracerShutdownTimeout bounds the export of the last
+// spans on shutdown.
+const tracerShutdownTimeout = 5 * time.Second
+
+// deckExpiryInterval is the longest time between two
+// sweeps of the expired decks.
+const deckExpiryInterval = time.Minute
+
+// jwksTimeout bounds the fetch of a JWKS URL.
+const jwksTimeout = 10 * time.Second
+
 // Run create all the main objects and run the
 // application.
 func Run() {
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger := newLogger(cfg.Log, os.Stderr)
+	slog.SetDefault(logger)
+	slog.Info("config loaded", slog.Any("config", cfg))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var (
+		open     closers
+		jobs     = newWorkers()
+		health   = usecase.NewHealth()
+		requests inFlight
+	)
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	tp := tracerProvider(cfg.Trace, &open)
+
 	m := chi.NewRouter()
+	m.Use(requests.middleware)
+	m.Use(middleware.RequestID)
+	m.Use(middleware.AccessLog(logger))
+	m.Use(middleware.Tracing(tp, propagation.TraceContext{}))
+	m.Use(middleware.Metrics(reg))
+	m.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	var (
+		deckRepo      usecase.DeckRepo
+		deckEventRepo usecase.DeckEventRepo
+		snapshotFile  *repo.DeckSnapshotFile
+		deckWAL       usecase.DeckWALRepo
+		apiKeyRepo    usecase.APIKeyRepo
+		tenantRepo    usecase.TenantRepo
+		// Rate limits are shared by the instances through
+		// Redis, and kept by each instance on its own with the
+		// other stores.
+		rateLimitRepo usecase.RateLimitRepo = repo.NewRateLimit()
+	)
+	switch cfg.Store.Backend {
+	case "memory":
+		deckRepo, deckEventRepo, snapshotFile, deckWAL = memoryDeckStores(cfg, &open)
+		apiKeyRepo, tenantRepo = repo.NewAPIKey(), repo.NewTenant()
+	case "bolt":
+		db, err := repo.OpenBolt(cfg.Store.DSN)
+		if err != nil {
+			fatal("opening bolt database", err)
+		}
+		open.add("bolt database", db.Close)
+		store := repo.NewBoltDeck(db)
+		health.AddReadiness("deck store", store.Ping)
+		deckRepo, deckEventRepo = store, repo.NewBoltDeckEvents(db)
+		apiKeyRepo, tenantRepo = repo.NewBoltAPIKey(db), repo.NewBoltTenant(db)
+	case "redis":
+		opts := &redis.Options{Addr: cfg.Store.DSN}
+		if strings.Contains(cfg.Store.DSN, "://") {
+			if opts, err = redis.ParseURL(cfg.Store.DSN); err != nil {
+				fatal("parsing store.dsn", err)
+			}
+		}
+		client := redis.NewClient(opts)
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			fatal("connecting to redis", err)
+		}
+		open.add("redis client", client.Close)
+		store := repo.NewRedisDeck(client)
+		health.AddReadiness("deck store", store.Ping)
+		deckRepo, deckEventRepo = store, repo.NewRedisDeckEvents(client)
+		apiKeyRepo, tenantRepo = repo.NewRedisAPIKey(client), repo.NewRedisTenant(client)
+		rateLimitRepo = repo.NewRedisRateLimit(client)
+	case "postgres":
+		db, err := repo.OpenPostgres(cfg.Store.DSN, cfg.Store.MaxConns)
+		if err != nil {
+			fatal("opening postgres database", err)
+		}
+		open.add("postgres database", db.Close)
+		store := repo.NewPostgresDeck(db)
+		health.AddReadiness("deck store", store.Ping)
+		deckRepo, deckEventRepo = store, repo.NewPostgresDeckEvents(db)
+		apiKeyRepo, tenantRepo = repo.NewPostgresAPIKey(db), repo.NewPostgresTenant(db)
+	}
+
+	if sink := auditSink(cfg.Audit); sink != nil {
+		if c, ok := sink.(io.Closer); ok {
+			open.add("audit log", c.Close)
+		}
+		deckRepo = usecase.NewAuditedDeckRepo(deckRepo, usecase.NewAuditLog(sink))
+	}
+	deckRepo = usecase.NewDeckRepoTracing(usecase.NewDeckRepoMetrics(deckRepo, reg), tp)
+	dm := usecase.NewDeckManager(deckRepo, deckEventRepo, usecase.DeckOptions{
+		TTL:      cfg.Deck.TTL,
+		MaxDecks: cfg.Deck.MaxDecks,
+		Shuffle:  cfg.Deck.Shuffle,
+	})
+	if cfg.Deck.TTL > 0 {
+		interval := deckExpiryInterval
+		if cfg.Deck.TTL < interval {
+			interval = cfg.Deck.TTL
+		}
+		jobs.run(func(ctx context.Context) {
+			dm.RunExpiry(ctx, interval)
+		})
+		health.AddLiveness("deck expiry", usecase.HealthFresh(dm.LastExpiry, 2*interval))
+	}
+
+	decks := usecase.NewDeckLogging(usecase.NewDeckTracing(usecase.NewDeckMetrics(dm, deckRepo, tenantRepo, reg), tp), logger)
+
+	var (
+		snapshots *usecase.DeckSnapshots
+		sm        usecase.DeckSnapshotManager
+	)
+	if snapshotFile != nil {
+		snapshots = usecase.NewDeckSnapshots(dm, snapshotFile, deckWAL)
+		if cfg.Snapshot.Interval > 0 {
+			jobs.run(func(ctx context.Context) {
+				snapshots.Run(ctx, cfg.Snapshot.Interval)
+			})
+			// A snapshot falling behind, such as on a full disk,
+			// isn't fixed by a restart, which would lose the decks
+			// since the last one.
+			health.AddReadiness("deck snapshots", usecase.HealthFresh(snapshots.LastTaken, 2*cfg.Snapshot.Interval))
+		}
+		sm = snapshots
+	}
+
+	blackjackRepo := repo.NewBlackjackTable()
+	bm := usecase.NewBlackjackManager(decks, blackjackRepo)
+
+	holdemRepo := repo.NewHoldemTable()
+	hm := usecase.NewHoldemManager(decks, holdemRepo)
+
+	casualRepo := repo.NewCasualGame()
+	cm := usecase.NewCasualGamesManager(decks, casualRepo)
+
+	klondikeRepo := repo.NewKlondikeGame()
+	km := usecase.NewKlondikeManager(decks, klondikeRepo, usecase.KlondikeBudget{MaxStates: 200000, Timeout: cfg.Klondike.SolveTimeout})
+
+	bridgeRepo := repo.NewBridgeDeal()
+	brm := usecase.NewBridgeManager(decks, bridgeRepo, cfg.Deck.Shuffle)
+
+	var (
+		keys    usecase.APIKeyManager
+		tokens  usecase.TokenManager
+		tenants usecase.TenantManager
+	)
+	if cfg.Auth.Uses("api_key") {
+		keys = usecase.NewAPIKeys(apiKeyRepo, tenantRepo, configAPIKeys(cfg.Auth))
+	}
+	if cfg.Auth.Uses("jwt") {
+		tokens = usecase.NewTokens(tokenOptions(cfg.Auth.JWT))
+	}
+	// Callers are only told apart by tenant once they
+	// authenticate.
+	if keys != nil || tokens != nil {
+		tenants = usecase.NewTenants(tenantRepo, deckRepo)
+	}
+
+	var limits usecase.RateLimiter
+	if rl := cfg.RateLimit; rl.Create.PerMinute > 0 || rl.Draw.PerMinute > 0 || rl.DeckDraw.PerMinute > 0 {
+		limits = usecase.NewRateLimits(rateLimitRepo, usecase.RateLimitOptions{
+			Create:   entity.RateLimit{PerMinute: rl.Create.PerMinute, Burst: rl.Create.Burst},
+			Draw:     entity.RateLimit{PerMinute: rl.Draw.PerMinute, Burst: rl.Draw.Burst},
+			DeckDraw: entity.RateLimit{PerMinute: rl.DeckDraw.PerMinute, Burst: rl.DeckDraw.Burst},
+		})
+	}
+
+	// Only the deck routes check who owns a deck; the games
+	// reach the decks they run on their own.
+	v1.StartRoutes(m, usecase.NewDeckOwnership(decks), bm, hm, cm, km, brm, sm, health, keys, tokens, tenants, limits)
+
+	srv := &http.Server{
+		Addr:         cfg.HTTP.Addr,
+		Handler:      m,
+		ReadTimeout:  cfg.HTTP.ReadTimeout,
+		WriteTimeout: cfg.HTTP.WriteTimeout,
+		IdleTimeout:  cfg.HTTP.IdleTimeout,
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		slog.Info("listening", slog.String("addr", cfg.HTTP.Addr))
+		if cfg.HTTP.TLSCert != "" {
+			served <- srv.ListenAndServeTLS(cfg.HTTP.TLSCert, cfg.HTTP.TLSKey)
+			return
+		}
+		served <- srv.ListenAndServe()
+	}()
 
-	deckRepo := make(repo.Deck)
-	dm := usecase.NewDeckManager(deckRepo)
+	select {
+	case err := <-served:
+		fatal("serving HTTP", err)
+	case <-ctx.Done():
+	}
+	// A second signal kills the process right away.
+	stop()
 
-	v1.StartRoutes(m, dm)
+	// Load balancers see the application isn't ready and
+	// stop sending requests. The ones in flight then finish
+	// before the background jobs stop, the last snapshot is
+	// taken and the stores close, so no draw is cut off half
+	// way.
+	health.Drain()
+	if cfg.HTTP.ShutdownDelay > 0 {
+		slog.Info("shutting down after a delay", slog.Duration("delay", cfg.HTTP.ShutdownDelay))
+		time.Sleep(cfg.HTTP.ShutdownDelay)
+	}
+	slog.Info("shutting down, draining requests", slog.Duration("timeout", cfg.HTTP.ShutdownTimeout))
+	drain, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(drain); err != nil {
+		slog.Error("draining requests", slog.Any("err", err))
+		srv.Close()
+	}
+	// Handlers outlive the connections srv.Close drops. The
+	// stores are left open under those still running past
+	// the wait, for the process exit to close.
+	wait, cancelWait := context.WithTimeout(context.Background(), handlerWaitTimeout)
+	defer cancelWait()
+	idle := requests.wait(wait)
+
+	jobs.stop()
+	if snapshots != nil {
+		if _, err := snapshots.Take(context.Background()); err != nil {
+			slog.Error("deck snapshot", slog.Any("err", err))
+		}
+	}
+	if idle {
+		open.closeAll()
+	} else {
+		slog.Error("requests still running, leaving the stores open", slog.Int64("requests", requests.count()))
+	}
+
+	slog.Info("stopped")
+}
+
+// memoryDeckStores creates the in-memory deck stores,
+// restoring them from the snapshot and WAL when set.
+func memoryDeckStores(cfg config.Config, open *closers) (usecase.DeckRepo, usecase.DeckEventRepo, *repo.DeckSnapshotFile, usecase.DeckWALRepo) {
+	deckStore := repo.NewDeck()
+	var deckRepo usecase.DeckRepo = deckStore
+
+	walDir, snapshotPath := cfg.WAL.Dir, cfg.Snapshot.File
+
+	// Snapshots and the WAL are restored before the audit log
+	// wraps the deck store, as their events were audited when done.
+	var snapshotFile *repo.DeckSnapshotFile
+	if snapshotPath != "" {
+		snapshotFile = repo.NewDeckSnapshotFile(snapshotPath)
+		snapshot, err := snapshotFile.Read()
+		switch {
+		case err == nil:
+			if err := usecase.RestoreDecks(context.Background(), snapshot, deckStore, deckStore.Events()); err != nil {
+				fatal("restoring the deck snapshot", err)
+			}
+			slog.Info("restored the deck snapshot", slog.Int("decks", len(snapshot.Decks)), slog.Time("taken", snapshot.Taken))
+		case !errors.Is(err, repo.DeckSnapshotNotFoundErr):
+			fatal("reading the deck snapshot", err)
+		}
+	}
+
+	var deckWAL usecase.DeckWALRepo
+	if walDir != "" {
+		n, err := repo.ReplayDeckWAL(walDir, deckStore)
+		if err != nil {
+			fatal("replaying the deck WAL", err)
+		}
+		slog.Info("replayed the deck WAL", slog.Int("records", n))
+
+		wal, err := repo.OpenDeckWAL(walDir, cfg.WAL.Sync, cfg.WAL.SyncInterval)
+		if err != nil {
+			fatal("opening the deck WAL", err)
+		}
+		open.add("deck wal", wal.Close)
+		deckRepo = repo.NewWALDeck(deckStore, wal)
+		deckWAL = wal
+	}
+
+	return deckRepo, deckStore.Events(), snapshotFile, deckWAL
+}
+
+// auditSink opens the audit log, if any.
+func auditSink(cfg config.Audit) usecase.AuditSink {
+	if cfg.SQLite != "" {
+		sink, err := repo.NewAuditSQLite(cfg.SQLite)
+		if err != nil {
+			fatal("opening the SQLite audit log", err)
+		}
+		return sink
+	}
+	if cfg.File != "" {
+		sink, err := repo.NewAuditFile(cfg.File)
+		if err != nil {
+			fatal("opening the audit log file", err)
+		}
+		return sink
+	}
+	return nil
+}
+
+// configAPIKeys hashes the API keys of the configuration.
+func configAPIKeys(cfg config.Auth) []entity.APIKey {
+	var keys []entity.APIKey
+	if cfg.AdminKey != "" {
+		keys = append(keys, entity.APIKey{Name: "admin", Hash: usecase.HashAPIKey(cfg.AdminKey), Admin: true})
+	}
+	for _, k := range cfg.APIKeys {
+		keys = append(keys, entity.APIKey{Name: k.Name, Hash: usecase.HashAPIKey(k.Key), Admin: k.Admin, Tenant: k.Tenant})
+	}
+	return keys
+}
+
+// tokenOptions returns how the bearer tokens are checked.
+func tokenOptions(cfg config.JWT) usecase.TokenOptions {
+	opts := usecase.TokenOptions{
+		Secret:   []byte(cfg.Secret),
+		Issuer:   cfg.Issuer,
+		Audience: cfg.Audience,
+	}
+	if cfg.JWKS != "" {
+		opts.Keys = repo.NewJWKS(cfg.JWKS, &http.Client{Timeout: jwksTimeout}, cfg.JWKSRefresh)
+	}
+	return opts
+}
+
+// tracerProvider returns the provider of the spans, which
+// batches them to the configured exporter.
+func tracerProvider(cfg config.Trace, open *closers) trace.TracerProvider {
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case "none":
+		return trace.NewNoopTracerProvider()
+	case "stdout":
+		exporter = tracing.NewOTLPExporter(os.Stdout)
+	case "file":
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			fatal("opening the trace file", err)
+		}
+		open.add("trace file", f.Close)
+		exporter = tracing.NewOTLPExporter(f)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName("card-game"))),
+	)
+	open.add("tracer provider", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), tracerShutdownTimeout)
+		defer cancel()
+		return tp.Shutdown(ctx)
+	})
+	return tp
+}
+
+// newLogger creates the logger of the application, adding
+// their request_id to the records of requests.
+func newLogger(cfg config.Log, w io.Writer) *slog.Logger {
+	var level slog.Level
+	// The level was validated with the configuration.
+	_ = level.UnmarshalText([]byte(cfg.Level))
+	opts := &slog.HandlerOptions{Level: level}
+
+	var h slog.Handler = slog.NewTextHandler(w, opts)
+	if cfg.Format == "json" {
+		h = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(middleware.LogRequestID(h))
+}
 
-	log.Println("Listening on port 8080")
-	log.Fatal(http.ListenAndServe(":8080", m))
+// fatal logs err and exits, as log.Fatal does.
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("err", err))
+	os.Exit(1)
 }
@@ -0,0 +1,267 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+// TestMain runs the application instead of the tests when
+// started by startApp.
+func TestMain(m *testing.M) {
+	if args := os.Getenv("APP_TEST_ARGS"); args != "" {
+		os.Args = append(os.Args[:1], strings.Fields(args)...)
+		Run()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// TestRun_Shutdown sends SIGTERM to the application, run
+// by a child process, while clients draw from a deck. Every
+// draw the application did must have reached its client,
+// and be kept by the stores the application leaves behind.
+func TestRun_Shutdown(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the application")
+	}
+
+	dir := t.TempDir()
+	cmd, base, logs := startApp(t, "-wal.dir", dir, "-snapshot.interval", "0")
+
+	const size = 5000
+	deckID := newTestDeck(t, base, size)
+
+	const clients = 8
+	var (
+		mu    sync.Mutex
+		drawn int
+		wg    sync.WaitGroup
+		stop  = make(chan struct{})
+	)
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				n, err := drawTestCards(base, deckID)
+				if err != nil {
+					// The server stopped listening.
+					return
+				}
+				mu.Lock()
+				drawn += n
+				mu.Unlock()
+			}
+		}()
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() | exited with %v, want 0\n%s", err, logs.String())
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatalf("Run() | still running 30s after SIGTERM\n%s", logs.String())
+	}
+	close(stop)
+	wg.Wait()
+
+	if !strings.Contains(logs.String(), "msg=stopped") {
+		t.Errorf("Run() | didn't shut down cleanly:\n%s", logs.String())
+	}
+	if drawn == 0 {
+		t.Fatal("Run() | no card drawn before SIGTERM")
+	}
+
+	// The final snapshot compacts the WAL, so the snapshot
+	// alone holds every draw.
+	snapshot, err := repo.NewDeckSnapshotFile(filepath.Join(dir, "decks.snapshot")).Read()
+	if err != nil {
+		t.Fatalf("reading the snapshot | got error %v, want nil", err)
+	}
+	decks := repo.NewDeck()
+	if err := usecase.RestoreDecks(context.Background(), snapshot, decks, decks.Events()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.ReplayDeckWAL(dir, decks); err != nil {
+		t.Fatal(err)
+	}
+	deck, err := decks.Get(context.Background(), "", deckID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deck.Remaining != size-drawn {
+		t.Errorf("Run() | deck kept %d cards, want %d after %d draws reached their clients", deck.Remaining, size-drawn, drawn)
+	}
+}
+
+// TestRun_Draining checks the application reports not
+// ready, while still alive, during the shutdown delay.
+func TestRun_Draining(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the application")
+	}
+
+	cmd, base, logs := startApp(t, "-http.shutdown_delay", "2s")
+	newTestDeck(t, base, 1)
+	if got := probe(t, base+"/readyz"); got != http.StatusOK {
+		t.Fatalf("GET /readyz | got status %d before SIGTERM, want %d", got, http.StatusOK)
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for probe(t, base+"/readyz") != http.StatusServiceUnavailable {
+		if time.Now().After(deadline) {
+			t.Fatalf("GET /readyz | still ready after SIGTERM\n%s", logs.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := probe(t, base+"/healthz"); got != http.StatusOK {
+		t.Errorf("GET /healthz | got status %d while draining, want %d", got, http.StatusOK)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("Run() | exited with %v, want 0\n%s", err, logs.String())
+	}
+}
+
+func TestInFlight(t *testing.T) {
+	var requests inFlight
+	release := make(chan struct{})
+	h := requests.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		close(done)
+	}()
+	for requests.count() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if requests.wait(ctx) {
+		t.Fatal("inFlight.wait() | got idle with a handler running")
+	}
+
+	close(release)
+	<-done
+	if !requests.wait(context.Background()) {
+		t.Error("inFlight.wait() | got busy once the handler returned")
+	}
+}
+
+// startApp runs the application in a child process with
+// the given flags, returning its base URL.
+func startApp(t *testing.T, args ...string) (*exec.Cmd, string, *bytes.Buffer) {
+	t.Helper()
+	addr := freeAddr(t)
+
+	logs := &bytes.Buffer{}
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), "APP_TEST_ARGS=-http.addr "+addr+" "+strings.Join(args, " "))
+	cmd.Stderr = logs
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cmd.Process.Kill() })
+
+	return cmd, "http://" + addr, logs
+}
+
+func probe(t *testing.T, url string) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// newTestDeck creates a deck of size cards once the
+// application is up.
+func newTestDeck(t *testing.T, base string, size int) string {
+	t.Helper()
+	codes := strings.TrimSuffix(strings.Repeat("AS,", size), ",")
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		resp, err := http.Post(base+"/v1/decks/?cards="+codes, "", nil)
+		if err == nil {
+			defer resp.Body.Close()
+			var deck struct {
+				ID string `json:"deck_id"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&deck); err != nil {
+				t.Fatal(err)
+			}
+			return deck.ID
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("application not up: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func drawTestCards(base, deckID string) (int, error) {
+	resp, err := http.Get(base + "/v1/decks/withdrawals/" + deckID + "?amount=1")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("got status %d", resp.StatusCode)
+	}
+
+	var draw struct {
+		Cards []json.RawMessage `json:"cards"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&draw); err != nil {
+		return 0, err
+	}
+	return len(draw.Cards), nil
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// closers are the resources closed on shutdown, in the
+// reverse order they were opened, as a defer would.
+type closers []closer
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+func (c *closers) add(name string, close func() error) {
+	*c = append(*c, closer{name: name, close: close})
+}
+
+// closeAll closes every resource, logging the ones that
+// fail and moving on to the rest.
+func (c closers) closeAll() {
+	for i := len(c) - 1; i >= 0; i-- {
+		if err := c[i].close(); err != nil {
+			slog.Error("closing "+c[i].name, slog.Any("err", err))
+		}
+	}
+}
+
+// workers runs the background jobs of the application
+// until they're stopped.
+type workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newWorkers() *workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workers{ctx: ctx, cancel: cancel}
+}
+
+func (w *workers) run(job func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		job(w.ctx)
+	}()
+}
+
+// stop cancels the jobs and waits for them to return.
+func (w *workers) stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// handlerWaitTimeout bounds how long the handlers left
+// running once the connections are closed get to return.
+const handlerWaitTimeout = 5 * time.Second
+
+// inFlight counts the requests being handled. Closing the
+// server drops their connections but doesn't stop their
+// handlers, which may still be using the stores.
+type inFlight struct {
+	n int64
+}
+
+// middleware counts the requests through next.
+func (f *inFlight) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&f.n, 1)
+		defer atomic.AddInt64(&f.n, -1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// count returns how many requests are being handled.
+func (f *inFlight) count() int64 {
+	return atomic.LoadInt64(&f.n)
+}
+
+// wait waits for every handler to return until ctx is
+// done, telling whether they did.
+func (f *inFlight) wait(ctx context.Context) bool {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for f.count() > 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
@@ -0,0 +1,590 @@
+// Package config loads the runtime configuration of the
+// application. Settings come from defaults, an optional
+// YAML or TOML file, environment variables and flags, each
+// overriding the ones before.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// InvalidErr happens when a setting has a value the application can't run with.
+	InvalidErr = errors.New("invalid config")
+	// FileErr happens when the config file can't be read.
+	FileErr = errors.New("reading config file")
+)
+
+// Config is the runtime configuration of the application.
+type Config struct {
+	HTTP      HTTP      `yaml:"http" toml:"http"`
+	Store     Store     `yaml:"store" toml:"store"`
+	Deck      Deck      `yaml:"deck" toml:"deck"`
+	Snapshot  Snapshot  `yaml:"snapshot" toml:"snapshot"`
+	WAL       WAL       `yaml:"wal" toml:"wal"`
+	Audit     Audit     `yaml:"audit" toml:"audit"`
+	Klondike  Klondike  `yaml:"klondike" toml:"klondike"`
+	Trace     Trace     `yaml:"trace" toml:"trace"`
+	Log       Log       `yaml:"log" toml:"log"`
+	Auth      Auth      `yaml:"auth" toml:"auth"`
+	RateLimit RateLimit `yaml:"rate_limit" toml:"rate_limit"`
+}
+
+// HTTP configures the HTTP server. TLS is served when
+// both the certificate and key files are set.
+type HTTP struct {
+	Addr         string        `yaml:"addr" toml:"addr"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" toml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout" toml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	// ShutdownDelay is how long the application reports
+	// not ready before it stops taking requests, so load
+	// balancers stop sending them first.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" toml:"shutdown_delay"`
+	// ShutdownTimeout is how long requests in flight have
+	// to finish once the application stops taking requests.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	TLSCert         string        `yaml:"tls_cert" toml:"tls_cert"`
+	TLSKey          string        `yaml:"tls_key" toml:"tls_key"`
+}
+
+// Store configures the deck store. The DSN is the bolt file
+// for bolt, the address or URL of the server for redis and
+// the connection string for postgres.
+type Store struct {
+	Backend  string `yaml:"backend" toml:"backend"`
+	DSN      string `yaml:"dsn" toml:"dsn"`
+	MaxConns int    `yaml:"max_conns" toml:"max_conns"`
+}
+
+// Deck bounds the decks. Zero TTL and MaxDecks mean no bound.
+type Deck struct {
+	TTL      time.Duration `yaml:"ttl" toml:"ttl"`
+	MaxDecks int           `yaml:"max_decks" toml:"max_decks"`
+	Shuffle  string        `yaml:"shuffle" toml:"shuffle"`
+}
+
+// Snapshot configures the snapshots of the memory store.
+type Snapshot struct {
+	File     string        `yaml:"file" toml:"file"`
+	Interval time.Duration `yaml:"interval" toml:"interval"`
+}
+
+// WAL configures the write-ahead log of the memory store.
+type WAL struct {
+	Dir          string        `yaml:"dir" toml:"dir"`
+	Sync         string        `yaml:"sync" toml:"sync"`
+	SyncInterval time.Duration `yaml:"sync_interval" toml:"sync_interval"`
+}
+
+// Audit configures the audit log, kept in a file or in
+// SQLite.
+type Audit struct {
+	File   string `yaml:"file" toml:"file"`
+	SQLite string `yaml:"sqlite" toml:"sqlite"`
+}
+
+// Klondike configures the Klondike solver.
+type Klondike struct {
+	SolveTimeout time.Duration `yaml:"solve_timeout" toml:"solve_timeout"`
+}
+
+// Trace configures the tracing. Spans are written as OTLP
+// JSON to stdout or a file, or not exported with none.
+type Trace struct {
+	Exporter string `yaml:"exporter" toml:"exporter"`
+	File     string `yaml:"file" toml:"file"`
+	// SampleRatio is the share of the traces started by the
+	// application that are kept. Traces started by a caller
+	// keep the caller's decision.
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+// Log configures the logs, written to stderr as text or
+// JSON.
+type Log struct {
+	Level  string `yaml:"level" toml:"level"`
+	Format string `yaml:"format" toml:"format"`
+}
+
+// Auth configures how callers authenticate: not at all
+// with none, or with an API key with api_key, a bearer JWT
+// with jwt, or either with api_key,jwt. AdminKey is an
+// admin key named admin, for setting up the others.
+// APIKeys can only be set in the config file.
+type Auth struct {
+	Mode     string   `yaml:"mode" toml:"mode"`
+	AdminKey string   `yaml:"admin_key" toml:"admin_key"`
+	APIKeys  []APIKey `yaml:"api_keys" toml:"api_keys"`
+	JWT      JWT      `yaml:"jwt" toml:"jwt"`
+}
+
+// Uses tells if callers can authenticate the way of mode,
+// api_key or jwt.
+func (a Auth) Uses(mode string) bool {
+	for _, m := range strings.Split(a.Mode, ",") {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// JWT configures the bearer tokens. HS256 tokens are
+// checked with Secret, RS256 tokens with the keys of JWKS,
+// a JSON Web Key Set file or URL, such as the jwks_uri of
+// an OIDC provider. Issuer and Audience, when set, must
+// match the tokens.
+type JWT struct {
+	Secret      string        `yaml:"secret" toml:"secret"`
+	JWKS        string        `yaml:"jwks" toml:"jwks"`
+	JWKSRefresh time.Duration `yaml:"jwks_refresh" toml:"jwks_refresh"`
+	Issuer      string        `yaml:"issuer" toml:"issuer"`
+	Audience    string        `yaml:"audience" toml:"audience"`
+}
+
+// APIKey is a key of the config file. Tenant is the tenant
+// it works in, the default one when empty.
+type APIKey struct {
+	Name   string `yaml:"name" toml:"name"`
+	Key    string `yaml:"key" toml:"key"`
+	Admin  bool   `yaml:"admin" toml:"admin"`
+	Tenant string `yaml:"tenant" toml:"tenant"`
+}
+
+// RateLimit configures the rate limits of each client, by
+// API key, bearer token subject or IP address, on deck
+// creation and draws, and of each deck on the draws from
+// it. Deals and returns count as draws.
+type RateLimit struct {
+	Create   Bucket `yaml:"create" toml:"create"`
+	Draw     Bucket `yaml:"draw" toml:"draw"`
+	DeckDraw Bucket `yaml:"deck_draw" toml:"deck_draw"`
+}
+
+// Bucket is a token bucket of Burst requests, refilled
+// with PerMinute of them each minute. Zero PerMinute means
+// no limit, and Burst defaults to PerMinute.
+type Bucket struct {
+	PerMinute int `yaml:"per_minute" toml:"per_minute"`
+	Burst     int `yaml:"burst" toml:"burst"`
+}
+
+// Default returns the configuration used when nothing
+// else is set.
+func Default() Config {
+	return Config{
+		HTTP: HTTP{
+			Addr:            ":8080",
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     2 * time.Minute,
+			ShutdownTimeout: 20 * time.Second,
+		},
+		Store: Store{
+			Backend:  "memory",
+			MaxConns: 10,
+		},
+		Deck: Deck{
+			Shuffle: "math",
+		},
+		Snapshot: Snapshot{
+			Interval: 5 * time.Minute,
+		},
+		WAL: WAL{
+			Sync:         "always",
+			SyncInterval: time.Second,
+		},
+		Klondike: Klondike{
+			SolveTimeout: 2 * time.Second,
+		},
+		Trace: Trace{
+			Exporter:    "none",
+			SampleRatio: 1,
+		},
+		Log: Log{
+			Level:  "info",
+			Format: "text",
+		},
+		Auth: Auth{
+			Mode: "none",
+			JWT: JWT{
+				JWKSRefresh: time.Hour,
+			},
+		},
+	}
+}
+
+// setting is a value of the configuration, named by its
+// key in the config file, which is also its flag.
+type setting struct {
+	key   string
+	env   string
+	usage string
+	value interface{}
+	// secret settings have their passwords hidden when
+	// the configuration is printed.
+	secret bool
+	// hidden settings, such as keys, are hidden whole.
+	hidden bool
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{key: "http.addr", env: "HTTP_ADDR", usage: "address the HTTP server listens on", value: &c.HTTP.Addr},
+		{key: "http.read_timeout", env: "HTTP_READ_TIMEOUT", usage: "time to read a whole request", value: &c.HTTP.ReadTimeout},
+		{key: "http.write_timeout", env: "HTTP_WRITE_TIMEOUT", usage: "time to write a response", value: &c.HTTP.WriteTimeout},
+		{key: "http.idle_timeout", env: "HTTP_IDLE_TIMEOUT", usage: "time an idle connection is kept", value: &c.HTTP.IdleTimeout},
+		{key: "http.shutdown_delay", env: "HTTP_SHUTDOWN_DELAY", usage: "time reported not ready before shutting down", value: &c.HTTP.ShutdownDelay},
+		{key: "http.shutdown_timeout", env: "HTTP_SHUTDOWN_TIMEOUT", usage: "time requests in flight have to finish on shutdown", value: &c.HTTP.ShutdownTimeout},
+		{key: "http.tls_cert", env: "HTTP_TLS_CERT", usage: "TLS certificate file", value: &c.HTTP.TLSCert},
+		{key: "http.tls_key", env: "HTTP_TLS_KEY", usage: "TLS key file", value: &c.HTTP.TLSKey},
+		{key: "store.backend", env: "DECK_STORE", usage: "deck store: memory, bolt, redis or postgres", value: &c.Store.Backend},
+		{key: "store.dsn", env: "DECK_STORE_DSN", usage: "bolt file, redis address or postgres connection string", value: &c.Store.DSN, secret: true},
+		{key: "store.max_conns", env: "DECK_STORE_MAX_CONNS", usage: "connections to postgres", value: &c.Store.MaxConns},
+		{key: "deck.ttl", env: "DECK_TTL", usage: "time a deck is kept after its last change, 0 to keep it", value: &c.Deck.TTL},
+		{key: "deck.max_decks", env: "DECK_MAX_DECKS", usage: "decks each tenant can keep, 0 for no limit", value: &c.Deck.MaxDecks},
+		{key: "deck.shuffle", env: "DECK_SHUFFLE", usage: "shuffle strategy: math or crypto", value: &c.Deck.Shuffle},
+		{key: "snapshot.file", env: "DECK_SNAPSHOT_FILE", usage: "snapshot file of the memory store", value: &c.Snapshot.File},
+		{key: "snapshot.interval", env: "DECK_SNAPSHOT_INTERVAL", usage: "time between snapshots, 0 to only take them on demand", value: &c.Snapshot.Interval},
+		{key: "wal.dir", env: "DECK_WAL_DIR", usage: "write-ahead log directory of the memory store", value: &c.WAL.Dir},
+		{key: "wal.sync", env: "DECK_WAL_SYNC", usage: "WAL sync policy: always, interval or never", value: &c.WAL.Sync},
+		{key: "wal.sync_interval", env: "DECK_WAL_SYNC_INTERVAL", usage: "time between WAL syncs of the interval policy", value: &c.WAL.SyncInterval},
+		{key: "audit.file", env: "AUDIT_LOG_FILE", usage: "audit log file", value: &c.Audit.File},
+		{key: "audit.sqlite", env: "AUDIT_LOG_SQLITE", usage: "audit log SQLite database", value: &c.Audit.SQLite},
+		{key: "klondike.solve_timeout", env: "KLONDIKE_SOLVE_TIMEOUT", usage: "time the Klondike solver may run", value: &c.Klondike.SolveTimeout},
+		{key: "trace.exporter", env: "TRACE_EXPORTER", usage: "trace exporter: none, stdout or file", value: &c.Trace.Exporter},
+		{key: "trace.file", env: "TRACE_FILE", usage: "file the file exporter appends the spans to", value: &c.Trace.File},
+		{key: "trace.sample_ratio", env: "TRACE_SAMPLE_RATIO", usage: "share of the new traces kept, from 0 to 1", value: &c.Trace.SampleRatio},
+		{key: "log.level", env: "LOG_LEVEL", usage: "lowest level logged: debug, info, warn or error", value: &c.Log.Level},
+		{key: "log.format", env: "LOG_FORMAT", usage: "log format: text or json", value: &c.Log.Format},
+		{key: "auth.mode", env: "AUTH_MODE", usage: "authentication: none, api_key, jwt or api_key,jwt", value: &c.Auth.Mode},
+		{key: "auth.admin_key", env: "AUTH_ADMIN_KEY", usage: "admin API key, named admin", value: &c.Auth.AdminKey, hidden: true},
+		{key: "auth.jwt.secret", env: "AUTH_JWT_SECRET", usage: "secret checking HS256 bearer tokens", value: &c.Auth.JWT.Secret, hidden: true},
+		{key: "auth.jwt.jwks", env: "AUTH_JWT_JWKS", usage: "JWKS file or URL checking RS256 bearer tokens", value: &c.Auth.JWT.JWKS},
+		{key: "auth.jwt.jwks_refresh", env: "AUTH_JWT_JWKS_REFRESH", usage: "time between JWKS reloads, 0 to reload only for unknown keys", value: &c.Auth.JWT.JWKSRefresh},
+		{key: "auth.jwt.issuer", env: "AUTH_JWT_ISSUER", usage: "issuer the bearer tokens must have", value: &c.Auth.JWT.Issuer},
+		{key: "auth.jwt.audience", env: "AUTH_JWT_AUDIENCE", usage: "audience the bearer tokens must have", value: &c.Auth.JWT.Audience},
+		{key: "rate_limit.create.per_minute", env: "RATE_LIMIT_CREATE_PER_MINUTE", usage: "decks each client can create a minute, 0 for no limit", value: &c.RateLimit.Create.PerMinute},
+		{key: "rate_limit.create.burst", env: "RATE_LIMIT_CREATE_BURST", usage: "decks each client can create at once, 0 for per_minute", value: &c.RateLimit.Create.Burst},
+		{key: "rate_limit.draw.per_minute", env: "RATE_LIMIT_DRAW_PER_MINUTE", usage: "draws each client can make a minute, 0 for no limit", value: &c.RateLimit.Draw.PerMinute},
+		{key: "rate_limit.draw.burst", env: "RATE_LIMIT_DRAW_BURST", usage: "draws each client can make at once, 0 for per_minute", value: &c.RateLimit.Draw.Burst},
+		{key: "rate_limit.deck_draw.per_minute", env: "RATE_LIMIT_DECK_DRAW_PER_MINUTE", usage: "draws from each deck a minute, 0 for no limit", value: &c.RateLimit.DeckDraw.PerMinute},
+		{key: "rate_limit.deck_draw.burst", env: "RATE_LIMIT_DECK_DRAW_BURST", usage: "draws from each deck at once, 0 for per_minute", value: &c.RateLimit.DeckDraw.Burst},
+	}
+}
+
+// Load builds the configuration from the command line
+// arguments and the environment read by getenv. The config
+// file is set by the -config flag or CONFIG_FILE; its
+// format comes from its extension, .yaml, .yml or .toml.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	cfg := Default()
+	settings := cfg.settings()
+
+	type flagValue struct {
+		setting setting
+		value   string
+	}
+	var (
+		path    = getenv("CONFIG_FILE")
+		flagged []flagValue
+	)
+	fs := flag.NewFlagSet("card-game", flag.ContinueOnError)
+	fs.Func("config", "YAML or TOML config file", func(v string) error {
+		path = v
+		return nil
+	})
+	for _, s := range settings {
+		s := s
+		usage := s.usage
+		if s.env != "" {
+			usage += " (" + s.env + ")"
+		}
+		fs.Func(s.key, usage, func(v string) error {
+			flagged = append(flagged, flagValue{setting: s, value: v})
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+
+	for _, s := range settings {
+		v := getenv(s.env)
+		if v == "" {
+			continue
+		}
+		if err := set(s.value, v); err != nil {
+			return Config{}, fmt.Errorf("%w: %s: %v", InvalidErr, s.env, err)
+		}
+	}
+
+	for _, f := range flagged {
+		if err := set(f.setting.value, f.value); err != nil {
+			return Config{}, fmt.Errorf("%w: -%s: %v", InvalidErr, f.setting.key, err)
+		}
+	}
+
+	cfg.fill()
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile decodes the config file over the configuration.
+// Keys the configuration doesn't have are an error, so a
+// typo doesn't go unnoticed.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%w: %v", FileErr, err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("%w: %s: %v", FileErr, path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), c)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", FileErr, path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("%w: %s: unknown key %s", FileErr, path, undecoded[0])
+		}
+	default:
+		return fmt.Errorf("%w: %s: unknown format %q, want .yaml, .yml or .toml", FileErr, path, ext)
+	}
+
+	return nil
+}
+
+// fill sets the values that default to others.
+func (c *Config) fill() {
+	if c.Store.DSN == "" {
+		switch c.Store.Backend {
+		case "bolt":
+			c.Store.DSN = "decks.db"
+		case "redis":
+			c.Store.DSN = "localhost:6379"
+		}
+	}
+
+	// The WAL keeps the writes done since the last snapshot,
+	// so the snapshot defaults to living next to it.
+	if c.Snapshot.File == "" && c.WAL.Dir != "" {
+		c.Snapshot.File = filepath.Join(c.WAL.Dir, "decks.snapshot")
+	}
+
+	for _, b := range []*Bucket{&c.RateLimit.Create, &c.RateLimit.Draw, &c.RateLimit.DeckDraw} {
+		if b.Burst == 0 {
+			b.Burst = b.PerMinute
+		}
+	}
+}
+
+// Validate checks every setting, reporting all the
+// problems found at once.
+func (c Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.HTTP.Addr != "", "http.addr is empty")
+	check(c.HTTP.ReadTimeout >= 0, "http.read_timeout can't be negative")
+	check(c.HTTP.WriteTimeout >= 0, "http.write_timeout can't be negative")
+	check(c.HTTP.IdleTimeout >= 0, "http.idle_timeout can't be negative")
+	check(c.HTTP.ShutdownDelay >= 0, "http.shutdown_delay can't be negative")
+	check(c.HTTP.ShutdownTimeout > 0, "http.shutdown_timeout must be positive")
+	check((c.HTTP.TLSCert == "") == (c.HTTP.TLSKey == ""), "http.tls_cert and http.tls_key must be set together")
+
+	check(oneOf(c.Store.Backend, "memory", "bolt", "redis", "postgres"), "store.backend %q isn't memory, bolt, redis or postgres", c.Store.Backend)
+	check(c.Store.Backend != "postgres" || c.Store.DSN != "", "store.dsn is needed by postgres")
+	check(c.Store.MaxConns > 0, "store.max_conns must be positive")
+
+	check(c.Deck.TTL >= 0, "deck.ttl can't be negative")
+	check(c.Deck.MaxDecks >= 0, "deck.max_decks can't be negative")
+	check(oneOf(c.Deck.Shuffle, "math", "crypto"), "deck.shuffle %q isn't math or crypto", c.Deck.Shuffle)
+
+	check(c.Store.Backend == "memory" || (c.Snapshot.File == "" && c.WAL.Dir == ""), "snapshot.file and wal.dir only apply to the memory store")
+	check(c.Snapshot.Interval >= 0, "snapshot.interval can't be negative")
+	check(oneOf(c.WAL.Sync, "always", "interval", "never"), "wal.sync %q isn't always, interval or never", c.WAL.Sync)
+	check(c.WAL.Sync != "interval" || c.WAL.SyncInterval > 0, "wal.sync_interval must be positive with the interval policy")
+
+	check(c.Audit.File == "" || c.Audit.SQLite == "", "audit.file and audit.sqlite can't both be set")
+
+	check(c.Klondike.SolveTimeout > 0, "klondike.solve_timeout must be positive")
+
+	check(oneOf(c.Trace.Exporter, "none", "stdout", "file"), "trace.exporter %q isn't none, stdout or file", c.Trace.Exporter)
+	check(c.Trace.Exporter != "file" || c.Trace.File != "", "trace.file is needed by the file exporter")
+	check(c.Trace.SampleRatio >= 0 && c.Trace.SampleRatio <= 1, "trace.sample_ratio must be from 0 to 1")
+
+	check(oneOf(c.Log.Level, "debug", "info", "warn", "error"), "log.level %q isn't debug, info, warn or error", c.Log.Level)
+	check(oneOf(c.Log.Format, "text", "json"), "log.format %q isn't text or json", c.Log.Format)
+
+	check(oneOf(c.Auth.Mode, "none", "api_key", "jwt", "api_key,jwt", "jwt,api_key"), "auth.mode %q isn't none, api_key, jwt or api_key,jwt", c.Auth.Mode)
+	check(!c.Auth.Uses("jwt") || c.Auth.JWT.Secret != "" || c.Auth.JWT.JWKS != "", "auth.jwt.secret or auth.jwt.jwks is needed by jwt")
+	check(c.Auth.JWT.JWKSRefresh >= 0, "auth.jwt.jwks_refresh can't be negative")
+	names := map[string]bool{}
+	if c.Auth.AdminKey != "" {
+		names["admin"] = true
+	}
+	for i, k := range c.Auth.APIKeys {
+		check(k.Name != "" && k.Key != "", "auth.api_keys[%d] needs a name and a key", i)
+		check(!names[k.Name], "auth.api_keys[%d]: name %q is taken", i, k.Name)
+		names[k.Name] = true
+	}
+
+	check(c.RateLimit.Create.PerMinute >= 0 && c.RateLimit.Create.Burst >= 0, "rate_limit.create can't be negative")
+	check(c.RateLimit.Draw.PerMinute >= 0 && c.RateLimit.Draw.Burst >= 0, "rate_limit.draw can't be negative")
+	check(c.RateLimit.DeckDraw.PerMinute >= 0 && c.RateLimit.DeckDraw.Burst >= 0, "rate_limit.deck_draw can't be negative")
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", InvalidErr, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// String lists every setting with its value, one per line,
+// with the passwords of secret settings and the hidden
+// settings hidden. The keys of the config file are left out.
+func (c Config) String() string {
+	var b strings.Builder
+	for _, s := range c.settings() {
+		v := format(s.value)
+		if s.secret || s.hidden {
+			v = strconv.Quote(s.shown())
+		}
+		fmt.Fprintf(&b, "%s = %s\n", s.key, v)
+	}
+	return b.String()
+}
+
+// LogValue logs every setting as an attribute, with the
+// passwords of secret settings and the hidden settings
+// hidden. The keys of the config file are left out.
+func (c Config) LogValue() slog.Value {
+	var attrs []slog.Attr
+	for _, s := range c.settings() {
+		var v slog.Value
+		switch value := s.value.(type) {
+		case *string:
+			v = slog.StringValue(s.shown())
+		case *int:
+			v = slog.IntValue(*value)
+		case *float64:
+			v = slog.Float64Value(*value)
+		case *time.Duration:
+			v = slog.DurationValue(*value)
+		default:
+			panic(fmt.Sprintf("config: setting of type %T", s.value))
+		}
+		attrs = append(attrs, slog.Attr{Key: s.key, Value: v})
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// shown is the value of a string setting as printed.
+func (s setting) shown() string {
+	v := *s.value.(*string)
+	switch {
+	case s.hidden && v != "":
+		return "xxxxx"
+	case s.secret:
+		return redact(v)
+	}
+	return v
+}
+
+func set(value interface{}, s string) error {
+	switch v := value.(type) {
+	case *string:
+		*v = s
+	case *int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("%q isn't a number", s)
+		}
+		*v = n
+	case *float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("%q isn't a number", s)
+		}
+		*v = f
+	case *time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("%q isn't a duration", s)
+		}
+		*v = d
+	default:
+		panic(fmt.Sprintf("config: setting of type %T", value))
+	}
+	return nil
+}
+
+func format(value interface{}) string {
+	switch v := value.(type) {
+	case *string:
+		return strconv.Quote(*v)
+	case *int:
+		return strconv.Itoa(*v)
+	case *float64:
+		return strconv.FormatFloat(*v, 'g', -1, 64)
+	case *time.Duration:
+		return v.String()
+	default:
+		panic(fmt.Sprintf("config: setting of type %T", value))
+	}
+}
+
+var passwordParam = regexp.MustCompile(`(?i)(password\s*=\s*)('[^']*'|\S+)`)
+
+// redact hides the password of a URL or key=value
+// connection string.
+func redact(s string) string {
+	if u, err := url.Parse(s); err == nil && u.User != nil {
+		s = u.Redacted()
+	}
+	return passwordParam.ReplaceAllString(s, "${1}xxxxx")
+}
+
+func oneOf(v string, values ...string) bool {
+	for _, want := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,340 @@
+package config
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Default(t *testing.T) {
+	got, err := Load(nil, env(nil))
+	if err != nil {
+		t.Fatalf("Load() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(got, Default()); diff != "" {
+		t.Errorf("Load() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+http:
+  addr: ":9000"
+  read_timeout: 1m
+deck:
+  max_decks: 10
+  shuffle: crypto
+trace:
+  sample_ratio: 0.5
+`)
+
+	got, err := Load(
+		[]string{"-config", path, "-deck.max_decks", "30"},
+		env(map[string]string{"HTTP_ADDR": ":9100", "DECK_MAX_DECKS": "20", "TRACE_SAMPLE_RATIO": "0.25"}),
+	)
+	if err != nil {
+		t.Fatalf("Load() | got error %v, want nil", err)
+	}
+
+	want := Default()
+	want.HTTP.Addr = ":9100"
+	want.HTTP.ReadTimeout = time.Minute
+	want.Deck.MaxDecks = 30
+	want.Deck.Shuffle = "crypto"
+	want.Trace.SampleRatio = 0.25
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Load() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestLoad_File(t *testing.T) {
+	want := Default()
+	want.Store = Store{Backend: "redis", DSN: "redis://cache:6379/1", MaxConns: 10}
+	want.Deck.TTL = 90 * time.Minute
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "config.yml",
+			data: "store:\n  backend: redis\n  dsn: redis://cache:6379/1\ndeck:\n  ttl: 1h30m\n",
+		},
+		{
+			name: "config.toml",
+			data: "[store]\nbackend = \"redis\"\ndsn = \"redis://cache:6379/1\"\n\n[deck]\nttl = \"1h30m\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(nil, env(map[string]string{"CONFIG_FILE": writeFile(t, tt.name, tt.data)}))
+			if err != nil {
+				t.Fatalf("Load() | got error %v, want nil", err)
+			}
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("Load() | (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoad_FileErr(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "unknown.yaml", data: "http:\n  adr: \":9000\"\n"},
+		{name: "unknown.toml", data: "[http]\nadr = \":9000\"\n"},
+		{name: "broken.yaml", data: "http: [\n"},
+		{name: "config.json", data: "{}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load([]string{"-config", writeFile(t, tt.name, tt.data)}, env(nil))
+			if !errors.Is(err, FileErr) {
+				t.Errorf("Load() | got error %v, want %v", err, FileErr)
+			}
+		})
+	}
+
+	if _, err := Load([]string{"-config", "missing.yaml"}, env(nil)); !errors.Is(err, FileErr) {
+		t.Errorf("Load() | got error %v for a missing file, want %v", err, FileErr)
+	}
+}
+
+func TestLoad_Fill(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantDSN      string
+		wantSnapshot string
+	}{
+		{name: "Bolt", args: []string{"-store.backend", "bolt"}, wantDSN: "decks.db"},
+		{name: "Redis", args: []string{"-store.backend", "redis"}, wantDSN: "localhost:6379"},
+		{name: "Set DSN", args: []string{"-store.backend", "redis", "-store.dsn", "cache:6380"}, wantDSN: "cache:6380"},
+		{name: "WAL", args: []string{"-wal.dir", "data"}, wantSnapshot: filepath.Join("data", "decks.snapshot")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.args, env(nil))
+			if err != nil {
+				t.Fatalf("Load() | got error %v, want nil", err)
+			}
+			if got.Store.DSN != tt.wantDSN || got.Snapshot.File != tt.wantSnapshot {
+				t.Errorf("Load() | got dsn %q and snapshot %q, want %q and %q", got.Store.DSN, got.Snapshot.File, tt.wantDSN, tt.wantSnapshot)
+			}
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{name: "Bad Duration", env: map[string]string{"DECK_TTL": "soon"}, want: "DECK_TTL"},
+		{name: "Bad Number", args: []string{"-deck.max_decks", "many"}, want: "-deck.max_decks"},
+		{name: "Negative TTL", args: []string{"-deck.ttl", "-1m"}, want: "deck.ttl"},
+		{name: "Shuffle", args: []string{"-deck.shuffle", "riffle"}, want: "deck.shuffle"},
+		{name: "Backend", args: []string{"-store.backend", "mongo"}, want: "store.backend"},
+		{name: "Postgres DSN", args: []string{"-store.backend", "postgres"}, want: "store.dsn"},
+		{name: "TLS Key", args: []string{"-http.tls_cert", "cert.pem"}, want: "http.tls_key"},
+		{name: "WAL Backend", args: []string{"-store.backend", "bolt", "-wal.dir", "data"}, want: "wal.dir"},
+		{name: "WAL Sync", env: map[string]string{"DECK_WAL_SYNC": "sometimes"}, want: "wal.sync"},
+		{name: "Trace Exporter", env: map[string]string{"TRACE_EXPORTER": "jaeger"}, want: "trace.exporter"},
+		{name: "Trace File", args: []string{"-trace.exporter", "file"}, want: "trace.file"},
+		{name: "Sample Ratio", args: []string{"-trace.sample_ratio", "1.5"}, want: "trace.sample_ratio"},
+		{name: "Log Level", env: map[string]string{"LOG_LEVEL": "verbose"}, want: "log.level"},
+		{name: "Log Format", args: []string{"-log.format", "logfmt"}, want: "log.format"},
+		{name: "Audit", args: []string{"-audit.file", "audit.log", "-audit.sqlite", "audit.db"}, want: "audit.sqlite"},
+		{name: "Auth Mode", env: map[string]string{"AUTH_MODE": "basic"}, want: "auth.mode"},
+		{name: "JWT Keys", args: []string{"-auth.mode", "api_key,jwt"}, want: "auth.jwt.jwks"},
+		{name: "Rate Limit", env: map[string]string{"RATE_LIMIT_DRAW_PER_MINUTE": "-1"}, want: "rate_limit.draw"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.args, env(tt.env))
+			if !errors.Is(err, InvalidErr) {
+				t.Fatalf("Load() | got error %v, want %v", err, InvalidErr)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() | got error %v, want it to name %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_APIKeys(t *testing.T) {
+	want := []APIKey{{Name: "ops", Key: "ops-secret", Admin: true}, {Name: "ci", Key: "ci-secret", Tenant: "acme"}}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "config.yaml",
+			data: "auth:\n  mode: api_key\n  api_keys:\n    - {name: ops, key: ops-secret, admin: true}\n    - {name: ci, key: ci-secret, tenant: acme}\n",
+		},
+		{
+			name: "config.toml",
+			data: "[auth]\nmode = \"api_key\"\n\n[[auth.api_keys]]\nname = \"ops\"\nkey = \"ops-secret\"\nadmin = true\n\n[[auth.api_keys]]\nname = \"ci\"\nkey = \"ci-secret\"\ntenant = \"acme\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(nil, env(map[string]string{"CONFIG_FILE": writeFile(t, tt.name, tt.data)}))
+			if err != nil {
+				t.Fatalf("Load() | got error %v, want nil", err)
+			}
+			if diff := cmp.Diff(got.Auth.APIKeys, want); diff != "" {
+				t.Errorf("Load() | (-got +want):\n%s", diff)
+			}
+		})
+	}
+
+	invalid := []string{
+		"auth:\n  api_keys:\n    - {name: ops}\n",
+		"auth:\n  api_keys:\n    - {name: ops, key: a}\n    - {name: ops, key: b}\n",
+		"auth:\n  admin_key: root\n  api_keys:\n    - {name: admin, key: a}\n",
+	}
+	for _, data := range invalid {
+		if _, err := Load(nil, env(map[string]string{"CONFIG_FILE": writeFile(t, "config.yaml", data)})); !errors.Is(err, InvalidErr) {
+			t.Errorf("Load() | got error %v for\n%s\nwant %v", err, data, InvalidErr)
+		}
+	}
+}
+
+func TestLoad_JWT(t *testing.T) {
+	data := "auth:\n  mode: api_key,jwt\n  jwt:\n    jwks: https://issuer.test/.well-known/jwks.json\n    issuer: https://issuer.test\n    audience: card-game\n"
+	got, err := Load(
+		[]string{"-auth.jwt.jwks_refresh", "10m"},
+		env(map[string]string{"CONFIG_FILE": writeFile(t, "config.yaml", data), "AUTH_JWT_SECRET": "s3cret"}),
+	)
+	if err != nil {
+		t.Fatalf("Load() | got error %v, want nil", err)
+	}
+
+	want := JWT{
+		Secret:      "s3cret",
+		JWKS:        "https://issuer.test/.well-known/jwks.json",
+		JWKSRefresh: 10 * time.Minute,
+		Issuer:      "https://issuer.test",
+		Audience:    "card-game",
+	}
+	if diff := cmp.Diff(got.Auth.JWT, want); diff != "" {
+		t.Errorf("Load() | (-got +want):\n%s", diff)
+	}
+	if !got.Auth.Uses("api_key") || !got.Auth.Uses("jwt") {
+		t.Errorf("Auth.Uses() | got false for a mode of %q", got.Auth.Mode)
+	}
+}
+
+func TestLoad_RateLimit(t *testing.T) {
+	data := "rate_limit:\n  create:\n    per_minute: 10\n    burst: 20\n"
+	got, err := Load(
+		[]string{"-rate_limit.deck_draw.burst", "5"},
+		env(map[string]string{"CONFIG_FILE": writeFile(t, "config.yaml", data), "RATE_LIMIT_DRAW_PER_MINUTE": "60"}),
+	)
+	if err != nil {
+		t.Fatalf("Load() | got error %v, want nil", err)
+	}
+
+	want := RateLimit{
+		Create:   Bucket{PerMinute: 10, Burst: 20},
+		Draw:     Bucket{PerMinute: 60, Burst: 60},
+		DeckDraw: Bucket{Burst: 5},
+	}
+	if diff := cmp.Diff(got.RateLimit, want); diff != "" {
+		t.Errorf("Load() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestConfig_Validate_All(t *testing.T) {
+	cfg := Default()
+	cfg.HTTP.Addr = ""
+	cfg.Store.MaxConns = 0
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "http.addr") || !strings.Contains(err.Error(), "store.max_conns") {
+		t.Errorf("Config.Validate() | got error %v, want both problems", err)
+	}
+}
+
+func TestConfig_String(t *testing.T) {
+	tests := []struct {
+		dsn  string
+		want string
+	}{
+		{dsn: "postgres://cards:s3cret@db:5432/cards?sslmode=disable", want: `"postgres://cards:xxxxx@db:5432/cards?sslmode=disable"`},
+		{dsn: "host=db user=cards password=s3cret dbname=cards", want: `"host=db user=cards password=xxxxx dbname=cards"`},
+		{dsn: "redis://:s3cret@cache:6379/0", want: `"redis://:xxxxx@cache:6379/0"`},
+		{dsn: "localhost:6379", want: `"localhost:6379"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dsn, func(t *testing.T) {
+			cfg := Default()
+			cfg.Store.DSN = tt.dsn
+
+			got := cfg.String()
+			if strings.Contains(got, "s3cret") {
+				t.Errorf("Config.String() | password shown:\n%s", got)
+			}
+			if !strings.Contains(got, "store.dsn = "+tt.want+"\n") {
+				t.Errorf("Config.String() | got\n%s\nwant store.dsn = %s", got, tt.want)
+			}
+		})
+	}
+
+	if got := Default().String(); !strings.Contains(got, "http.addr = \":8080\"\n") || !strings.Contains(got, "deck.ttl = 0s\n") {
+		t.Errorf("Config.String() | got\n%s", got)
+	}
+
+	cfg := Default()
+	cfg.Auth.AdminKey = "s3cret"
+	cfg.Auth.APIKeys = []APIKey{{Name: "ci", Key: "s3cret"}}
+	cfg.Auth.JWT.Secret = "s3cret"
+	if got := cfg.String(); strings.Contains(got, "s3cret") || !strings.Contains(got, "auth.admin_key = \"xxxxx\"\n") || !strings.Contains(got, "auth.jwt.secret = \"xxxxx\"\n") {
+		t.Errorf("Config.String() | key shown:\n%s", got)
+	}
+}
+
+func TestConfig_LogValue(t *testing.T) {
+	cfg := Default()
+	cfg.Store.DSN = "postgres://cards:s3cret@db:5432/cards"
+	cfg.Auth.AdminKey = "s3cret"
+	cfg.Auth.JWT.Secret = "s3cret"
+
+	var buf strings.Builder
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("config", slog.Any("config", cfg))
+
+	got := buf.String()
+	if strings.Contains(got, "s3cret") {
+		t.Errorf("Config.LogValue() | password shown:\n%s", got)
+	}
+	if !strings.Contains(got, "config.http.addr=:8080") || !strings.Contains(got, "config.deck.ttl=0s") {
+		t.Errorf("Config.LogValue() | got\n%s", got)
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(LogRequestID(slog.NewJSONHandler(&buf, nil)))
+
+	m := chi.NewRouter()
+	m.Use(RequestID)
+	m.Use(AccessLog(logger))
+	m.Get("/v1/decks/{deckID}", func(w http.ResponseWriter, r *http.Request) {
+		if chi.URLParam(r, "deckID") == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("deck"))
+	})
+
+	m.ServeHTTP(httptest.NewRecorder(), requestWithID("req-1"))
+	for _, path := range []string{"/v1/decks/a", "/v1/decks/broken"} {
+		req := requestWithID("req-2")
+		req.URL.Path = path
+		m.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	type entry struct {
+		Level     string `json:"level"`
+		Msg       string `json:"msg"`
+		Method    string `json:"method"`
+		Route     string `json:"route"`
+		Path      string `json:"path"`
+		Status    int    `json:"status"`
+		Duration  *int64 `json:"duration"`
+		Bytes     int    `json:"bytes"`
+		RequestID string `json:"request_id"`
+	}
+	want := []entry{
+		{Level: "INFO", Msg: "request", Method: "GET", Route: "unmatched", Path: "/", Status: 404, Bytes: 19, RequestID: "req-1"},
+		{Level: "INFO", Msg: "request", Method: "GET", Route: "/v1/decks/{deckID}", Path: "/v1/decks/a", Status: 200, Bytes: 4, RequestID: "req-2"},
+		{Level: "ERROR", Msg: "request", Method: "GET", Route: "/v1/decks/{deckID}", Path: "/v1/decks/broken", Status: 500, RequestID: "req-2"},
+	}
+
+	dec := json.NewDecoder(&buf)
+	for i, w := range want {
+		var got entry
+		if err := dec.Decode(&got); err != nil {
+			t.Fatalf("AccessLog() | line %d: %v", i, err)
+		}
+		if got.Duration == nil {
+			t.Errorf("AccessLog() | line %d has no duration", i)
+		}
+		got.Duration = nil
+		if got != w {
+			t.Errorf("AccessLog() | got line %d %+v, want %+v", i, got, w)
+		}
+	}
+	if dec.More() {
+		t.Error("AccessLog() | got more lines than requests")
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lualfe/card-game/internal/controller/http/response"
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+// APIKeyHeader is the request header carrying the API key.
+const APIKeyHeader = "X-API-Key"
+
+// bearerPrefix starts an Authorization header carrying a
+// bearer token.
+const bearerPrefix = "Bearer "
+
+// Auth lets through the requests with a bearer token of
+// tokens or a key of keys, keeping who they belong to in
+// their context. The others get a 401. Either of keys and
+// tokens can be nil, turning off that way in.
+func Auth(keys usecase.APIKeyManager, tokens usecase.TokenManager) func(http.Handler) http.Handler {
+	challenge, missing := "ApiKey", "missing "+APIKeyHeader+" header"
+	switch {
+	case tokens != nil && keys != nil:
+		challenge, missing = "Bearer", "missing bearer token or "+APIKeyHeader+" header"
+	case tokens != nil:
+		challenge, missing = "Bearer", "missing bearer token"
+	}
+	unauthorized := func(w http.ResponseWriter, msg string) {
+		w.Header().Set("WWW-Authenticate", challenge)
+		response.JSONError(w, msg, http.StatusUnauthorized)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				p   entity.Principal
+				err error
+			)
+			token, isBearer := bearerToken(r)
+			key := r.Header.Get(APIKeyHeader)
+			switch {
+			case isBearer && tokens != nil:
+				p, err = tokens.Authenticate(r.Context(), token)
+			case key != "" && keys != nil:
+				p, err = keys.Authenticate(r.Context(), key)
+			default:
+				unauthorized(w, missing)
+				return
+			}
+			if err != nil {
+				if errors.Is(err, usecase.APIKeyInvalidErr) || errors.Is(err, usecase.TokenInvalidErr) {
+					unauthorized(w, err.Error())
+					return
+				}
+				response.JSONError(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(usecase.WithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	if len(h) < len(bearerPrefix) || !strings.EqualFold(h[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(h[len(bearerPrefix):]), true
+}
+
+// Admin lets through the requests of admins of the default
+// tenant, and those without a principal when
+// authentication is off. The others, admins of another
+// tenant included, get a 403.
+func Admin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p, ok := usecase.PrincipalFrom(r.Context()); ok && (!p.Admin || p.Tenant != "") {
+			response.JSONError(w, "admin access needed", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Scope lets through the requests of principals with
+// scope, and those without a principal when authentication
+// is off. The others get a 403.
+func Scope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if p, ok := usecase.PrincipalFrom(r.Context()); ok && !p.Can(scope) {
+				response.JSONError(w, "missing scope "+scope, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+func TestAuth(t *testing.T) {
+	keys := usecase.NewAPIKeys(repo.NewAPIKey(), nil, []entity.APIKey{
+		{Name: "ops", Hash: usecase.HashAPIKey("ops-secret"), Admin: true},
+		{Name: "ci", Hash: usecase.HashAPIKey("ci-secret")},
+		{Name: "acme-ops", Hash: usecase.HashAPIKey("acme-secret"), Admin: true, Tenant: "acme"},
+	})
+
+	var got entity.Principal
+	h := Auth(keys, nil)(Admin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = usecase.PrincipalFrom(r.Context())
+	})))
+
+	tests := []struct {
+		name       string
+		key        string
+		statusCode int
+		want       entity.Principal
+	}{
+		{name: "Admin", key: "ops-secret", statusCode: http.StatusOK, want: entity.Principal{ID: "key:ops", Admin: true, Scopes: entity.DeckScopes}},
+		{name: "Not Admin", key: "ci-secret", statusCode: http.StatusForbidden},
+		{name: "Tenant Admin", key: "acme-secret", statusCode: http.StatusForbidden},
+		{name: "Missing", statusCode: http.StatusUnauthorized},
+		{name: "Invalid", key: "guess", statusCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = entity.Principal{}
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.key != "" {
+				r.Header.Set(APIKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.statusCode {
+				t.Errorf("Auth() | got status code %d, want %d", w.Code, tt.statusCode)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Auth() | (-got +want):\n%s", diff)
+			}
+			if tt.statusCode == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Auth() | no WWW-Authenticate header on a 401")
+			}
+		})
+	}
+}
+
+type failingAPIKeys struct{ usecase.APIKeyManager }
+
+func (failingAPIKeys) Authenticate(context.Context, string) (entity.Principal, error) {
+	return entity.Principal{}, errors.New("store down")
+}
+
+func TestAuth_StoreError(t *testing.T) {
+	h := Auth(failingAPIKeys{}, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Auth() | handler called though the key couldn't be checked")
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(APIKeyHeader, "key")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Auth() | got status code %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestAuth_Bearer(t *testing.T) {
+	secret := []byte("test-secret")
+	keys := usecase.NewAPIKeys(repo.NewAPIKey(), nil, []entity.APIKey{{Name: "ci", Hash: usecase.HashAPIKey("ci-secret")}})
+	tokens := usecase.NewTokens(usecase.TokenOptions{Secret: secret})
+
+	var got entity.Principal
+	h := Auth(keys, tokens)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = usecase.PrincipalFrom(r.Context())
+	}))
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "alice",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "decks:read",
+	}).SignedString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		key           string
+		statusCode    int
+		want          entity.Principal
+	}{
+		{name: "Token", authorization: "Bearer " + token, statusCode: http.StatusOK, want: entity.Principal{ID: "jwt:alice", Scopes: []string{"decks:read"}}},
+		{name: "Lowercase Scheme", authorization: "bearer " + token, statusCode: http.StatusOK, want: entity.Principal{ID: "jwt:alice", Scopes: []string{"decks:read"}}},
+		{name: "API Key", key: "ci-secret", statusCode: http.StatusOK, want: entity.Principal{ID: "key:ci", Scopes: entity.DeckScopes}},
+		{name: "Invalid Token", authorization: "Bearer " + token + "x", statusCode: http.StatusUnauthorized},
+		{name: "Basic", authorization: "Basic YWxpY2U6cHc=", statusCode: http.StatusUnauthorized},
+		{name: "Missing", statusCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = entity.Principal{}
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.key != "" {
+				r.Header.Set(APIKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.statusCode {
+				t.Errorf("Auth() | got status code %d, want %d", w.Code, tt.statusCode)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Auth() | (-got +want):\n%s", diff)
+			}
+			if tt.statusCode == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("Auth() | got WWW-Authenticate %q on a 401, want Bearer", w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestScope(t *testing.T) {
+	h := Scope(entity.ScopeDecksDraw)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name       string
+		principal  *entity.Principal
+		statusCode int
+	}{
+		{name: "No Principal", statusCode: http.StatusOK},
+		{name: "Scope", principal: &entity.Principal{ID: "alice", Scopes: []string{"decks:read", "decks:draw"}}, statusCode: http.StatusOK},
+		{name: "Admin", principal: &entity.Principal{ID: "root", Admin: true}, statusCode: http.StatusOK},
+		{name: "Missing Scope", principal: &entity.Principal{ID: "alice", Scopes: []string{"decks:read"}}, statusCode: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.principal != nil {
+				r = r.WithContext(usecase.WithPrincipal(r.Context(), *tt.principal))
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.statusCode {
+				t.Errorf("Scope() | got status code %d, want %d", w.Code, tt.statusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Package middleware holds the HTTP middlewares shared by
+// every route of the application.
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute labels the requests no route matched, so
+// unknown paths don't each make a series.
+const unmatchedRoute = "unmatched"
+
+// Metrics records the count and latency of the requests as
+// Prometheus metrics registered with reg. Requests are
+// labeled by route pattern, so every deck shares the
+// /v1/decks/{deckID} series. It must wrap the whole router.
+func Metrics(reg prometheus.Registerer) func(http.Handler) http.Handler {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests, by method, route pattern and status code.",
+	}, []string{"method", "route", "code"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of the HTTP requests, by method and route pattern.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+	reg.MustRegister(requests, duration)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			// The pattern is only known once chi routed the request.
+			route := routePattern(r)
+			if route == "" {
+				route = unmatchedRoute
+			}
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			requests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+			duration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// routePattern returns the pattern of the chi route r
+// matched, or "" when none did.
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+	// Mounted routers join their patterns with an extra
+	// slash, as in /v1/decks//.
+	return strings.ReplaceAll(rctx.RoutePattern(), "//", "/")
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := chi.NewRouter()
+	m.Use(Metrics(reg))
+	m.Route("/v1/decks", func(r chi.Router) {
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		})
+	})
+	m.Get("/v1/decks/{deckID}", func(w http.ResponseWriter, r *http.Request) {
+		if chi.URLParam(r, "deckID") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/decks/", nil))
+	for _, path := range []string{"/v1/decks/a", "/v1/decks/b", "/v1/decks/missing", "/nowhere"} {
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	requests, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]float64{}
+	for _, mf := range requests {
+		if mf.GetName() != "http_requests_total" {
+			continue
+		}
+		for _, metric := range mf.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range metric.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			got[labels["method"]+" "+labels["route"]+" "+labels["code"]] = metric.GetCounter().GetValue()
+		}
+	}
+
+	want := map[string]float64{
+		"POST /v1/decks/ 201":        1,
+		"GET /v1/decks/{deckID} 200": 2,
+		"GET /v1/decks/{deckID} 404": 1,
+		"GET unmatched 404":          1,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Metrics() | got series %v, want %v", got, want)
+	}
+	for series, n := range want {
+		if got[series] != n {
+			t.Errorf("Metrics() | got %v requests for %s, want %v", got[series], series, n)
+		}
+	}
+	if n := testutil.CollectAndCount(reg, "http_request_duration_seconds"); n != 3 {
+		t.Errorf("Metrics() | got %d latency series, want 3", n)
+	}
+}
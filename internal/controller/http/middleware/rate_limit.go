@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lualfe/card-game/internal/controller/http/response"
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+// RateLimit holds the requests to budget, in the bucket
+// key returns for each, to the limiter. It goes after
+// Auth and Tenant, so keys can tell callers apart by their
+// principal. Requests get the RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers of their
+// bucket, or of the one with the least remaining when
+// several hold a request. Those past the limit get a 429
+// with a Retry-After header.
+func RateLimit(limiter usecase.RateLimiter, budget usecase.RateLimitBudget, key func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			status := limiter.Take(r.Context(), budget, key(r))
+			if status.Limit > 0 {
+				setRateLimitHeaders(w.Header(), status)
+			}
+			if !status.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(status.RetryAfter)))
+				response.JSONError(w, "rate limit of "+string(budget)+" exceeded", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientKey tells callers apart by their principal, within
+// its tenant, or by their IP address without one. The
+// address is the one the request came from; forwarding
+// headers can be made up, so they're left alone.
+func ClientKey(r *http.Request) string {
+	if p, ok := usecase.PrincipalFrom(r.Context()); ok {
+		return "principal:" + p.Tenant + "/" + p.ID
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// setRateLimitHeaders sets the headers of status, unless
+// h already has those of a bucket with fewer requests
+// remaining.
+func setRateLimitHeaders(h http.Header, status entity.RateLimitStatus) {
+	if v := h.Get("RateLimit-Remaining"); v != "" {
+		if remaining, err := strconv.Atoi(v); err == nil && remaining <= status.Remaining {
+			return
+		}
+	}
+	h.Set("RateLimit-Limit", strconv.Itoa(status.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(status.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(status.Reset)))
+}
+
+// ceilSeconds returns d in whole seconds, rounded up.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
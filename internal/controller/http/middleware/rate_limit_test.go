@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+func TestRateLimit(t *testing.T) {
+	limiter := usecase.NewRateLimits(repo.NewRateLimit(), usecase.RateLimitOptions{
+		Create: entity.RateLimit{PerMinute: 60, Burst: 2},
+	})
+	h := RateLimit(limiter, usecase.RateLimitCreate, ClientKey)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		statusCode int
+		header     http.Header
+	}{
+		{
+			name:       "First",
+			remoteAddr: "192.0.2.1:1234",
+			statusCode: http.StatusOK,
+			header:     http.Header{"Ratelimit-Limit": {"2"}, "Ratelimit-Remaining": {"1"}, "Ratelimit-Reset": {"1"}},
+		},
+		{
+			name:       "Other Port",
+			remoteAddr: "192.0.2.1:5678",
+			statusCode: http.StatusOK,
+			header:     http.Header{"Ratelimit-Limit": {"2"}, "Ratelimit-Remaining": {"0"}, "Ratelimit-Reset": {"2"}},
+		},
+		{
+			name:       "Over Limit",
+			remoteAddr: "192.0.2.1:1234",
+			statusCode: http.StatusTooManyRequests,
+			header:     http.Header{"Ratelimit-Limit": {"2"}, "Ratelimit-Remaining": {"0"}, "Ratelimit-Reset": {"2"}, "Retry-After": {"1"}},
+		},
+		{
+			name:       "Other Address",
+			remoteAddr: "192.0.2.2:1234",
+			statusCode: http.StatusOK,
+			header:     http.Header{"Ratelimit-Limit": {"2"}, "Ratelimit-Remaining": {"1"}, "Ratelimit-Reset": {"1"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.statusCode {
+				t.Fatalf("RateLimit() | got status code %d, want %d", w.Code, tt.statusCode)
+			}
+			got := http.Header{}
+			for _, k := range []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"} {
+				if v := w.Header().Values(k); v != nil {
+					got[http.CanonicalHeaderKey(k)] = v
+				}
+			}
+			if diff := cmp.Diff(got, tt.header); diff != "" {
+				t.Errorf("RateLimit() | (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRateLimit_Tightest(t *testing.T) {
+	limiter := usecase.NewRateLimits(repo.NewRateLimit(), usecase.RateLimitOptions{
+		Draw:     entity.RateLimit{PerMinute: 60, Burst: 5},
+		DeckDraw: entity.RateLimit{PerMinute: 60, Burst: 2},
+	})
+	deck := func(*http.Request) string { return "deck" }
+	h := RateLimit(limiter, usecase.RateLimitDraw, ClientKey)(
+		RateLimit(limiter, usecase.RateLimitDeckDraw, deck)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})),
+	)
+
+	var codes []int
+	for i := 0; i < 3; i++ {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r = r.WithContext(usecase.WithPrincipal(r.Context(), entity.Principal{ID: "alice"}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		codes = append(codes, w.Code)
+
+		if got := w.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("RateLimit() | request %d got RateLimit-Limit %q, want the deck's 2", i, got)
+		}
+	}
+	if diff := cmp.Diff(codes, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}); diff != "" {
+		t.Errorf("RateLimit() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "[2001:db8::1]:1234"
+	if got, want := ClientKey(r), "ip:2001:db8::1"; got != want {
+		t.Errorf("ClientKey() | got %q, want %q", got, want)
+	}
+
+	r = r.WithContext(usecase.WithPrincipal(r.Context(), entity.Principal{ID: "ci", Tenant: "acme"}))
+	if got, want := ClientKey(r), "principal:acme/ci"; got != want {
+		t.Errorf("ClientKey() | got %q, want %q", got, want)
+	}
+}
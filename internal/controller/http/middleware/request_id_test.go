@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lualfe/card-game/internal/controller/http/response"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "Caller's", header: "req-42", want: "req-42"},
+		{name: "Missing"},
+		{name: "Too Long", header: strings.Repeat("a", maxRequestIDLen+1)},
+		{name: "Not Printable", header: "req 42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = RequestIDFromContext(r.Context())
+				response.JSONError(w, "deck not found", http.StatusNotFound)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/decks/a", nil)
+			if tt.header != "" {
+				req.Header.Set(response.RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if tt.want != "" && got != tt.want {
+				t.Errorf("RequestID() | got ID %q, want %q", got, tt.want)
+			}
+			if tt.want == "" && (got == "" || got == tt.header) {
+				t.Errorf("RequestID() | got ID %q, want a new one", got)
+			}
+			if header := w.Header().Get(response.RequestIDHeader); header != got {
+				t.Errorf("RequestID() | got header %q, want %q", header, got)
+			}
+			var body response.Error
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.RequestID != got {
+				t.Errorf("RequestID() | got error body ID %q, want %q", body.RequestID, got)
+			}
+		})
+	}
+}
+
+func TestLogRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(LogRequestID(slog.NewTextHandler(&buf, nil))).With("deck_id", "a")
+
+	var ctx context.Context
+	RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	})).ServeHTTP(httptest.NewRecorder(), requestWithID("req-42"))
+
+	logger.InfoContext(ctx, "cards drawn")
+	logger.InfoContext(context.Background(), "deck expiry")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("LogRequestID() | got %d lines, want 2", len(lines))
+	}
+	if !strings.Contains(lines[0], "deck_id=a request_id=req-42") {
+		t.Errorf("LogRequestID() | got %q, want the request ID", lines[0])
+	}
+	if strings.Contains(lines[1], "request_id") {
+		t.Errorf("LogRequestID() | got %q outside of a request, want no request ID", lines[1])
+	}
+}
+
+func requestWithID(id string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(response.RequestIDHeader, id)
+	return req
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/lualfe/card-game/internal/controller/http/response"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+// Tenant keeps the tenant of the principal of a request in
+// its context, so its decks are held to its limits. It
+// goes after Auth. Requests of a tenant past its rate get
+// a 429, and those of a tenant that doesn't exist a 403.
+// Requests of the default tenant, or without a principal,
+// go through as they are.
+func Tenant(tenants usecase.TenantManager) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := usecase.PrincipalFrom(r.Context())
+			if !ok || p.Tenant == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tenant, err := tenants.Tenant(r.Context(), p.Tenant)
+			if err != nil {
+				if errors.Is(err, usecase.TenantNotFoundErr) {
+					response.JSONError(w, "unknown tenant "+p.Tenant, http.StatusForbidden)
+					return
+				}
+				response.JSONError(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if retry, ok := tenants.Allow(tenant); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(retry)))
+				response.JSONError(w, "tenant "+tenant.Name+" is over its requests per minute", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(usecase.WithTenant(r.Context(), tenant)))
+		})
+	}
+}
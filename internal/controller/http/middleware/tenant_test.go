@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+func TestTenant(t *testing.T) {
+	tenants := usecase.NewTenants(repo.NewTenant(), repo.NewDeck())
+	if _, err := tenants.Create(context.Background(), "acme", entity.TenantLimits{RequestsPerMinute: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		got    entity.Tenant
+		gotOK  bool
+		called bool
+	)
+	h := Tenant(tenants)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		got, gotOK = usecase.TenantFrom(r.Context())
+	}))
+
+	tests := []struct {
+		name       string
+		principal  *entity.Principal
+		statusCode int
+		want       string
+	}{
+		{name: "No Principal", statusCode: http.StatusOK},
+		{name: "Default Tenant", principal: &entity.Principal{ID: "ci"}, statusCode: http.StatusOK},
+		{name: "Tenant", principal: &entity.Principal{ID: "ci", Tenant: "acme"}, statusCode: http.StatusOK, want: "acme"},
+		{name: "Over Rate", principal: &entity.Principal{ID: "ci", Tenant: "acme"}, statusCode: http.StatusTooManyRequests},
+		{name: "Unknown Tenant", principal: &entity.Principal{ID: "ci", Tenant: "globex"}, statusCode: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOK, called = entity.Tenant{}, false, false
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.principal != nil {
+				r = r.WithContext(usecase.WithPrincipal(r.Context(), *tt.principal))
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.statusCode {
+				t.Fatalf("Tenant() | got status code %d, want %d", w.Code, tt.statusCode)
+			}
+			if called != (tt.statusCode == http.StatusOK) {
+				t.Errorf("Tenant() | got next called %t, want %t", called, tt.statusCode == http.StatusOK)
+			}
+			if got.Name != tt.want || gotOK != (tt.want != "") {
+				t.Errorf("Tenant() | got tenant %q in the context, want %q", got.Name, tt.want)
+			}
+			if tt.statusCode == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+				t.Error("Tenant() | no Retry-After header on a 429")
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName names the spans of the HTTP requests.
+const tracerName = "github.com/lualfe/card-game/internal/controller/http"
+
+// Tracing starts a server span for every request, with a
+// tracer of tp, continuing the trace of the caller found
+// by propagator in the request headers, such as a W3C
+// traceparent. Spans are named by route pattern once the
+// request is routed. It must wrap the whole router.
+func Tracing(tp trace.TracerProvider, propagator propagation.TextMapPropagator) func(http.Handler) http.Handler {
+	tracer := tp.Tracer(tracerName)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracer.Start(ctx, "HTTP "+r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPMethodKey.String(r.Method),
+					semconv.HTTPTargetKey.String(r.URL.RequestURI()),
+					semconv.HTTPSchemeKey.String(scheme(r)),
+					semconv.NetHostNameKey.String(r.Host),
+					semconv.HTTPUserAgentKey.String(r.UserAgent()),
+				),
+			)
+			defer span.End()
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			if route := routePattern(r); route != "" {
+				span.SetName(r.Method + " " + route)
+				span.SetAttributes(semconv.HTTPRouteKey.String(route))
+			}
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetAttributes(semconv.HTTPStatusCodeKey.Int(status))
+			// Client errors are the caller's, not the server's.
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+		})
+	}
+}
+
+func scheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
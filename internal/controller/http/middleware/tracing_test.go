@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var handlerSpan trace.SpanContext
+	m := chi.NewRouter()
+	m.Use(Tracing(tp, propagation.TraceContext{}))
+	m.Get("/v1/decks/{deckID}", func(w http.ResponseWriter, r *http.Request) {
+		if chi.URLParam(r, "deckID") == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/decks/a", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	m.ServeHTTP(httptest.NewRecorder(), req)
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/decks/broken", nil))
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nowhere", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("Tracing() | got %d spans, want 3", len(spans))
+	}
+
+	s := spans[0]
+	if s.Name() != "GET /v1/decks/{deckID}" || s.SpanKind() != trace.SpanKindServer {
+		t.Errorf("Tracing() | got %v span %q, want server span GET /v1/decks/{deckID}", s.SpanKind(), s.Name())
+	}
+	if got := s.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Tracing() | got trace ID %s, want the caller's", got)
+	}
+	if got := s.Parent().SpanID().String(); got != "00f067aa0ba902b7" || !s.Parent().IsRemote() {
+		t.Errorf("Tracing() | got parent %s, want the caller's remote span", got)
+	}
+	if handlerSpan.SpanID() != s.SpanContext().SpanID() {
+		t.Errorf("Tracing() | handler got span %s, want %s", handlerSpan.SpanID(), s.SpanContext().SpanID())
+	}
+	if s.Status().Code != codes.Unset {
+		t.Errorf("Tracing() | got status %v for a 200, want unset", s.Status().Code)
+	}
+
+	if s := spans[1]; s.Parent().IsValid() || s.Status().Code != codes.Error {
+		t.Errorf("Tracing() | got parent %v and status %v for a new trace failing, want none and error", s.Parent(), s.Status().Code)
+	}
+	if s := spans[2]; s.Name() != "HTTP GET" || s.Status().Code != codes.Unset {
+		t.Errorf("Tracing() | got span %q with status %v for an unmatched path, want HTTP GET unset", s.Name(), s.Status().Code)
+	}
+}
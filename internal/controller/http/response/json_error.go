@@ -5,15 +5,22 @@ import (
 	"net/http"
 )
 
+// RequestIDHeader carries the ID of the request a response
+// answers.
+const RequestIDHeader = "X-Request-ID"
+
 // Error is an object that will be sent in http errors.
 type Error struct {
-	Message string `json:"message"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // JSONError will write a given error message in a json object to a response writer along with the status code.
+// The request ID set in the response headers is echoed, so errors can be matched with the logs.
 func JSONError(w http.ResponseWriter, msg string, statusCode int) {
 	resp := Error{
-		Message: msg,
+		Message:   msg,
+		RequestID: w.Header().Get(RequestIDHeader),
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -14,6 +14,7 @@ func TestJSONError(t *testing.T) {
 		name       string
 		message    string
 		statusCode int
+		requestID  string
 		wantBody   Error
 	}{
 		{
@@ -32,10 +33,23 @@ func TestJSONError(t *testing.T) {
 				Message: "server error",
 			},
 		},
+		{
+			name:       "Request ID",
+			message:    "not found",
+			statusCode: http.StatusNotFound,
+			requestID:  "req-1",
+			wantBody: Error{
+				Message:   "not found",
+				RequestID: "req-1",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
+			if tt.requestID != "" {
+				w.Header().Set(RequestIDHeader, tt.requestID)
+			}
 			JSONError(w, tt.message, tt.statusCode)
 
 			resp := w.Result()
@@ -0,0 +1,304 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/lualfe/card-game/internal/controller/http/middleware"
+	"github.com/lualfe/card-game/internal/controller/http/response"
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+// createAdminRoutes mounts the admin routes, which need an
+// admin key when authentication is on. Snapshot routes are
+// left out when snapshots aren't set up, key routes when
+// authentication is off, and tenant routes without
+// tenants.
+func createAdminRoutes(m chi.Router, snapshots usecase.DeckSnapshotManager, keys usecase.APIKeyManager, tenants usecase.TenantManager) {
+	if snapshots == nil && keys == nil && tenants == nil {
+		return
+	}
+
+	ar := &adminRoutes{snapshots, keys, tenants}
+
+	m.Route("/v1/admin", func(r chi.Router) {
+		r.Use(middleware.Admin)
+		if snapshots != nil {
+			r.Post("/snapshots", ar.takeSnapshot)
+		}
+		if keys != nil {
+			r.Get("/keys", ar.listKeys)
+			r.Post("/keys", ar.createKey)
+			r.Delete("/keys/{name}", ar.revokeKey)
+		}
+		if tenants != nil {
+			r.Get("/tenants", ar.listTenants)
+			r.Post("/tenants", ar.createTenant)
+			r.Get("/tenants/{name}/usage", ar.tenantUsage)
+		}
+	})
+}
+
+type adminRoutes struct {
+	snapshots usecase.DeckSnapshotManager
+	keys      usecase.APIKeyManager
+	tenants   usecase.TenantManager
+}
+
+type snapshotResp struct {
+	Taken  time.Time `json:"taken"`
+	Decks  int       `json:"decks"`
+	Events int       `json:"events"`
+}
+
+// takeSnapshot godoc
+// @Summary      Snapshots the deck store.
+// @Description  Writes every deck and deck event to the snapshot file, replacing the previous snapshot.
+// @Produce      json
+// @Success      201  {object}  snapshotResp
+// @Failure      401  {object}  response.Error
+// @Failure      403  {object}  response.Error
+// @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /admin/snapshots [post]
+func (a *adminRoutes) takeSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := a.snapshots.Take(r.Context())
+	if err != nil {
+		response.JSONError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := snapshotResp{
+		Taken:  snapshot.Taken,
+		Decks:  len(snapshot.Decks),
+		Events: len(snapshot.Events),
+	}
+
+	response.JSON(w, resp, http.StatusCreated)
+}
+
+type apiKeyResp struct {
+	Name    string    `json:"name"`
+	Admin   bool      `json:"admin"`
+	Tenant  string    `json:"tenant,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+type apiKeysResp struct {
+	Keys []apiKeyResp `json:"keys"`
+}
+
+type newAPIKeyResp struct {
+	apiKeyResp
+	Key string `json:"key"`
+}
+
+// listKeys godoc
+// @Summary      Lists the API keys.
+// @Description  Lists the API keys, from the configuration or created through the API, without their secrets.
+// @Produce      json
+// @Success      200  {object}  apiKeysResp
+// @Failure      401  {object}  response.Error
+// @Failure      403  {object}  response.Error
+// @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /admin/keys [get]
+func (a *adminRoutes) listKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := a.keys.Keys(r.Context())
+	if err != nil {
+		response.JSONError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := apiKeysResp{Keys: []apiKeyResp{}}
+	for _, k := range keys {
+		resp.Keys = append(resp.Keys, apiKeyResp{Name: k.Name, Admin: k.Admin, Tenant: k.Tenant, Created: k.Created})
+	}
+
+	response.JSON(w, resp, http.StatusOK)
+}
+
+// createKey godoc
+// @Summary      Creates an API key.
+// @Description  Creates an API key. Its secret is only ever shown in this response.
+// @Produce      json
+// @Param        name    query     string  true   "Key name, owning the decks created with it"  example(ci)
+// @Param        admin   query     bool    false  "Whether the key reaches every deck of its tenant, and the admin routes without a tenant"  default(false)
+// @Param        tenant  query     string  false  "Tenant the key works in, the default one if not sent"  example(acme)
+// @Success      201     {object}  newAPIKeyResp
+// @Failure      400     {object}  response.Error
+// @Failure      401     {object}  response.Error
+// @Failure      403     {object}  response.Error
+// @Failure      409     {object}  response.Error
+// @Failure      500     {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /admin/keys [post]
+func (a *adminRoutes) createKey(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	key, secret, err := a.keys.Create(r.Context(), q.Get("name"), q.Get("admin") == "true", q.Get("tenant"))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.APIKeyInvalidNameErr), errors.Is(err, usecase.TenantNotFoundErr):
+			response.JSONError(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, usecase.APIKeyExistsErr):
+			response.JSONError(w, err.Error(), http.StatusConflict)
+		default:
+			response.JSONError(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	resp := newAPIKeyResp{
+		apiKeyResp: apiKeyResp{Name: key.Name, Admin: key.Admin, Tenant: key.Tenant, Created: key.Created},
+		Key:        secret,
+	}
+
+	response.JSON(w, resp, http.StatusCreated)
+}
+
+// revokeKey godoc
+// @Summary      Revokes an API key.
+// @Description  Revokes an API key created through the API. Keys of the configuration are removed from it instead.
+// @Produce      json
+// @Param        name  path  string  true  "Key name"
+// @Success      204
+// @Failure      401  {object}  response.Error
+// @Failure      403  {object}  response.Error
+// @Failure      404  {object}  response.Error
+// @Failure      409  {object}  response.Error
+// @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /admin/keys/{name} [delete]
+func (a *adminRoutes) revokeKey(w http.ResponseWriter, r *http.Request) {
+	if err := a.keys.Revoke(r.Context(), chi.URLParam(r, "name")); err != nil {
+		switch {
+		case errors.Is(err, usecase.APIKeyNotFoundErr):
+			response.JSONError(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, usecase.APIKeyStaticErr):
+			response.JSONError(w, err.Error(), http.StatusConflict)
+		default:
+			response.JSONError(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response.JSON(w, nil, http.StatusNoContent)
+}
+
+type tenantsResp struct {
+	Tenants []entity.Tenant `json:"tenants"`
+}
+
+// listTenants godoc
+// @Summary      Lists the tenants.
+// @Description  Lists the tenants and their limits, by name.
+// @Produce      json
+// @Success      200  {object}  tenantsResp
+// @Failure      401  {object}  response.Error
+// @Failure      403  {object}  response.Error
+// @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /admin/tenants [get]
+func (a *adminRoutes) listTenants(w http.ResponseWriter, r *http.Request) {
+	tenants, err := a.tenants.Tenants(r.Context())
+	if err != nil {
+		response.JSONError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := tenantsResp{Tenants: []entity.Tenant{}}
+	resp.Tenants = append(resp.Tenants, tenants...)
+
+	response.JSON(w, resp, http.StatusOK)
+}
+
+// createTenant godoc
+// @Summary      Creates a tenant.
+// @Description  Creates a tenant, whose decks are kept apart from the others'. Limits left out or 0 don't bound the tenant.
+// @Produce      json
+// @Param        name                 query     string  true   "Tenant name: lowercase letters, digits and dashes"  example(acme)
+// @Param        max_decks            query     int     false  "Decks the tenant can keep"
+// @Param        max_cards            query     int     false  "Cards a deck of the tenant can be created with"
+// @Param        requests_per_minute  query     int     false  "Requests the tenant can make each minute, to each instance"
+// @Success      201                  {object}  entity.Tenant
+// @Failure      400                  {object}  response.Error
+// @Failure      401                  {object}  response.Error
+// @Failure      403                  {object}  response.Error
+// @Failure      409                  {object}  response.Error
+// @Failure      500                  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /admin/tenants [post]
+func (a *adminRoutes) createTenant(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var limits entity.TenantLimits
+	for param, limit := range map[string]*int{
+		"max_decks":           &limits.MaxDecks,
+		"max_cards":           &limits.MaxCards,
+		"requests_per_minute": &limits.RequestsPerMinute,
+	} {
+		v := q.Get(param)
+		if v == "" {
+			continue
+		}
+		var err error
+		if *limit, err = strconv.Atoi(v); err != nil {
+			response.JSONError(w, param+" must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	tenant, err := a.tenants.Create(r.Context(), q.Get("name"), limits)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.TenantInvalidErr):
+			response.JSONError(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, usecase.TenantExistsErr):
+			response.JSONError(w, err.Error(), http.StatusConflict)
+		default:
+			response.JSONError(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response.JSON(w, tenant, http.StatusCreated)
+}
+
+// tenantUsage godoc
+// @Summary      Shows what a tenant uses.
+// @Description  Shows the decks a tenant keeps and the requests it made this minute, to the instance answering, next to its limits.
+// @Produce      json
+// @Param        name  path      string  true  "Tenant name"
+// @Success      200   {object}  entity.TenantUsage
+// @Failure      401   {object}  response.Error
+// @Failure      403   {object}  response.Error
+// @Failure      404   {object}  response.Error
+// @Failure      500   {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /admin/tenants/{name}/usage [get]
+func (a *adminRoutes) tenantUsage(w http.ResponseWriter, r *http.Request) {
+	usage, err := a.tenants.Usage(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		if errors.Is(err, usecase.TenantNotFoundErr) {
+			response.JSONError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		response.JSONError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response.JSON(w, usage, http.StatusOK)
+}
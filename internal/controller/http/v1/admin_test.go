@@ -0,0 +1,220 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/controller/http/middleware"
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+type stubDeckSnapshotManager struct {
+	take func() (entity.DeckSnapshot, error)
+}
+
+func (s *stubDeckSnapshotManager) Take(context.Context) (entity.DeckSnapshot, error) {
+	return s.take()
+}
+
+func Test_adminRoutes_takeSnapshot(t *testing.T) {
+	taken := time.Date(2024, time.March, 9, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+		want       *snapshotResp
+	}{
+		{
+			name:       "Success",
+			statusCode: http.StatusCreated,
+			want:       &snapshotResp{Taken: taken, Decks: 1, Events: 2},
+		},
+		{
+			name:       "Write Error",
+			err:        errors.New("disk full"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/v1/admin/snapshots", nil)
+
+			a := &adminRoutes{
+				snapshots: &stubDeckSnapshotManager{
+					take: func() (entity.DeckSnapshot, error) {
+						if tt.err != nil {
+							return entity.DeckSnapshot{}, tt.err
+						}
+						return entity.DeckSnapshot{
+							Taken:  taken,
+							Decks:  make([]entity.Deck, 1),
+							Events: make([]entity.DeckEvent, 2),
+						}, nil
+					},
+				},
+			}
+			a.takeSnapshot(w, r)
+
+			if w.Code != tt.statusCode {
+				t.Fatalf("adminRoutes.takeSnapshot() | got status %d, want %d", w.Code, tt.statusCode)
+			}
+			if tt.want == nil {
+				return
+			}
+
+			var got snapshotResp
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(&got, tt.want); diff != "" {
+				t.Errorf("adminRoutes.takeSnapshot() | (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_adminRoutes_keys(t *testing.T) {
+	keys := usecase.NewAPIKeys(repo.NewAPIKey(), nil, []entity.APIKey{
+		{Name: "ops", Hash: usecase.HashAPIKey("ops-secret"), Admin: true},
+		{Name: "ci", Hash: usecase.HashAPIKey("ci-secret")},
+	})
+	m := chi.NewRouter()
+	m.Group(func(r chi.Router) {
+		r.Use(middleware.Auth(keys, nil))
+		createAdminRoutes(r, nil, keys, nil)
+	})
+
+	do := func(method, target, key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, nil)
+		r.Header.Set(middleware.APIKeyHeader, key)
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := do(http.MethodPost, "/v1/admin/keys?name=dev", "ci-secret"); w.Code != http.StatusForbidden {
+		t.Errorf("POST /v1/admin/keys | got status %d with a key that isn't admin, want %d", w.Code, http.StatusForbidden)
+	}
+
+	w := do(http.MethodPost, "/v1/admin/keys?name=dev", "ops-secret")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /v1/admin/keys | got status %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	var created newAPIKeyResp
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Name != "dev" || created.Admin || created.Key == "" {
+		t.Errorf("POST /v1/admin/keys | got %+v, want a dev key that isn't admin", created)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		statusCode int
+	}{
+		{name: "Taken", method: http.MethodPost, target: "/v1/admin/keys?name=dev", statusCode: http.StatusConflict},
+		{name: "Bad Name", method: http.MethodPost, target: "/v1/admin/keys?name=a%20b", statusCode: http.StatusBadRequest},
+		{name: "List", method: http.MethodGet, target: "/v1/admin/keys", statusCode: http.StatusOK},
+		{name: "Revoke Static", method: http.MethodDelete, target: "/v1/admin/keys/ci", statusCode: http.StatusConflict},
+		{name: "Revoke", method: http.MethodDelete, target: "/v1/admin/keys/dev", statusCode: http.StatusNoContent},
+		{name: "Revoke Missing", method: http.MethodDelete, target: "/v1/admin/keys/dev", statusCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := do(tt.method, tt.target, "ops-secret"); w.Code != tt.statusCode {
+				t.Errorf("%s %s | got status %d, want %d", tt.method, tt.target, w.Code, tt.statusCode)
+			}
+		})
+	}
+
+	if w := do(http.MethodGet, "/v1/admin/keys", created.Key); w.Code != http.StatusUnauthorized {
+		t.Errorf("GET /v1/admin/keys | got status %d with a revoked key, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func Test_adminRoutes_tenants(t *testing.T) {
+	tenantRepo := repo.NewTenant()
+	tenants := usecase.NewTenants(tenantRepo, repo.NewDeck())
+	keys := usecase.NewAPIKeys(repo.NewAPIKey(), tenantRepo, []entity.APIKey{
+		{Name: "ops", Hash: usecase.HashAPIKey("ops-secret"), Admin: true},
+	})
+	m := chi.NewRouter()
+	m.Group(func(r chi.Router) {
+		r.Use(middleware.Auth(keys, nil))
+		r.Use(middleware.Tenant(tenants))
+		createAdminRoutes(r, nil, keys, tenants)
+	})
+
+	do := func(method, target, key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, nil)
+		r.Header.Set(middleware.APIKeyHeader, key)
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, r)
+		return w
+	}
+
+	w := do(http.MethodPost, "/v1/admin/tenants?name=acme&max_decks=5&max_cards=104", "ops-secret")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /v1/admin/tenants | got status %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	var created entity.Tenant
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(created.Limits, entity.TenantLimits{MaxDecks: 5, MaxCards: 104}); created.Name != "acme" || diff != "" {
+		t.Errorf("POST /v1/admin/tenants | got tenant %s, limits (-got +want):\n%s", created.Name, diff)
+	}
+
+	// An admin key of the tenant is no admin of the
+	// application.
+	w = do(http.MethodPost, "/v1/admin/keys?name=acme-ops&admin=true&tenant=acme", "ops-secret")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /v1/admin/keys | got status %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	var key newAPIKeyResp
+	if err := json.NewDecoder(w.Body).Decode(&key); err != nil {
+		t.Fatal(err)
+	}
+	if key.Tenant != "acme" {
+		t.Errorf("POST /v1/admin/keys | got tenant %q, want acme", key.Tenant)
+	}
+	if w := do(http.MethodGet, "/v1/admin/tenants", key.Key); w.Code != http.StatusForbidden {
+		t.Errorf("GET /v1/admin/tenants | got status %d with a tenant admin key, want %d", w.Code, http.StatusForbidden)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		statusCode int
+	}{
+		{name: "Taken", method: http.MethodPost, target: "/v1/admin/tenants?name=acme", statusCode: http.StatusConflict},
+		{name: "Bad Name", method: http.MethodPost, target: "/v1/admin/tenants?name=Acme", statusCode: http.StatusBadRequest},
+		{name: "Bad Limit", method: http.MethodPost, target: "/v1/admin/tenants?name=globex&max_decks=many", statusCode: http.StatusBadRequest},
+		{name: "List", method: http.MethodGet, target: "/v1/admin/tenants", statusCode: http.StatusOK},
+		{name: "Usage", method: http.MethodGet, target: "/v1/admin/tenants/acme/usage", statusCode: http.StatusOK},
+		{name: "Usage Missing", method: http.MethodGet, target: "/v1/admin/tenants/globex/usage", statusCode: http.StatusNotFound},
+		{name: "Key Of Unknown Tenant", method: http.MethodPost, target: "/v1/admin/keys?name=ci&tenant=globex", statusCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := do(tt.method, tt.target, "ops-secret"); w.Code != tt.statusCode {
+				t.Errorf("%s %s | got status %d, want %d", tt.method, tt.target, w.Code, tt.statusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/lualfe/card-game/internal/controller/http/middleware"
+	"github.com/lualfe/card-game/internal/controller/http/response"
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+func createBlackjackRoutes(m chi.Router, blackjack usecase.BlackjackManager, limits usecase.RateLimiter) {
+	br := &blackjackRoutes{blackjack}
+	play := drawing(limits)
+
+	m.Route("/v1/games/blackjack", func(r chi.Router) {
+		r.With(creating(limits)...).Post("/", br.newTable)
+		r.With(middleware.Scope(entity.ScopeDecksRead)).Get("/{tableID}", br.table)
+		r.With(play...).Post("/{tableID}/deals", br.deal)
+		r.With(play...).Post("/{tableID}/hits", br.action(blackjack.Hit))
+		r.With(play...).Post("/{tableID}/stands", br.action(blackjack.Stand))
+		r.With(play...).Post("/{tableID}/doubles", br.action(blackjack.Double))
+		r.With(play...).Post("/{tableID}/splits", br.action(blackjack.Split))
+		r.With(play...).Post("/{tableID}/surrenders", br.action(blackjack.Surrender))
+	})
+}
+
+type blackjackRoutes struct {
+	blackjack usecase.BlackjackManager
+}
+
+type blackjackHandResp struct {
+	Cards   []entity.Card `json:"cards"`
+	Score   int           `json:"score"`
+	Bet     int           `json:"bet"`
+	Doubled bool          `json:"doubled"`
+	Result  string        `json:"result,omitempty"`
+	Payout  int           `json:"payout"`
+}
+
+type blackjackTableResp struct {
+	ID               string              `json:"table_id"`
+	Decks            int                 `json:"decks"`
+	ShoeRemaining    int                 `json:"shoe_remaining"`
+	DealerHitsSoft17 bool                `json:"dealer_hits_soft_17"`
+	Bankroll         int                 `json:"bankroll"`
+	Status           string              `json:"status"`
+	Dealer           []entity.Card       `json:"dealer"`
+	DealerScore      int                 `json:"dealer_score"`
+	Hands            []blackjackHandResp `json:"hands"`
+	ActiveHand       int                 `json:"active_hand"`
+}
+
+// newBlackjackTableResp builds the table view, hiding
+// the dealer hole card while the round is being played.
+func newBlackjackTableResp(table entity.BlackjackTable) blackjackTableResp {
+	dealer := table.Dealer
+	if table.Status == entity.BlackjackStatusPlaying && len(dealer) > 1 {
+		dealer = dealer[:1]
+	}
+
+	resp := blackjackTableResp{
+		ID:               table.ID,
+		Decks:            table.Decks,
+		ShoeRemaining:    table.ShoeRemaining,
+		DealerHitsSoft17: table.DealerHitsSoft17,
+		Bankroll:         table.Bankroll,
+		Status:           table.Status,
+		Dealer:           dealer,
+		DealerScore:      usecase.BlackjackScore(dealer),
+		Hands:            []blackjackHandResp{},
+		ActiveHand:       table.ActiveHand,
+	}
+	for _, h := range table.Hands {
+		resp.Hands = append(resp.Hands, blackjackHandResp{
+			Cards:   h.Cards,
+			Score:   usecase.BlackjackScore(h.Cards),
+			Bet:     h.Bet,
+			Doubled: h.Doubled,
+			Result:  h.Result,
+			Payout:  h.Payout,
+		})
+	}
+
+	return resp
+}
+
+// newTable godoc
+// @Summary      Creates a blackjack table.
+// @Description  Creates a blackjack table bound to a shuffled multi-deck shoe.
+// @Produce      json
+// @Param        decks        query     int     false  "Number of decks in the shoe."                      default(6)
+// @Param        penetration  query     number  false  "Fraction of the shoe dealt before the cut card."  default(0.75)
+// @Param        h17          query     bool    false  "Dealer hits soft 17."                              default(false)
+// @Param        bankroll     query     int     false  "Player chips."                                     default(1000)
+// @Success      201          {object}  blackjackTableResp
+// @Failure      400          {object}  response.Error
+// @Failure      403          {object}  response.Error
+// @Failure      429          {object}  response.Error
+// @Failure      500          {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/blackjack [post]
+func (b *blackjackRoutes) newTable(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := usecase.BlackjackOptions{
+		DealerHitsSoft17: q.Get("h17") == "true",
+	}
+
+	var err error
+	if v := q.Get("decks"); v != "" {
+		if opts.Decks, err = strconv.Atoi(v); err != nil {
+			response.JSONError(w, "decks must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := q.Get("penetration"); v != "" {
+		if opts.Penetration, err = strconv.ParseFloat(v, 64); err != nil {
+			response.JSONError(w, "penetration must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := q.Get("bankroll"); v != "" {
+		if opts.Bankroll, err = strconv.Atoi(v); err != nil {
+			response.JSONError(w, "bankroll must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	table, err := b.blackjack.NewTable(r.Context(), opts)
+	if err != nil {
+		blackjackError(w, err)
+		return
+	}
+
+	response.JSON(w, newBlackjackTableResp(table), http.StatusCreated)
+}
+
+// table godoc
+// @Summary      Shows a blackjack table.
+// @Description  Shows a blackjack table. The dealer hole card is hidden during a round.
+// @Produce      json
+// @Param        id   path      string  true  "Table id"
+// @Success      200  {object}  blackjackTableResp
+// @Failure      403  {object}  response.Error
+// @Failure      404  {object}  response.Error
+// @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/blackjack/{id} [get]
+func (b *blackjackRoutes) table(w http.ResponseWriter, r *http.Request) {
+	table, err := b.blackjack.Table(r.Context(), chi.URLParam(r, "tableID"))
+	if err != nil {
+		blackjackError(w, err)
+		return
+	}
+
+	response.JSON(w, newBlackjackTableResp(table), http.StatusOK)
+}
+
+// deal godoc
+// @Summary      Deals a blackjack round.
+// @Description  Takes the bet and deals a new round, reshuffling when the cut card was reached.
+// @Produce      json
+// @Param        id   path      string  true  "Table id"
+// @Param        bet  query     int     true  "Bet amount"
+// @Success      200  {object}  blackjackTableResp
+// @Failure      400  {object}  response.Error
+// @Failure      403  {object}  response.Error
+// @Failure      404  {object}  response.Error
+// @Failure      409  {object}  response.Error
+// @Failure      429  {object}  response.Error
+// @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/blackjack/{id}/deals [post]
+func (b *blackjackRoutes) deal(w http.ResponseWriter, r *http.Request) {
+	bet, err := strconv.Atoi(r.URL.Query().Get("bet"))
+	if err != nil {
+		response.JSONError(w, "bet must be a number", http.StatusBadRequest)
+		return
+	}
+
+	table, err := b.blackjack.Deal(r.Context(), chi.URLParam(r, "tableID"), bet)
+	if err != nil {
+		blackjackError(w, err)
+		return
+	}
+
+	response.JSON(w, newBlackjackTableResp(table), http.StatusOK)
+}
+
+// action godoc
+// @Summary      Plays a blackjack decision.
+// @Description  Plays hit, stand, double, split or surrender on the active hand.
+// @Produce      json
+// @Param        id      path      string  true  "Table id"
+// @Param        action  path      string  true  "Decision"  Enums(hits, stands, doubles, splits, surrenders)
+// @Success      200     {object}  blackjackTableResp
+// @Failure      403     {object}  response.Error
+// @Failure      404     {object}  response.Error
+// @Failure      409     {object}  response.Error
+// @Failure      429     {object}  response.Error
+// @Failure      500     {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/blackjack/{id}/{action} [post]
+func (b *blackjackRoutes) action(play func(ctx context.Context, id string) (entity.BlackjackTable, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		table, err := play(r.Context(), chi.URLParam(r, "tableID"))
+		if err != nil {
+			blackjackError(w, err)
+			return
+		}
+
+		response.JSON(w, newBlackjackTableResp(table), http.StatusOK)
+	}
+}
+
+func blackjackError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.BlackjackTableNotFoundErr):
+		response.JSONError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, usecase.GameForbiddenErr):
+		response.JSONError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, usecase.BlackjackInvalidOptionsErr):
+		response.JSONError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, usecase.BlackjackIllegalActionErr):
+		response.JSONError(w, err.Error(), http.StatusConflict)
+	default:
+		response.JSONError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
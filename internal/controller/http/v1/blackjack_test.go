@@ -0,0 +1,270 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+type stubBlackjackManager struct {
+	newTable func(opts usecase.BlackjackOptions) (entity.BlackjackTable, error)
+	table    func(id string) (entity.BlackjackTable, error)
+	deal     func(id string, bet int) (entity.BlackjackTable, error)
+	play     func(id string) (entity.BlackjackTable, error)
+}
+
+func (s *stubBlackjackManager) NewTable(_ context.Context, opts usecase.BlackjackOptions) (entity.BlackjackTable, error) {
+	return s.newTable(opts)
+}
+
+func (s *stubBlackjackManager) Table(_ context.Context, id string) (entity.BlackjackTable, error) {
+	return s.table(id)
+}
+
+func (s *stubBlackjackManager) Deal(_ context.Context, id string, bet int) (entity.BlackjackTable, error) {
+	return s.deal(id, bet)
+}
+
+func (s *stubBlackjackManager) Hit(_ context.Context, id string) (entity.BlackjackTable, error) {
+	return s.play(id)
+}
+
+func (s *stubBlackjackManager) Stand(_ context.Context, id string) (entity.BlackjackTable, error) {
+	return s.play(id)
+}
+
+func (s *stubBlackjackManager) Double(_ context.Context, id string) (entity.BlackjackTable, error) {
+	return s.play(id)
+}
+
+func (s *stubBlackjackManager) Split(_ context.Context, id string) (entity.BlackjackTable, error) {
+	return s.play(id)
+}
+
+func (s *stubBlackjackManager) Surrender(_ context.Context, id string) (entity.BlackjackTable, error) {
+	return s.play(id)
+}
+
+var (
+	blackjackKing = entity.Card{Value: "KING", Suit: "SPADES", Code: "KS"}
+	blackjackSix  = entity.Card{Value: "6", Suit: "SPADES", Code: "6S"}
+)
+
+func Test_blackjackRoutes_newTable(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		err        error
+		statusCode int
+		wantOpts   usecase.BlackjackOptions
+	}{
+		{
+			name:       "Success",
+			target:     "/v1/games/blackjack?decks=2&penetration=0.5&h17=true&bankroll=200",
+			statusCode: http.StatusCreated,
+			wantOpts:   usecase.BlackjackOptions{Decks: 2, Penetration: 0.5, DealerHitsSoft17: true, Bankroll: 200},
+		},
+		{
+			name:       "Bad Decks",
+			target:     "/v1/games/blackjack?decks=six",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Invalid Options",
+			target:     "/v1/games/blackjack?decks=20",
+			err:        usecase.BlackjackInvalidOptionsErr,
+			statusCode: http.StatusBadRequest,
+			wantOpts:   usecase.BlackjackOptions{Decks: 20},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, tt.target, nil)
+
+			b := &blackjackRoutes{
+				blackjack: &stubBlackjackManager{
+					newTable: func(opts usecase.BlackjackOptions) (entity.BlackjackTable, error) {
+						if diff := cmp.Diff(opts, tt.wantOpts); diff != "" {
+							t.Errorf("blackjackRoutes.newTable() | options (-got +want):\n%s", diff)
+						}
+						return entity.BlackjackTable{ID: "id"}, tt.err
+					},
+				},
+			}
+			b.newTable(w, r)
+
+			if code := w.Result().StatusCode; code != tt.statusCode {
+				t.Fatalf("blackjackRoutes.newTable() | got status code %d, want %d", code, tt.statusCode)
+			}
+		})
+	}
+}
+
+func Test_blackjackRoutes_table(t *testing.T) {
+	tests := []struct {
+		name       string
+		table      entity.BlackjackTable
+		err        error
+		statusCode int
+		wantDealer []entity.Card
+	}{
+		{
+			name: "Hides Hole Card While Playing",
+			table: entity.BlackjackTable{
+				Status: entity.BlackjackStatusPlaying,
+				Dealer: []entity.Card{blackjackKing, blackjackSix},
+			},
+			statusCode: http.StatusOK,
+			wantDealer: []entity.Card{blackjackKing},
+		},
+		{
+			name: "Shows Dealer After Round",
+			table: entity.BlackjackTable{
+				Status: entity.BlackjackStatusBetting,
+				Dealer: []entity.Card{blackjackKing, blackjackSix},
+			},
+			statusCode: http.StatusOK,
+			wantDealer: []entity.Card{blackjackKing, blackjackSix},
+		},
+		{
+			name:       "Not Found Error",
+			err:        usecase.BlackjackTableNotFoundErr,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Another Key Error",
+			err:        usecase.GameForbiddenErr,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Unknown Error",
+			err:        errors.New("error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/v1/games/blackjack/id", nil)
+
+			b := &blackjackRoutes{
+				blackjack: &stubBlackjackManager{
+					table: func(id string) (entity.BlackjackTable, error) {
+						return tt.table, tt.err
+					},
+				},
+			}
+			b.table(w, r)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if code := resp.StatusCode; code != tt.statusCode {
+				t.Fatalf("blackjackRoutes.table() | got status code %d, want %d", code, tt.statusCode)
+			}
+
+			if tt.err == nil {
+				var got blackjackTableResp
+				if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+					t.Fatal(err)
+				}
+
+				if diff := cmp.Diff(got.Dealer, tt.wantDealer); diff != "" {
+					t.Fatalf("blackjackRoutes.table() | dealer (-got +want):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_blackjackRoutes_deal(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		err        error
+		statusCode int
+	}{
+		{
+			name:       "Success",
+			target:     "/v1/games/blackjack/id/deals?bet=10",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Missing Bet",
+			target:     "/v1/games/blackjack/id/deals",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Illegal Action",
+			target:     "/v1/games/blackjack/id/deals?bet=10",
+			err:        usecase.BlackjackIllegalActionErr,
+			statusCode: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, tt.target, nil)
+
+			b := &blackjackRoutes{
+				blackjack: &stubBlackjackManager{
+					deal: func(id string, bet int) (entity.BlackjackTable, error) {
+						return entity.BlackjackTable{}, tt.err
+					},
+				},
+			}
+			b.deal(w, r)
+
+			if code := w.Result().StatusCode; code != tt.statusCode {
+				t.Fatalf("blackjackRoutes.deal() | got status code %d, want %d", code, tt.statusCode)
+			}
+		})
+	}
+}
+
+func Test_blackjackRoutes_action(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{
+			name:       "Success",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Illegal Action",
+			err:        usecase.BlackjackIllegalActionErr,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "Not Found Error",
+			err:        usecase.BlackjackTableNotFoundErr,
+			statusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/v1/games/blackjack/id/hits", nil)
+
+			b := &blackjackRoutes{}
+			b.action(func(_ context.Context, id string) (entity.BlackjackTable, error) {
+				return entity.BlackjackTable{}, tt.err
+			})(w, r)
+
+			if code := w.Result().StatusCode; code != tt.statusCode {
+				t.Fatalf("blackjackRoutes.action() | got status code %d, want %d", code, tt.statusCode)
+			}
+		})
+	}
+}
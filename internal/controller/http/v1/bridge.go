@@ -0,0 +1,239 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/lualfe/card-game/internal/controller/http/middleware"
+	"github.com/lualfe/card-game/internal/controller/http/response"
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+// maxPBNSize bounds the size of an imported PBN file.
+const maxPBNSize = 1 << 20
+
+func createBridgeRoutes(m chi.Router, bridge usecase.BridgeManager, limits usecase.RateLimiter) {
+	br := &bridgeRoutes{bridge}
+	read := middleware.Scope(entity.ScopeDecksRead)
+
+	m.Route("/v1/games/bridge", func(r chi.Router) {
+		r.With(creating(limits)...).Post("/", br.newDeal)
+		r.With(creating(limits)...).Post("/pbn", br.importPBN)
+		r.With(read).Get("/{dealID}", br.deal)
+		r.With(read).Get("/{dealID}/pbn", br.exportPBN)
+	})
+}
+
+type bridgeRoutes struct {
+	bridge usecase.BridgeManager
+}
+
+type bridgeHandResp struct {
+	Cards []entity.Card `json:"cards"`
+	entity.BridgeHandStats
+}
+
+type bridgeDealResp struct {
+	ID         string                    `json:"deal_id"`
+	DeckID     string                    `json:"deck_id"`
+	Board      int                       `json:"board"`
+	Dealer     string                    `json:"dealer"`
+	Vulnerable string                    `json:"vulnerable"`
+	Hands      map[string]bridgeHandResp `json:"hands"`
+}
+
+func newBridgeDealResp(deal entity.BridgeDeal) bridgeDealResp {
+	resp := bridgeDealResp{
+		ID:         deal.ID,
+		DeckID:     deal.DeckID,
+		Board:      deal.Board,
+		Dealer:     deal.Dealer,
+		Vulnerable: deal.Vulnerable,
+		Hands:      make(map[string]bridgeHandResp, len(deal.Hands)),
+	}
+	for seat, cards := range deal.Hands {
+		resp.Hands[seat] = bridgeHandResp{
+			Cards:           cards,
+			BridgeHandStats: usecase.BridgeStats(cards),
+		}
+	}
+
+	return resp
+}
+
+// newDeal godoc
+// @Summary      Deals a bridge board.
+// @Description  Deals 13 cards to each seat, with the dealer and vulnerability of the board. Constraints are met by dealing again until they hold, giving up with a 422 after 10000 deals.
+// @Produce      json
+// @Param        board       query     int     false  "Board number"  default(1)
+// @Param        constraint  query     string  false  "Seat constraint as SEAT:MIN-MAX or SEAT:MIN-MAX:balanced, e.g. N:15-17:balanced. Can be repeated."
+// @Success      201         {object}  bridgeDealResp
+// @Failure      400         {object}  response.Error
+// @Failure      403         {object}  response.Error
+// @Failure      422         {object}  response.Error
+// @Failure      429         {object}  response.Error
+// @Failure      500         {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/bridge [post]
+func (b *bridgeRoutes) newDeal(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	board := 1
+	if v := q.Get("board"); v != "" {
+		var err error
+		if board, err = strconv.Atoi(v); err != nil {
+			response.JSONError(w, "board must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var constraints []usecase.BridgeConstraint
+	for _, v := range q["constraint"] {
+		c, err := parseBridgeConstraint(v)
+		if err != nil {
+			response.JSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		constraints = append(constraints, c)
+	}
+
+	deal, err := b.bridge.NewDeal(r.Context(), board, constraints)
+	if err != nil {
+		bridgeError(w, err)
+		return
+	}
+
+	response.JSON(w, newBridgeDealResp(deal), http.StatusCreated)
+}
+
+// deal godoc
+// @Summary      Shows a bridge board.
+// @Description  Shows the hands of a bridge board with their HCP and distribution.
+// @Produce      json
+// @Param        id   path      string  true  "Deal id"
+// @Success      200  {object}  bridgeDealResp
+// @Failure      403  {object}  response.Error
+// @Failure      404  {object}  response.Error
+// @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/bridge/{id} [get]
+func (b *bridgeRoutes) deal(w http.ResponseWriter, r *http.Request) {
+	deal, err := b.bridge.Deal(r.Context(), chi.URLParam(r, "dealID"))
+	if err != nil {
+		bridgeError(w, err)
+		return
+	}
+
+	response.JSON(w, newBridgeDealResp(deal), http.StatusOK)
+}
+
+// exportPBN godoc
+// @Summary      Exports a bridge board.
+// @Description  Exports a bridge board in Portable Bridge Notation.
+// @Produce      plain
+// @Param        id   path      string  true  "Deal id"
+// @Success      200  {string}  string
+// @Failure      403  {object}  response.Error
+// @Failure      404  {object}  response.Error
+// @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/bridge/{id}/pbn [get]
+func (b *bridgeRoutes) exportPBN(w http.ResponseWriter, r *http.Request) {
+	pbn, err := b.bridge.ExportPBN(r.Context(), chi.URLParam(r, "dealID"))
+	if err != nil {
+		bridgeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, pbn)
+}
+
+// importPBN godoc
+// @Summary      Imports bridge boards.
+// @Description  Imports the deals of a Portable Bridge Notation file sent as the request body.
+// @Accept       plain
+// @Produce      json
+// @Param        pbn  body      string  true  "PBN file"
+// @Success      201  {array}   bridgeDealResp
+// @Failure      400  {object}  response.Error
+// @Failure      403  {object}  response.Error
+// @Failure      429  {object}  response.Error
+// @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/bridge/pbn [post]
+func (b *bridgeRoutes) importPBN(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPBNSize))
+	if err != nil {
+		response.JSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	deals, err := b.bridge.ImportPBN(r.Context(), string(body))
+	if err != nil {
+		bridgeError(w, err)
+		return
+	}
+
+	resp := make([]bridgeDealResp, 0, len(deals))
+	for _, deal := range deals {
+		resp = append(resp, newBridgeDealResp(deal))
+	}
+
+	response.JSON(w, resp, http.StatusCreated)
+}
+
+// parseBridgeConstraint reads a constraint such as N:15-17:balanced.
+func parseBridgeConstraint(s string) (usecase.BridgeConstraint, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 || (len(parts) == 3 && parts[2] != "balanced") {
+		return usecase.BridgeConstraint{}, fmt.Errorf("constraint %q must look like N:15-17:balanced", s)
+	}
+
+	min, max, ok := strings.Cut(parts[1], "-")
+	if !ok {
+		max = min
+	}
+	c := usecase.BridgeConstraint{
+		Seat:     strings.ToUpper(parts[0]),
+		Balanced: len(parts) == 3,
+	}
+
+	var err error
+	if c.MinHCP, err = strconv.Atoi(min); err != nil {
+		return usecase.BridgeConstraint{}, fmt.Errorf("constraint %q has no HCP range", s)
+	}
+	if c.MaxHCP, err = strconv.Atoi(max); err != nil {
+		return usecase.BridgeConstraint{}, fmt.Errorf("constraint %q has no HCP range", s)
+	}
+
+	return c, nil
+}
+
+func bridgeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.BridgeDealNotFoundErr):
+		response.JSONError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, usecase.GameForbiddenErr):
+		response.JSONError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, usecase.BridgeInvalidOptionsErr), errors.Is(err, usecase.BridgeInvalidPBNErr):
+		response.JSONError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, usecase.BridgeConstraintsErr):
+		response.JSONError(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		response.JSONError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,157 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+type stubBridgeManager struct {
+	newDeal   func(board int, constraints []usecase.BridgeConstraint) (entity.BridgeDeal, error)
+	deal      func(id string) (entity.BridgeDeal, error)
+	exportPBN func(id string) (string, error)
+	importPBN func(pbn string) ([]entity.BridgeDeal, error)
+}
+
+func (s *stubBridgeManager) NewDeal(_ context.Context, board int, constraints []usecase.BridgeConstraint) (entity.BridgeDeal, error) {
+	return s.newDeal(board, constraints)
+}
+
+func (s *stubBridgeManager) Deal(_ context.Context, id string) (entity.BridgeDeal, error) {
+	return s.deal(id)
+}
+
+func (s *stubBridgeManager) ExportPBN(_ context.Context, id string) (string, error) {
+	return s.exportPBN(id)
+}
+
+func (s *stubBridgeManager) ImportPBN(_ context.Context, pbn string) ([]entity.BridgeDeal, error) {
+	return s.importPBN(pbn)
+}
+
+func Test_bridgeRoutes_newDeal(t *testing.T) {
+	tests := []struct {
+		name            string
+		query           string
+		err             error
+		statusCode      int
+		wantConstraints []usecase.BridgeConstraint
+	}{
+		{
+			name:       "Success",
+			query:      "board=3&constraint=N:15-17:balanced&constraint=s:8",
+			statusCode: http.StatusCreated,
+			wantConstraints: []usecase.BridgeConstraint{
+				{Seat: entity.BridgeNorth, MinHCP: 15, MaxHCP: 17, Balanced: true},
+				{Seat: entity.BridgeSouth, MinHCP: 8, MaxHCP: 8},
+			},
+		},
+		{
+			name:       "Bad Constraint",
+			query:      "constraint=N:strong",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Unsatisfiable",
+			query:      "board=3",
+			err:        usecase.BridgeConstraintsErr,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/v1/games/bridge?"+tt.query, nil)
+
+			b := &bridgeRoutes{
+				bridge: &stubBridgeManager{
+					newDeal: func(board int, constraints []usecase.BridgeConstraint) (entity.BridgeDeal, error) {
+						if board != 3 {
+							t.Errorf("bridgeRoutes.newDeal() | got board %d, want 3", board)
+						}
+						if diff := cmp.Diff(constraints, tt.wantConstraints); diff != "" {
+							t.Errorf("bridgeRoutes.newDeal() | constraints (-got +want):\n%s", diff)
+						}
+						return entity.BridgeDeal{
+							ID:    "id",
+							Board: board,
+							Hands: map[string][]entity.Card{entity.BridgeNorth: {blackjackKing, blackjackSix}},
+						}, tt.err
+					},
+				},
+			}
+			b.newDeal(w, r)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Fatalf("bridgeRoutes.newDeal() | got status code %d, want %d", resp.StatusCode, tt.statusCode)
+			}
+			if tt.statusCode != http.StatusCreated {
+				return
+			}
+
+			var got bridgeDealResp
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if north := got.Hands[entity.BridgeNorth]; north.HCP != 3 || north.Shape != "2-0-0-0" {
+				t.Errorf("bridgeRoutes.newDeal() | got North %+v, want 3 HCP and 2-0-0-0", north)
+			}
+		})
+	}
+}
+
+func Test_bridgeRoutes_pbn(t *testing.T) {
+	const pbn = "[Deal \"N:...\"]\n"
+
+	b := &bridgeRoutes{
+		bridge: &stubBridgeManager{
+			exportPBN: func(id string) (string, error) {
+				return pbn, nil
+			},
+			importPBN: func(got string) ([]entity.BridgeDeal, error) {
+				if got != pbn {
+					t.Errorf("bridgeRoutes.importPBN() | got body %q, want %q", got, pbn)
+				}
+				return []entity.BridgeDeal{{ID: "a"}, {ID: "b"}}, nil
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	b.exportPBN(w, withURLParam(httptest.NewRequest(http.MethodGet, "/v1/games/bridge/id/pbn", nil), "dealID", "id"))
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != pbn {
+		t.Errorf("bridgeRoutes.exportPBN() | got status code %d and body %q", resp.StatusCode, body)
+	}
+
+	w = httptest.NewRecorder()
+	b.importPBN(w, httptest.NewRequest(http.MethodPost, "/v1/games/bridge/pbn", strings.NewReader(pbn)))
+
+	resp = w.Result()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("bridgeRoutes.importPBN() | got status code %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	var got []bridgeDealResp
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("bridgeRoutes.importPBN() | got %d deals, want 2", len(got))
+	}
+}
@@ -0,0 +1,313 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/lualfe/card-game/internal/controller/http/middleware"
+	"github.com/lualfe/card-game/internal/controller/http/response"
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+// casualGamePaths maps the route of each casual game to its kind.
+var casualGamePaths = map[string]string{
+	"war":         entity.CasualGameWar,
+	"gofish":      entity.CasualGameGoFish,
+	"crazyeights": entity.CasualGameCrazyEights,
+}
+
+func createCasualGameRoutes(m chi.Router, games usecase.CasualGameManager, limits usecase.RateLimiter) {
+	var (
+		play = drawing(limits)
+		read = middleware.Scope(entity.ScopeDecksRead)
+	)
+	for path, kind := range casualGamePaths {
+		cr := &casualGameRoutes{games: games, kind: kind}
+
+		m.Route("/v1/games/"+path, func(r chi.Router) {
+			r.With(creating(limits)...).Post("/", cr.newGame)
+			r.With(read).Get("/{gameID}", cr.game)
+			r.With(read).Get("/{gameID}/moves", cr.legalMoves)
+			r.With(play...).Post("/{gameID}/moves", cr.play)
+			r.With(play...).Post("/{gameID}/bots", cr.playBot)
+		})
+	}
+}
+
+type casualGameRoutes struct {
+	games usecase.CasualGameManager
+	kind  string
+}
+
+type casualPlayerResp struct {
+	ID        string        `json:"player_id"`
+	Hand      []entity.Card `json:"hand,omitempty"`
+	HandCount int           `json:"hand_count"`
+	Books     []string      `json:"books"`
+}
+
+type casualGameResp struct {
+	ID       string             `json:"game_id"`
+	Kind     string             `json:"kind"`
+	Stock    int                `json:"stock"`
+	Players  []casualPlayerResp `json:"players"`
+	Turn     int                `json:"turn"`
+	Pile     []entity.Card      `json:"pile"`
+	Suit     string             `json:"suit,omitempty"`
+	Round    int                `json:"round"`
+	Message  string             `json:"message"`
+	Finished bool               `json:"finished"`
+	Winner   string             `json:"winner,omitempty"`
+}
+
+type createdCasualGameResp struct {
+	Tokens map[string]string `json:"tokens"`
+	Game   casualGameResp    `json:"game"`
+}
+
+type casualMovesResp struct {
+	Moves []entity.CasualMove `json:"moves"`
+}
+
+// newCasualGameResp builds the game view for the player owning
+// the token, who only sees the size of the other players' hands.
+func newCasualGameResp(game entity.CasualGame, token string) casualGameResp {
+	resp := casualGameResp{
+		ID:       game.ID,
+		Kind:     game.Kind,
+		Stock:    game.Stock,
+		Players:  []casualPlayerResp{},
+		Turn:     game.Turn,
+		Pile:     game.Pile,
+		Suit:     game.Suit,
+		Round:    game.Round,
+		Message:  game.Message,
+		Finished: game.Finished,
+		Winner:   game.Winner,
+	}
+	for _, p := range game.Players {
+		player := casualPlayerResp{
+			ID:        p.ID,
+			HandCount: len(p.Hand),
+			Books:     p.Books,
+		}
+		if token != "" && p.Token == token {
+			player.Hand = p.Hand
+		}
+		resp.Players = append(resp.Players, player)
+	}
+
+	return resp
+}
+
+// newGame godoc
+// @Summary      Creates a casual card game.
+// @Description  Deals a new game of War, Go Fish or Crazy Eights and returns a token per player.
+// @Produce      json
+// @Param        game     path      string  true  "Game"  Enums(war, gofish, crazyeights)
+// @Param        players  query     string  true  "Comma separated player ids"  example(alice,bob)
+// @Success      201      {object}  createdCasualGameResp
+// @Failure      400      {object}  response.Error
+// @Failure      403      {object}  response.Error
+// @Failure      429      {object}  response.Error
+// @Failure      500      {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/{game} [post]
+func (c *casualGameRoutes) newGame(w http.ResponseWriter, r *http.Request) {
+	var players []string
+	if p := r.URL.Query().Get("players"); p != "" {
+		players = strings.Split(p, ",")
+	}
+
+	game, err := c.games.New(r.Context(), c.kind, players)
+	if err != nil {
+		casualGameError(w, err)
+		return
+	}
+
+	resp := createdCasualGameResp{
+		Tokens: map[string]string{},
+		Game:   newCasualGameResp(game, ""),
+	}
+	for _, p := range game.Players {
+		resp.Tokens[p.ID] = p.Token
+	}
+
+	response.JSON(w, resp, http.StatusCreated)
+}
+
+// game godoc
+// @Summary      Shows a casual card game.
+// @Description  Shows a game, with only the hand of the player owning the token.
+// @Produce      json
+// @Param        game            path      string  true   "Game"  Enums(war, gofish, crazyeights)
+// @Param        id              path      string  true   "Game id"
+// @Param        X-Player-Token  header    string  false  "Token received when the game was created"
+// @Success      200             {object}  casualGameResp
+// @Failure      403             {object}  response.Error
+// @Failure      404             {object}  response.Error
+// @Failure      500             {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/{game}/{id} [get]
+func (c *casualGameRoutes) game(w http.ResponseWriter, r *http.Request) {
+	game, err := c.find(r.Context(), chi.URLParam(r, "gameID"))
+	if err != nil {
+		casualGameError(w, err)
+		return
+	}
+
+	response.JSON(w, newCasualGameResp(game, r.Header.Get(playerTokenHeader)), http.StatusOK)
+}
+
+// legalMoves godoc
+// @Summary      Lists legal moves.
+// @Description  Lists the moves the player owning the token can make now.
+// @Produce      json
+// @Param        game            path      string  true  "Game"  Enums(war, gofish, crazyeights)
+// @Param        id              path      string  true  "Game id"
+// @Param        X-Player-Token  header    string  true  "Token received when the game was created"
+// @Success      200             {object}  casualMovesResp
+// @Failure      403             {object}  response.Error
+// @Failure      404             {object}  response.Error
+// @Failure      500             {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/{game}/{id}/moves [get]
+func (c *casualGameRoutes) legalMoves(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "gameID")
+	if _, err := c.find(r.Context(), id); err != nil {
+		casualGameError(w, err)
+		return
+	}
+
+	moves, err := c.games.LegalMoves(r.Context(), id, r.Header.Get(playerTokenHeader))
+	if err != nil {
+		casualGameError(w, err)
+		return
+	}
+
+	resp := casualMovesResp{
+		Moves: moves,
+	}
+	if resp.Moves == nil {
+		resp.Moves = []entity.CasualMove{}
+	}
+
+	response.JSON(w, resp, http.StatusOK)
+}
+
+// play godoc
+// @Summary      Plays a move.
+// @Description  Validates and plays a move for the player owning the token.
+// @Produce      json
+// @Param        game            path      string  true   "Game"  Enums(war, gofish, crazyeights)
+// @Param        id              path      string  true   "Game id"
+// @Param        X-Player-Token  header    string  true   "Token received when the game was created"
+// @Param        action          query     string  true   "Move"  Enums(flip, ask, play, draw, pass)
+// @Param        target          query     string  false  "Player asked in Go Fish"
+// @Param        value           query     string  false  "Value asked in Go Fish"  example(KING)
+// @Param        card            query     string  false  "Card played in Crazy Eights"  example(8S)
+// @Param        suit            query     string  false  "Suit named with an eight in Crazy Eights"  example(HEARTS)
+// @Success      200             {object}  casualGameResp
+// @Failure      403             {object}  response.Error
+// @Failure      404             {object}  response.Error
+// @Failure      409             {object}  response.Error
+// @Failure      429             {object}  response.Error
+// @Failure      500             {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/{game}/{id}/moves [post]
+func (c *casualGameRoutes) play(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "gameID")
+	if _, err := c.find(r.Context(), id); err != nil {
+		casualGameError(w, err)
+		return
+	}
+
+	q := r.URL.Query()
+	move := entity.CasualMove{
+		Action: q.Get("action"),
+		Target: q.Get("target"),
+		Value:  q.Get("value"),
+		Card:   q.Get("card"),
+		Suit:   q.Get("suit"),
+	}
+
+	token := r.Header.Get(playerTokenHeader)
+	game, err := c.games.Play(r.Context(), id, token, move)
+	if err != nil {
+		casualGameError(w, err)
+		return
+	}
+
+	response.JSON(w, newCasualGameResp(game, token), http.StatusOK)
+}
+
+// playBot godoc
+// @Summary      Lets a bot move.
+// @Description  Lets a bot make the move of the player whose turn it is.
+// @Produce      json
+// @Param        game  path      string  true  "Game"  Enums(war, gofish, crazyeights)
+// @Param        id    path      string  true  "Game id"
+// @Success      200   {object}  casualGameResp
+// @Failure      403   {object}  response.Error
+// @Failure      404   {object}  response.Error
+// @Failure      409   {object}  response.Error
+// @Failure      429   {object}  response.Error
+// @Failure      500   {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/{game}/{id}/bots [post]
+func (c *casualGameRoutes) playBot(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "gameID")
+	if _, err := c.find(r.Context(), id); err != nil {
+		casualGameError(w, err)
+		return
+	}
+
+	game, err := c.games.PlayBot(r.Context(), id)
+	if err != nil {
+		casualGameError(w, err)
+		return
+	}
+
+	response.JSON(w, newCasualGameResp(game, r.Header.Get(playerTokenHeader)), http.StatusOK)
+}
+
+// find gets a game, making sure it is played
+// under the route it was requested from.
+func (c *casualGameRoutes) find(ctx context.Context, id string) (entity.CasualGame, error) {
+	game, err := c.games.Game(ctx, id)
+	if err != nil {
+		return entity.CasualGame{}, err
+	}
+	if game.Kind != c.kind {
+		return entity.CasualGame{}, fmt.Errorf("%w with id %s", usecase.CasualGameNotFoundErr, id)
+	}
+	return game, nil
+}
+
+func casualGameError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.CasualGameNotFoundErr):
+		response.JSONError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, usecase.GameForbiddenErr):
+		response.JSONError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, usecase.CasualPlayerNotFoundErr):
+		response.JSONError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, usecase.CasualInvalidPlayersErr), errors.Is(err, usecase.CasualGameUnknownKindErr):
+		response.JSONError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, usecase.CasualIllegalMoveErr):
+		response.JSONError(w, err.Error(), http.StatusConflict)
+	default:
+		response.JSONError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,215 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+type stubCasualGameManager struct {
+	new        func(kind string, players []string) (entity.CasualGame, error)
+	game       func(id string) (entity.CasualGame, error)
+	legalMoves func(id, token string) ([]entity.CasualMove, error)
+	play       func(id, token string, move entity.CasualMove) (entity.CasualGame, error)
+	playBot    func(id string) (entity.CasualGame, error)
+}
+
+func (s *stubCasualGameManager) New(_ context.Context, kind string, players []string) (entity.CasualGame, error) {
+	return s.new(kind, players)
+}
+
+func (s *stubCasualGameManager) Game(_ context.Context, id string) (entity.CasualGame, error) {
+	return s.game(id)
+}
+
+func (s *stubCasualGameManager) LegalMoves(_ context.Context, id, token string) ([]entity.CasualMove, error) {
+	return s.legalMoves(id, token)
+}
+
+func (s *stubCasualGameManager) Play(_ context.Context, id, token string, move entity.CasualMove) (entity.CasualGame, error) {
+	return s.play(id, token, move)
+}
+
+func (s *stubCasualGameManager) PlayBot(_ context.Context, id string) (entity.CasualGame, error) {
+	return s.playBot(id)
+}
+
+func withURLParam(r *http.Request, key, value string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(key, value)
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
+
+func Test_casualGameRoutes_newGame(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/v1/games/gofish?players=a,b", nil)
+
+	c := &casualGameRoutes{
+		kind: entity.CasualGameGoFish,
+		games: &stubCasualGameManager{
+			new: func(kind string, players []string) (entity.CasualGame, error) {
+				if kind != entity.CasualGameGoFish {
+					t.Errorf("casualGameRoutes.newGame() | got kind %s, want %s", kind, entity.CasualGameGoFish)
+				}
+				return entity.CasualGame{
+					ID:   "id",
+					Kind: kind,
+					Players: []entity.CasualPlayer{
+						{ID: players[0], Token: "token-a", Hand: []entity.Card{blackjackKing}},
+						{ID: players[1], Token: "token-b", Hand: []entity.Card{blackjackSix}},
+					},
+				}, nil
+			},
+		},
+	}
+	c.newGame(w, r)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("casualGameRoutes.newGame() | got status code %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+
+	var got createdCasualGameResp
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got.Tokens, map[string]string{"a": "token-a", "b": "token-b"}); diff != "" {
+		t.Errorf("casualGameRoutes.newGame() | tokens (-got +want):\n%s", diff)
+	}
+	for _, p := range got.Game.Players {
+		if p.Hand != nil || p.HandCount != 1 {
+			t.Errorf("casualGameRoutes.newGame() | player %s shows hand %v with count %d", p.ID, p.Hand, p.HandCount)
+		}
+	}
+}
+
+func Test_casualGameRoutes_game(t *testing.T) {
+	game := entity.CasualGame{
+		ID:   "id",
+		Kind: entity.CasualGameCrazyEights,
+		Players: []entity.CasualPlayer{
+			{ID: "a", Token: "token-a", Hand: []entity.Card{blackjackKing}},
+			{ID: "b", Token: "token-b", Hand: []entity.Card{blackjackSix}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		kind       string
+		statusCode int
+		wantHands  [][]entity.Card
+	}{
+		{
+			name:       "Own Hand Only",
+			kind:       entity.CasualGameCrazyEights,
+			statusCode: http.StatusOK,
+			wantHands:  [][]entity.Card{{blackjackKing}, nil},
+		},
+		{
+			name:       "Other Game Route",
+			kind:       entity.CasualGameWar,
+			statusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := withURLParam(httptest.NewRequest(http.MethodGet, "/v1/games/crazyeights/id", nil), "gameID", "id")
+			r.Header.Set(playerTokenHeader, "token-a")
+
+			c := &casualGameRoutes{
+				kind: tt.kind,
+				games: &stubCasualGameManager{
+					game: func(id string) (entity.CasualGame, error) {
+						return game, nil
+					},
+				},
+			}
+			c.game(w, r)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Fatalf("casualGameRoutes.game() | got status code %d, want %d", resp.StatusCode, tt.statusCode)
+			}
+
+			if tt.wantHands != nil {
+				var got casualGameResp
+				if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+					t.Fatal(err)
+				}
+
+				var hands [][]entity.Card
+				for _, p := range got.Players {
+					hands = append(hands, p.Hand)
+				}
+				if diff := cmp.Diff(hands, tt.wantHands); diff != "" {
+					t.Errorf("casualGameRoutes.game() | hands (-got +want):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_casualGameRoutes_play(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{
+			name:       "Success",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Illegal Move",
+			err:        usecase.CasualIllegalMoveErr,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "Unknown Player",
+			err:        usecase.CasualPlayerNotFoundErr,
+			statusCode: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/v1/games/crazyeights/id/moves?action=play&card=8S&suit=HEARTS", nil)
+			r = withURLParam(r, "gameID", "id")
+			r.Header.Set(playerTokenHeader, "token")
+
+			c := &casualGameRoutes{
+				kind: entity.CasualGameCrazyEights,
+				games: &stubCasualGameManager{
+					game: func(id string) (entity.CasualGame, error) {
+						return entity.CasualGame{Kind: entity.CasualGameCrazyEights}, nil
+					},
+					play: func(id, token string, move entity.CasualMove) (entity.CasualGame, error) {
+						want := entity.CasualMove{Action: entity.CasualMovePlay, Card: "8S", Suit: "HEARTS"}
+						if diff := cmp.Diff(move, want); diff != "" {
+							t.Errorf("casualGameRoutes.play() | move (-got +want):\n%s", diff)
+						}
+						return entity.CasualGame{}, tt.err
+					},
+				},
+			}
+			c.play(w, r)
+
+			if code := w.Result().StatusCode; code != tt.statusCode {
+				t.Fatalf("casualGameRoutes.play() | got status code %d, want %d", code, tt.statusCode)
+			}
+		})
+	}
+}
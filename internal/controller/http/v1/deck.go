@@ -5,29 +5,95 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/lualfe/card-game/internal/entity"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/lualfe/card-game/internal/controller/http/middleware"
 	"github.com/lualfe/card-game/internal/controller/http/response"
 	"github.com/lualfe/card-game/internal/usecase"
 )
 
-func createDeckRoutes(m *chi.Mux, deck usecase.DeckManager) {
+func createDeckRoutes(m chi.Router, deck usecase.DeckManager, limits usecase.RateLimiter) {
 	dr := &deckRoutes{deck}
 
+	// Callers limited to scopes, as bearer tokens are, only
+	// reach the routes of their scopes, and creating decks
+	// and drawing from them are held to the rate limits once
+	// the scope lets the caller in. Draws count against the
+	// caller and the deck both, the deck only once the
+	// caller is found to reach it, so others can't spend its
+	// budget with draws that fail.
+	var (
+		newDeck  = creating(limits)
+		drawFrom = drawing(limits)
+		read     = middleware.Scope(entity.ScopeDecksRead)
+	)
+	if limits != nil {
+		drawFrom = append(drawFrom,
+			dr.reachable,
+			middleware.RateLimit(limits, usecase.RateLimitDeckDraw, deckRateLimitKey),
+		)
+	}
+
 	m.Route("/v1/decks", func(r chi.Router) {
-		r.Post("/", dr.newDeck)
-		r.Get("/{deckID}", dr.openDeck)
-		r.Get("/withdrawals/{deckID}", dr.drawCards)
+		r.With(newDeck...).Post("/", dr.newDeck)
+		r.With(read).Get("/{deckID}", dr.openDeck)
+		r.With(drawFrom...).Post("/{deckID}/hands", dr.deal)
+		r.With(drawFrom...).Post("/{deckID}/returns", dr.returnCards)
+		r.With(read).Get("/{deckID}/events", dr.events)
+		r.With(middleware.Scope(entity.ScopeDecksDelete)).Delete("/{deckID}", dr.deleteDeck)
+		r.With(drawFrom...).Get("/withdrawals/{deckID}", dr.drawCards)
 	})
 }
 
+// deckRateLimitKey is the deck of a request, within the
+// tenant of its principal, as deck IDs are.
+func deckRateLimitKey(r *http.Request) string {
+	p, _ := usecase.PrincipalFrom(r.Context())
+	return p.Tenant + "/" + chi.URLParam(r, "deckID")
+}
+
+// creating holds the routes creating decks, those of the
+// games included, to the create scope and the create
+// budget of the caller.
+func creating(limits usecase.RateLimiter) chi.Middlewares {
+	m := chi.Middlewares{middleware.Scope(entity.ScopeDecksCreate)}
+	if limits != nil {
+		m = append(m, middleware.RateLimit(limits, usecase.RateLimitCreate, middleware.ClientKey))
+	}
+	return m
+}
+
+// drawing holds the routes drawing cards, the moves of the
+// games included, to the draw scope and the draw budget
+// of the caller.
+func drawing(limits usecase.RateLimiter) chi.Middlewares {
+	m := chi.Middlewares{middleware.Scope(entity.ScopeDecksDraw)}
+	if limits != nil {
+		m = append(m, middleware.RateLimit(limits, usecase.RateLimitDraw, middleware.ClientKey))
+	}
+	return m
+}
+
 type deckRoutes struct {
 	deck usecase.DeckManager
 }
 
+// reachable lets the request through only if its caller
+// can open the deck of the route.
+func (dr *deckRoutes) reachable(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := dr.deck.Open(r.Context(), chi.URLParam(r, "deckID")); err != nil {
+			deckError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 type newDeckResponse struct {
 	ID        string `json:"deck_id"`
 	Shuffled  bool   `json:"shuffled"`
@@ -36,11 +102,18 @@ type newDeckResponse struct {
 
 // newDeck godoc
 // @Summary      Creates a new deck.
-// @Description  Creates a new deck with cards.
+// @Description  Creates a new deck with cards. A tenant over its decks, or asking for more cards than its decks can have, gets a 403.
 // @Produce      json
 // @Param        shuffle  query     bool    false  "Activate or deactivate cards shuffling."                                                                      default(false)
 // @Param        cards    query     string  false  "Comma separated card codes to create a custom deck. If not sent, the regular 52 cards deck will be created."  example(AS,2S)
 // @Success      200      {object}  newDeckResponse
+// @Failure      401      {object}  response.Error
+// @Failure      403      {object}  response.Error
+// @Failure      429      {object}  response.Error
+// @Failure      500      {object}  response.Error
+// @Failure      503      {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
 // @Router       /decks [post]
 func (d *deckRoutes) newDeck(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
@@ -54,7 +127,18 @@ func (d *deckRoutes) newDeck(w http.ResponseWriter, r *http.Request) {
 		cardCodes = strings.Split(cards, ",")
 	}
 
-	deck := d.deck.New(shuffle, cardCodes)
+	deck, err := d.deck.New(r.Context(), shuffle, cardCodes)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.TenantQuotaErr):
+			response.JSONError(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, usecase.DeckLimitErr):
+			response.JSONError(w, err.Error(), http.StatusServiceUnavailable)
+		default:
+			response.JSONError(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
 
 	resp := newDeckResponse{
 		ID:        deck.ID,
@@ -65,30 +149,270 @@ func (d *deckRoutes) newDeck(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, resp, http.StatusCreated)
 }
 
+type handResp struct {
+	PlayerID string        `json:"player_id"`
+	Count    int           `json:"count"`
+	Cards    []entity.Card `json:"cards,omitempty"`
+}
+
+type deckResp struct {
+	ID        string        `json:"deck_id"`
+	Shuffled  bool          `json:"shuffled"`
+	Remaining int           `json:"remaining"`
+	Cards     []entity.Card `json:"cards"`
+	Hands     []handResp    `json:"hands,omitempty"`
+	Version   int           `json:"version"`
+	Owner     string        `json:"owner,omitempty"`
+}
+
+type dealResp struct {
+	Tokens map[string]string `json:"tokens"`
+	Deck   deckResp          `json:"deck"`
+}
+
+// newDeckResp builds the deck view for the player owning
+// the token, who only sees the size of the other hands.
+func newDeckResp(deck entity.Deck, token string) deckResp {
+	resp := deckResp{
+		ID:        deck.ID,
+		Shuffled:  deck.Shuffled,
+		Remaining: deck.Remaining,
+		Cards:     deck.Cards,
+		Version:   deck.Version,
+		Owner:     deck.Owner,
+	}
+	for _, h := range deck.Hands {
+		hand := handResp{
+			PlayerID: h.PlayerID,
+			Count:    len(h.Cards),
+		}
+		if token != "" && h.Token == token {
+			hand.Cards = h.Cards
+		}
+		resp.Hands = append(resp.Hands, hand)
+	}
+
+	return resp
+}
+
 // openDeck godoc
 // @Summary      Opens a deck.
-// @Description  Opens a deck, showing all its cards.
+// @Description  Opens a deck, showing all its cards. Only the hand of the player owning the token is shown, the others show their size.
+// @Description  With at_version the deck is shown as it was right after that event; only its owner sees the cards left in it.
 // @Produce      json
-// @Param        id   path      string  true  "Deck id"
-// @Success      200  {object}  entity.Deck
+// @Param        id              path      string  true   "Deck id"
+// @Param        at_version      query     int     false  "Event version to rebuild the deck at"
+// @Param        X-Player-Token  header    string  false  "Player token"
+// @Success      200  {object}  deckResp
+// @Failure      400     {object}  response.Error
+// @Failure      401     {object}  response.Error
+// @Failure      403     {object}  response.Error
 // @Failure      404     {object}  response.Error
 // @Failure      500     {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
 // @Router       /decks/{id} [get]
 func (d *deckRoutes) openDeck(w http.ResponseWriter, r *http.Request) {
 	deckID := chi.URLParam(r, "deckID")
 
-	deck, err := d.deck.Open(deckID)
+	var (
+		deck entity.Deck
+		err  error
+	)
+	if v := r.URL.Query().Get("at_version"); v != "" {
+		version, convErr := strconv.Atoi(v)
+		if convErr != nil {
+			response.JSONError(w, "at_version must be a number", http.StatusBadRequest)
+			return
+		}
+		deck, err = d.deck.At(r.Context(), deckID, version)
+	} else {
+		deck, err = d.deck.Open(r.Context(), deckID)
+	}
 	if err != nil {
-		if errors.Is(err, usecase.DeckNotFoundErr) {
+		switch {
+		case errors.Is(err, usecase.DeckNotFoundErr) || errors.Is(err, usecase.DeckVersionNotFoundErr):
 			response.JSONError(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, usecase.DeckForbiddenErr):
+			response.JSONError(w, err.Error(), http.StatusForbidden)
+		default:
+			response.JSONError(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response.JSON(w, newDeckResp(deck, r.Header.Get(playerTokenHeader)), http.StatusOK)
+}
+
+// deal godoc
+// @Summary      Deals hands from a deck.
+// @Description  Deals an amount of cards to each player in turn into hands kept with the deck, returning a token for each player getting a first hand.
+// @Produce      json
+// @Param        id       path      string  true   "Deck id"
+// @Param        players  query     string  true   "Comma separated player ids"  example(alice,bob)
+// @Param        amount   query     int     false  "Cards dealt to each player"  default(1)
+// @Success      201      {object}  dealResp
+// @Failure      400      {object}  response.Error
+// @Failure      401      {object}  response.Error
+// @Failure      403      {object}  response.Error
+// @Failure      404      {object}  response.Error
+// @Failure      409      {object}  response.Error
+// @Failure      429      {object}  response.Error
+// @Failure      500      {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /decks/{id}/hands [post]
+func (d *deckRoutes) deal(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var players []string
+	if p := q.Get("players"); p != "" {
+		players = strings.Split(p, ",")
+	}
+
+	amount := 1
+	if am := q.Get("amount"); am != "" {
+		v, err := strconv.Atoi(am)
+		if err != nil {
+			response.JSONError(w, "amount must be a number", http.StatusBadRequest)
 			return
 		}
+		amount = v
+	}
 
-		response.JSONError(w, err.Error(), http.StatusInternalServerError)
+	deck, tokens, err := d.deck.Deal(r.Context(), chi.URLParam(r, "deckID"), players, amount)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.DeckNotFoundErr):
+			response.JSONError(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, usecase.DeckForbiddenErr):
+			response.JSONError(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, usecase.DeckInvalidDealErr):
+			response.JSONError(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, usecase.DeckNotEnoughCardsErr):
+			response.JSONError(w, err.Error(), http.StatusConflict)
+		default:
+			response.JSONError(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response.JSON(w, dealResp{Tokens: tokens, Deck: newDeckResp(deck, "")}, http.StatusCreated)
+}
+
+// returnCards godoc
+// @Summary      Returns cards to a deck.
+// @Description  Puts cards drawn from a deck back at its bottom.
+// @Produce      json
+// @Param        id     path      string  true  "Deck id"
+// @Param        cards  query     string  true  "Comma separated card codes"  example(AS,2S)
+// @Success      200    {object}  deckResp
+// @Failure      401    {object}  response.Error
+// @Failure      403    {object}  response.Error
+// @Failure      404    {object}  response.Error
+// @Failure      409    {object}  response.Error
+// @Failure      429    {object}  response.Error
+// @Failure      500    {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /decks/{id}/returns [post]
+func (d *deckRoutes) returnCards(w http.ResponseWriter, r *http.Request) {
+	var cardCodes []string
+	if cards := r.URL.Query().Get("cards"); cards != "" {
+		cardCodes = strings.Split(cards, ",")
+	}
+
+	deck, err := d.deck.Return(r.Context(), chi.URLParam(r, "deckID"), cardCodes)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.DeckNotFoundErr):
+			response.JSONError(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, usecase.DeckForbiddenErr):
+			response.JSONError(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, usecase.DeckInvalidReturnErr):
+			response.JSONError(w, err.Error(), http.StatusConflict)
+		default:
+			response.JSONError(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response.JSON(w, newDeckResp(deck, ""), http.StatusOK)
+}
+
+type deckEventResp struct {
+	DeckID   string        `json:"deck_id"`
+	Version  int           `json:"version"`
+	Type     string        `json:"type"`
+	Time     time.Time     `json:"time"`
+	Count    int           `json:"count"`
+	Cards    []entity.Card `json:"cards,omitempty"`
+	PlayerID string        `json:"player_id,omitempty"`
+	Owner    string        `json:"owner,omitempty"`
+	Tenant   string        `json:"tenant,omitempty"`
+	Actor    string        `json:"actor,omitempty"`
+}
+
+type deckEventsResp struct {
+	Events []deckEventResp `json:"events"`
+}
+
+// newDeckEventsResp builds the history seen by the player
+// owning the token. Cards moved into the other hands only
+// show how many they were, as in newDeckResp. Created and
+// shuffled decks only show their size too, as their order
+// gives away every hand dealt from them.
+func newDeckEventsResp(events []entity.DeckEvent, token string) deckEventsResp {
+	resp := deckEventsResp{Events: make([]deckEventResp, len(events))}
+	for i, e := range events {
+		event := deckEventResp{
+			DeckID:   e.DeckID,
+			Version:  e.Version,
+			Type:     e.Type,
+			Time:     e.Time,
+			Count:    len(e.Cards),
+			Cards:    e.Cards,
+			PlayerID: e.PlayerID,
+			Owner:    e.Owner,
+			Tenant:   e.Tenant,
+			Actor:    e.Actor,
+		}
+		switch e.Type {
+		case entity.DeckEventCreated, entity.DeckEventShuffled:
+			event.Cards = nil
+		case entity.DeckEventMoved:
+			if token == "" || e.Token != token {
+				event.Cards = nil
+			}
+		}
+		resp.Events[i] = event
+	}
+
+	return resp
+}
+
+// events godoc
+// @Summary      Lists the events of a deck.
+// @Description  Lists every operation done on a deck, oldest first. Created and shuffled decks show how many cards they hold, not their order. Only the cards dealt to the player owning the token are shown, the other deals show how many cards they moved.
+// @Produce      json
+// @Param        id              path      string  true   "Deck id"
+// @Param        X-Player-Token  header    string  false  "Player token"
+// @Success      200  {object}  deckEventsResp
+// @Failure      401  {object}  response.Error
+// @Failure      403  {object}  response.Error
+// @Failure      404  {object}  response.Error
+// @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /decks/{id}/events [get]
+func (d *deckRoutes) events(w http.ResponseWriter, r *http.Request) {
+	events, err := d.deck.Events(r.Context(), chi.URLParam(r, "deckID"))
+	if err != nil {
+		deckError(w, err)
 		return
 	}
 
-	response.JSON(w, deck, http.StatusOK)
+	response.JSON(w, newDeckEventsResp(events, r.Header.Get(playerTokenHeader)), http.StatusOK)
 }
 
 type drawCardsResp struct {
@@ -102,8 +426,13 @@ type drawCardsResp struct {
 // @Param        id      path      string  true   "Deck id"
 // @Param        amount  query     int     false  "Amount of cards to draw"  default(1)
 // @Success      200     {object}  drawCardsResp
+// @Failure      401  {object}  response.Error
+// @Failure      403  {object}  response.Error
 // @Failure      404  {object}  response.Error
+// @Failure      429  {object}  response.Error
 // @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
 // @Router       /decks/withdrawals/{id} [get]
 func (d *deckRoutes) drawCards(w http.ResponseWriter, r *http.Request) {
 	deckID := chi.URLParam(r, "deckID")
@@ -114,14 +443,9 @@ func (d *deckRoutes) drawCards(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	cards, err := d.deck.DrawCards(deckID, amount)
+	cards, err := d.deck.DrawCards(r.Context(), deckID, amount)
 	if err != nil {
-		if errors.Is(err, usecase.DeckNotFoundErr) {
-			response.JSONError(w, err.Error(), http.StatusNotFound)
-			return
-		}
-
-		response.JSONError(w, err.Error(), http.StatusInternalServerError)
+		deckError(w, err)
 		return
 	}
 
@@ -131,3 +455,38 @@ func (d *deckRoutes) drawCards(w http.ResponseWriter, r *http.Request) {
 
 	response.JSON(w, resp, http.StatusOK)
 }
+
+// deleteDeck godoc
+// @Summary      Deletes a deck.
+// @Description  Deletes a deck. Its events are kept, so its history can still be read. Tokens need the decks:delete scope.
+// @Produce      json
+// @Param        id   path  string  true  "Deck id"
+// @Success      204
+// @Failure      401  {object}  response.Error
+// @Failure      403  {object}  response.Error
+// @Failure      404  {object}  response.Error
+// @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /decks/{id} [delete]
+func (d *deckRoutes) deleteDeck(w http.ResponseWriter, r *http.Request) {
+	if err := d.deck.Delete(r.Context(), chi.URLParam(r, "deckID")); err != nil {
+		deckError(w, err)
+		return
+	}
+
+	response.JSON(w, nil, http.StatusNoContent)
+}
+
+// deckError responds with the status of the errors every
+// deck route can run into.
+func deckError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.DeckNotFoundErr):
+		response.JSONError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, usecase.DeckForbiddenErr):
+		response.JSONError(w, err.Error(), http.StatusForbidden)
+	default:
+		response.JSONError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -1,40 +1,71 @@
 package v1
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
 
 	"github.com/lualfe/card-game/internal/entity"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/go-cmp/cmp"
 )
 
 type stubDeckManager struct {
-	new       func(shuffle bool, cardCodes []string) entity.Deck
+	new       func(shuffle bool, cardCodes []string) (entity.Deck, error)
 	open      func(id string) (entity.Deck, error)
 	drawCards func(id string, amount int) ([]entity.Card, error)
+	deal      func(id string, players []string, amount int) (entity.Deck, map[string]string, error)
+	returnFn  func(id string, cardCodes []string) (entity.Deck, error)
+	events    func(id string) ([]entity.DeckEvent, error)
+	at        func(id string, version int) (entity.Deck, error)
+	delete    func(id string) error
 }
 
-func (s *stubDeckManager) DrawCards(id string, amount int) ([]entity.Card, error) {
+func (s *stubDeckManager) DrawCards(_ context.Context, id string, amount int) ([]entity.Card, error) {
 	return s.drawCards(id, amount)
 }
 
-func (s *stubDeckManager) Open(id string) (entity.Deck, error) {
+func (s *stubDeckManager) Open(_ context.Context, id string) (entity.Deck, error) {
 	return s.open(id)
 }
 
-func (s *stubDeckManager) New(shuffle bool, cardCodes []string) entity.Deck {
+func (s *stubDeckManager) New(_ context.Context, shuffle bool, cardCodes []string) (entity.Deck, error) {
 	return s.new(shuffle, cardCodes)
 }
 
+func (s *stubDeckManager) Deal(_ context.Context, id string, players []string, amount int) (entity.Deck, map[string]string, error) {
+	return s.deal(id, players, amount)
+}
+
+func (s *stubDeckManager) Return(_ context.Context, id string, cardCodes []string) (entity.Deck, error) {
+	return s.returnFn(id, cardCodes)
+}
+
+func (s *stubDeckManager) Events(_ context.Context, id string) ([]entity.DeckEvent, error) {
+	return s.events(id)
+}
+
+func (s *stubDeckManager) At(_ context.Context, id string, version int) (entity.Deck, error) {
+	return s.at(id, version)
+}
+
+func (s *stubDeckManager) Delete(_ context.Context, id string) error {
+	return s.delete(id)
+}
+
 func Test_deckRoutes_newDeck(t *testing.T) {
 	tests := []struct {
 		name       string
+		err        error
 		statusCode int
 		want       newDeckResponse
 	}{
@@ -56,6 +87,21 @@ func Test_deckRoutes_newDeck(t *testing.T) {
 				Remaining: 30,
 			},
 		},
+		{
+			name:       "Store Error",
+			err:        context.DeadlineExceeded,
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "Deck Limit",
+			err:        usecase.DeckLimitErr,
+			statusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "Tenant Quota",
+			err:        usecase.TenantQuotaErr,
+			statusCode: http.StatusForbidden,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -65,13 +111,13 @@ func Test_deckRoutes_newDeck(t *testing.T) {
 
 			d := &deckRoutes{
 				deck: &stubDeckManager{
-					new: func(shuffle bool, cardCodes []string) entity.Deck {
+					new: func(shuffle bool, cardCodes []string) (entity.Deck, error) {
 						return entity.Deck{
 							ID:        tt.want.ID,
 							Shuffled:  tt.want.Shuffled,
 							Remaining: tt.want.Remaining,
 							Cards:     []entity.Card{},
-						}
+						}, tt.err
 					},
 				},
 			}
@@ -84,6 +130,9 @@ func Test_deckRoutes_newDeck(t *testing.T) {
 			if code != tt.statusCode {
 				t.Fatalf("deckRoutes.newDeck() | got status code %d, want %d", code, tt.statusCode)
 			}
+			if tt.err != nil {
+				return
+			}
 
 			var got newDeckResponse
 			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
@@ -124,6 +173,11 @@ func Test_deckRoutes_openDeck(t *testing.T) {
 			statusCode: http.StatusNotFound,
 			wantErr:    usecase.DeckNotFoundErr,
 		},
+		{
+			name:       "Forbidden Error",
+			statusCode: http.StatusForbidden,
+			wantErr:    usecase.DeckForbiddenErr,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -190,6 +244,11 @@ func Test_deckRoutes_drawCards(t *testing.T) {
 			statusCode: http.StatusNotFound,
 			wantErr:    usecase.DeckNotFoundErr,
 		},
+		{
+			name:       "Forbidden Error",
+			statusCode: http.StatusForbidden,
+			wantErr:    usecase.DeckForbiddenErr,
+		},
 		{
 			name:       "Not Found Error",
 			statusCode: http.StatusInternalServerError,
@@ -236,3 +295,447 @@ func Test_deckRoutes_drawCards(t *testing.T) {
 		})
 	}
 }
+
+func Test_deckRoutes_deal(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := withURLParam(httptest.NewRequest(http.MethodPost, "/v1/decks/id/hands?players=a,b&amount=1", nil), "deckID", "id")
+
+	d := &deckRoutes{
+		deck: &stubDeckManager{
+			deal: func(id string, players []string, amount int) (entity.Deck, map[string]string, error) {
+				if diff := cmp.Diff(players, []string{"a", "b"}); diff != "" || amount != 1 {
+					t.Errorf("deckRoutes.deal() | got players %v and amount %d", players, amount)
+				}
+				return entity.Deck{
+					ID: id,
+					Hands: []entity.Hand{
+						{PlayerID: "a", Token: "token-a", Cards: []entity.Card{blackjackKing}},
+						{PlayerID: "b", Token: "token-b", Cards: []entity.Card{blackjackSix}},
+					},
+				}, map[string]string{"a": "token-a", "b": "token-b"}, nil
+			},
+		},
+	}
+	d.deal(w, r)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("deckRoutes.deal() | got status code %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+
+	var got dealResp
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := dealResp{
+		Tokens: map[string]string{"a": "token-a", "b": "token-b"},
+		Deck: deckResp{
+			ID:    "id",
+			Hands: []handResp{{PlayerID: "a", Count: 1}, {PlayerID: "b", Count: 1}},
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("deckRoutes.deal() | (-got +want):\n%s", diff)
+	}
+}
+
+func Test_deckRoutes_openDeck_Hands(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := withURLParam(httptest.NewRequest(http.MethodGet, "/v1/decks/id", nil), "deckID", "id")
+	r.Header.Set(playerTokenHeader, "token-b")
+
+	d := &deckRoutes{
+		deck: &stubDeckManager{
+			open: func(id string) (entity.Deck, error) {
+				return entity.Deck{
+					ID: id,
+					Hands: []entity.Hand{
+						{PlayerID: "a", Token: "token-a", Cards: []entity.Card{blackjackKing}},
+						{PlayerID: "b", Token: "token-b", Cards: []entity.Card{blackjackSix}},
+					},
+				}, nil
+			},
+		},
+	}
+	d.openDeck(w, r)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	var got deckResp
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := []handResp{{PlayerID: "a", Count: 1}, {PlayerID: "b", Count: 1, Cards: []entity.Card{blackjackSix}}}
+	if diff := cmp.Diff(got.Hands, want); diff != "" {
+		t.Errorf("deckRoutes.openDeck() | hands (-got +want):\n%s", diff)
+	}
+}
+
+func Test_deckRoutes_openDeck_AtVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		statusCode int
+	}{
+		{name: "Success", query: "?at_version=3", statusCode: http.StatusOK},
+		{name: "Unknown Version", query: "?at_version=3", err: usecase.DeckVersionNotFoundErr, statusCode: http.StatusNotFound},
+		{name: "Bad Version", query: "?at_version=last", statusCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := withURLParam(httptest.NewRequest(http.MethodGet, "/v1/decks/id"+tt.query, nil), "deckID", "id")
+
+			d := &deckRoutes{
+				deck: &stubDeckManager{
+					at: func(id string, version int) (entity.Deck, error) {
+						if version != 3 {
+							t.Errorf("deckRoutes.openDeck() | got version %d, want 3", version)
+						}
+						return entity.Deck{ID: id, Version: version}, tt.err
+					},
+				},
+			}
+			d.openDeck(w, r)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Fatalf("deckRoutes.openDeck() | got status code %d, want %d", resp.StatusCode, tt.statusCode)
+			}
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+
+			var got deckResp
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Version != 3 {
+				t.Errorf("deckRoutes.openDeck() | got version %d, want 3", got.Version)
+			}
+		})
+	}
+}
+
+func Test_deckRoutes_events(t *testing.T) {
+	events := []entity.DeckEvent{
+		{DeckID: "id", Version: 1, Type: entity.DeckEventCreated, Cards: []entity.Card{blackjackKing, blackjackSix}},
+		{DeckID: "id", Version: 2, Type: entity.DeckEventMoved, Cards: []entity.Card{blackjackKing}, PlayerID: "a", Token: "token-a"},
+		{DeckID: "id", Version: 3, Type: entity.DeckEventMoved, Cards: []entity.Card{blackjackSix}, PlayerID: "b", Token: "token-b"},
+		{DeckID: "id", Version: 4, Type: entity.DeckEventReturned, Cards: []entity.Card{blackjackKing, blackjackSix}},
+		{DeckID: "id", Version: 5, Type: entity.DeckEventShuffled, Cards: []entity.Card{blackjackSix, blackjackKing}},
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  []deckEventResp
+	}{
+		{
+			name:  "Player",
+			token: "token-b",
+			want: []deckEventResp{
+				{DeckID: "id", Version: 1, Type: entity.DeckEventCreated, Count: 2},
+				{DeckID: "id", Version: 2, Type: entity.DeckEventMoved, Count: 1, PlayerID: "a"},
+				{DeckID: "id", Version: 3, Type: entity.DeckEventMoved, Count: 1, Cards: []entity.Card{blackjackSix}, PlayerID: "b"},
+				{DeckID: "id", Version: 4, Type: entity.DeckEventReturned, Count: 2, Cards: []entity.Card{blackjackKing, blackjackSix}},
+				{DeckID: "id", Version: 5, Type: entity.DeckEventShuffled, Count: 2},
+			},
+		},
+		{
+			name: "No Token",
+			want: []deckEventResp{
+				{DeckID: "id", Version: 1, Type: entity.DeckEventCreated, Count: 2},
+				{DeckID: "id", Version: 2, Type: entity.DeckEventMoved, Count: 1, PlayerID: "a"},
+				{DeckID: "id", Version: 3, Type: entity.DeckEventMoved, Count: 1, PlayerID: "b"},
+				{DeckID: "id", Version: 4, Type: entity.DeckEventReturned, Count: 2, Cards: []entity.Card{blackjackKing, blackjackSix}},
+				{DeckID: "id", Version: 5, Type: entity.DeckEventShuffled, Count: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := withURLParam(httptest.NewRequest(http.MethodGet, "/v1/decks/id/events", nil), "deckID", "id")
+			if tt.token != "" {
+				r.Header.Set(playerTokenHeader, tt.token)
+			}
+
+			d := &deckRoutes{
+				deck: &stubDeckManager{
+					events: func(id string) ([]entity.DeckEvent, error) {
+						return events, nil
+					},
+				},
+			}
+			d.events(w, r)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("deckRoutes.events() | got status code %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Contains(body, []byte("token-")) {
+				t.Errorf("deckRoutes.events() | got a player token in %s", body)
+			}
+
+			var got deckEventsResp
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got.Events, tt.want); diff != "" {
+				t.Errorf("deckRoutes.events() | (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_deckRoutes_deleteDeck(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{name: "Success", statusCode: http.StatusNoContent},
+		{name: "Not Found Error", err: usecase.DeckNotFoundErr, statusCode: http.StatusNotFound},
+		{name: "Forbidden Error", err: usecase.DeckForbiddenErr, statusCode: http.StatusForbidden},
+		{name: "Unknown Error", err: errors.New("error"), statusCode: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted string
+			m := chi.NewRouter()
+			createDeckRoutes(m, &stubDeckManager{
+				delete: func(id string) error {
+					deleted = id
+					return tt.err
+				},
+			}, nil)
+
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/v1/decks/id", nil))
+
+			if w.Code != tt.statusCode {
+				t.Errorf("deckRoutes.deleteDeck() | got status code %d, want %d", w.Code, tt.statusCode)
+			}
+			if deleted != "id" {
+				t.Errorf("deckRoutes.deleteDeck() | deleted deck %q, want %q", deleted, "id")
+			}
+		})
+	}
+}
+
+func Test_createDeckRoutes_Scopes(t *testing.T) {
+	deck := &stubDeckManager{
+		new:       func(bool, []string) (entity.Deck, error) { return entity.Deck{ID: "id"}, nil },
+		open:      func(string) (entity.Deck, error) { return entity.Deck{ID: "id"}, nil },
+		drawCards: func(string, int) ([]entity.Card, error) { return nil, nil },
+		events:    func(string) ([]entity.DeckEvent, error) { return nil, nil },
+		delete:    func(string) error { return nil },
+	}
+	reader := entity.Principal{ID: "alice", Scopes: []string{entity.ScopeDecksRead}}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		principal  entity.Principal
+		statusCode int
+	}{
+		{name: "Read Open", method: http.MethodGet, target: "/v1/decks/id", principal: reader, statusCode: http.StatusOK},
+		{name: "Read Events", method: http.MethodGet, target: "/v1/decks/id/events", principal: reader, statusCode: http.StatusOK},
+		{name: "Read Create", method: http.MethodPost, target: "/v1/decks/", principal: reader, statusCode: http.StatusForbidden},
+		{name: "Read Draw", method: http.MethodGet, target: "/v1/decks/withdrawals/id?amount=1", principal: reader, statusCode: http.StatusForbidden},
+		{name: "Read Delete", method: http.MethodDelete, target: "/v1/decks/id", principal: reader, statusCode: http.StatusForbidden},
+		{
+			name:       "Create",
+			method:     http.MethodPost,
+			target:     "/v1/decks/",
+			principal:  entity.Principal{ID: "alice", Scopes: []string{entity.ScopeDecksCreate}},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Draw",
+			method:     http.MethodGet,
+			target:     "/v1/decks/withdrawals/id?amount=1",
+			principal:  entity.Principal{ID: "alice", Scopes: []string{entity.ScopeDecksDraw}},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Create Delete",
+			method:     http.MethodDelete,
+			target:     "/v1/decks/id",
+			principal:  entity.Principal{ID: "alice", Scopes: []string{entity.ScopeDecksCreate}},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Delete",
+			method:     http.MethodDelete,
+			target:     "/v1/decks/id",
+			principal:  entity.Principal{ID: "alice", Scopes: []string{entity.ScopeDecksDelete}},
+			statusCode: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := chi.NewRouter()
+			createDeckRoutes(m, deck, nil)
+
+			r := httptest.NewRequest(tt.method, tt.target, nil)
+			r = r.WithContext(usecase.WithPrincipal(r.Context(), tt.principal))
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, r)
+
+			if w.Code != tt.statusCode {
+				t.Errorf("%s %s | got status code %d, want %d", tt.method, tt.target, w.Code, tt.statusCode)
+			}
+		})
+	}
+}
+
+func Test_createDeckRoutes_RateLimits(t *testing.T) {
+	deck := &stubDeckManager{
+		new: func(bool, []string) (entity.Deck, error) { return entity.Deck{ID: "id"}, nil },
+		open: func(id string) (entity.Deck, error) {
+			switch id {
+			case "theirs":
+				return entity.Deck{}, usecase.DeckForbiddenErr
+			case "gone":
+				return entity.Deck{}, usecase.DeckNotFoundErr
+			}
+			return entity.Deck{ID: id}, nil
+		},
+		drawCards: func(string, int) ([]entity.Card, error) { return nil, nil },
+		delete:    func(string) error { return nil },
+	}
+	limits := usecase.NewRateLimits(repo.NewRateLimit(), usecase.RateLimitOptions{
+		Create:   entity.RateLimit{PerMinute: 1},
+		Draw:     entity.RateLimit{PerMinute: 60, Burst: 10},
+		DeckDraw: entity.RateLimit{PerMinute: 60, Burst: 1},
+	})
+	m := chi.NewRouter()
+	createDeckRoutes(m, deck, limits)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		statusCode int
+	}{
+		{name: "Create", method: http.MethodPost, target: "/v1/decks/", statusCode: http.StatusCreated},
+		{name: "Create Again", method: http.MethodPost, target: "/v1/decks/", statusCode: http.StatusTooManyRequests},
+		{name: "Draw", method: http.MethodGet, target: "/v1/decks/withdrawals/a?amount=1", statusCode: http.StatusOK},
+		{name: "Draw Same Deck", method: http.MethodGet, target: "/v1/decks/withdrawals/a?amount=1", statusCode: http.StatusTooManyRequests},
+		{name: "Draw Other Deck", method: http.MethodGet, target: "/v1/decks/withdrawals/b?amount=1", statusCode: http.StatusOK},
+		{name: "Draw Unreachable Deck", method: http.MethodGet, target: "/v1/decks/withdrawals/theirs?amount=1", statusCode: http.StatusForbidden},
+		{name: "Draw Unreachable Deck Again", method: http.MethodGet, target: "/v1/decks/withdrawals/theirs?amount=1", statusCode: http.StatusForbidden},
+		{name: "Draw Missing Deck", method: http.MethodGet, target: "/v1/decks/withdrawals/gone?amount=1", statusCode: http.StatusNotFound},
+		{name: "Draw Missing Deck Again", method: http.MethodGet, target: "/v1/decks/withdrawals/gone?amount=1", statusCode: http.StatusNotFound},
+		{name: "Delete", method: http.MethodDelete, target: "/v1/decks/a", statusCode: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if w.Code != tt.statusCode {
+				t.Fatalf("%s %s | got status code %d, want %d", tt.method, tt.target, w.Code, tt.statusCode)
+			}
+			if tt.statusCode == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+				t.Errorf("%s %s | no Retry-After header on a 429", tt.method, tt.target)
+			}
+		})
+	}
+}
+
+func Test_creating(t *testing.T) {
+	deck := &stubDeckManager{
+		new: func(bool, []string) (entity.Deck, error) { return entity.Deck{ID: "id"}, nil },
+	}
+	klondike := &stubKlondikeManager{
+		new: func(seed int64, drawCount int) (entity.KlondikeGame, error) {
+			return entity.KlondikeGame{ID: "id", Seed: seed, DrawCount: drawCount}, nil
+		},
+	}
+	limits := usecase.NewRateLimits(repo.NewRateLimit(), usecase.RateLimitOptions{
+		Create: entity.RateLimit{PerMinute: 60, Burst: 2},
+	})
+	m := chi.NewRouter()
+	createDeckRoutes(m, deck, limits)
+	createKlondikeRoutes(m, klondike, limits)
+
+	// Games open decks of their own, so they take from the
+	// same budget as the decks created directly.
+	tests := []struct {
+		name       string
+		target     string
+		statusCode int
+	}{
+		{name: "Deck", target: "/v1/decks/", statusCode: http.StatusCreated},
+		{name: "Game", target: "/v1/games/klondike/", statusCode: http.StatusCreated},
+		{name: "Game Again", target: "/v1/games/klondike/", statusCode: http.StatusTooManyRequests},
+		{name: "Deck Again", target: "/v1/decks/", statusCode: http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.target, nil))
+
+			if w.Code != tt.statusCode {
+				t.Fatalf("POST %s | got status code %d, want %d", tt.target, w.Code, tt.statusCode)
+			}
+		})
+	}
+}
+
+func Test_drawing(t *testing.T) {
+	deck := &stubDeckManager{
+		open:      func(id string) (entity.Deck, error) { return entity.Deck{ID: id}, nil },
+		drawCards: func(string, int) ([]entity.Card, error) { return nil, nil },
+	}
+	klondike := &stubKlondikeManager{
+		undo: func(id string) (entity.KlondikeGame, error) { return entity.KlondikeGame{ID: id}, nil },
+	}
+	limits := usecase.NewRateLimits(repo.NewRateLimit(), usecase.RateLimitOptions{
+		Draw: entity.RateLimit{PerMinute: 60, Burst: 2},
+	})
+	m := chi.NewRouter()
+	createDeckRoutes(m, deck, limits)
+	createKlondikeRoutes(m, klondike, limits)
+
+	// Game moves draw from the decks of the games, so they
+	// take from the same budget as the draws from decks.
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		statusCode int
+	}{
+		{name: "Deck", method: http.MethodGet, target: "/v1/decks/withdrawals/a?amount=1", statusCode: http.StatusOK},
+		{name: "Game", method: http.MethodPost, target: "/v1/games/klondike/id/undos", statusCode: http.StatusOK},
+		{name: "Game Again", method: http.MethodPost, target: "/v1/games/klondike/id/undos", statusCode: http.StatusTooManyRequests},
+		{name: "Deck Again", method: http.MethodGet, target: "/v1/decks/withdrawals/b?amount=1", statusCode: http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if w.Code != tt.statusCode {
+				t.Fatalf("%s %s | got status code %d, want %d", tt.method, tt.target, w.Code, tt.statusCode)
+			}
+		})
+	}
+}
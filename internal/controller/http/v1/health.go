@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/lualfe/card-game/internal/controller/http/response"
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+// createHealthRoutes mounts the probes. They live outside
+// the versioned API, where orchestrators look for them.
+func createHealthRoutes(m *chi.Mux, health usecase.HealthManager) {
+	hr := &healthRoutes{health}
+
+	m.Get("/healthz", hr.live)
+	m.Get("/readyz", hr.ready)
+}
+
+type healthRoutes struct {
+	health usecase.HealthManager
+}
+
+type healthCheckResp struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+type healthResp struct {
+	Status string            `json:"status"`
+	Checks []healthCheckResp `json:"checks"`
+}
+
+// live answers whether the application is alive, with 503
+// when a liveness check fails.
+func (h *healthRoutes) live(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, h.health.Live(r.Context()))
+}
+
+// ready answers whether the application can take requests,
+// with 503 when a readiness check fails or it's shutting down.
+func (h *healthRoutes) ready(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, h.health.Ready(r.Context()))
+}
+
+func writeHealth(w http.ResponseWriter, report entity.HealthReport) {
+	resp := healthResp{
+		Status: report.Status,
+		Checks: []healthCheckResp{},
+	}
+	for _, c := range report.Checks {
+		resp.Checks = append(resp.Checks, healthCheckResp{
+			Name:       c.Name,
+			Status:     c.Status,
+			Error:      c.Error,
+			DurationMS: float64(c.Duration.Microseconds()) / 1000,
+		})
+	}
+
+	status := http.StatusOK
+	if report.Status != entity.HealthUp {
+		status = http.StatusServiceUnavailable
+	}
+	response.JSON(w, resp, status)
+}
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+type stubHealthManager struct {
+	live, ready entity.HealthReport
+}
+
+func (s *stubHealthManager) Live(context.Context) entity.HealthReport { return s.live }
+
+func (s *stubHealthManager) Ready(context.Context) entity.HealthReport { return s.ready }
+
+func Test_healthRoutes(t *testing.T) {
+	health := &stubHealthManager{
+		live: entity.HealthReport{
+			Status: entity.HealthUp,
+			Checks: []entity.HealthCheckResult{
+				{Name: "deck expiry", Status: entity.HealthUp, Duration: 1500 * time.Microsecond},
+			},
+		},
+		ready: entity.HealthReport{
+			Status: entity.HealthDown,
+			Checks: []entity.HealthCheckResult{
+				{Name: "shutdown", Status: entity.HealthDown, Error: "shutting down"},
+				{Name: "deck store", Status: entity.HealthUp},
+			},
+		},
+	}
+	m := chi.NewRouter()
+	createHealthRoutes(m, health)
+
+	tests := []struct {
+		path       string
+		statusCode int
+		want       healthResp
+	}{
+		{
+			path:       "/healthz",
+			statusCode: http.StatusOK,
+			want: healthResp{
+				Status: entity.HealthUp,
+				Checks: []healthCheckResp{{Name: "deck expiry", Status: entity.HealthUp, DurationMS: 1.5}},
+			},
+		},
+		{
+			path:       "/readyz",
+			statusCode: http.StatusServiceUnavailable,
+			want: healthResp{
+				Status: entity.HealthDown,
+				Checks: []healthCheckResp{
+					{Name: "shutdown", Status: entity.HealthDown, Error: "shutting down"},
+					{Name: "deck store", Status: entity.HealthUp},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.statusCode {
+				t.Fatalf("healthRoutes | got status %d, want %d", w.Code, tt.statusCode)
+			}
+			var got healthResp
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("healthRoutes | (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
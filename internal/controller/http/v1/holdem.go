@@ -0,0 +1,284 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/lualfe/card-game/internal/controller/http/middleware"
+	"github.com/lualfe/card-game/internal/controller/http/response"
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+// playerTokenHeader carries the token a player got when
+// sitting down, used to act and to see its own cards.
+const playerTokenHeader = "X-Player-Token"
+
+func createHoldemRoutes(m chi.Router, holdem usecase.HoldemManager, limits usecase.RateLimiter) {
+	hr := &holdemRoutes{holdem}
+	play := drawing(limits)
+
+	m.Route("/v1/games/holdem", func(r chi.Router) {
+		r.With(creating(limits)...).Post("/", hr.newTable)
+		r.With(middleware.Scope(entity.ScopeDecksRead)).Get("/{tableID}", hr.table)
+		r.With(play...).Post("/{tableID}/seats", hr.sit)
+		r.With(play...).Post("/{tableID}/hands", hr.startHand)
+		r.With(play...).Post("/{tableID}/actions", hr.act)
+	})
+}
+
+type holdemRoutes struct {
+	holdem usecase.HoldemManager
+}
+
+type holdemSeatResp struct {
+	PlayerID  string        `json:"player_id"`
+	Stack     int           `json:"stack"`
+	Hole      []entity.Card `json:"hole,omitempty"`
+	HoleCount int           `json:"hole_count"`
+	Bet       int           `json:"bet"`
+	InHand    bool          `json:"in_hand"`
+	Folded    bool          `json:"folded"`
+	AllIn     bool          `json:"all_in"`
+}
+
+type holdemTableResp struct {
+	ID         string                `json:"table_id"`
+	SmallBlind int                   `json:"small_blind"`
+	BigBlind   int                   `json:"big_blind"`
+	HandNumber int                   `json:"hand_number"`
+	Button     int                   `json:"button"`
+	Street     string                `json:"street"`
+	Seats      []holdemSeatResp      `json:"seats"`
+	Board      []entity.Card         `json:"board"`
+	Pots       []entity.HoldemPot    `json:"pots"`
+	CurrentBet int                   `json:"current_bet"`
+	MinRaise   int                   `json:"min_raise"`
+	ToAct      int                   `json:"to_act"`
+	Winners    []entity.HoldemWinner `json:"winners"`
+}
+
+type holdemSitResp struct {
+	Token string          `json:"token"`
+	Table holdemTableResp `json:"table"`
+}
+
+// newHoldemTableResp builds the table view for the player owning
+// the token. Other players' hole cards are only shown at showdown.
+func newHoldemTableResp(table entity.HoldemTable, token string) holdemTableResp {
+	resp := holdemTableResp{
+		ID:         table.ID,
+		SmallBlind: table.SmallBlind,
+		BigBlind:   table.BigBlind,
+		HandNumber: table.HandNumber,
+		Button:     table.Button,
+		Street:     table.Street,
+		Seats:      []holdemSeatResp{},
+		Board:      table.Board,
+		Pots:       table.Pots,
+		CurrentBet: table.CurrentBet,
+		MinRaise:   table.MinRaise,
+		ToAct:      table.ToAct,
+		Winners:    table.Winners,
+	}
+	for _, s := range table.Seats {
+		seat := holdemSeatResp{
+			PlayerID:  s.PlayerID,
+			Stack:     s.Stack,
+			HoleCount: len(s.Hole),
+			Bet:       s.Bet,
+			InHand:    s.InHand,
+			Folded:    s.Folded,
+			AllIn:     s.AllIn,
+		}
+		if (token != "" && s.Token == token) || (table.Showdown && s.InHand && !s.Folded) {
+			seat.Hole = s.Hole
+		}
+		resp.Seats = append(resp.Seats, seat)
+	}
+
+	return resp
+}
+
+// newTable godoc
+// @Summary      Creates a Hold'em table.
+// @Description  Creates an empty no-limit Texas Hold'em table.
+// @Produce      json
+// @Param        small_blind  query     int  true  "Small blind"
+// @Param        big_blind    query     int  true  "Big blind"
+// @Success      201          {object}  holdemTableResp
+// @Failure      400          {object}  response.Error
+// @Failure      403          {object}  response.Error
+// @Failure      429          {object}  response.Error
+// @Failure      500          {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/holdem [post]
+func (h *holdemRoutes) newTable(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	smallBlind, err := strconv.Atoi(q.Get("small_blind"))
+	if err != nil {
+		response.JSONError(w, "small_blind must be a number", http.StatusBadRequest)
+		return
+	}
+	bigBlind, err := strconv.Atoi(q.Get("big_blind"))
+	if err != nil {
+		response.JSONError(w, "big_blind must be a number", http.StatusBadRequest)
+		return
+	}
+
+	table, err := h.holdem.NewTable(r.Context(), smallBlind, bigBlind)
+	if err != nil {
+		holdemError(w, err)
+		return
+	}
+
+	response.JSON(w, newHoldemTableResp(table, ""), http.StatusCreated)
+}
+
+// table godoc
+// @Summary      Shows a Hold'em table.
+// @Description  Shows a Hold'em table, hiding other players' hole cards until showdown.
+// @Produce      json
+// @Param        id              path      string  true   "Table id"
+// @Param        X-Player-Token  header    string  false  "Token received when sitting down"
+// @Success      200             {object}  holdemTableResp
+// @Failure      403             {object}  response.Error
+// @Failure      404             {object}  response.Error
+// @Failure      500             {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/holdem/{id} [get]
+func (h *holdemRoutes) table(w http.ResponseWriter, r *http.Request) {
+	table, err := h.holdem.Table(r.Context(), chi.URLParam(r, "tableID"))
+	if err != nil {
+		holdemError(w, err)
+		return
+	}
+
+	response.JSON(w, newHoldemTableResp(table, r.Header.Get(playerTokenHeader)), http.StatusOK)
+}
+
+// sit godoc
+// @Summary      Sits a player at a Hold'em table.
+// @Description  Sits a player with a buy-in and returns the token used to act.
+// @Produce      json
+// @Param        id         path      string  true  "Table id"
+// @Param        player_id  query     string  true  "Player id"
+// @Param        buy_in     query     int     true  "Chips to sit with"
+// @Success      201        {object}  holdemSitResp
+// @Failure      400        {object}  response.Error
+// @Failure      403        {object}  response.Error
+// @Failure      404        {object}  response.Error
+// @Failure      409        {object}  response.Error
+// @Failure      429        {object}  response.Error
+// @Failure      500        {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/holdem/{id}/seats [post]
+func (h *holdemRoutes) sit(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	buyIn, err := strconv.Atoi(q.Get("buy_in"))
+	if err != nil {
+		response.JSONError(w, "buy_in must be a number", http.StatusBadRequest)
+		return
+	}
+
+	table, token, err := h.holdem.Sit(r.Context(), chi.URLParam(r, "tableID"), q.Get("player_id"), buyIn)
+	if err != nil {
+		holdemError(w, err)
+		return
+	}
+
+	resp := holdemSitResp{
+		Token: token,
+		Table: newHoldemTableResp(table, token),
+	}
+
+	response.JSON(w, resp, http.StatusCreated)
+}
+
+// startHand godoc
+// @Summary      Starts a Hold'em hand.
+// @Description  Moves the button, posts blinds and deals hole cards from a new deck.
+// @Produce      json
+// @Param        id              path      string  true   "Table id"
+// @Param        X-Player-Token  header    string  false  "Token received when sitting down"
+// @Success      200             {object}  holdemTableResp
+// @Failure      403             {object}  response.Error
+// @Failure      404             {object}  response.Error
+// @Failure      409             {object}  response.Error
+// @Failure      429             {object}  response.Error
+// @Failure      500             {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/holdem/{id}/hands [post]
+func (h *holdemRoutes) startHand(w http.ResponseWriter, r *http.Request) {
+	table, err := h.holdem.StartHand(r.Context(), chi.URLParam(r, "tableID"))
+	if err != nil {
+		holdemError(w, err)
+		return
+	}
+
+	response.JSON(w, newHoldemTableResp(table, r.Header.Get(playerTokenHeader)), http.StatusOK)
+}
+
+// act godoc
+// @Summary      Plays a Hold'em action.
+// @Description  Plays an action for the player owning the token. Bet and raise amounts are the total to raise to.
+// @Produce      json
+// @Param        id              path      string  true   "Table id"
+// @Param        X-Player-Token  header    string  true   "Token received when sitting down"
+// @Param        action          query     string  true   "Action"  Enums(fold, check, call, bet, raise, allin)
+// @Param        amount          query     int     false  "Amount to raise to"
+// @Success      200             {object}  holdemTableResp
+// @Failure      400             {object}  response.Error
+// @Failure      403             {object}  response.Error
+// @Failure      404             {object}  response.Error
+// @Failure      409             {object}  response.Error
+// @Failure      429             {object}  response.Error
+// @Failure      500             {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/holdem/{id}/actions [post]
+func (h *holdemRoutes) act(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	amount := 0
+	if am := q.Get("amount"); am != "" {
+		v, err := strconv.Atoi(am)
+		if err != nil {
+			response.JSONError(w, "amount must be a number", http.StatusBadRequest)
+			return
+		}
+		amount = v
+	}
+
+	token := r.Header.Get(playerTokenHeader)
+	table, err := h.holdem.Act(r.Context(), chi.URLParam(r, "tableID"), token, q.Get("action"), amount)
+	if err != nil {
+		holdemError(w, err)
+		return
+	}
+
+	response.JSON(w, newHoldemTableResp(table, token), http.StatusOK)
+}
+
+func holdemError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.HoldemTableNotFoundErr):
+		response.JSONError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, usecase.GameForbiddenErr):
+		response.JSONError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, usecase.HoldemPlayerNotFoundErr):
+		response.JSONError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, usecase.HoldemInvalidOptionsErr):
+		response.JSONError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, usecase.HoldemIllegalActionErr):
+		response.JSONError(w, err.Error(), http.StatusConflict)
+	default:
+		response.JSONError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,229 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+type stubHoldemManager struct {
+	newTable  func(smallBlind, bigBlind int) (entity.HoldemTable, error)
+	table     func(id string) (entity.HoldemTable, error)
+	sit       func(id, playerID string, buyIn int) (entity.HoldemTable, string, error)
+	startHand func(id string) (entity.HoldemTable, error)
+	act       func(id, token, action string, amount int) (entity.HoldemTable, error)
+}
+
+func (s *stubHoldemManager) NewTable(_ context.Context, smallBlind, bigBlind int) (entity.HoldemTable, error) {
+	return s.newTable(smallBlind, bigBlind)
+}
+
+func (s *stubHoldemManager) Table(_ context.Context, id string) (entity.HoldemTable, error) {
+	return s.table(id)
+}
+
+func (s *stubHoldemManager) Sit(_ context.Context, id, playerID string, buyIn int) (entity.HoldemTable, string, error) {
+	return s.sit(id, playerID, buyIn)
+}
+
+func (s *stubHoldemManager) StartHand(_ context.Context, id string) (entity.HoldemTable, error) {
+	return s.startHand(id)
+}
+
+func (s *stubHoldemManager) Act(_ context.Context, id, token, action string, amount int) (entity.HoldemTable, error) {
+	return s.act(id, token, action, amount)
+}
+
+func Test_holdemRoutes_table(t *testing.T) {
+	hole := []entity.Card{blackjackKing, blackjackSix}
+	playing := entity.HoldemTable{
+		Street: entity.HoldemStreetFlop,
+		Seats: []entity.HoldemSeat{
+			{PlayerID: "a", Token: "token-a", Hole: hole, InHand: true},
+			{PlayerID: "b", Token: "token-b", Hole: hole, InHand: true},
+		},
+	}
+	showdown := entity.HoldemTable{
+		Street:   entity.HoldemStreetWaiting,
+		Showdown: true,
+		Seats: []entity.HoldemSeat{
+			{PlayerID: "a", Token: "token-a", Hole: hole, InHand: true},
+			{PlayerID: "b", Token: "token-b", Hole: hole, InHand: true, Folded: true},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		table      entity.HoldemTable
+		err        error
+		statusCode int
+		wantHoles  [][]entity.Card
+	}{
+		{
+			name:       "Own Cards Only",
+			token:      "token-a",
+			table:      playing,
+			statusCode: http.StatusOK,
+			wantHoles:  [][]entity.Card{hole, nil},
+		},
+		{
+			name:       "Spectator",
+			table:      playing,
+			statusCode: http.StatusOK,
+			wantHoles:  [][]entity.Card{nil, nil},
+		},
+		{
+			name:       "Showdown Reveals Live Hands",
+			table:      showdown,
+			statusCode: http.StatusOK,
+			wantHoles:  [][]entity.Card{hole, nil},
+		},
+		{
+			name:       "Not Found Error",
+			err:        usecase.HoldemTableNotFoundErr,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Unknown Error",
+			err:        errors.New("error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/v1/games/holdem/id", nil)
+			r.Header.Set(playerTokenHeader, tt.token)
+
+			h := &holdemRoutes{
+				holdem: &stubHoldemManager{
+					table: func(id string) (entity.HoldemTable, error) {
+						return tt.table, tt.err
+					},
+				},
+			}
+			h.table(w, r)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if code := resp.StatusCode; code != tt.statusCode {
+				t.Fatalf("holdemRoutes.table() | got status code %d, want %d", code, tt.statusCode)
+			}
+
+			if tt.err == nil {
+				var got holdemTableResp
+				if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+					t.Fatal(err)
+				}
+
+				var holes [][]entity.Card
+				for _, s := range got.Seats {
+					holes = append(holes, s.Hole)
+					if s.HoleCount != len(hole) {
+						t.Errorf("holdemRoutes.table() | got hole count %d, want %d", s.HoleCount, len(hole))
+					}
+				}
+				if diff := cmp.Diff(holes, tt.wantHoles); diff != "" {
+					t.Fatalf("holdemRoutes.table() | holes (-got +want):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_holdemRoutes_act(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		err        error
+		statusCode int
+	}{
+		{
+			name:       "Success",
+			target:     "/v1/games/holdem/id/actions?action=raise&amount=10",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Bad Amount",
+			target:     "/v1/games/holdem/id/actions?action=raise&amount=ten",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Unknown Player",
+			target:     "/v1/games/holdem/id/actions?action=fold",
+			err:        usecase.HoldemPlayerNotFoundErr,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Illegal Action",
+			target:     "/v1/games/holdem/id/actions?action=check",
+			err:        usecase.HoldemIllegalActionErr,
+			statusCode: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			r.Header.Set(playerTokenHeader, "token")
+
+			h := &holdemRoutes{
+				holdem: &stubHoldemManager{
+					act: func(id, token, action string, amount int) (entity.HoldemTable, error) {
+						if token != "token" {
+							t.Errorf("holdemRoutes.act() | got token %s, want token", token)
+						}
+						return entity.HoldemTable{}, tt.err
+					},
+				},
+			}
+			h.act(w, r)
+
+			if code := w.Result().StatusCode; code != tt.statusCode {
+				t.Fatalf("holdemRoutes.act() | got status code %d, want %d", code, tt.statusCode)
+			}
+		})
+	}
+}
+
+func Test_holdemRoutes_sit(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/v1/games/holdem/id/seats?player_id=a&buy_in=100", nil)
+
+	h := &holdemRoutes{
+		holdem: &stubHoldemManager{
+			sit: func(id, playerID string, buyIn int) (entity.HoldemTable, string, error) {
+				if playerID != "a" || buyIn != 100 {
+					t.Errorf("holdemRoutes.sit() | got player %s with %d, want a with 100", playerID, buyIn)
+				}
+				return entity.HoldemTable{}, "token", nil
+			},
+		},
+	}
+	h.sit(w, r)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("holdemRoutes.sit() | got status code %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+
+	var got holdemSitResp
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Token != "token" {
+		t.Errorf("holdemRoutes.sit() | got token %s, want token", got.Token)
+	}
+}
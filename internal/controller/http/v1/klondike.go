@@ -0,0 +1,342 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/lualfe/card-game/internal/controller/http/middleware"
+	"github.com/lualfe/card-game/internal/controller/http/response"
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+// klondikeDailyTimeout bounds the search of a daily deal,
+// so it ends within the default write timeout of the
+// server rather than past it.
+const klondikeDailyTimeout = 20 * time.Second
+
+func createKlondikeRoutes(m chi.Router, klondike usecase.KlondikeManager, limits usecase.RateLimiter) {
+	kr := &klondikeRoutes{klondike}
+	var (
+		play = drawing(limits)
+		read = middleware.Scope(entity.ScopeDecksRead)
+	)
+
+	m.Route("/v1/games/klondike", func(r chi.Router) {
+		r.With(creating(limits)...).Post("/", kr.newGame)
+		r.With(read).Get("/solutions", kr.solve)
+		r.With(read).Get("/daily", kr.daily)
+		r.With(read).Get("/{gameID}", kr.game)
+		r.With(play...).Post("/{gameID}/moves", kr.move)
+		r.With(play...).Post("/{gameID}/undos", kr.undo)
+	})
+}
+
+type klondikeRoutes struct {
+	klondike usecase.KlondikeManager
+}
+
+type klondikeStackResp struct {
+	Cards    []entity.Card `json:"cards"`
+	FaceDown int           `json:"face_down"`
+}
+
+type klondikeGameResp struct {
+	ID          string              `json:"game_id"`
+	Seed        int64               `json:"seed"`
+	DrawCount   int                 `json:"draw_count"`
+	Stock       int                 `json:"stock"`
+	Waste       []entity.Card       `json:"waste"`
+	Foundations [][]entity.Card     `json:"foundations"`
+	Tableau     []klondikeStackResp `json:"tableau"`
+	Moves       int                 `json:"moves"`
+	CanUndo     bool                `json:"can_undo"`
+	Won         bool                `json:"won"`
+}
+
+type klondikeDailyResp struct {
+	Date      string `json:"date"`
+	Seed      int64  `json:"seed"`
+	DrawCount int    `json:"draw_count"`
+}
+
+// newKlondikeGameResp builds the game view, hiding the
+// stock and the face down tableau cards.
+func newKlondikeGameResp(game entity.KlondikeGame) klondikeGameResp {
+	resp := klondikeGameResp{
+		ID:          game.ID,
+		Seed:        game.Seed,
+		DrawCount:   game.DrawCount,
+		Stock:       len(game.Stock),
+		Waste:       game.Waste,
+		Foundations: game.Foundations,
+		Moves:       game.Moves,
+		CanUndo:     len(game.History) > 0,
+		Won:         game.Won,
+	}
+	for _, t := range game.Tableau {
+		resp.Tableau = append(resp.Tableau, klondikeStackResp{
+			Cards:    t.Cards[t.FaceDown:],
+			FaceDown: t.FaceDown,
+		})
+	}
+
+	return resp
+}
+
+// newGame godoc
+// @Summary      Deals a Klondike game.
+// @Description  Deals a Klondike game. The same seed always deals the same game.
+// @Produce      json
+// @Param        seed  query     int  false  "Deal seed, random when missing."
+// @Param        draw  query     int  false  "Cards turned from the stock, 1 or 3."  default(1)
+// @Success      201   {object}  klondikeGameResp
+// @Failure      400   {object}  response.Error
+// @Failure      403   {object}  response.Error
+// @Failure      429   {object}  response.Error
+// @Failure      500   {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/klondike [post]
+func (k *klondikeRoutes) newGame(w http.ResponseWriter, r *http.Request) {
+	seed, draw, ok := klondikeDeal(w, r)
+	if !ok {
+		return
+	}
+	if r.URL.Query().Get("seed") == "" {
+		seed = time.Now().UnixNano()
+	}
+
+	game, err := k.klondike.New(r.Context(), seed, draw)
+	if err != nil {
+		klondikeError(w, err)
+		return
+	}
+
+	response.JSON(w, newKlondikeGameResp(game), http.StatusCreated)
+}
+
+// game godoc
+// @Summary      Shows a Klondike game.
+// @Description  Shows a Klondike game. The stock and the face down cards are hidden.
+// @Produce      json
+// @Param        id   path      string  true  "Game id"
+// @Success      200  {object}  klondikeGameResp
+// @Failure      403  {object}  response.Error
+// @Failure      404  {object}  response.Error
+// @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/klondike/{id} [get]
+func (k *klondikeRoutes) game(w http.ResponseWriter, r *http.Request) {
+	game, err := k.klondike.Game(r.Context(), chi.URLParam(r, "gameID"))
+	if err != nil {
+		klondikeError(w, err)
+		return
+	}
+
+	response.JSON(w, newKlondikeGameResp(game), http.StatusOK)
+}
+
+// move godoc
+// @Summary      Plays a Klondike move.
+// @Description  Draws from the stock or moves cards between the waste, tableau and foundation piles.
+// @Produce      json
+// @Param        id          path      string  true   "Game id"
+// @Param        action      query     string  true   "Move action"  Enums(draw, move)
+// @Param        from        query     string  false  "Source pile"  Enums(waste, tableau, foundation)
+// @Param        from_index  query     int     false  "Source pile index"
+// @Param        to          query     string  false  "Target pile"  Enums(tableau, foundation)
+// @Param        to_index    query     int     false  "Target pile index"
+// @Param        count       query     int     false  "Cards moved from a tableau pile."  default(1)
+// @Success      200         {object}  klondikeGameResp
+// @Failure      400         {object}  response.Error
+// @Failure      403         {object}  response.Error
+// @Failure      404         {object}  response.Error
+// @Failure      409         {object}  response.Error
+// @Failure      429         {object}  response.Error
+// @Failure      500         {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/klondike/{id}/moves [post]
+func (k *klondikeRoutes) move(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	move := entity.KlondikeMove{
+		Action: q.Get("action"),
+		From:   q.Get("from"),
+		To:     q.Get("to"),
+	}
+
+	for name, dst := range map[string]*int{"from_index": &move.FromIndex, "to_index": &move.ToIndex, "count": &move.Count} {
+		v := q.Get(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			response.JSONError(w, name+" must be a number", http.StatusBadRequest)
+			return
+		}
+		*dst = n
+	}
+
+	game, err := k.klondike.Move(r.Context(), chi.URLParam(r, "gameID"), move)
+	if err != nil {
+		klondikeError(w, err)
+		return
+	}
+
+	response.JSON(w, newKlondikeGameResp(game), http.StatusOK)
+}
+
+// undo godoc
+// @Summary      Takes back a Klondike move.
+// @Description  Takes back the last move of a Klondike game.
+// @Produce      json
+// @Param        id   path      string  true  "Game id"
+// @Success      200  {object}  klondikeGameResp
+// @Failure      403  {object}  response.Error
+// @Failure      404  {object}  response.Error
+// @Failure      409  {object}  response.Error
+// @Failure      429  {object}  response.Error
+// @Failure      500  {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/klondike/{id}/undos [post]
+func (k *klondikeRoutes) undo(w http.ResponseWriter, r *http.Request) {
+	game, err := k.klondike.Undo(r.Context(), chi.URLParam(r, "gameID"))
+	if err != nil {
+		klondikeError(w, err)
+		return
+	}
+
+	response.JSON(w, newKlondikeGameResp(game), http.StatusOK)
+}
+
+// solve godoc
+// @Summary      Solves a Klondike deal.
+// @Description  Tells whether the deal of a seed is winnable, unwinnable or unknown within the solver budget.
+// @Produce      json
+// @Param        seed  query     int  true   "Deal seed"
+// @Param        draw  query     int  false  "Cards turned from the stock, 1 or 3."  default(1)
+// @Success      200   {object}  usecase.KlondikeSolution
+// @Failure      400   {object}  response.Error
+// @Failure      403   {object}  response.Error
+// @Failure      500   {object}  response.Error
+// @Failure      503   {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/klondike/solutions [get]
+func (k *klondikeRoutes) solve(w http.ResponseWriter, r *http.Request) {
+	seed, draw, ok := klondikeDeal(w, r)
+	if !ok {
+		return
+	}
+	if r.URL.Query().Get("seed") == "" {
+		response.JSONError(w, "seed is required", http.StatusBadRequest)
+		return
+	}
+
+	solution, err := k.klondike.Solve(seed, draw)
+	if err != nil {
+		klondikeError(w, err)
+		return
+	}
+
+	response.JSON(w, solution, http.StatusOK)
+}
+
+// daily godoc
+// @Summary      Shows the daily Klondike deal.
+// @Description  Shows the seed of the day, proven winnable by the solver. A search that can't end within 20 seconds gets a 503.
+// @Produce      json
+// @Param        date  query     string  false  "Day as YYYY-MM-DD, today when missing."
+// @Param        draw  query     int     false  "Cards turned from the stock, 1 or 3."  default(1)
+// @Success      200   {object}  klondikeDailyResp
+// @Failure      400   {object}  response.Error
+// @Failure      403   {object}  response.Error
+// @Failure      500   {object}  response.Error
+// @Failure      503   {object}  response.Error
+// @Security     APIKey
+// @Security     Bearer
+// @Router       /games/klondike/daily [get]
+func (k *klondikeRoutes) daily(w http.ResponseWriter, r *http.Request) {
+	_, draw, ok := klondikeDeal(w, r)
+	if !ok {
+		return
+	}
+
+	day := time.Now().UTC()
+	if v := r.URL.Query().Get("date"); v != "" {
+		var err error
+		if day, err = time.Parse("2006-01-02", v); err != nil {
+			response.JSONError(w, "date must be formatted as YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), klondikeDailyTimeout)
+	defer cancel()
+
+	solution, err := k.klondike.DailySeed(ctx, day, draw)
+	if err != nil {
+		klondikeError(w, err)
+		return
+	}
+
+	response.JSON(w, klondikeDailyResp{
+		Date:      day.Format("2006-01-02"),
+		Seed:      solution.Seed,
+		DrawCount: solution.DrawCount,
+	}, http.StatusOK)
+}
+
+// klondikeDeal reads the seed and draw count of a request,
+// answering with an error when they aren't numbers.
+func klondikeDeal(w http.ResponseWriter, r *http.Request) (int64, int, bool) {
+	q := r.URL.Query()
+
+	var seed int64
+	if v := q.Get("seed"); v != "" {
+		var err error
+		if seed, err = strconv.ParseInt(v, 10, 64); err != nil {
+			response.JSONError(w, "seed must be a number", http.StatusBadRequest)
+			return 0, 0, false
+		}
+	}
+
+	draw := 1
+	if v := q.Get("draw"); v != "" {
+		var err error
+		if draw, err = strconv.Atoi(v); err != nil {
+			response.JSONError(w, "draw must be a number", http.StatusBadRequest)
+			return 0, 0, false
+		}
+	}
+
+	return seed, draw, true
+}
+
+func klondikeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.KlondikeGameNotFoundErr):
+		response.JSONError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, usecase.GameForbiddenErr):
+		response.JSONError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, usecase.KlondikeInvalidOptionsErr):
+		response.JSONError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, usecase.KlondikeIllegalMoveErr):
+		response.JSONError(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, usecase.KlondikeSolverBusyErr):
+		response.JSONError(w, err.Error(), http.StatusServiceUnavailable)
+	case errors.Is(err, context.DeadlineExceeded):
+		response.JSONError(w, "the search took too long", http.StatusServiceUnavailable)
+	default:
+		response.JSONError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,245 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+type stubKlondikeManager struct {
+	new       func(seed int64, drawCount int) (entity.KlondikeGame, error)
+	game      func(id string) (entity.KlondikeGame, error)
+	move      func(id string, move entity.KlondikeMove) (entity.KlondikeGame, error)
+	undo      func(id string) (entity.KlondikeGame, error)
+	solve     func(seed int64, drawCount int) (usecase.KlondikeSolution, error)
+	dailySeed func(day time.Time, drawCount int) (usecase.KlondikeSolution, error)
+}
+
+func (s *stubKlondikeManager) New(_ context.Context, seed int64, drawCount int) (entity.KlondikeGame, error) {
+	return s.new(seed, drawCount)
+}
+
+func (s *stubKlondikeManager) Game(_ context.Context, id string) (entity.KlondikeGame, error) {
+	return s.game(id)
+}
+
+func (s *stubKlondikeManager) Move(_ context.Context, id string, move entity.KlondikeMove) (entity.KlondikeGame, error) {
+	return s.move(id, move)
+}
+
+func (s *stubKlondikeManager) Undo(_ context.Context, id string) (entity.KlondikeGame, error) {
+	return s.undo(id)
+}
+
+func (s *stubKlondikeManager) Solve(seed int64, drawCount int) (usecase.KlondikeSolution, error) {
+	return s.solve(seed, drawCount)
+}
+
+func (s *stubKlondikeManager) DailySeed(_ context.Context, day time.Time, drawCount int) (usecase.KlondikeSolution, error) {
+	return s.dailySeed(day, drawCount)
+}
+
+func Test_klondikeRoutes_newGame(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/v1/games/klondike?seed=7&draw=3", nil)
+
+	k := &klondikeRoutes{
+		klondike: &stubKlondikeManager{
+			new: func(seed int64, drawCount int) (entity.KlondikeGame, error) {
+				if seed != 7 || drawCount != 3 {
+					t.Errorf("klondikeRoutes.newGame() | got seed %d and draw %d, want 7 and 3", seed, drawCount)
+				}
+				return entity.KlondikeGame{
+					ID:        "id",
+					Seed:      seed,
+					DrawCount: drawCount,
+					KlondikeState: entity.KlondikeState{
+						Stock:   []entity.Card{blackjackSix},
+						Tableau: []entity.KlondikeStack{{Cards: []entity.Card{blackjackSix, blackjackKing}, FaceDown: 1}},
+					},
+				}, nil
+			},
+		},
+	}
+	k.newGame(w, r)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("klondikeRoutes.newGame() | got status code %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+
+	var got klondikeGameResp
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := []klondikeStackResp{{Cards: []entity.Card{blackjackKing}, FaceDown: 1}}
+	if diff := cmp.Diff(got.Tableau, want); diff != "" {
+		t.Errorf("klondikeRoutes.newGame() | tableau (-got +want):\n%s", diff)
+	}
+	if got.Stock != 1 {
+		t.Errorf("klondikeRoutes.newGame() | got stock %d, want 1", got.Stock)
+	}
+}
+
+func Test_klondikeRoutes_move(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		statusCode int
+	}{
+		{
+			name:       "Success",
+			query:      "action=move&from=tableau&from_index=2&to=tableau&to_index=5&count=3",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Illegal Move",
+			query:      "action=move&from=tableau&from_index=2&to=tableau&to_index=5&count=3",
+			err:        usecase.KlondikeIllegalMoveErr,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "Not Found",
+			query:      "action=move&from=tableau&from_index=2&to=tableau&to_index=5&count=3",
+			err:        usecase.KlondikeGameNotFoundErr,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Bad Index",
+			query:      "action=move&from=tableau&from_index=two",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := withURLParam(httptest.NewRequest(http.MethodPost, "/v1/games/klondike/id/moves?"+tt.query, nil), "gameID", "id")
+
+			k := &klondikeRoutes{
+				klondike: &stubKlondikeManager{
+					move: func(id string, move entity.KlondikeMove) (entity.KlondikeGame, error) {
+						want := entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileTableau, FromIndex: 2, To: entity.KlondikePileTableau, ToIndex: 5, Count: 3}
+						if diff := cmp.Diff(move, want); diff != "" {
+							t.Errorf("klondikeRoutes.move() | move (-got +want):\n%s", diff)
+						}
+						return entity.KlondikeGame{ID: id}, tt.err
+					},
+				},
+			}
+			k.move(w, r)
+
+			if code := w.Result().StatusCode; code != tt.statusCode {
+				t.Fatalf("klondikeRoutes.move() | got status code %d, want %d", code, tt.statusCode)
+			}
+		})
+	}
+}
+
+func Test_klondikeRoutes_daily(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		statusCode int
+	}{
+		{name: "Success", query: "date=2024-03-09&draw=3", statusCode: http.StatusOK},
+		{name: "Bad Date", query: "date=09/03/2024", statusCode: http.StatusBadRequest},
+		{name: "Too Long", query: "date=2024-03-09", err: context.DeadlineExceeded, statusCode: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/v1/games/klondike/daily?"+tt.query, nil)
+
+			k := &klondikeRoutes{
+				klondike: &stubKlondikeManager{
+					dailySeed: func(day time.Time, drawCount int) (usecase.KlondikeSolution, error) {
+						return usecase.KlondikeSolution{Seed: 20240309001, DrawCount: drawCount, Result: usecase.KlondikeWinnable}, tt.err
+					},
+				},
+			}
+			k.daily(w, r)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Fatalf("klondikeRoutes.daily() | got status code %d, want %d", resp.StatusCode, tt.statusCode)
+			}
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+
+			var got klondikeDailyResp
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			want := klondikeDailyResp{Date: "2024-03-09", Seed: 20240309001, DrawCount: 3}
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("klondikeRoutes.daily() | (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_createKlondikeRoutes_Scopes(t *testing.T) {
+	klondike := &stubKlondikeManager{
+		new:  func(int64, int) (entity.KlondikeGame, error) { return entity.KlondikeGame{ID: "id"}, nil },
+		game: func(id string) (entity.KlondikeGame, error) { return entity.KlondikeGame{ID: id}, nil },
+		undo: func(id string) (entity.KlondikeGame, error) { return entity.KlondikeGame{ID: id}, nil },
+	}
+	reader := entity.Principal{ID: "jwt:alice", Scopes: []string{entity.ScopeDecksRead}}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		principal  entity.Principal
+		statusCode int
+	}{
+		{name: "Read Game", method: http.MethodGet, target: "/v1/games/klondike/id", principal: reader, statusCode: http.StatusOK},
+		{name: "Read Create", method: http.MethodPost, target: "/v1/games/klondike/", principal: reader, statusCode: http.StatusForbidden},
+		{name: "Read Undo", method: http.MethodPost, target: "/v1/games/klondike/id/undos", principal: reader, statusCode: http.StatusForbidden},
+		{
+			name:       "Create",
+			method:     http.MethodPost,
+			target:     "/v1/games/klondike/",
+			principal:  entity.Principal{ID: "jwt:alice", Scopes: []string{entity.ScopeDecksCreate}},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Draw Undo",
+			method:     http.MethodPost,
+			target:     "/v1/games/klondike/id/undos",
+			principal:  entity.Principal{ID: "jwt:alice", Scopes: []string{entity.ScopeDecksDraw}},
+			statusCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := chi.NewRouter()
+			createKlondikeRoutes(m, klondike, nil)
+
+			r := httptest.NewRequest(tt.method, tt.target, nil)
+			r = r.WithContext(usecase.WithPrincipal(r.Context(), tt.principal))
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, r)
+
+			if w.Code != tt.statusCode {
+				t.Errorf("%s %s | got status code %d, want %d", tt.method, tt.target, w.Code, tt.statusCode)
+			}
+		})
+	}
+}
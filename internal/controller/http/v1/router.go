@@ -6,6 +6,7 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 
 	_ "github.com/lualfe/card-game/docs"
+	"github.com/lualfe/card-game/internal/controller/http/middleware"
 	"github.com/lualfe/card-game/internal/usecase"
 )
 
@@ -16,8 +17,37 @@ import (
 // @host      localhost:8080
 // @BasePath  /v1
 
-// StartRoutes starts the application routes.
-func StartRoutes(m *chi.Mux, deck usecase.DeckManager) {
+// @securityDefinitions.apikey  APIKey
+// @in                          header
+// @name                        X-API-Key
+
+// @securityDefinitions.apikey  Bearer
+// @in                          header
+// @name                        Authorization
+// @description                 A JWT as "Bearer <token>".
+
+// StartRoutes starts the application routes. With keys or
+// tokens set, the versioned API needs an API key or a
+// bearer token, and callers work in the tenant of theirs;
+// the probes and the docs stay open. With limits set, deck
+// and game creation and draws are rate limited.
+func StartRoutes(m *chi.Mux, deck usecase.DeckManager, blackjack usecase.BlackjackManager, holdem usecase.HoldemManager, casual usecase.CasualGameManager, klondike usecase.KlondikeManager, bridge usecase.BridgeManager, snapshots usecase.DeckSnapshotManager, health usecase.HealthManager, keys usecase.APIKeyManager, tokens usecase.TokenManager, tenants usecase.TenantManager, limits usecase.RateLimiter) {
 	m.Mount("/swagger", httpSwagger.WrapHandler)
-	createDeckRoutes(m, deck)
+	createHealthRoutes(m, health)
+
+	m.Group(func(r chi.Router) {
+		if keys != nil || tokens != nil {
+			r.Use(middleware.Auth(keys, tokens))
+			if tenants != nil {
+				r.Use(middleware.Tenant(tenants))
+			}
+		}
+		createDeckRoutes(r, deck, limits)
+		createBlackjackRoutes(r, blackjack, limits)
+		createHoldemRoutes(r, holdem, limits)
+		createCasualGameRoutes(r, casual, limits)
+		createKlondikeRoutes(r, klondike, limits)
+		createBridgeRoutes(r, bridge, limits)
+		createAdminRoutes(r, snapshots, keys, tenants)
+	})
 }
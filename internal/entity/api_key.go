@@ -0,0 +1,69 @@
+package entity
+
+import "time"
+
+// APIKey is a key callers authenticate with. Only the
+// SHA-256 of the key is kept, so a stolen store doesn't
+// give the keys away.
+type APIKey struct {
+	Name    string    `json:"name"`
+	Hash    string    `json:"-"`
+	Admin   bool      `json:"admin"`
+	Tenant  string    `json:"tenant,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// Scopes of the deck routes a principal can be limited to.
+const (
+	// ScopeDecksCreate creates decks and games.
+	ScopeDecksCreate = "decks:create"
+	// ScopeDecksDelete deletes decks.
+	ScopeDecksDelete = "decks:delete"
+	// ScopeDecksDraw draws, deals and returns cards, and plays games.
+	ScopeDecksDraw = "decks:draw"
+	// ScopeDecksRead reads decks, their history and games.
+	ScopeDecksRead = "decks:read"
+	// ScopeAdmin makes a token's subject an admin.
+	ScopeAdmin = "admin"
+)
+
+// DeckScopes are every scope of the deck routes, which API
+// keys are given.
+var DeckScopes = []string{ScopeDecksCreate, ScopeDecksDelete, ScopeDecksDraw, ScopeDecksRead}
+
+// Prefixes of the principal IDs, telling keys and token
+// subjects apart, so a key can't be taken over by a token
+// whose subject is named after it.
+const (
+	// PrincipalKeyPrefix starts the ID of an API key, followed by its name.
+	PrincipalKeyPrefix = "key:"
+	// PrincipalTokenPrefix starts the ID of a token, followed by its subject.
+	PrincipalTokenPrefix = "jwt:"
+)
+
+// Principal is who a request is made by. Admins can reach
+// every deck of their tenant and the admin routes, and
+// have every scope.
+type Principal struct {
+	// ID is the key name or token subject, after the
+	// prefix of its kind.
+	ID     string
+	Admin  bool
+	Scopes []string
+	// Tenant is the tenant the principal works in, empty
+	// for the default one.
+	Tenant string
+}
+
+// Can tells if p has scope.
+func (p Principal) Can(scope string) bool {
+	if p.Admin {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// AuditRecord is a deck operation in the audit log. Hash
+// covers the record and PrevHash, which is the hash of the
+// previous record of the same deck, chaining the records.
+// Version is the deck version the operation moved to.
+type AuditRecord struct {
+	DeckID   string    `json:"deck_id"`
+	Seq      int       `json:"seq"`
+	Version  int       `json:"version"`
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	Cards    []string  `json:"cards"`
+	PlayerID string    `json:"player_id,omitempty"`
+	PrevHash string    `json:"prev_hash"`
+	Hash     string    `json:"hash"`
+}
@@ -0,0 +1,54 @@
+package entity
+
+const (
+	// BlackjackStatusBetting means the table is waiting for a new bet.
+	BlackjackStatusBetting = "BETTING"
+	// BlackjackStatusPlaying means a round is in progress.
+	BlackjackStatusPlaying = "PLAYING"
+)
+
+const (
+	// BlackjackResultWin means the hand beat the dealer.
+	BlackjackResultWin = "WIN"
+	// BlackjackResultBlackjack means the hand was a natural blackjack.
+	BlackjackResultBlackjack = "BLACKJACK"
+	// BlackjackResultPush means the hand tied with the dealer.
+	BlackjackResultPush = "PUSH"
+	// BlackjackResultLose means the hand lost to the dealer.
+	BlackjackResultLose = "LOSE"
+	// BlackjackResultBust means the hand went over 21.
+	BlackjackResultBust = "BUST"
+	// BlackjackResultSurrender means the player gave up half the bet.
+	BlackjackResultSurrender = "SURRENDER"
+)
+
+// BlackjackTable represents a blackjack table bound to a shoe.
+type BlackjackTable struct {
+	ID               string          `json:"table_id"`
+	ShoeID           string          `json:"shoe_id"`
+	Decks            int             `json:"decks"`
+	ShoeRemaining    int             `json:"shoe_remaining"`
+	CutCard          int             `json:"cut_card"`
+	DealerHitsSoft17 bool            `json:"dealer_hits_soft_17"`
+	Bankroll         int             `json:"bankroll"`
+	Status           string          `json:"status"`
+	Dealer           []Card          `json:"dealer"`
+	Hands            []BlackjackHand `json:"hands"`
+	ActiveHand       int             `json:"active_hand"`
+	// Owner is who created the table, empty when it was
+	// created without a principal. Tenant is the tenant it
+	// belongs to, as for decks.
+	Owner  string `json:"owner,omitempty"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// BlackjackHand is a player hand on a blackjack table.
+type BlackjackHand struct {
+	Cards     []Card `json:"cards"`
+	Bet       int    `json:"bet"`
+	Doubled   bool   `json:"doubled"`
+	FromSplit bool   `json:"from_split"`
+	Done      bool   `json:"done"`
+	Result    string `json:"result,omitempty"`
+	Payout    int    `json:"payout"`
+}
@@ -0,0 +1,51 @@
+package entity
+
+const (
+	// BridgeNorth is the North seat.
+	BridgeNorth = "N"
+	// BridgeEast is the East seat.
+	BridgeEast = "E"
+	// BridgeSouth is the South seat.
+	BridgeSouth = "S"
+	// BridgeWest is the West seat.
+	BridgeWest = "W"
+)
+
+const (
+	// BridgeVulnerableNone means no side is vulnerable.
+	BridgeVulnerableNone = "None"
+	// BridgeVulnerableNS means North and South are vulnerable.
+	BridgeVulnerableNS = "NS"
+	// BridgeVulnerableEW means East and West are vulnerable.
+	BridgeVulnerableEW = "EW"
+	// BridgeVulnerableAll means both sides are vulnerable.
+	BridgeVulnerableAll = "All"
+)
+
+// BridgeSeats are the seats in dealing order.
+var BridgeSeats = []string{BridgeNorth, BridgeEast, BridgeSouth, BridgeWest}
+
+// BridgeDeal is a bridge board with the 13 cards
+// dealt to each seat.
+type BridgeDeal struct {
+	ID         string            `json:"deal_id"`
+	DeckID     string            `json:"deck_id"`
+	Board      int               `json:"board"`
+	Dealer     string            `json:"dealer"`
+	Vulnerable string            `json:"vulnerable"`
+	Hands      map[string][]Card `json:"hands"`
+	// Owner is who created the deal, empty when it was
+	// created without a principal. Tenant is the tenant it
+	// belongs to, as for decks.
+	Owner  string `json:"owner,omitempty"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// BridgeHandStats describes a bridge hand. Lengths are
+// in spades, hearts, diamonds and clubs order.
+type BridgeHandStats struct {
+	HCP      int    `json:"hcp"`
+	Lengths  [4]int `json:"lengths"`
+	Shape    string `json:"shape"`
+	Balanced bool   `json:"balanced"`
+}
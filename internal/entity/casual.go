@@ -0,0 +1,64 @@
+package entity
+
+const (
+	// CasualGameWar is the game of War.
+	CasualGameWar = "WAR"
+	// CasualGameGoFish is the game of Go Fish.
+	CasualGameGoFish = "GO_FISH"
+	// CasualGameCrazyEights is the game of Crazy Eights.
+	CasualGameCrazyEights = "CRAZY_EIGHTS"
+)
+
+const (
+	// CasualMoveFlip flips the top card in War.
+	CasualMoveFlip = "flip"
+	// CasualMoveAsk asks another player for a value in Go Fish.
+	CasualMoveAsk = "ask"
+	// CasualMovePlay plays a card from the hand in Crazy Eights.
+	CasualMovePlay = "play"
+	// CasualMoveDraw draws a card from the stock in Crazy Eights.
+	CasualMoveDraw = "draw"
+	// CasualMovePass passes the turn in Crazy Eights when nothing can be done.
+	CasualMovePass = "pass"
+)
+
+// CasualGame is the state of a casual card game.
+type CasualGame struct {
+	ID       string         `json:"game_id"`
+	Kind     string         `json:"kind"`
+	DeckID   string         `json:"deck_id"`
+	Stock    int            `json:"stock"`
+	Players  []CasualPlayer `json:"players"`
+	Turn     int            `json:"turn"`
+	Pile     []Card         `json:"pile"`
+	Suit     string         `json:"suit,omitempty"`
+	Round    int            `json:"round"`
+	Passes   int            `json:"passes"`
+	Message  string         `json:"message"`
+	Finished bool           `json:"finished"`
+	Winner   string         `json:"winner,omitempty"`
+	// Owner is who created the game, empty when it was
+	// created without a principal. Tenant is the tenant it
+	// belongs to, as for decks.
+	Owner  string `json:"owner,omitempty"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// CasualPlayer is a player of a casual card game.
+type CasualPlayer struct {
+	ID    string   `json:"player_id"`
+	Token string   `json:"-"`
+	Hand  []Card   `json:"hand"`
+	Books []string `json:"books"`
+}
+
+// CasualMove is a move in a casual card game. Which
+// fields are used depends on the game and action.
+type CasualMove struct {
+	Player string `json:"player_id"`
+	Action string `json:"action"`
+	Target string `json:"target,omitempty"`
+	Value  string `json:"value,omitempty"`
+	Card   string `json:"card,omitempty"`
+	Suit   string `json:"suit,omitempty"`
+}
@@ -6,6 +6,21 @@ type Deck struct {
 	Shuffled  bool   `json:"shuffled"`
 	Remaining int    `json:"remaining"`
 	Cards     []Card `json:"cards"`
+	Hands     []Hand `json:"hands,omitempty"`
+	Version   int    `json:"version"`
+	// Owner is the name of the API key that created the
+	// deck, empty when it was created without one.
+	Owner string `json:"owner,omitempty"`
+	// Tenant is the tenant the deck belongs to, empty for
+	// the default one. Stores keep decks apart by tenant.
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// Hand is the cards dealt from a deck to a player.
+type Hand struct {
+	PlayerID string `json:"player_id"`
+	Token    string `json:"-"`
+	Cards    []Card `json:"cards"`
 }
 
 // Card ~.
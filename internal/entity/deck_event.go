@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+const (
+	// DeckEventCreated opens a deck with its cards in order.
+	DeckEventCreated = "CREATED"
+	// DeckEventShuffled puts the cards of a deck in a new order.
+	DeckEventShuffled = "SHUFFLED"
+	// DeckEventDrawn takes cards from the top of a deck.
+	DeckEventDrawn = "DRAWN"
+	// DeckEventReturned puts drawn cards back at the bottom of a deck.
+	DeckEventReturned = "RETURNED"
+	// DeckEventMoved takes cards from the top of a deck into a player hand.
+	DeckEventMoved = "MOVED"
+)
+
+// DeckEvent is an operation done on a deck. Folding the
+// events of a deck in version order gives its state.
+type DeckEvent struct {
+	DeckID   string    `json:"deck_id"`
+	Version  int       `json:"version"`
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	Cards    []Card    `json:"cards"`
+	PlayerID string    `json:"player_id,omitempty"`
+	Token    string    `json:"-"`
+	// Owner is the owner of the deck, kept on its CREATED
+	// event so its history stays owned once it expires.
+	Owner string `json:"owner,omitempty"`
+	// Tenant is the tenant of the deck, kept on its
+	// CREATED event like its owner.
+	Tenant string `json:"tenant,omitempty"`
+	// Actor is who did the operation, when it was made by
+	// an authenticated caller.
+	Actor string `json:"actor,omitempty"`
+}
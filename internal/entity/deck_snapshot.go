@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// DeckSnapshot is every deck and deck event of the store
+// at one point in time.
+type DeckSnapshot struct {
+	Taken  time.Time
+	Decks  []Deck
+	Events []DeckEvent
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+const (
+	// HealthUp is the status of a passing check, or of a
+	// report where every check passes.
+	HealthUp = "up"
+	// HealthDown is the status of a failing check, or of a
+	// report where a check fails.
+	HealthDown = "down"
+)
+
+// HealthReport is the outcome of a set of health checks.
+type HealthReport struct {
+	Status string
+	Checks []HealthCheckResult
+}
+
+// HealthCheckResult is the outcome of one health check.
+type HealthCheckResult struct {
+	Name     string
+	Status   string
+	Error    string
+	Duration time.Duration
+}
@@ -0,0 +1,67 @@
+package entity
+
+const (
+	// HoldemStreetWaiting means no hand is being played.
+	HoldemStreetWaiting = "WAITING"
+	// HoldemStreetPreflop is the betting round after hole cards are dealt.
+	HoldemStreetPreflop = "PREFLOP"
+	// HoldemStreetFlop is the betting round after the first three board cards.
+	HoldemStreetFlop = "FLOP"
+	// HoldemStreetTurn is the betting round after the fourth board card.
+	HoldemStreetTurn = "TURN"
+	// HoldemStreetRiver is the betting round after the last board card.
+	HoldemStreetRiver = "RIVER"
+)
+
+// HoldemTable represents a no-limit Texas Hold'em table.
+type HoldemTable struct {
+	ID         string         `json:"table_id"`
+	DeckID     string         `json:"deck_id"`
+	SmallBlind int            `json:"small_blind"`
+	BigBlind   int            `json:"big_blind"`
+	HandNumber int            `json:"hand_number"`
+	Button     int            `json:"button"`
+	Street     string         `json:"street"`
+	Seats      []HoldemSeat   `json:"seats"`
+	Board      []Card         `json:"board"`
+	Burned     []Card         `json:"burned"`
+	Pots       []HoldemPot    `json:"pots"`
+	CurrentBet int            `json:"current_bet"`
+	MinRaise   int            `json:"min_raise"`
+	ToAct      int            `json:"to_act"`
+	Showdown   bool           `json:"showdown"`
+	Winners    []HoldemWinner `json:"winners"`
+	// Owner is who created the table, empty when it was
+	// created without a principal. Tenant is the tenant it
+	// belongs to, as for decks.
+	Owner  string `json:"owner,omitempty"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// HoldemSeat is a player sitting at a Hold'em table.
+type HoldemSeat struct {
+	PlayerID  string `json:"player_id"`
+	Token     string `json:"-"`
+	Stack     int    `json:"stack"`
+	Hole      []Card `json:"hole"`
+	Bet       int    `json:"bet"`
+	Committed int    `json:"committed"`
+	InHand    bool   `json:"in_hand"`
+	Folded    bool   `json:"folded"`
+	AllIn     bool   `json:"all_in"`
+	Acted     bool   `json:"acted"`
+}
+
+// HoldemPot is a main or side pot and the players
+// who can win it.
+type HoldemPot struct {
+	Amount   int      `json:"amount"`
+	Eligible []string `json:"eligible"`
+}
+
+// HoldemWinner is a player paid at the end of a hand.
+type HoldemWinner struct {
+	PlayerID string `json:"player_id"`
+	Amount   int    `json:"amount"`
+	Hand     string `json:"hand,omitempty"`
+}
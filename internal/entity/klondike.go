@@ -0,0 +1,62 @@
+package entity
+
+const (
+	// KlondikeActionDraw turns cards from the stock to the waste,
+	// or recycles the waste when the stock is empty.
+	KlondikeActionDraw = "draw"
+	// KlondikeActionMove moves cards between piles.
+	KlondikeActionMove = "move"
+)
+
+const (
+	// KlondikePileWaste is the waste pile.
+	KlondikePileWaste = "waste"
+	// KlondikePileTableau is one of the seven tableau piles.
+	KlondikePileTableau = "tableau"
+	// KlondikePileFoundation is one of the four foundations.
+	KlondikePileFoundation = "foundation"
+)
+
+// KlondikeGame is a game of Klondike solitaire.
+type KlondikeGame struct {
+	ID        string `json:"game_id"`
+	DeckID    string `json:"deck_id"`
+	Seed      int64  `json:"seed"`
+	DrawCount int    `json:"draw_count"`
+	KlondikeState
+	History []KlondikeState `json:"-"`
+	Won     bool            `json:"won"`
+	// Owner is who created the game, empty when it was
+	// created without a principal. Tenant is the tenant it
+	// belongs to, as for decks.
+	Owner  string `json:"owner,omitempty"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// KlondikeState is the layout of the cards in a game
+// of Klondike. The last card of each pile is its top.
+type KlondikeState struct {
+	Stock       []Card          `json:"stock"`
+	Waste       []Card          `json:"waste"`
+	Foundations [][]Card        `json:"foundations"`
+	Tableau     []KlondikeStack `json:"tableau"`
+	Moves       int             `json:"moves"`
+}
+
+// KlondikeStack is a tableau pile. Its first
+// FaceDown cards are turned down.
+type KlondikeStack struct {
+	Cards    []Card `json:"cards"`
+	FaceDown int    `json:"face_down"`
+}
+
+// KlondikeMove is a move in a game of Klondike. Count is the
+// number of cards moved from a tableau pile.
+type KlondikeMove struct {
+	Action    string `json:"action"`
+	From      string `json:"from,omitempty"`
+	FromIndex int    `json:"from_index"`
+	To        string `json:"to,omitempty"`
+	ToIndex   int    `json:"to_index"`
+	Count     int    `json:"count,omitempty"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// RateLimit is a token bucket: it holds up to Burst
+// requests, and gets PerMinute of them back each minute.
+// Zero PerMinute means no limit.
+type RateLimit struct {
+	PerMinute int
+	Burst     int
+}
+
+// RateLimitStatus is what a bucket said of a request.
+type RateLimitStatus struct {
+	Allowed bool
+	// Limit is the most requests the bucket holds, zero
+	// when there's no limit.
+	Limit int
+	// Remaining is how many requests the bucket holds
+	// after this one.
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until a request is allowed
+	// again, when this one isn't.
+	RetryAfter time.Duration
+}
@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// Tenant is a customer of the application, with decks of
+// its own. Decks created without a tenant belong to the
+// default one, which has no name and no limits.
+type Tenant struct {
+	Name    string       `json:"name"`
+	Limits  TenantLimits `json:"limits"`
+	Created time.Time    `json:"created"`
+}
+
+// TenantLimits bound what a tenant can use. Zero means no
+// limit.
+type TenantLimits struct {
+	// MaxDecks is how many decks the tenant can keep.
+	MaxDecks int `json:"max_decks"`
+	// MaxCards is how many cards a deck of the tenant can
+	// be created with.
+	MaxCards int `json:"max_cards"`
+	// RequestsPerMinute is how many requests the tenant
+	// can make each minute.
+	RequestsPerMinute int `json:"requests_per_minute"`
+}
+
+// TenantUsage is what a tenant uses against its limits.
+type TenantUsage struct {
+	Tenant Tenant `json:"tenant"`
+	Decks  int    `json:"decks"`
+	// Requests is how many requests the tenant made in
+	// the current minute.
+	Requests int `json:"requests"`
+}
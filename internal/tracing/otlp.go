@@ -0,0 +1,307 @@
+// Package tracing exports the spans of the application as
+// OTLP JSON, the format the OpenTelemetry collector reads
+// and writes as files, so traces can be kept locally and
+// looked at without running a collector.
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StoppedErr happens when spans are exported after the
+// exporter was shut down.
+var StoppedErr = errors.New("exporter stopped")
+
+// OTLPExporter writes every batch of spans as a line of
+// OTLP JSON.
+type OTLPExporter struct {
+	mu      sync.Mutex
+	enc     *json.Encoder
+	stopped bool
+}
+
+// NewOTLPExporter creates an OTLPExporter writing to w. The
+// exporter doesn't close w.
+func NewOTLPExporter(w io.Writer) *OTLPExporter {
+	return &OTLPExporter{enc: json.NewEncoder(w)}
+}
+
+// ExportSpans writes spans as a single line, grouped by
+// resource and instrumentation scope.
+func (e *OTLPExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data := tracesData(spans)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.stopped {
+		return StoppedErr
+	}
+	return e.enc.Encode(data)
+}
+
+// Shutdown stops the exporter. The spans are written as
+// they're exported, so there's nothing left to flush.
+func (e *OTLPExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stopped = true
+	return nil
+}
+
+// The types below follow the JSON encoding of the OTLP
+// protobuf messages: IDs are hex, 64-bit integers are
+// strings and enums are numbers.
+
+type otlpTracesData struct {
+	ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource      `json:"resource"`
+	ScopeSpans []*otlpScopeSpans `json:"scopeSpans"`
+	SchemaURL  string            `json:"schemaUrl,omitempty"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope     otlpScope  `json:"scope"`
+	Spans     []otlpSpan `json:"spans"`
+	SchemaURL string     `json:"schemaUrl,omitempty"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID                string         `json:"traceId"`
+	SpanID                 string         `json:"spanId"`
+	TraceState             string         `json:"traceState,omitempty"`
+	ParentSpanID           string         `json:"parentSpanId,omitempty"`
+	Name                   string         `json:"name"`
+	Kind                   int            `json:"kind"`
+	StartTimeUnixNano      string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano        string         `json:"endTimeUnixNano"`
+	Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+	Events                 []otlpEvent    `json:"events,omitempty"`
+	DroppedEventsCount     int            `json:"droppedEventsCount,omitempty"`
+	Links                  []otlpLink     `json:"links,omitempty"`
+	DroppedLinksCount      int            `json:"droppedLinksCount,omitempty"`
+	Status                 otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpLink struct {
+	TraceID    string         `json:"traceId"`
+	SpanID     string         `json:"spanId"`
+	TraceState string         `json:"traceState,omitempty"`
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Message string `json:"message,omitempty"`
+	Code    int    `json:"code,omitempty"`
+}
+
+// OTLP status codes, which aren't numbered as codes.Code.
+const (
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+)
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+func tracesData(spans []sdktrace.ReadOnlySpan) otlpTracesData {
+	var (
+		data       otlpTracesData
+		resources  = map[attribute.Distinct]*otlpResourceSpans{}
+		scopeSpans = map[attribute.Distinct]map[instrumentation.Scope]*otlpScopeSpans{}
+	)
+	for _, s := range spans {
+		res := s.Resource()
+		key := res.Equivalent()
+		rs, ok := resources[key]
+		if !ok {
+			rs = &otlpResourceSpans{Resource: otlpResource{Attributes: resourceAttributes(res)}, SchemaURL: res.SchemaURL()}
+			resources[key] = rs
+			scopeSpans[key] = map[instrumentation.Scope]*otlpScopeSpans{}
+			data.ResourceSpans = append(data.ResourceSpans, rs)
+		}
+
+		scope := s.InstrumentationScope()
+		ss, ok := scopeSpans[key][scope]
+		if !ok {
+			ss = &otlpScopeSpans{Scope: otlpScope{Name: scope.Name, Version: scope.Version}, SchemaURL: scope.SchemaURL}
+			scopeSpans[key][scope] = ss
+			rs.ScopeSpans = append(rs.ScopeSpans, ss)
+		}
+		ss.Spans = append(ss.Spans, span(s))
+	}
+	return data
+}
+
+func resourceAttributes(res *resource.Resource) []otlpKeyValue {
+	if res == nil {
+		return nil
+	}
+	return keyValues(res.Attributes())
+}
+
+func span(s sdktrace.ReadOnlySpan) otlpSpan {
+	sc := s.SpanContext()
+	out := otlpSpan{
+		TraceID:                traceID(sc.TraceID()),
+		SpanID:                 spanID(sc.SpanID()),
+		TraceState:             sc.TraceState().String(),
+		Name:                   s.Name(),
+		Kind:                   int(s.SpanKind()),
+		StartTimeUnixNano:      unixNano(s.StartTime()),
+		EndTimeUnixNano:        unixNano(s.EndTime()),
+		Attributes:             keyValues(s.Attributes()),
+		DroppedAttributesCount: s.DroppedAttributes(),
+		DroppedEventsCount:     s.DroppedEvents(),
+		DroppedLinksCount:      s.DroppedLinks(),
+		Status:                 status(s.Status()),
+	}
+	if parent := s.Parent(); parent.HasSpanID() {
+		out.ParentSpanID = spanID(parent.SpanID())
+	}
+	for _, e := range s.Events() {
+		out.Events = append(out.Events, otlpEvent{
+			TimeUnixNano: unixNano(e.Time),
+			Name:         e.Name,
+			Attributes:   keyValues(e.Attributes),
+		})
+	}
+	for _, l := range s.Links() {
+		out.Links = append(out.Links, otlpLink{
+			TraceID:    traceID(l.SpanContext.TraceID()),
+			SpanID:     spanID(l.SpanContext.SpanID()),
+			TraceState: l.SpanContext.TraceState().String(),
+			Attributes: keyValues(l.Attributes),
+		})
+	}
+	return out
+}
+
+func status(s sdktrace.Status) otlpStatus {
+	switch s.Code {
+	case codes.Ok:
+		return otlpStatus{Code: otlpStatusOK}
+	case codes.Error:
+		return otlpStatus{Code: otlpStatusError, Message: s.Description}
+	}
+	return otlpStatus{}
+}
+
+func traceID(id trace.TraceID) string {
+	return hex.EncodeToString(id[:])
+}
+
+func spanID(id trace.SpanID) string {
+	return hex.EncodeToString(id[:])
+}
+
+func unixNano(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func keyValues(attrs []attribute.KeyValue) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		kvs = append(kvs, otlpKeyValue{Key: string(a.Key), Value: anyValue(a.Value)})
+	}
+	return kvs
+}
+
+func anyValue(v attribute.Value) otlpAnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		return otlpAnyValue{BoolValue: &b}
+	case attribute.INT64:
+		n := strconv.FormatInt(v.AsInt64(), 10)
+		return otlpAnyValue{IntValue: &n}
+	case attribute.FLOAT64:
+		f := v.AsFloat64()
+		return otlpAnyValue{DoubleValue: &f}
+	case attribute.BOOLSLICE:
+		var values []otlpAnyValue
+		for _, b := range v.AsBoolSlice() {
+			values = append(values, anyValue(attribute.BoolValue(b)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case attribute.INT64SLICE:
+		var values []otlpAnyValue
+		for _, n := range v.AsInt64Slice() {
+			values = append(values, anyValue(attribute.Int64Value(n)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case attribute.FLOAT64SLICE:
+		var values []otlpAnyValue
+		for _, f := range v.AsFloat64Slice() {
+			values = append(values, anyValue(attribute.Float64Value(f)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case attribute.STRINGSLICE:
+		var values []otlpAnyValue
+		for _, s := range v.AsStringSlice() {
+			values = append(values, anyValue(attribute.StringValue(s)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	}
+	s := v.Emit()
+	return otlpAnyValue{StringValue: &s}
+}
@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestOTLPExporter(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := NewOTLPExporter(&buf)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "card-game"))),
+	)
+	tracer := tp.Tracer("test")
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := tracer.Start(ctx, "child")
+	child.SetAttributes(attribute.Int("deck.amount", 3), attribute.StringSlice("deck.cards", []string{"AS", "2S"}))
+	child.RecordError(errors.New("boom"))
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	parent.End()
+
+	var lines []otlpTracesData
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var data otlpTracesData
+		if err := dec.Decode(&data); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, data)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("ExportSpans() | got %d lines, want one per span", len(lines))
+	}
+
+	rs := lines[0].ResourceSpans[0]
+	if got := rs.Resource.Attributes[0]; got.Key != "service.name" || *got.Value.StringValue != "card-game" {
+		t.Errorf("ExportSpans() | got resource attribute %+v", got)
+	}
+	if got := rs.ScopeSpans[0].Scope.Name; got != "test" {
+		t.Errorf("ExportSpans() | got scope %q, want test", got)
+	}
+
+	got := rs.ScopeSpans[0].Spans[0]
+	want := lines[1].ResourceSpans[0].ScopeSpans[0].Spans[0]
+	if got.Name != "child" || want.Name != "parent" {
+		t.Fatalf("ExportSpans() | got spans %q and %q, want child then parent", got.Name, want.Name)
+	}
+	if got.TraceID != want.TraceID || got.ParentSpanID != want.SpanID || want.ParentSpanID != "" {
+		t.Errorf("ExportSpans() | child %s/%s doesn't belong to parent %s/%s", got.TraceID, got.ParentSpanID, want.TraceID, want.SpanID)
+	}
+	if len(got.TraceID) != 32 || len(got.SpanID) != 16 {
+		t.Errorf("ExportSpans() | got trace ID %q and span ID %q, want hex", got.TraceID, got.SpanID)
+	}
+	if got.Status != (otlpStatus{Code: otlpStatusError, Message: "boom"}) {
+		t.Errorf("ExportSpans() | got status %+v, want error boom", got.Status)
+	}
+	if len(got.Events) != 1 || got.Events[0].Name != "exception" {
+		t.Errorf("ExportSpans() | got events %+v, want the error", got.Events)
+	}
+	if got.StartTimeUnixNano == "0" || got.EndTimeUnixNano < got.StartTimeUnixNano {
+		t.Errorf("ExportSpans() | got times %s to %s", got.StartTimeUnixNano, got.EndTimeUnixNano)
+	}
+
+	attrs := map[string]otlpAnyValue{}
+	for _, kv := range got.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	if v := attrs["deck.amount"].IntValue; v == nil || *v != "3" {
+		t.Errorf("ExportSpans() | got deck.amount %+v, want intValue \"3\"", attrs["deck.amount"])
+	}
+	if v := attrs["deck.cards"].ArrayValue; v == nil || len(v.Values) != 2 || *v.Values[1].StringValue != "2S" {
+		t.Errorf("ExportSpans() | got deck.cards %+v, want [AS 2S]", attrs["deck.cards"])
+	}
+}
+
+func TestOTLPExporter_Shutdown(t *testing.T) {
+	exporter := NewOTLPExporter(&bytes.Buffer{})
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	_, span := tp.Tracer("test").Start(context.Background(), "span")
+
+	if err := exporter.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	span.End()
+
+	spans := []sdktrace.ReadOnlySpan{span.(sdktrace.ReadOnlySpan)}
+	if err := exporter.ExportSpans(context.Background(), spans); !errors.Is(err, StoppedErr) {
+		t.Errorf("ExportSpans() | got error %v after shutdown, want %v", err, StoppedErr)
+	}
+}
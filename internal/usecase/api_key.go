@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+var (
+	// APIKeyInvalidErr happens when a caller's API key is unknown or revoked.
+	APIKeyInvalidErr = errors.New("invalid api key")
+	// APIKeyInvalidNameErr happens when an API key is created with a name it can't have.
+	APIKeyInvalidNameErr = errors.New("invalid api key name")
+	// APIKeyExistsErr happens when an API key is created with a name already taken.
+	APIKeyExistsErr = errors.New("api key already exists")
+	// APIKeyNotFoundErr happens when an API key to revoke can't be found.
+	APIKeyNotFoundErr = errors.New("api key not found")
+	// APIKeyStaticErr happens when an API key of the configuration is revoked.
+	APIKeyStaticErr = errors.New("api key is set by the configuration")
+)
+
+// apiKeyPrefix starts the keys created by APIKeys, so they
+// stand out in a leaked file or a secret scanner.
+const apiKeyPrefix = "cgk_"
+
+// apiKeyName is what API key names look like. They end up
+// as deck owners and in the logs.
+var apiKeyName = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// HashAPIKey returns how a key is kept by the stores.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeys is a use case to authenticate callers by API
+// key. Keys either come from the configuration, which
+// can't be revoked through it, or are created and kept in
+// the store. A key works in the tenant it's created for.
+type APIKeys struct {
+	store   APIKeyRepo
+	tenants TenantRepo
+	static  map[string]entity.APIKey
+}
+
+// NewAPIKeys creates a new APIKeys, with the keys of the
+// configuration already hashed. New keys can only be
+// created for the tenants of tenants.
+func NewAPIKeys(store APIKeyRepo, tenants TenantRepo, static []entity.APIKey) *APIKeys {
+	keys := make(map[string]entity.APIKey, len(static))
+	for _, k := range static {
+		keys[k.Hash] = k
+	}
+	return &APIKeys{store: store, tenants: tenants, static: keys}
+}
+
+// Authenticate returns who key belongs to. Keys are looked
+// up by their hash, so how long it takes says nothing of
+// how close a guess was.
+func (a *APIKeys) Authenticate(ctx context.Context, key string) (entity.Principal, error) {
+	hash := HashAPIKey(key)
+	if k, ok := a.static[hash]; ok {
+		return apiKeyPrincipal(k), nil
+	}
+
+	k, err := a.store.Get(ctx, hash)
+	if err != nil {
+		if errors.Is(err, repo.APIKeyNotFoundErr) {
+			return entity.Principal{}, APIKeyInvalidErr
+		}
+		return entity.Principal{}, err
+	}
+	return apiKeyPrincipal(k), nil
+}
+
+// apiKeyPrincipal returns who k authenticates. Keys reach
+// every deck route, only limited by the decks they own.
+func apiKeyPrincipal(k entity.APIKey) entity.Principal {
+	return entity.Principal{ID: entity.PrincipalKeyPrefix + k.Name, Admin: k.Admin, Scopes: entity.DeckScopes, Tenant: k.Tenant}
+}
+
+// Create makes a new key of tenant, empty for the default
+// one, returning its secret. The secret isn't kept, so it
+// can't be shown again.
+func (a *APIKeys) Create(ctx context.Context, name string, admin bool, tenant string) (entity.APIKey, string, error) {
+	if !apiKeyName.MatchString(name) {
+		return entity.APIKey{}, "", fmt.Errorf("%w: %q must be 1 to 64 letters, digits, dots, dashes or underscores", APIKeyInvalidNameErr, name)
+	}
+	for _, k := range a.static {
+		if k.Name == name {
+			return entity.APIKey{}, "", fmt.Errorf("%w with name %s", APIKeyExistsErr, name)
+		}
+	}
+	if tenant != "" {
+		if _, err := a.tenants.Get(ctx, tenant); err != nil {
+			if errors.Is(err, repo.TenantNotFoundErr) {
+				return entity.APIKey{}, "", fmt.Errorf("%w with name %s", TenantNotFoundErr, tenant)
+			}
+			return entity.APIKey{}, "", err
+		}
+	}
+
+	b := make([]byte, 32)
+	if _, err := crand.Read(b); err != nil {
+		return entity.APIKey{}, "", fmt.Errorf("reading crypto/rand: %w", err)
+	}
+	secret := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	key := entity.APIKey{
+		Name:    name,
+		Hash:    HashAPIKey(secret),
+		Admin:   admin,
+		Tenant:  tenant,
+		Created: time.Now().UTC(),
+	}
+	if err := a.store.Save(ctx, key); err != nil {
+		if errors.Is(err, repo.APIKeyExistsErr) {
+			return entity.APIKey{}, "", fmt.Errorf("%w with name %s", APIKeyExistsErr, name)
+		}
+		return entity.APIKey{}, "", err
+	}
+
+	return key, secret, nil
+}
+
+// Keys returns every key, from the configuration or the
+// store, by name.
+func (a *APIKeys) Keys(ctx context.Context) ([]entity.APIKey, error) {
+	keys, err := a.store.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range a.static {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys, nil
+}
+
+// Revoke removes a key of the store, which stops
+// authenticating right away.
+func (a *APIKeys) Revoke(ctx context.Context, name string) error {
+	for _, k := range a.static {
+		if k.Name == name {
+			return fmt.Errorf("%w: %s", APIKeyStaticErr, name)
+		}
+	}
+
+	if err := a.store.Delete(ctx, name); err != nil {
+		if errors.Is(err, repo.APIKeyNotFoundErr) {
+			return fmt.Errorf("%w with name %s", APIKeyNotFoundErr, name)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+func TestAPIKeys(t *testing.T) {
+	ctx := context.Background()
+	keys := NewAPIKeys(repo.NewAPIKey(), repo.NewTenant(), []entity.APIKey{{Name: "ops", Hash: HashAPIKey("ops-secret"), Admin: true}})
+
+	p, err := keys.Authenticate(ctx, "ops-secret")
+	if err != nil {
+		t.Fatalf("APIKeys.Authenticate() | got error %v for a configured key, want nil", err)
+	}
+	if diff := cmp.Diff(p, entity.Principal{ID: "key:ops", Admin: true, Scopes: entity.DeckScopes}); diff != "" {
+		t.Errorf("APIKeys.Authenticate() | (-got +want):\n%s", diff)
+	}
+
+	key, secret, err := keys.Create(ctx, "ci", false, "")
+	if err != nil {
+		t.Fatalf("APIKeys.Create() | got error %v, want nil", err)
+	}
+	if !strings.HasPrefix(secret, apiKeyPrefix) || key.Hash != HashAPIKey(secret) {
+		t.Errorf("APIKeys.Create() | got secret %q with hash %s, want a %s key matching its hash", secret, key.Hash, apiKeyPrefix)
+	}
+	p, err = keys.Authenticate(ctx, secret)
+	if err != nil {
+		t.Fatalf("APIKeys.Authenticate() | got error %v for a created key, want nil", err)
+	}
+	if diff := cmp.Diff(p, entity.Principal{ID: "key:ci", Scopes: entity.DeckScopes}); diff != "" {
+		t.Errorf("APIKeys.Authenticate() | (-got +want):\n%s", diff)
+	}
+
+	all, err := keys.Keys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, k := range all {
+		names = append(names, k.Name)
+	}
+	if diff := cmp.Diff(names, []string{"ci", "ops"}); diff != "" {
+		t.Errorf("APIKeys.Keys() | (-got +want):\n%s", diff)
+	}
+
+	for _, name := range []string{"ci", "ops"} {
+		if _, _, err := keys.Create(ctx, name, false, ""); !errors.Is(err, APIKeyExistsErr) {
+			t.Errorf("APIKeys.Create(%q) | got error %v, want %v", name, err, APIKeyExistsErr)
+		}
+	}
+	for _, name := range []string{"", "has space", strings.Repeat("a", 65)} {
+		if _, _, err := keys.Create(ctx, name, false, ""); !errors.Is(err, APIKeyInvalidNameErr) {
+			t.Errorf("APIKeys.Create(%q) | got error %v, want %v", name, err, APIKeyInvalidNameErr)
+		}
+	}
+
+	if err := keys.Revoke(ctx, "ops"); !errors.Is(err, APIKeyStaticErr) {
+		t.Errorf("APIKeys.Revoke() | got error %v for a configured key, want %v", err, APIKeyStaticErr)
+	}
+	if err := keys.Revoke(ctx, "ci"); err != nil {
+		t.Fatalf("APIKeys.Revoke() | got error %v, want nil", err)
+	}
+	if _, err := keys.Authenticate(ctx, secret); !errors.Is(err, APIKeyInvalidErr) {
+		t.Errorf("APIKeys.Authenticate() | got error %v for a revoked key, want %v", err, APIKeyInvalidErr)
+	}
+	if err := keys.Revoke(ctx, "ci"); !errors.Is(err, APIKeyNotFoundErr) {
+		t.Errorf("APIKeys.Revoke() | got error %v revoking twice, want %v", err, APIKeyNotFoundErr)
+	}
+}
+
+func TestAPIKeys_Tenant(t *testing.T) {
+	ctx := context.Background()
+	tenants := repo.NewTenant()
+	if err := tenants.Save(ctx, entity.Tenant{Name: "acme"}); err != nil {
+		t.Fatal(err)
+	}
+	keys := NewAPIKeys(repo.NewAPIKey(), tenants, nil)
+
+	if _, _, err := keys.Create(ctx, "ci", false, "missing"); !errors.Is(err, TenantNotFoundErr) {
+		t.Errorf("APIKeys.Create() | got error %v for an unknown tenant, want %v", err, TenantNotFoundErr)
+	}
+	_, secret, err := keys.Create(ctx, "ci", true, "acme")
+	if err != nil {
+		t.Fatalf("APIKeys.Create() | got error %v, want nil", err)
+	}
+	p, err := keys.Authenticate(ctx, secret)
+	if err != nil {
+		t.Fatalf("APIKeys.Authenticate() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(p, entity.Principal{ID: "key:ci", Admin: true, Scopes: entity.DeckScopes, Tenant: "acme"}); diff != "" {
+		t.Errorf("APIKeys.Authenticate() | (-got +want):\n%s", diff)
+	}
+}
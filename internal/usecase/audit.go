@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// AuditRecordErr happens when a deck event can't be
+// written to the audit log.
+var AuditRecordErr = errors.New("recording audit event")
+
+// auditLastCached bounds how many decks AuditLog keeps the
+// last record of. Past it, the record of one of them is
+// dropped and read from the sink again when it's needed.
+const auditLastCached = 10000
+
+// AuditLog chains the operations of each deck into
+// tamper-evident records written to a sink.
+type AuditLog struct {
+	sink AuditSink
+
+	mu   sync.Mutex
+	last map[string]entity.AuditRecord
+}
+
+// NewAuditLog creates a new AuditLog.
+func NewAuditLog(sink AuditSink) *AuditLog {
+	return &AuditLog{
+		sink: sink,
+		last: make(map[string]entity.AuditRecord),
+	}
+}
+
+// Record writes a deck event to the log, chained to the
+// previous record of the deck.
+func (a *AuditLog) Record(event entity.DeckEvent) (entity.AuditRecord, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	prev, ok := a.last[event.DeckID]
+	if !ok {
+		var err error
+		if prev, _, err = a.sink.Last(event.DeckID); err != nil {
+			return entity.AuditRecord{}, err
+		}
+	}
+
+	record := entity.AuditRecord{
+		DeckID:   event.DeckID,
+		Seq:      prev.Seq + 1,
+		Version:  event.Version,
+		Type:     event.Type,
+		Time:     event.Time.UTC(),
+		Cards:    make([]string, len(event.Cards)),
+		PlayerID: event.PlayerID,
+		PrevHash: prev.Hash,
+	}
+	for i, c := range event.Cards {
+		record.Cards[i] = c.Code
+	}
+	record.Hash = AuditHash(record)
+
+	if err := a.sink.Append(record); err != nil {
+		return entity.AuditRecord{}, err
+	}
+	if _, ok := a.last[event.DeckID]; !ok && len(a.last) >= auditLastCached {
+		for deckID := range a.last {
+			delete(a.last, deckID)
+			break
+		}
+	}
+	a.last[event.DeckID] = record
+
+	return record, nil
+}
+
+// AuditHash returns the hash of a record, which covers
+// every field but the hash itself.
+func AuditHash(r entity.AuditRecord) string {
+	h := sha256.New()
+	for _, field := range []string{
+		r.PrevHash,
+		r.DeckID,
+		strconv.Itoa(r.Seq),
+		strconv.Itoa(r.Version),
+		r.Type,
+		r.Time.UTC().Format(time.RFC3339Nano),
+		strings.Join(r.Cards, ","),
+		r.PlayerID,
+	} {
+		// Lengths keep fields from running into each other.
+		fmt.Fprintf(h, "%d:%s;", len(field), field)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// AuditBreak is the first broken link of an audit log.
+type AuditBreak struct {
+	DeckID string `json:"deck_id"`
+	Seq    int    `json:"seq"`
+	Reason string `json:"reason"`
+}
+
+// AuditReport is the outcome of verifying an audit log.
+type AuditReport struct {
+	Records int         `json:"records"`
+	Decks   int         `json:"decks"`
+	Broken  *AuditBreak `json:"broken,omitempty"`
+}
+
+// VerifyAudit walks the chain of every deck, in the order
+// the records were written, and reports the first broken link.
+func VerifyAudit(records []entity.AuditRecord) AuditReport {
+	var report AuditReport
+	last := make(map[string]entity.AuditRecord)
+
+	for _, r := range records {
+		prev, seen := last[r.DeckID]
+		if !seen {
+			report.Decks++
+		}
+
+		var reason string
+		switch {
+		case r.Seq != prev.Seq+1:
+			reason = fmt.Sprintf("sequence %d follows %d", r.Seq, prev.Seq)
+		case r.PrevHash != prev.Hash:
+			reason = "previous hash doesn't match the previous record"
+		case r.Hash != AuditHash(r):
+			reason = "hash doesn't match the record"
+		}
+		if reason != "" {
+			report.Broken = &AuditBreak{DeckID: r.DeckID, Seq: r.Seq, Reason: reason}
+			return report
+		}
+
+		last[r.DeckID] = r
+		report.Records++
+	}
+
+	return report
+}
+
+// AuditedDeckRepo is a deck store that also writes the
+// events of every save and update to an audit log, before
+// they're stored, so an operation whose records can't be
+// written isn't stored either.
+type AuditedDeckRepo struct {
+	DeckRepo
+	audit *AuditLog
+}
+
+// NewAuditedDeckRepo creates a new AuditedDeckRepo.
+func NewAuditedDeckRepo(store DeckRepo, audit *AuditLog) *AuditedDeckRepo {
+	return &AuditedDeckRepo{
+		DeckRepo: store,
+		audit:    audit,
+	}
+}
+
+// Save records the events in the audit log and saves the
+// deck with them. A failed save leaves the records of a
+// deck that was never stored, which nothing follows.
+func (a *AuditedDeckRepo) Save(ctx context.Context, deck entity.Deck, events ...entity.DeckEvent) error {
+	if err := a.record(events); err != nil {
+		return err
+	}
+	return a.DeckRepo.Save(ctx, deck, events...)
+}
+
+// Update changes the deck with fn and records its events
+// in the audit log while the store holds the deck, so the
+// records of a deck follow its versions. A failed audit
+// write fails the update. With the Redis store, a run of
+// fn started over because of a write from another
+// instance is recorded too.
+func (a *AuditedDeckRepo) Update(ctx context.Context, tenant, id string, fn func(deck *entity.Deck) ([]entity.DeckEvent, error)) (entity.Deck, error) {
+	return a.DeckRepo.Update(ctx, tenant, id, func(deck *entity.Deck) ([]entity.DeckEvent, error) {
+		events, err := fn(deck)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.record(events); err != nil {
+			return nil, err
+		}
+		return events, nil
+	})
+}
+
+func (a *AuditedDeckRepo) record(events []entity.DeckEvent) error {
+	for _, e := range events {
+		if _, err := a.audit.Record(e); err != nil {
+			return fmt.Errorf("%w: deck %s version %d: %v", AuditRecordErr, e.DeckID, e.Version, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+type stubAuditSink struct {
+	records []entity.AuditRecord
+	err     error
+}
+
+func (s *stubAuditSink) Append(record entity.AuditRecord) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *stubAuditSink) Last(deckID string) (entity.AuditRecord, bool, error) {
+	for i := len(s.records) - 1; i >= 0; i-- {
+		if s.records[i].DeckID == deckID {
+			return s.records[i], true, nil
+		}
+	}
+	return entity.AuditRecord{}, false, nil
+}
+
+func (s *stubAuditSink) Records() ([]entity.AuditRecord, error) {
+	return s.records, nil
+}
+
+func TestAuditedDeckRepo_AuditFails(t *testing.T) {
+	sink := &stubAuditSink{}
+	store := repo.NewDeck()
+	d := NewDeckManager(NewAuditedDeckRepo(store, NewAuditLog(sink)), store.Events(), DeckOptions{})
+
+	deck, err := d.New(context.Background(), false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sink.err = errors.New("disk full")
+	if _, err := d.DrawCards(context.Background(), deck.ID, 1); !errors.Is(err, AuditRecordErr) {
+		t.Errorf("DrawCards() | got error %v, want %v", err, AuditRecordErr)
+	}
+	if _, err := d.New(context.Background(), false, nil); !errors.Is(err, AuditRecordErr) {
+		t.Errorf("New() | got error %v, want %v", err, AuditRecordErr)
+	}
+
+	// Neither failed operation was stored.
+	if got, _ := d.Open(context.Background(), deck.ID); got.Remaining != 52 || got.Version != deck.Version {
+		t.Errorf("Open() | got %d cards at version %d, want 52 at version %d", got.Remaining, got.Version, deck.Version)
+	}
+	if n, _ := store.Count(context.Background(), ""); n != 1 {
+		t.Errorf("Count() | got %d decks, want 1", n)
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	sink := &stubAuditSink{}
+	store := repo.NewDeck()
+	d := NewDeckManager(NewAuditedDeckRepo(store, NewAuditLog(sink)), store.Events(), DeckOptions{})
+
+	first, err := d.New(context.Background(), true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := d.New(context.Background(), false, []string{"AS", "2S", "3S"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.DrawCards(context.Background(), first.ID, 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := d.Deal(context.Background(), second.ID, []string{"a"}, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new log over the same sink keeps the chains going.
+	restarted := NewAuditLog(sink)
+	last, _ := d.Open(context.Background(), second.ID)
+	if _, err := restarted.Record(entity.DeckEvent{DeckID: second.ID, Version: last.Version + 1, Type: entity.DeckEventDrawn}); err != nil {
+		t.Fatal(err)
+	}
+
+	report := VerifyAudit(sink.records)
+	if report.Broken != nil || report.Records != 6 || report.Decks != 2 {
+		t.Fatalf("VerifyAudit() | got %+v, want 6 sound records over 2 decks", report)
+	}
+	for _, r := range sink.records {
+		if r.DeckID == first.ID && r.Version != r.Seq {
+			t.Errorf("AuditLog.Record() | got version %d for record %d, want %d", r.Version, r.Seq, r.Seq)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		tamper  func(records []entity.AuditRecord) []entity.AuditRecord
+		wantSeq int
+	}{
+		{
+			name: "Changed Cards",
+			tamper: func(records []entity.AuditRecord) []entity.AuditRecord {
+				records[3].Cards = []string{"KH"}
+				return records
+			},
+			wantSeq: 3,
+		},
+		{
+			name: "Rehashed Record",
+			tamper: func(records []entity.AuditRecord) []entity.AuditRecord {
+				records[2].Cards = []string{"KH"}
+				records[2].Hash = AuditHash(records[2])
+				return records
+			},
+			wantSeq: 2,
+		},
+		{
+			name: "Removed Record",
+			tamper: func(records []entity.AuditRecord) []entity.AuditRecord {
+				return append(records[:4], records[5:]...)
+			},
+			wantSeq: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := tt.tamper(append([]entity.AuditRecord{}, sink.records...))
+
+			report := VerifyAudit(records)
+			if report.Broken == nil {
+				t.Fatal("VerifyAudit() | got no broken link")
+			}
+			if report.Broken.Seq != tt.wantSeq {
+				t.Errorf("VerifyAudit() | got broken link at %+v, want record %d", report.Broken, tt.wantSeq)
+			}
+		})
+	}
+}
@@ -0,0 +1,487 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+var (
+	// BlackjackTableNotFoundErr happens when a blackjack table can't be found in the repo.
+	BlackjackTableNotFoundErr = errors.New("blackjack table not found")
+	// BlackjackIllegalActionErr happens when an action is not allowed in the current table state.
+	BlackjackIllegalActionErr = errors.New("illegal blackjack action")
+	// BlackjackInvalidOptionsErr happens when a table is created with invalid options.
+	BlackjackInvalidOptionsErr = errors.New("invalid blackjack options")
+)
+
+const (
+	defaultBlackjackDecks       = 6
+	defaultBlackjackPenetration = 0.75
+	defaultBlackjackBankroll    = 1000
+	maxBlackjackDecks           = 8
+	maxBlackjackHands           = 4
+)
+
+// BlackjackOptions configures a new blackjack table.
+// Zero values fall back to the defaults.
+type BlackjackOptions struct {
+	// Decks is the number of decks in the shoe.
+	Decks int
+	// Penetration is the fraction of the shoe dealt
+	// before the cut card comes out.
+	Penetration float64
+	// DealerHitsSoft17 switches the dealer from S17 to H17.
+	DealerHitsSoft17 bool
+	// Bankroll is the amount of chips the player sits with.
+	Bankroll int
+}
+
+// Blackjack is a use case to play blackjack on top of decks.
+type Blackjack struct {
+	deck      DeckManager
+	tableRepo BlackjackRepo
+}
+
+// NewBlackjackManager creates a new Blackjack.
+func NewBlackjackManager(deck DeckManager, store BlackjackRepo) *Blackjack {
+	return &Blackjack{
+		deck:      deck,
+		tableRepo: store,
+	}
+}
+
+// NewTable creates a new table with a freshly shuffled shoe.
+func (b *Blackjack) NewTable(ctx context.Context, opts BlackjackOptions) (entity.BlackjackTable, error) {
+	if opts.Decks == 0 {
+		opts.Decks = defaultBlackjackDecks
+	}
+	if opts.Penetration == 0 {
+		opts.Penetration = defaultBlackjackPenetration
+	}
+	if opts.Bankroll == 0 {
+		opts.Bankroll = defaultBlackjackBankroll
+	}
+
+	if opts.Decks < 1 || opts.Decks > maxBlackjackDecks {
+		return entity.BlackjackTable{}, fmt.Errorf("%w: decks must be between 1 and %d", BlackjackInvalidOptionsErr, maxBlackjackDecks)
+	}
+	if opts.Penetration < 0 || opts.Penetration > 1 {
+		return entity.BlackjackTable{}, fmt.Errorf("%w: penetration must be between 0 and 1", BlackjackInvalidOptionsErr)
+	}
+	if opts.Bankroll < 0 {
+		return entity.BlackjackTable{}, fmt.Errorf("%w: bankroll must be positive", BlackjackInvalidOptionsErr)
+	}
+
+	shoeSize := opts.Decks * len(entity.DefaultCards)
+	table := entity.BlackjackTable{
+		ID:               uuid.New().String(),
+		Decks:            opts.Decks,
+		CutCard:          shoeSize - int(float64(shoeSize)*opts.Penetration),
+		DealerHitsSoft17: opts.DealerHitsSoft17,
+		Bankroll:         opts.Bankroll,
+		Status:           entity.BlackjackStatusBetting,
+		Owner:            gameOwner(ctx),
+		Tenant:           deckTenant(ctx),
+	}
+	if err := b.reshuffle(ctx, &table); err != nil {
+		return entity.BlackjackTable{}, err
+	}
+
+	b.tableRepo.Save(table)
+
+	return table, nil
+}
+
+// Table returns a table or an error in case the
+// table can't be found.
+func (b *Blackjack) Table(ctx context.Context, id string) (entity.BlackjackTable, error) {
+	return b.table(ctx, id)
+}
+
+// Deal takes the bet and deals a new round. The shoe is
+// replaced when the cut card has been reached.
+func (b *Blackjack) Deal(ctx context.Context, id string, bet int) (entity.BlackjackTable, error) {
+	return b.update(ctx, id, func(table *entity.BlackjackTable) error {
+		if table.Status == entity.BlackjackStatusPlaying {
+			return fmt.Errorf("%w: round already in progress", BlackjackIllegalActionErr)
+		}
+		if bet <= 0 || bet > table.Bankroll {
+			return fmt.Errorf("%w: bet must be between 1 and %d", BlackjackIllegalActionErr, table.Bankroll)
+		}
+
+		if table.ShoeRemaining <= table.CutCard {
+			if err := b.reshuffle(ctx, table); err != nil {
+				return err
+			}
+		}
+
+		table.Bankroll -= bet
+		table.Status = entity.BlackjackStatusPlaying
+		table.ActiveHand = 0
+		table.Dealer = nil
+		hand := entity.BlackjackHand{Bet: bet}
+		for i := 0; i < 2; i++ {
+			card, err := b.draw(ctx, table)
+			if err != nil {
+				return err
+			}
+			hand.Cards = append(hand.Cards, card)
+
+			card, err = b.draw(ctx, table)
+			if err != nil {
+				return err
+			}
+			table.Dealer = append(table.Dealer, card)
+		}
+		table.Hands = []entity.BlackjackHand{hand}
+
+		// The dealer peeks for blackjack, so naturals end the round right away.
+		if isNatural(table.Hands[0]) || isBlackjackNatural(table.Dealer) {
+			table.Hands[0].Done = true
+		}
+
+		return b.advance(ctx, table)
+	})
+}
+
+// Hit draws a card to the active hand.
+func (b *Blackjack) Hit(ctx context.Context, id string) (entity.BlackjackTable, error) {
+	return b.act(ctx, id, func(table *entity.BlackjackTable, hand *entity.BlackjackHand) error {
+		card, err := b.draw(ctx, table)
+		if err != nil {
+			return err
+		}
+		hand.Cards = append(hand.Cards, card)
+
+		if total, _ := blackjackScore(hand.Cards); total >= 21 {
+			hand.Done = true
+		}
+		return nil
+	})
+}
+
+// Stand finishes the active hand.
+func (b *Blackjack) Stand(ctx context.Context, id string) (entity.BlackjackTable, error) {
+	return b.act(ctx, id, func(_ *entity.BlackjackTable, hand *entity.BlackjackHand) error {
+		hand.Done = true
+		return nil
+	})
+}
+
+// Double doubles the bet of the active hand and draws
+// exactly one more card to it.
+func (b *Blackjack) Double(ctx context.Context, id string) (entity.BlackjackTable, error) {
+	return b.act(ctx, id, func(table *entity.BlackjackTable, hand *entity.BlackjackHand) error {
+		if len(hand.Cards) != 2 {
+			return fmt.Errorf("%w: can only double on the first two cards", BlackjackIllegalActionErr)
+		}
+		if table.Bankroll < hand.Bet {
+			return fmt.Errorf("%w: not enough bankroll to double", BlackjackIllegalActionErr)
+		}
+
+		card, err := b.draw(ctx, table)
+		if err != nil {
+			return err
+		}
+
+		table.Bankroll -= hand.Bet
+		hand.Bet *= 2
+		hand.Doubled = true
+		hand.Cards = append(hand.Cards, card)
+		hand.Done = true
+		return nil
+	})
+}
+
+// Split splits a pair in the active hand into two hands.
+func (b *Blackjack) Split(ctx context.Context, id string) (entity.BlackjackTable, error) {
+	return b.act(ctx, id, func(table *entity.BlackjackTable, hand *entity.BlackjackHand) error {
+		if len(hand.Cards) != 2 || cardPoints(hand.Cards[0]) != cardPoints(hand.Cards[1]) {
+			return fmt.Errorf("%w: can only split a pair", BlackjackIllegalActionErr)
+		}
+		if len(table.Hands) >= maxBlackjackHands {
+			return fmt.Errorf("%w: can't split to more than %d hands", BlackjackIllegalActionErr, maxBlackjackHands)
+		}
+		if table.Bankroll < hand.Bet {
+			return fmt.Errorf("%w: not enough bankroll to split", BlackjackIllegalActionErr)
+		}
+
+		table.Bankroll -= hand.Bet
+		second := entity.BlackjackHand{
+			Cards:     []entity.Card{hand.Cards[1]},
+			Bet:       hand.Bet,
+			FromSplit: true,
+		}
+		hand.Cards = []entity.Card{hand.Cards[0]}
+		hand.FromSplit = true
+
+		hands := make([]entity.BlackjackHand, 0, len(table.Hands)+1)
+		hands = append(hands, table.Hands[:table.ActiveHand+1]...)
+		hands = append(hands, second)
+		hands = append(hands, table.Hands[table.ActiveHand+1:]...)
+		table.Hands = hands
+		return nil
+	})
+}
+
+// Surrender gives up the hand, returning half of the bet.
+// It is only allowed as the first decision of the round.
+func (b *Blackjack) Surrender(ctx context.Context, id string) (entity.BlackjackTable, error) {
+	return b.act(ctx, id, func(_ *entity.BlackjackTable, hand *entity.BlackjackHand) error {
+		if len(hand.Cards) != 2 || hand.FromSplit {
+			return fmt.Errorf("%w: can only surrender the initial hand", BlackjackIllegalActionErr)
+		}
+
+		hand.Result = entity.BlackjackResultSurrender
+		hand.Done = true
+		return nil
+	})
+}
+
+// act runs a player decision on the active hand, moves the
+// round forward and saves the table.
+func (b *Blackjack) act(ctx context.Context, id string, action func(table *entity.BlackjackTable, hand *entity.BlackjackHand) error) (entity.BlackjackTable, error) {
+	return b.update(ctx, id, func(table *entity.BlackjackTable) error {
+		if table.Status != entity.BlackjackStatusPlaying {
+			return fmt.Errorf("%w: no round in progress", BlackjackIllegalActionErr)
+		}
+
+		hand := table.Hands[table.ActiveHand]
+		if err := action(table, &hand); err != nil {
+			return err
+		}
+		table.Hands[table.ActiveHand] = hand
+
+		return b.advance(ctx, table)
+	})
+}
+
+// advance moves to the next hand waiting for a decision and
+// finishes the round when there is none left.
+func (b *Blackjack) advance(ctx context.Context, table *entity.BlackjackTable) error {
+	for table.ActiveHand < len(table.Hands) {
+		hand := &table.Hands[table.ActiveHand]
+
+		// Split hands get their second card once they become active.
+		if len(hand.Cards) == 1 {
+			card, err := b.draw(ctx, table)
+			if err != nil {
+				return err
+			}
+			hand.Cards = append(hand.Cards, card)
+
+			total, _ := blackjackScore(hand.Cards)
+			if hand.Cards[0].Value == "ACE" || total == 21 {
+				hand.Done = true
+			}
+		}
+
+		if !hand.Done {
+			return nil
+		}
+		table.ActiveHand++
+	}
+
+	return b.finishRound(ctx, table)
+}
+
+// finishRound plays the dealer hand and settles every bet.
+func (b *Blackjack) finishRound(ctx context.Context, table *entity.BlackjackTable) error {
+	dealerPlays := false
+	for _, hand := range table.Hands {
+		total, _ := blackjackScore(hand.Cards)
+		if total <= 21 && hand.Result != entity.BlackjackResultSurrender && !isNatural(hand) {
+			dealerPlays = true
+		}
+	}
+
+	if dealerPlays && !isBlackjackNatural(table.Dealer) {
+		for {
+			total, soft := blackjackScore(table.Dealer)
+			if total > 17 || (total == 17 && !(soft && table.DealerHitsSoft17)) {
+				break
+			}
+
+			card, err := b.draw(ctx, table)
+			if err != nil {
+				return err
+			}
+			table.Dealer = append(table.Dealer, card)
+		}
+	}
+
+	dealerTotal, _ := blackjackScore(table.Dealer)
+	dealerNatural := isBlackjackNatural(table.Dealer)
+	for i := range table.Hands {
+		hand := &table.Hands[i]
+		total, _ := blackjackScore(hand.Cards)
+
+		switch {
+		case hand.Result == entity.BlackjackResultSurrender:
+			hand.Payout = hand.Bet / 2
+		case total > 21:
+			hand.Result = entity.BlackjackResultBust
+		case isNatural(*hand) && dealerNatural:
+			hand.Result = entity.BlackjackResultPush
+			hand.Payout = hand.Bet
+		case isNatural(*hand):
+			hand.Result = entity.BlackjackResultBlackjack
+			hand.Payout = hand.Bet + hand.Bet*3/2
+		case dealerNatural:
+			hand.Result = entity.BlackjackResultLose
+		case dealerTotal > 21 || total > dealerTotal:
+			hand.Result = entity.BlackjackResultWin
+			hand.Payout = hand.Bet * 2
+		case total == dealerTotal:
+			hand.Result = entity.BlackjackResultPush
+			hand.Payout = hand.Bet
+		default:
+			hand.Result = entity.BlackjackResultLose
+		}
+
+		hand.Done = true
+		table.Bankroll += hand.Payout
+	}
+
+	table.Status = entity.BlackjackStatusBetting
+
+	return nil
+}
+
+// draw takes the next card from the shoe, replacing
+// the shoe when it runs out mid-round, or when it's gone
+// because a failed reshuffle deleted it.
+func (b *Blackjack) draw(ctx context.Context, table *entity.BlackjackTable) (entity.Card, error) {
+	cards, err := b.deck.DrawCards(ctx, table.ShoeID, 1)
+	if err != nil && !errors.Is(err, DeckNotFoundErr) {
+		return entity.Card{}, err
+	}
+
+	if len(cards) == 0 {
+		if err := b.reshuffle(ctx, table); err != nil {
+			return entity.Card{}, err
+		}
+		cards, err = b.deck.DrawCards(ctx, table.ShoeID, 1)
+		if err != nil {
+			return entity.Card{}, err
+		}
+		if len(cards) == 0 {
+			return entity.Card{}, fmt.Errorf("shoe %s has no cards", table.ShoeID)
+		}
+	}
+
+	table.ShoeRemaining--
+
+	return cards[0], nil
+}
+
+// reshuffle binds the table to a new shuffled shoe. The
+// old shoe is deleted first, so it doesn't count against
+// the decks the tenant may keep.
+func (b *Blackjack) reshuffle(ctx context.Context, table *entity.BlackjackTable) error {
+	if table.ShoeID != "" {
+		if err := b.deck.Delete(ctx, table.ShoeID); err != nil && !errors.Is(err, DeckNotFoundErr) {
+			return err
+		}
+	}
+	shoe, err := b.deck.New(ctx, true, defaultCardCodes(table.Decks))
+	if err != nil {
+		return err
+	}
+	table.ShoeID = shoe.ID
+	table.ShoeRemaining = shoe.Remaining
+	return nil
+}
+
+// table returns a table of the tenant of ctx, once the
+// caller is found to own it.
+func (b *Blackjack) table(ctx context.Context, id string) (entity.BlackjackTable, error) {
+	table, err := b.tableRepo.Get(deckTenant(ctx), id)
+	if err != nil {
+		if errors.Is(err, repo.BlackjackTableNotFoundErr) {
+			return entity.BlackjackTable{}, fmt.Errorf("%w with id %s", BlackjackTableNotFoundErr, id)
+		}
+		return entity.BlackjackTable{}, err
+	}
+	if err := checkGameOwner(ctx, "blackjack table", id, table.Owner); err != nil {
+		return entity.BlackjackTable{}, err
+	}
+
+	return table, nil
+}
+
+// update runs fn on a table of the tenant of ctx, once the
+// caller is found to own it, and saves the table when fn
+// succeeds. Other changes to the table wait meanwhile.
+func (b *Blackjack) update(ctx context.Context, id string, fn func(table *entity.BlackjackTable) error) (entity.BlackjackTable, error) {
+	table, err := b.tableRepo.Update(deckTenant(ctx), id, func(table *entity.BlackjackTable) error {
+		if err := checkGameOwner(ctx, "blackjack table", id, table.Owner); err != nil {
+			return err
+		}
+
+		// Hands are changed in place, so they can't share
+		// memory with what is in the store.
+		table.Hands = append([]entity.BlackjackHand(nil), table.Hands...)
+
+		return fn(table)
+	})
+	if errors.Is(err, repo.BlackjackTableNotFoundErr) {
+		return entity.BlackjackTable{}, fmt.Errorf("%w with id %s", BlackjackTableNotFoundErr, id)
+	}
+	return table, err
+}
+
+// BlackjackScore returns the best blackjack total of the cards.
+func BlackjackScore(cards []entity.Card) int {
+	total, _ := blackjackScore(cards)
+	return total
+}
+
+// blackjackScore returns the best total of the cards and
+// whether an ace is being counted as 11.
+func blackjackScore(cards []entity.Card) (int, bool) {
+	total := 0
+	hasAce := false
+	for _, c := range cards {
+		total += cardPoints(c)
+		if c.Value == "ACE" {
+			hasAce = true
+		}
+	}
+
+	if hasAce && total+10 <= 21 {
+		return total + 10, true
+	}
+
+	return total, false
+}
+
+// cardPoints returns the blackjack value of a card,
+// counting aces as 1.
+func cardPoints(c entity.Card) int {
+	switch c.Value {
+	case "ACE":
+		return 1
+	case "JACK", "QUEEN", "KING":
+		return 10
+	}
+
+	v, _ := strconv.Atoi(c.Value)
+	return v
+}
+
+func isBlackjackNatural(cards []entity.Card) bool {
+	total, _ := blackjackScore(cards)
+	return len(cards) == 2 && total == 21
+}
+
+func isNatural(hand entity.BlackjackHand) bool {
+	return !hand.FromSplit && isBlackjackNatural(hand.Cards)
+}
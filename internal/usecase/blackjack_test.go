@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+// stubShoe deals the given codes in order and counts
+// how many shoes were created and deleted.
+type stubShoe struct {
+	codes   []string
+	shoes   int
+	deleted []string
+}
+
+func (s *stubShoe) New(_ context.Context, shuffle bool, cardCodes []string) (entity.Deck, error) {
+	s.shoes++
+	return entity.Deck{ID: "shoe", Shuffled: shuffle, Remaining: len(cardCodes)}, nil
+}
+
+func (s *stubShoe) Open(_ context.Context, id string) (entity.Deck, error) {
+	return entity.Deck{ID: id}, nil
+}
+
+func (s *stubShoe) DrawCards(_ context.Context, _ string, amount int) ([]entity.Card, error) {
+	var cards []entity.Card
+	for i := 0; i < amount && len(s.codes) > 0; i++ {
+		cards = append(cards, cardByCode(s.codes[0]))
+		s.codes = s.codes[1:]
+	}
+	return cards, nil
+}
+
+func (s *stubShoe) Deal(_ context.Context, id string, _ []string, _ int) (entity.Deck, map[string]string, error) {
+	return entity.Deck{ID: id}, nil, nil
+}
+
+func (s *stubShoe) Return(_ context.Context, id string, _ []string) (entity.Deck, error) {
+	return entity.Deck{ID: id}, nil
+}
+
+func (s *stubShoe) Events(context.Context, string) ([]entity.DeckEvent, error) {
+	return nil, nil
+}
+
+func (s *stubShoe) At(_ context.Context, id string, _ int) (entity.Deck, error) {
+	return entity.Deck{ID: id}, nil
+}
+
+func (s *stubShoe) Delete(_ context.Context, id string) error {
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+func cardByCode(code string) entity.Card {
+	for _, c := range entity.DefaultCards {
+		if c.Code == code {
+			return c
+		}
+	}
+	return entity.Card{}
+}
+
+func newTestTable(t *testing.T, h17 bool, codes ...string) (*Blackjack, entity.BlackjackTable) {
+	t.Helper()
+	b := NewBlackjackManager(&stubShoe{codes: codes}, repo.NewBlackjackTable())
+	table, err := b.NewTable(context.Background(), BlackjackOptions{Decks: 1, Bankroll: 100, DealerHitsSoft17: h17})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b, table
+}
+
+func TestBlackjack_NewTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    BlackjackOptions
+		want    entity.BlackjackTable
+		wantErr error
+	}{
+		{
+			name: "Defaults",
+			want: entity.BlackjackTable{
+				ShoeID:        "shoe",
+				Decks:         6,
+				ShoeRemaining: 312,
+				CutCard:       78,
+				Bankroll:      1000,
+				Status:        entity.BlackjackStatusBetting,
+			},
+		},
+		{
+			name: "Custom",
+			opts: BlackjackOptions{Decks: 2, Penetration: 0.5, Bankroll: 50, DealerHitsSoft17: true},
+			want: entity.BlackjackTable{
+				ShoeID:           "shoe",
+				Decks:            2,
+				ShoeRemaining:    104,
+				CutCard:          52,
+				DealerHitsSoft17: true,
+				Bankroll:         50,
+				Status:           entity.BlackjackStatusBetting,
+			},
+		},
+		{
+			name:    "Too Many Decks",
+			opts:    BlackjackOptions{Decks: 9},
+			wantErr: BlackjackInvalidOptionsErr,
+		},
+		{
+			name:    "Bad Penetration",
+			opts:    BlackjackOptions{Penetration: 1.5},
+			wantErr: BlackjackInvalidOptionsErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBlackjackManager(&stubShoe{}, repo.NewBlackjackTable())
+			got, err := b.NewTable(context.Background(), tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Blackjack.NewTable() | got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			tt.want.ID = got.ID
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Fatalf("Blackjack.NewTable() | (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBlackjack_Rounds(t *testing.T) {
+	type action func(b *Blackjack, ctx context.Context, id string) (entity.BlackjackTable, error)
+	hit, stand, double := (*Blackjack).Hit, (*Blackjack).Stand, (*Blackjack).Double
+	split, surrender := (*Blackjack).Split, (*Blackjack).Surrender
+
+	// Cards are dealt player, dealer, player, dealer, then in draw order.
+	tests := []struct {
+		name         string
+		h17          bool
+		codes        []string
+		actions      []action
+		wantResults  []string
+		wantBankroll int
+		wantDealer   int
+	}{
+		{
+			name:         "Stand And Win",
+			codes:        []string{"KS", "9S", "QS", "8S"},
+			actions:      []action{stand},
+			wantResults:  []string{entity.BlackjackResultWin},
+			wantBankroll: 110,
+			wantDealer:   2,
+		},
+		{
+			name:         "Player Blackjack Pays 3 To 2",
+			codes:        []string{"AS", "9S", "KS", "8S"},
+			wantResults:  []string{entity.BlackjackResultBlackjack},
+			wantBankroll: 115,
+			wantDealer:   2,
+		},
+		{
+			name:         "Dealer Blackjack",
+			codes:        []string{"9S", "AS", "9D", "KS"},
+			wantResults:  []string{entity.BlackjackResultLose},
+			wantBankroll: 90,
+			wantDealer:   2,
+		},
+		{
+			name:         "Hit And Bust",
+			codes:        []string{"KS", "9S", "6S", "8S", "QS"},
+			actions:      []action{hit},
+			wantResults:  []string{entity.BlackjackResultBust},
+			wantBankroll: 90,
+			wantDealer:   2,
+		},
+		{
+			name:         "Double Down",
+			codes:        []string{"6S", "KS", "5S", "7S", "KD"},
+			actions:      []action{double},
+			wantResults:  []string{entity.BlackjackResultWin},
+			wantBankroll: 120,
+			wantDealer:   2,
+		},
+		{
+			name:         "Surrender",
+			codes:        []string{"KS", "9S", "6S", "KD"},
+			actions:      []action{surrender},
+			wantResults:  []string{entity.BlackjackResultSurrender},
+			wantBankroll: 95,
+			wantDealer:   2,
+		},
+		{
+			name:         "Split Eights",
+			codes:        []string{"8S", "KS", "8D", "7S", "KD", "JS"},
+			actions:      []action{split, stand, stand},
+			wantResults:  []string{entity.BlackjackResultWin, entity.BlackjackResultWin},
+			wantBankroll: 120,
+			wantDealer:   2,
+		},
+		{
+			name:         "Dealer Stands On Soft 17",
+			codes:        []string{"KS", "AS", "8S", "6S"},
+			actions:      []action{stand},
+			wantResults:  []string{entity.BlackjackResultWin},
+			wantBankroll: 110,
+			wantDealer:   2,
+		},
+		{
+			name:         "Dealer Hits Soft 17",
+			h17:          true,
+			codes:        []string{"KS", "AS", "8S", "6S", "3S"},
+			actions:      []action{stand},
+			wantResults:  []string{entity.BlackjackResultLose},
+			wantBankroll: 90,
+			wantDealer:   3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, table := newTestTable(t, tt.h17, tt.codes...)
+
+			got, err := b.Deal(context.Background(), table.ID, 10)
+			if err != nil {
+				t.Fatalf("Blackjack.Deal() | got error %v, want nil", err)
+			}
+			for _, a := range tt.actions {
+				if got, err = a(b, context.Background(), table.ID); err != nil {
+					t.Fatalf("Blackjack action | got error %v, want nil", err)
+				}
+			}
+
+			if got.Status != entity.BlackjackStatusBetting {
+				t.Errorf("Blackjack | got status %s, want %s", got.Status, entity.BlackjackStatusBetting)
+			}
+			var results []string
+			for _, h := range got.Hands {
+				results = append(results, h.Result)
+			}
+			if diff := cmp.Diff(results, tt.wantResults); diff != "" {
+				t.Errorf("Blackjack | results (-got +want):\n%s", diff)
+			}
+			if got.Bankroll != tt.wantBankroll {
+				t.Errorf("Blackjack | got bankroll %d, want %d", got.Bankroll, tt.wantBankroll)
+			}
+			if len(got.Dealer) != tt.wantDealer {
+				t.Errorf("Blackjack | got %d dealer cards, want %d", len(got.Dealer), tt.wantDealer)
+			}
+		})
+	}
+}
+
+func TestBlackjack_IllegalActions(t *testing.T) {
+	b, table := newTestTable(t, false, "KS", "9S", "6S", "8S", "2S")
+
+	if _, err := b.Hit(context.Background(), table.ID); !errors.Is(err, BlackjackIllegalActionErr) {
+		t.Errorf("Blackjack.Hit() | got error %v, want %v", err, BlackjackIllegalActionErr)
+	}
+	if _, err := b.Deal(context.Background(), table.ID, 1000); !errors.Is(err, BlackjackIllegalActionErr) {
+		t.Errorf("Blackjack.Deal() | got error %v, want %v", err, BlackjackIllegalActionErr)
+	}
+	if _, err := b.Deal(context.Background(), table.ID, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Deal(context.Background(), table.ID, 10); !errors.Is(err, BlackjackIllegalActionErr) {
+		t.Errorf("Blackjack.Deal() | got error %v, want %v", err, BlackjackIllegalActionErr)
+	}
+	if _, err := b.Split(context.Background(), table.ID); !errors.Is(err, BlackjackIllegalActionErr) {
+		t.Errorf("Blackjack.Split() | got error %v, want %v", err, BlackjackIllegalActionErr)
+	}
+	if _, err := b.Hit(context.Background(), table.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Double(context.Background(), table.ID); !errors.Is(err, BlackjackIllegalActionErr) {
+		t.Errorf("Blackjack.Double() | got error %v, want %v", err, BlackjackIllegalActionErr)
+	}
+	if _, err := b.Surrender(context.Background(), table.ID); !errors.Is(err, BlackjackIllegalActionErr) {
+		t.Errorf("Blackjack.Surrender() | got error %v, want %v", err, BlackjackIllegalActionErr)
+	}
+}
+
+func TestBlackjack_Reshuffle(t *testing.T) {
+	shoe := &stubShoe{codes: []string{"KS", "9S", "QS", "8S"}}
+	b := NewBlackjackManager(shoe, repo.NewBlackjackTable())
+	table, err := b.NewTable(context.Background(), BlackjackOptions{Decks: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Past the cut card the next deal must bring a new shoe.
+	table.ShoeRemaining = table.CutCard
+	b.tableRepo.Save(table)
+
+	got, err := b.Deal(context.Background(), table.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shoe.shoes != 2 {
+		t.Errorf("Blackjack.Deal() | got %d shoes, want 2", shoe.shoes)
+	}
+	if diff := cmp.Diff(shoe.deleted, []string{"shoe"}); diff != "" {
+		t.Errorf("Blackjack.Deal() | deleted shoes (-got +want):\n%s", diff)
+	}
+	if got.ShoeRemaining != 52-4 {
+		t.Errorf("Blackjack.Deal() | got %d cards in the shoe, want %d", got.ShoeRemaining, 52-4)
+	}
+}
+
+func TestBlackjack_Reshuffle_TenantDecks(t *testing.T) {
+	ctx := WithPrincipal(context.Background(), entity.Principal{ID: "alice", Tenant: "acme"})
+	ctx = WithTenant(ctx, entity.Tenant{Name: "acme", Limits: entity.TenantLimits{MaxDecks: 1}})
+	b := NewBlackjackManager(newMemoryDeckManager(DeckOptions{}), repo.NewBlackjackTable())
+
+	table, err := b.NewTable(ctx, BlackjackOptions{Decks: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The old shoe makes way for the new one within the
+	// tenant's single deck.
+	for i := 0; i < 3; i++ {
+		table.ShoeRemaining = table.CutCard
+		table.Status = entity.BlackjackStatusBetting
+		b.tableRepo.Save(table)
+
+		if table, err = b.Deal(ctx, table.ID, 10); err != nil {
+			t.Fatalf("Blackjack.Deal() | reshuffle %d got error %v, want nil", i, err)
+		}
+	}
+}
+
+func TestBlackjack_Table_NotFound(t *testing.T) {
+	b := NewBlackjackManager(&stubShoe{}, repo.NewBlackjackTable())
+	if _, err := b.Table(context.Background(), "id"); !errors.Is(err, BlackjackTableNotFoundErr) {
+		t.Errorf("Blackjack.Table() | got error %v, want %v", err, BlackjackTableNotFoundErr)
+	}
+}
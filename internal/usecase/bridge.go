@@ -0,0 +1,303 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+var (
+	// BridgeDealNotFoundErr happens when a bridge deal can't be found in the repo.
+	BridgeDealNotFoundErr = errors.New("bridge deal not found")
+	// BridgeInvalidOptionsErr happens when a deal is asked with invalid options.
+	BridgeInvalidOptionsErr = errors.New("invalid bridge options")
+	// BridgeConstraintsErr happens when no deal matching the constraints is found.
+	BridgeConstraintsErr = errors.New("no bridge deal matches the constraints")
+)
+
+const (
+	bridgeHandSize = 13
+	bridgeMaxHCP   = 37
+	// bridgeMaxAttempts is how many deals are tried
+	// before giving up on the constraints, which keeps a
+	// request for a rare deal under a second.
+	bridgeMaxAttempts = 10000
+)
+
+// bridgeVulnerability is the vulnerability of boards 1 to 16,
+// repeated every 16 boards.
+var bridgeVulnerability = []string{
+	entity.BridgeVulnerableNone, entity.BridgeVulnerableNS, entity.BridgeVulnerableEW, entity.BridgeVulnerableAll,
+	entity.BridgeVulnerableNS, entity.BridgeVulnerableEW, entity.BridgeVulnerableAll, entity.BridgeVulnerableNone,
+	entity.BridgeVulnerableEW, entity.BridgeVulnerableAll, entity.BridgeVulnerableNone, entity.BridgeVulnerableNS,
+	entity.BridgeVulnerableAll, entity.BridgeVulnerableNone, entity.BridgeVulnerableNS, entity.BridgeVulnerableEW,
+}
+
+// bridgeSuits is the order suits are shown in.
+var bridgeSuits = []string{"SPADES", "HEARTS", "DIAMONDS", "CLUBS"}
+
+// BridgeConstraint limits the hand dealt to a seat.
+type BridgeConstraint struct {
+	Seat     string
+	MinHCP   int
+	MaxHCP   int
+	Balanced bool
+}
+
+// Bridge is a use case to deal bridge boards.
+type Bridge struct {
+	deck     DeckManager
+	dealRepo BridgeRepo
+	shuffler func([]entity.Card)
+}
+
+// NewBridgeManager creates a new Bridge. Deals are
+// shuffled with the shuffle strategy of the decks.
+func NewBridgeManager(deck DeckManager, store BridgeRepo, shuffle string) *Bridge {
+	return &Bridge{
+		deck:     deck,
+		dealRepo: store,
+		shuffler: deckShuffler(shuffle),
+	}
+}
+
+// NewDeal deals a board. Deals are shuffled again until
+// every constraint is met, or ctx is done.
+func (b *Bridge) NewDeal(ctx context.Context, board int, constraints []BridgeConstraint) (entity.BridgeDeal, error) {
+	if board < 1 {
+		return entity.BridgeDeal{}, fmt.Errorf("%w: board must be at least 1", BridgeInvalidOptionsErr)
+	}
+	if err := validateBridgeConstraints(constraints); err != nil {
+		return entity.BridgeDeal{}, err
+	}
+
+	dealer, vulnerable := bridgeBoard(board)
+
+	cards := append([]entity.Card{}, entity.DefaultCards...)
+	for attempt := 0; ; attempt++ {
+		if attempt == bridgeMaxAttempts {
+			return entity.BridgeDeal{}, fmt.Errorf("%w after %d deals", BridgeConstraintsErr, bridgeMaxAttempts)
+		}
+		if err := ctx.Err(); err != nil {
+			return entity.BridgeDeal{}, err
+		}
+		b.shuffler(cards)
+		if bridgeMatches(dealBridgeHands(cards, dealer), constraints) {
+			break
+		}
+	}
+
+	codes := make([]string, len(cards))
+	for i, c := range cards {
+		codes[i] = c.Code
+	}
+
+	deal := entity.BridgeDeal{
+		Board:      board,
+		Dealer:     dealer,
+		Vulnerable: vulnerable,
+	}
+	return b.save(ctx, deal, codes)
+}
+
+// Deal returns a deal of the tenant of ctx owned by the
+// caller, or an error in case the deal can't be found.
+func (b *Bridge) Deal(ctx context.Context, id string) (entity.BridgeDeal, error) {
+	deal, err := b.dealRepo.Get(deckTenant(ctx), id)
+	if err != nil {
+		if errors.Is(err, repo.BridgeDealNotFoundErr) {
+			return entity.BridgeDeal{}, fmt.Errorf("%w with id %s", BridgeDealNotFoundErr, id)
+		}
+		return entity.BridgeDeal{}, err
+	}
+	if err := checkGameOwner(ctx, "bridge deal", id, deal.Owner); err != nil {
+		return entity.BridgeDeal{}, err
+	}
+
+	return deal, nil
+}
+
+// ExportPBN returns a deal in Portable Bridge Notation.
+func (b *Bridge) ExportPBN(ctx context.Context, id string) (string, error) {
+	deal, err := b.Deal(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	return FormatPBN([]entity.BridgeDeal{deal}), nil
+}
+
+// ImportPBN stores the deals of a PBN file, each one
+// with its own deck.
+func (b *Bridge) ImportPBN(ctx context.Context, pbn string) ([]entity.BridgeDeal, error) {
+	parsed, err := ParsePBN(pbn)
+	if err != nil {
+		return nil, err
+	}
+
+	var deals []entity.BridgeDeal
+	for _, deal := range parsed {
+		// The deck is stacked so that dealing it gives
+		// every seat its hand back.
+		d := bridgeSeatIndex(deal.Dealer)
+		codes := make([]string, len(entity.DefaultCards))
+		for i := range codes {
+			seat := entity.BridgeSeats[(d+1+i)%len(entity.BridgeSeats)]
+			codes[i] = deal.Hands[seat][i/len(entity.BridgeSeats)].Code
+		}
+
+		saved, err := b.save(ctx, deal, codes)
+		if err != nil {
+			return nil, err
+		}
+		deals = append(deals, saved)
+	}
+
+	return deals, nil
+}
+
+// save opens a deck with the codes in dealing order
+// and deals it to the seats, deleting the deck after.
+func (b *Bridge) save(ctx context.Context, deal entity.BridgeDeal, codes []string) (entity.BridgeDeal, error) {
+	deck, err := b.deck.New(ctx, false, codes)
+	if err != nil {
+		return entity.BridgeDeal{}, err
+	}
+
+	// The deal keeps the cards, so the deck is deleted once
+	// drained rather than left counting against the decks
+	// the tenant may keep. Its history is still kept.
+	cards, err := b.deck.DrawCards(ctx, deck.ID, len(codes))
+	if delErr := b.deck.Delete(ctx, deck.ID); delErr != nil && err == nil {
+		err = delErr
+	}
+	if err != nil {
+		return entity.BridgeDeal{}, err
+	}
+	if len(cards) != len(entity.DefaultCards) {
+		return entity.BridgeDeal{}, fmt.Errorf("deck %s has %d cards, want %d", deck.ID, len(cards), len(entity.DefaultCards))
+	}
+
+	deal.ID = uuid.New().String()
+	deal.DeckID = deck.ID
+	deal.Hands = dealBridgeHands(cards, deal.Dealer)
+	deal.Owner = gameOwner(ctx)
+	deal.Tenant = deckTenant(ctx)
+
+	b.dealRepo.Save(deal)
+
+	return deal, nil
+}
+
+// BridgeStats returns the high card points and
+// distribution of a hand.
+func BridgeStats(cards []entity.Card) entity.BridgeHandStats {
+	var stats entity.BridgeHandStats
+	for _, c := range cards {
+		if r := pokerRank(c); r > 10 {
+			stats.HCP += r - 10
+		}
+		stats.Lengths[bridgeSuitIndex(c)]++
+	}
+
+	lengths := stats.Lengths[:]
+	sorted := append([]int{}, lengths...)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+
+	shape := make([]string, len(sorted))
+	for i, l := range sorted {
+		shape[i] = strconv.Itoa(l)
+	}
+	stats.Shape = strings.Join(shape, "-")
+
+	switch stats.Shape {
+	case "4-3-3-3", "4-4-3-2", "5-3-3-2":
+		stats.Balanced = true
+	}
+
+	return stats
+}
+
+// bridgeBoard returns the dealer and the vulnerability
+// of a board number.
+func bridgeBoard(board int) (string, string) {
+	return entity.BridgeSeats[(board-1)%len(entity.BridgeSeats)], bridgeVulnerability[(board-1)%len(bridgeVulnerability)]
+}
+
+// dealBridgeHands deals the cards one at a time, starting
+// at the left of the dealer, and sorts each hand.
+func dealBridgeHands(cards []entity.Card, dealer string) map[string][]entity.Card {
+	hands := make(map[string][]entity.Card, len(entity.BridgeSeats))
+	d := bridgeSeatIndex(dealer)
+	for i, c := range cards {
+		seat := entity.BridgeSeats[(d+1+i)%len(entity.BridgeSeats)]
+		hands[seat] = append(hands[seat], c)
+	}
+	for _, hand := range hands {
+		sortBridgeHand(hand)
+	}
+	return hands
+}
+
+func sortBridgeHand(hand []entity.Card) {
+	sort.Slice(hand, func(i, j int) bool {
+		si, sj := bridgeSuitIndex(hand[i]), bridgeSuitIndex(hand[j])
+		if si != sj {
+			return si < sj
+		}
+		return pokerRank(hand[i]) > pokerRank(hand[j])
+	})
+}
+
+func validateBridgeConstraints(constraints []BridgeConstraint) error {
+	seen := make(map[string]bool)
+	for _, c := range constraints {
+		if bridgeSeatIndex(c.Seat) < 0 {
+			return fmt.Errorf("%w: unknown seat %q", BridgeInvalidOptionsErr, c.Seat)
+		}
+		if seen[c.Seat] {
+			return fmt.Errorf("%w: seat %s has more than one constraint", BridgeInvalidOptionsErr, c.Seat)
+		}
+		seen[c.Seat] = true
+		if c.MinHCP < 0 || c.MaxHCP > bridgeMaxHCP || c.MinHCP > c.MaxHCP {
+			return fmt.Errorf("%w: seat %s HCP range %d-%d", BridgeInvalidOptionsErr, c.Seat, c.MinHCP, c.MaxHCP)
+		}
+	}
+	return nil
+}
+
+func bridgeMatches(hands map[string][]entity.Card, constraints []BridgeConstraint) bool {
+	for _, c := range constraints {
+		stats := BridgeStats(hands[c.Seat])
+		if stats.HCP < c.MinHCP || stats.HCP > c.MaxHCP || (c.Balanced && !stats.Balanced) {
+			return false
+		}
+	}
+	return true
+}
+
+func bridgeSeatIndex(seat string) int {
+	for i, s := range entity.BridgeSeats {
+		if s == seat {
+			return i
+		}
+	}
+	return -1
+}
+
+func bridgeSuitIndex(c entity.Card) int {
+	for i, s := range bridgeSuits {
+		if s == c.Suit {
+			return i
+		}
+	}
+	return 0
+}
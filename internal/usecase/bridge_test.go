@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+const bridgeTestPBN = `% A board from the club
+[Board "6"]
+[Dealer "E"]
+[Vulnerable "EW"]
+[Deal "E:AKQJ.AKQ.AKQ.AK2 T98.T98.T98.T987 765.765.765.6543 432.J432.J432.QJ"]
+`
+
+func newTestBridge(seed int64) *Bridge {
+	b := NewBridgeManager(newMemoryDeckManager(DeckOptions{}), repo.NewBridgeDeal(), "")
+	r := rand.New(rand.NewSource(seed))
+	b.shuffler = func(cards []entity.Card) {
+		r.Shuffle(len(cards), func(i, j int) {
+			cards[i], cards[j] = cards[j], cards[i]
+		})
+	}
+	return b
+}
+
+func TestBridge_NewDeal(t *testing.T) {
+	b := newTestBridge(1)
+
+	deal, err := b.NewDeal(context.Background(), 7, []BridgeConstraint{{Seat: entity.BridgeNorth, MinHCP: 15, MaxHCP: 17, Balanced: true}})
+	if err != nil {
+		t.Fatalf("Bridge.NewDeal() | got error %v, want nil", err)
+	}
+
+	if deal.Dealer != entity.BridgeSouth || deal.Vulnerable != entity.BridgeVulnerableAll {
+		t.Errorf("Bridge.NewDeal() | got dealer %s and vulnerable %s, want S and All", deal.Dealer, deal.Vulnerable)
+	}
+
+	seen := make(map[string]bool)
+	for _, seat := range entity.BridgeSeats {
+		if len(deal.Hands[seat]) != 13 {
+			t.Errorf("Bridge.NewDeal() | seat %s got %d cards, want 13", seat, len(deal.Hands[seat]))
+		}
+		for _, c := range deal.Hands[seat] {
+			seen[c.Code] = true
+		}
+	}
+	if len(seen) != 52 {
+		t.Errorf("Bridge.NewDeal() | got %d different cards, want 52", len(seen))
+	}
+
+	if _, err := b.deck.Open(context.Background(), deal.DeckID); !errors.Is(err, DeckNotFoundErr) {
+		t.Errorf("Bridge.NewDeal() | got error %v opening the dealt deck, want %v", err, DeckNotFoundErr)
+	}
+
+	stats := BridgeStats(deal.Hands[entity.BridgeNorth])
+	if stats.HCP < 15 || stats.HCP > 17 || !stats.Balanced {
+		t.Errorf("Bridge.NewDeal() | North got %+v, want 15-17 balanced", stats)
+	}
+
+	got, err := b.Deal(context.Background(), deal.ID)
+	if err != nil {
+		t.Fatalf("Bridge.Deal() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(got, deal); diff != "" {
+		t.Errorf("Bridge.Deal() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestBridge_NewDeal_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		board       int
+		constraints []BridgeConstraint
+		canceled    bool
+		wantErr     error
+	}{
+		{name: "No Board", board: 0, wantErr: BridgeInvalidOptionsErr},
+		{name: "Unknown Seat", board: 1, constraints: []BridgeConstraint{{Seat: "X", MaxHCP: 10}}, wantErr: BridgeInvalidOptionsErr},
+		{name: "Inverted Range", board: 1, constraints: []BridgeConstraint{{Seat: entity.BridgeEast, MinHCP: 12, MaxHCP: 10}}, wantErr: BridgeInvalidOptionsErr},
+		{
+			name:  "Too Many Points",
+			board: 1,
+			constraints: []BridgeConstraint{
+				{Seat: entity.BridgeNorth, MinHCP: 30, MaxHCP: 37},
+				{Seat: entity.BridgeSouth, MinHCP: 30, MaxHCP: 37},
+			},
+			wantErr: BridgeConstraintsErr,
+		},
+		{
+			name:        "Canceled",
+			board:       1,
+			constraints: []BridgeConstraint{{Seat: entity.BridgeNorth, MinHCP: 30, MaxHCP: 37}},
+			canceled:    true,
+			wantErr:     context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBridge(1)
+			ctx := context.Background()
+			if tt.canceled {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				cancel()
+			}
+			if _, err := b.NewDeal(ctx, tt.board, tt.constraints); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Bridge.NewDeal() | got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBridgeBoard(t *testing.T) {
+	tests := []struct {
+		board          int
+		wantDealer     string
+		wantVulnerable string
+	}{
+		{board: 1, wantDealer: entity.BridgeNorth, wantVulnerable: entity.BridgeVulnerableNone},
+		{board: 4, wantDealer: entity.BridgeWest, wantVulnerable: entity.BridgeVulnerableAll},
+		{board: 9, wantDealer: entity.BridgeNorth, wantVulnerable: entity.BridgeVulnerableEW},
+		{board: 16, wantDealer: entity.BridgeWest, wantVulnerable: entity.BridgeVulnerableEW},
+		{board: 17, wantDealer: entity.BridgeNorth, wantVulnerable: entity.BridgeVulnerableNone},
+	}
+	for _, tt := range tests {
+		dealer, vulnerable := bridgeBoard(tt.board)
+		if dealer != tt.wantDealer || vulnerable != tt.wantVulnerable {
+			t.Errorf("bridgeBoard(%d) | got %s %s, want %s %s", tt.board, dealer, vulnerable, tt.wantDealer, tt.wantVulnerable)
+		}
+	}
+}
+
+func TestBridgeStats(t *testing.T) {
+	got := BridgeStats(cardsByCodes("AS KS QS JS 10S AH 2H 3H KD 4D 5D 2C 3C"))
+	want := entity.BridgeHandStats{HCP: 17, Lengths: [4]int{5, 3, 3, 2}, Shape: "5-3-3-2", Balanced: true}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("BridgeStats() | (-got +want):\n%s", diff)
+	}
+
+	got = BridgeStats(cardsByCodes("AS KS QS JS 10S 9S AH 2H 3H KD 4D 5D 2C"))
+	if got.Shape != "6-3-3-1" || got.Balanced {
+		t.Errorf("BridgeStats() | got %+v, want unbalanced 6-3-3-1", got)
+	}
+}
+
+func TestBridge_PBN(t *testing.T) {
+	b := newTestBridge(1)
+
+	deals, err := b.ImportPBN(context.Background(), bridgeTestPBN)
+	if err != nil {
+		t.Fatalf("Bridge.ImportPBN() | got error %v, want nil", err)
+	}
+	if len(deals) != 1 {
+		t.Fatalf("Bridge.ImportPBN() | got %d deals, want 1", len(deals))
+	}
+
+	east := BridgeStats(deals[0].Hands[entity.BridgeEast])
+	if east.HCP != 35 {
+		t.Errorf("Bridge.ImportPBN() | East got %d HCP, want 35", east.HCP)
+	}
+
+	got, err := b.ExportPBN(context.Background(), deals[0].ID)
+	if err != nil {
+		t.Fatalf("Bridge.ExportPBN() | got error %v, want nil", err)
+	}
+	want := `[Board "6"]
+[Dealer "E"]
+[Vulnerable "EW"]
+[Deal "E:AKQJ.AKQ.AKQ.AK2 T98.T98.T98.T987 765.765.765.6543 432.J432.J432.QJ"]
+`
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Bridge.ExportPBN() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestParsePBN_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		pbn  string
+	}{
+		{name: "No Deal", pbn: `[Board "1"]`},
+		{name: "Missing Seat", pbn: `[Deal "AKQJ.AKQ.AKQ.AK2 T98.T98.T98.T987 765.765.765.6543 432.J432.J432.QJ"]`},
+		{name: "Short Hand", pbn: `[Deal "N:AKQ.AKQ.AKQ.AK2 T98.T98.T98.T987 765.765.765.6543 432.J432.J432.QJ"]`},
+		{name: "Card Twice", pbn: `[Deal "N:AKQJ.AKQ.AKQ.AK2 AKQJ.T98.T98.T9 765.765.765.6543 432.J432.J432.QJ"]`},
+		{name: "Unknown Rank", pbn: `[Deal "N:AKQX.AKQ.AKQ.AK2 T98.T98.T98.T987 765.765.765.6543 432.J432.J432.QJ"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePBN(tt.pbn); !errors.Is(err, BridgeInvalidPBNErr) {
+				t.Errorf("ParsePBN() | got error %v, want %v", err, BridgeInvalidPBNErr)
+			}
+		})
+	}
+}
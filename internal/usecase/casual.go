@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+var (
+	// CasualGameNotFoundErr happens when a casual game can't be found in the repo.
+	CasualGameNotFoundErr = errors.New("casual game not found")
+	// CasualGameUnknownKindErr happens when a game kind has no rules.
+	CasualGameUnknownKindErr = errors.New("unknown casual game")
+	// CasualPlayerNotFoundErr happens when a player token doesn't match any player.
+	CasualPlayerNotFoundErr = errors.New("casual game player not found")
+	// CasualIllegalMoveErr happens when a move is not legal in the current game state.
+	CasualIllegalMoveErr = errors.New("illegal move")
+	// CasualInvalidPlayersErr happens when a game is created with the wrong players.
+	CasualInvalidPlayersErr = errors.New("invalid players")
+)
+
+// CasualGames is a use case to play casual card games.
+// The rules of each game are kept in its own CardGame.
+type CasualGames struct {
+	deck     DeckManager
+	gameRepo CasualGameRepo
+	rules    map[string]CardGame
+}
+
+// NewCasualGamesManager creates a new CasualGames with
+// War, Go Fish and Crazy Eights.
+func NewCasualGamesManager(deck DeckManager, store CasualGameRepo) *CasualGames {
+	return &CasualGames{
+		deck:     deck,
+		gameRepo: store,
+		rules: map[string]CardGame{
+			entity.CasualGameWar:         &War{deck: deck},
+			entity.CasualGameGoFish:      &GoFish{deck: deck},
+			entity.CasualGameCrazyEights: &CrazyEights{deck: deck},
+		},
+	}
+}
+
+// New deals a new game of the given kind. Each player
+// gets a token to identify its moves.
+func (c *CasualGames) New(ctx context.Context, kind string, players []string) (entity.CasualGame, error) {
+	rules, ok := c.rules[kind]
+	if !ok {
+		return entity.CasualGame{}, fmt.Errorf("%w %s", CasualGameUnknownKindErr, kind)
+	}
+
+	minPlayers, maxPlayers := rules.Players()
+	if len(players) < minPlayers || len(players) > maxPlayers {
+		return entity.CasualGame{}, fmt.Errorf("%w: %s needs %d to %d players", CasualInvalidPlayersErr, kind, minPlayers, maxPlayers)
+	}
+
+	seen := map[string]bool{}
+	for _, p := range players {
+		if p == "" || seen[p] {
+			return entity.CasualGame{}, fmt.Errorf("%w: player ids must be unique and not empty", CasualInvalidPlayersErr)
+		}
+		seen[p] = true
+	}
+
+	deck, err := c.deck.New(ctx, true, defaultCardCodes(1))
+	if err != nil {
+		return entity.CasualGame{}, err
+	}
+	game := entity.CasualGame{
+		ID:     uuid.New().String(),
+		Kind:   kind,
+		DeckID: deck.ID,
+		Stock:  deck.Remaining,
+		Owner:  gameOwner(ctx),
+		Tenant: deckTenant(ctx),
+	}
+	for _, p := range players {
+		game.Players = append(game.Players, entity.CasualPlayer{
+			ID:    p,
+			Token: uuid.New().String(),
+		})
+	}
+
+	if err := rules.Deal(ctx, &game); err != nil {
+		// The deal failing is what the caller is told; a
+		// deck this fails to delete expires with its TTL.
+		_ = c.deck.Delete(ctx, deck.ID)
+		return entity.CasualGame{}, err
+	}
+	if err := c.finish(ctx, &game); err != nil {
+		return entity.CasualGame{}, err
+	}
+
+	c.gameRepo.Save(game)
+
+	return game, nil
+}
+
+// Game returns a game of the tenant of ctx owned by the
+// caller, or an error in case the game can't be found.
+func (c *CasualGames) Game(ctx context.Context, id string) (entity.CasualGame, error) {
+	game, err := c.gameRepo.Get(deckTenant(ctx), id)
+	if err != nil {
+		if errors.Is(err, repo.CasualGameNotFoundErr) {
+			return entity.CasualGame{}, fmt.Errorf("%w with id %s", CasualGameNotFoundErr, id)
+		}
+		return entity.CasualGame{}, err
+	}
+	if err := checkGameOwner(ctx, "casual game", id, game.Owner); err != nil {
+		return entity.CasualGame{}, err
+	}
+
+	return game, nil
+}
+
+// LegalMoves lists the moves the player owning the
+// token can make right now.
+func (c *CasualGames) LegalMoves(ctx context.Context, id, token string) ([]entity.CasualMove, error) {
+	game, err := c.Game(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := casualPlayerByToken(game, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.rules[game.Kind].LegalMoves(game, player), nil
+}
+
+// Play validates and plays a move for the player
+// owning the token.
+func (c *CasualGames) Play(ctx context.Context, id, token string, move entity.CasualMove) (entity.CasualGame, error) {
+	return c.update(ctx, id, func(game *entity.CasualGame) error {
+		var err error
+		if move.Player, err = casualPlayerByToken(*game, token); err != nil {
+			return err
+		}
+
+		return c.play(ctx, game, move)
+	})
+}
+
+// PlayBot lets a bot make the move for the player
+// whose turn it is.
+func (c *CasualGames) PlayBot(ctx context.Context, id string) (entity.CasualGame, error) {
+	return c.update(ctx, id, func(game *entity.CasualGame) error {
+		if game.Finished {
+			return fmt.Errorf("%w: game is finished", CasualIllegalMoveErr)
+		}
+
+		player := game.Players[game.Turn].ID
+		move, ok := c.rules[game.Kind].BotMove(*game, player)
+		if !ok {
+			return fmt.Errorf("%w: %s has no moves", CasualIllegalMoveErr, player)
+		}
+
+		return c.play(ctx, game, move)
+	})
+}
+
+func (c *CasualGames) play(ctx context.Context, game *entity.CasualGame, move entity.CasualMove) error {
+	rules := c.rules[game.Kind]
+
+	legal := false
+	for _, m := range rules.LegalMoves(*game, move.Player) {
+		if m == move {
+			legal = true
+		}
+	}
+	if !legal {
+		return fmt.Errorf("%w: %s can't %s now", CasualIllegalMoveErr, move.Player, move.Action)
+	}
+
+	if err := rules.Play(ctx, game, move); err != nil {
+		return err
+	}
+	return c.finish(ctx, game)
+}
+
+// finish deletes the deck of a finished game, so it
+// doesn't count against the decks the tenant may keep.
+// Its history is still kept.
+func (c *CasualGames) finish(ctx context.Context, game *entity.CasualGame) error {
+	if !game.Finished {
+		return nil
+	}
+	if err := c.deck.Delete(ctx, game.DeckID); err != nil && !errors.Is(err, DeckNotFoundErr) {
+		return err
+	}
+	return nil
+}
+
+// update runs fn on a game of the tenant of ctx, once the
+// caller is found to own it, and saves the game when fn
+// succeeds. Other changes to the game wait meanwhile.
+func (c *CasualGames) update(ctx context.Context, id string, fn func(game *entity.CasualGame) error) (entity.CasualGame, error) {
+	game, err := c.gameRepo.Update(deckTenant(ctx), id, func(game *entity.CasualGame) error {
+		if err := checkGameOwner(ctx, "casual game", id, game.Owner); err != nil {
+			return err
+		}
+
+		// Players are changed in place, so they can't share
+		// memory with what is in the store.
+		game.Players = append([]entity.CasualPlayer(nil), game.Players...)
+
+		return fn(game)
+	})
+	if errors.Is(err, repo.CasualGameNotFoundErr) {
+		return entity.CasualGame{}, fmt.Errorf("%w with id %s", CasualGameNotFoundErr, id)
+	}
+	return game, err
+}
+
+func casualPlayerByToken(game entity.CasualGame, token string) (string, error) {
+	for _, p := range game.Players {
+		if p.Token == token {
+			return p.ID, nil
+		}
+	}
+	return "", CasualPlayerNotFoundErr
+}
+
+func casualPlayerIndex(game entity.CasualGame, player string) int {
+	for i, p := range game.Players {
+		if p.ID == player {
+			return i
+		}
+	}
+	return -1
+}
+
+// drawToHand moves the top card of the game deck to the
+// hand of a player, telling whether there was a card.
+func drawToHand(ctx context.Context, deck DeckManager, game *entity.CasualGame, player int) (entity.Card, bool, error) {
+	cards, err := deck.DrawCards(ctx, game.DeckID, 1)
+	if err != nil {
+		return entity.Card{}, false, err
+	}
+	if len(cards) == 0 {
+		game.Stock = 0
+		return entity.Card{}, false, nil
+	}
+
+	game.Stock--
+	game.Players[player].Hand = append(game.Players[player].Hand, cards[0])
+
+	return cards[0], true, nil
+}
+
+// dealHands deals cards one at a time round-robin
+// until every player has the given hand size.
+func dealHands(ctx context.Context, deck DeckManager, game *entity.CasualGame, size int) error {
+	for round := 0; round < size; round++ {
+		for i := range game.Players {
+			if _, _, err := drawToHand(ctx, deck, game, i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func removeCard(cards []entity.Card, code string) ([]entity.Card, entity.Card, bool) {
+	for i, c := range cards {
+		if c.Code == code {
+			rest := append(append([]entity.Card{}, cards[:i]...), cards[i+1:]...)
+			return rest, c, true
+		}
+	}
+	return cards, entity.Card{}, false
+}
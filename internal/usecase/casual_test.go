@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+func newTestCasualGame(t *testing.T, kind string, players []string, codes ...string) (*CasualGames, entity.CasualGame) {
+	t.Helper()
+	c := NewCasualGamesManager(&stubShoe{codes: codes}, repo.NewCasualGame())
+	game, err := c.New(context.Background(), kind, players)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, game
+}
+
+func tokenOf(game entity.CasualGame, player string) string {
+	return game.Players[casualPlayerIndex(game, player)].Token
+}
+
+func handCodes(game entity.CasualGame, player string) []string {
+	var codes []string
+	for _, c := range game.Players[casualPlayerIndex(game, player)].Hand {
+		codes = append(codes, c.Code)
+	}
+	return codes
+}
+
+func TestCasualGames_New_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		players []string
+		wantErr error
+	}{
+		{name: "Unknown Kind", kind: "SNAP", players: []string{"a", "b"}, wantErr: CasualGameUnknownKindErr},
+		{name: "Too Few Players", kind: entity.CasualGameGoFish, players: []string{"a"}, wantErr: CasualInvalidPlayersErr},
+		{name: "Too Many Players", kind: entity.CasualGameWar, players: []string{"a", "b", "c"}, wantErr: CasualInvalidPlayersErr},
+		{name: "Duplicated Players", kind: entity.CasualGameCrazyEights, players: []string{"a", "a"}, wantErr: CasualInvalidPlayersErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shoe := &stubShoe{}
+			c := NewCasualGamesManager(shoe, repo.NewCasualGame())
+			if _, err := c.New(context.Background(), tt.kind, tt.players); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CasualGames.New() | got error %v, want %v", err, tt.wantErr)
+			}
+			if shoe.shoes != 0 {
+				t.Errorf("CasualGames.New() | opened %d decks for an invalid game, want 0", shoe.shoes)
+			}
+		})
+	}
+}
+
+func TestCasualGames_Play_Errors(t *testing.T) {
+	c, game := newTestCasualGame(t, entity.CasualGameWar, []string{"a", "b"}, "KS", "2S", "QS", "3S")
+
+	if _, err := c.Play(context.Background(), game.ID, "bad", entity.CasualMove{Action: entity.CasualMoveFlip}); !errors.Is(err, CasualPlayerNotFoundErr) {
+		t.Errorf("CasualGames.Play() | got error %v, want %v", err, CasualPlayerNotFoundErr)
+	}
+	if _, err := c.Play(context.Background(), game.ID, tokenOf(game, "b"), entity.CasualMove{Action: entity.CasualMoveFlip}); !errors.Is(err, CasualIllegalMoveErr) {
+		t.Errorf("CasualGames.Play() | got error %v, want %v", err, CasualIllegalMoveErr)
+	}
+	if _, err := c.Play(context.Background(), game.ID, tokenOf(game, "a"), entity.CasualMove{Action: entity.CasualMoveDraw}); !errors.Is(err, CasualIllegalMoveErr) {
+		t.Errorf("CasualGames.Play() | got error %v, want %v", err, CasualIllegalMoveErr)
+	}
+	if _, err := c.Game(context.Background(), "unknown"); !errors.Is(err, CasualGameNotFoundErr) {
+		t.Errorf("CasualGames.Game() | got error %v, want %v", err, CasualGameNotFoundErr)
+	}
+}
+
+func TestWar(t *testing.T) {
+	// Cards are dealt a, b, a, b...
+	c, game := newTestCasualGame(t, entity.CasualGameWar, []string{"a", "b"},
+		"5S", "5D", "2C", "2H", "3C", "3H", "4C", "4H", "KS", "2D")
+	shoe := c.deck.(*stubShoe)
+	if len(shoe.deleted) != 0 {
+		t.Errorf("CasualGames.New() | got decks %v deleted, want none", shoe.deleted)
+	}
+
+	got, err := c.Play(context.Background(), game.ID, tokenOf(game, "a"), entity.CasualMove{Action: entity.CasualMoveFlip})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !got.Finished || got.Winner != "a" {
+		t.Errorf("War.Play() | got finished %v with winner %q, want a", got.Finished, got.Winner)
+	}
+	if diff := cmp.Diff(shoe.deleted, []string{game.DeckID}); diff != "" {
+		t.Errorf("CasualGames.Play() | deleted decks of the finished game (-got +want):\n%s", diff)
+	}
+	if len(got.Pile) != 10 || len(got.Players[0].Hand) != 10 {
+		t.Errorf("War.Play() | got pile of %d and hand of %d, want 10 and 10", len(got.Pile), len(got.Players[0].Hand))
+	}
+}
+
+func TestGoFish(t *testing.T) {
+	c, game := newTestCasualGame(t, entity.CasualGameGoFish, []string{"a", "b"},
+		"AS", "AH", "AD", "6D", "AC", "7D", "2S", "8D", "3S", "9D", "4S", "10D", "5S", "JD",
+		"KC")
+
+	got, err := c.Play(context.Background(), game.ID, tokenOf(game, "a"), entity.CasualMove{Action: entity.CasualMoveAsk, Target: "b", Value: "ACE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got.Players[0].Books, []string{"ACE"}); diff != "" {
+		t.Errorf("GoFish.Play() | books (-got +want):\n%s", diff)
+	}
+	if got.Turn != 0 {
+		t.Errorf("GoFish.Play() | got turn %d, want 0 after a good ask", got.Turn)
+	}
+
+	got, err = c.Play(context.Background(), game.ID, tokenOf(game, "a"), entity.CasualMove{Action: entity.CasualMoveAsk, Target: "b", Value: "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(handCodes(got, "a"), []string{"2S", "3S", "4S", "5S", "KC"}); diff != "" {
+		t.Errorf("GoFish.Play() | hand after fishing (-got +want):\n%s", diff)
+	}
+	if got.Turn != 1 {
+		t.Errorf("GoFish.Play() | got turn %d, want 1 after going fishing", got.Turn)
+	}
+
+	if _, err := c.Play(context.Background(), game.ID, tokenOf(game, "b"), entity.CasualMove{Action: entity.CasualMoveAsk, Target: "a", Value: "2"}); !errors.Is(err, CasualIllegalMoveErr) {
+		t.Errorf("GoFish.Play() | asking for a value not held got error %v, want %v", err, CasualIllegalMoveErr)
+	}
+}
+
+func TestCrazyEights_LegalMoves(t *testing.T) {
+	rules := &CrazyEights{}
+	game := func(stock int, hand ...string) entity.CasualGame {
+		return entity.CasualGame{
+			Stock:   stock,
+			Pile:    cardsByCodes("3D"),
+			Suit:    "DIAMONDS",
+			Players: []entity.CasualPlayer{{ID: "a", Hand: cardsByCodes(joinCodes(hand))}, {ID: "b"}},
+		}
+	}
+
+	tests := []struct {
+		name string
+		game entity.CasualGame
+		want []entity.CasualMove
+	}{
+		{
+			name: "Match Suit And Value",
+			game: game(10, "KD", "3H", "5C"),
+			want: []entity.CasualMove{
+				{Player: "a", Action: entity.CasualMovePlay, Card: "KD"},
+				{Player: "a", Action: entity.CasualMovePlay, Card: "3H"},
+			},
+		},
+		{
+			name: "Wild Eights Name A Suit",
+			game: game(10, "8S"),
+			want: []entity.CasualMove{
+				{Player: "a", Action: entity.CasualMovePlay, Card: "8S", Suit: "SPADES"},
+				{Player: "a", Action: entity.CasualMovePlay, Card: "8S", Suit: "DIAMONDS"},
+				{Player: "a", Action: entity.CasualMovePlay, Card: "8S", Suit: "CLUBS"},
+				{Player: "a", Action: entity.CasualMovePlay, Card: "8S", Suit: "HEARTS"},
+			},
+		},
+		{
+			name: "Draw When Stuck",
+			game: game(10, "5C"),
+			want: []entity.CasualMove{{Player: "a", Action: entity.CasualMoveDraw}},
+		},
+		{
+			name: "Pass When Stock Is Empty",
+			game: game(0, "5C"),
+			want: []entity.CasualMove{{Player: "a", Action: entity.CasualMovePass}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(rules.LegalMoves(tt.game, "a"), tt.want); diff != "" {
+				t.Errorf("CrazyEights.LegalMoves() | (-got +want):\n%s", diff)
+			}
+			if got := rules.LegalMoves(tt.game, "b"); got != nil {
+				t.Errorf("CrazyEights.LegalMoves() | got moves %v out of turn", got)
+			}
+		})
+	}
+}
+
+func TestCrazyEights_BotNamesSuit(t *testing.T) {
+	c, game := newTestCasualGame(t, entity.CasualGameCrazyEights, []string{"a", "b"},
+		"8S", "7H", "2C", "7D", "4C", "9S", "5C", "9C", "6C", "JS", "KH", "QS", "QH", "10S",
+		"3D")
+
+	got, err := c.PlayBot(context.Background(), game.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Suit != "CLUBS" || got.Pile[len(got.Pile)-1].Code != "8S" {
+		t.Errorf("CrazyEights.BotMove() | got %s on the pile naming %s, want 8S naming CLUBS", got.Pile[len(got.Pile)-1].Code, got.Suit)
+	}
+}
+
+func TestCasualGames_BotsFinish(t *testing.T) {
+	tests := []struct {
+		kind    string
+		players []string
+	}{
+		{kind: entity.CasualGameWar, players: []string{"a", "b"}},
+		{kind: entity.CasualGameGoFish, players: []string{"a", "b", "c", "d"}},
+		{kind: entity.CasualGameCrazyEights, players: []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			for seed := int64(0); seed < 5; seed++ {
+				r := rand.New(rand.NewSource(seed))
+				store := repo.NewDeck()
+				deck := &Deck{
+					deckRepo:  store,
+					eventRepo: store.Events(),
+					shuffler: func(cards []entity.Card) {
+						r.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+					},
+				}
+				c := NewCasualGamesManager(deck, repo.NewCasualGame())
+
+				game, err := c.New(context.Background(), tt.kind, tt.players)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for moves := 0; !game.Finished; moves++ {
+					if moves > maxWarRounds {
+						t.Fatalf("seed %d | game did not finish", seed)
+					}
+					if game, err = c.PlayBot(context.Background(), game.ID); err != nil {
+						t.Fatalf("seed %d | CasualGames.PlayBot() | got error %v", seed, err)
+					}
+
+					cards := game.Stock + len(game.Pile)
+					for _, p := range game.Players {
+						cards += len(p.Hand) + 4*len(p.Books)
+					}
+					if tt.kind == entity.CasualGameWar {
+						cards -= len(game.Pile)
+					}
+					if cards != 52 {
+						t.Fatalf("seed %d | got %d cards in play, want 52", seed, cards)
+					}
+				}
+			}
+		})
+	}
+}
+
+func joinCodes(codes []string) string {
+	s := ""
+	for _, c := range codes {
+		s += c + " "
+	}
+	return s
+}
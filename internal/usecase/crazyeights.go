@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// crazyEightsSuits are the suits an eight can name.
+var crazyEightsSuits = []string{"SPADES", "DIAMONDS", "CLUBS", "HEARTS"}
+
+// CrazyEights holds the rules of Crazy Eights. Players play
+// a card matching the suit or value of the top of the pile,
+// or a wild eight naming a new suit, and draw when stuck.
+// The first player to empty the hand wins.
+type CrazyEights struct {
+	deck DeckManager
+}
+
+// Players returns how many players can play.
+func (c *CrazyEights) Players() (int, int) {
+	return 2, 5
+}
+
+// Deal gives seven cards to each of two players, or five
+// when there are more, and turns up the starter card.
+func (c *CrazyEights) Deal(ctx context.Context, game *entity.CasualGame) error {
+	size := 5
+	if len(game.Players) == 2 {
+		size = 7
+	}
+	if err := dealHands(ctx, c.deck, game, size); err != nil {
+		return err
+	}
+
+	cards, err := c.deck.DrawCards(ctx, game.DeckID, 1)
+	if err != nil {
+		return err
+	}
+	if len(cards) == 0 {
+		return fmt.Errorf("deck %s has no starter card", game.DeckID)
+	}
+	game.Stock--
+	game.Pile = cards
+	game.Suit = cards[0].Suit
+
+	return nil
+}
+
+// LegalMoves lists the moves of a player. Drawing is only
+// allowed when nothing can be played, and passing only when
+// the stock is empty too.
+func (c *CrazyEights) LegalMoves(game entity.CasualGame, player string) []entity.CasualMove {
+	if game.Finished || game.Players[game.Turn].ID != player {
+		return nil
+	}
+
+	top := game.Pile[len(game.Pile)-1]
+	var moves, eights []entity.CasualMove
+	for _, card := range game.Players[game.Turn].Hand {
+		switch {
+		case card.Value == "8":
+			for _, s := range crazyEightsSuits {
+				eights = append(eights, entity.CasualMove{Player: player, Action: entity.CasualMovePlay, Card: card.Code, Suit: s})
+			}
+		case card.Suit == game.Suit || card.Value == top.Value:
+			moves = append(moves, entity.CasualMove{Player: player, Action: entity.CasualMovePlay, Card: card.Code})
+		}
+	}
+	moves = append(moves, eights...)
+
+	if len(moves) > 0 {
+		return moves
+	}
+	if game.Stock > 0 {
+		return []entity.CasualMove{{Player: player, Action: entity.CasualMoveDraw}}
+	}
+	return []entity.CasualMove{{Player: player, Action: entity.CasualMovePass}}
+}
+
+// Play plays a card, draws or passes. The game is also
+// over when every player passes in a row, and the player
+// with the fewest cards wins.
+func (c *CrazyEights) Play(ctx context.Context, game *entity.CasualGame, move entity.CasualMove) error {
+	idx := casualPlayerIndex(*game, move.Player)
+	player := &game.Players[idx]
+
+	switch move.Action {
+	case entity.CasualMovePlay:
+		hand, card, _ := removeCard(player.Hand, move.Card)
+		player.Hand = hand
+		game.Pile = append(append([]entity.Card{}, game.Pile...), card)
+		game.Suit = card.Suit
+		game.Message = fmt.Sprintf("%s played %s", move.Player, card.Code)
+		if move.Suit != "" {
+			game.Suit = move.Suit
+			game.Message = fmt.Sprintf("%s played %s and named %s", move.Player, card.Code, move.Suit)
+		}
+		game.Passes = 0
+
+		if len(player.Hand) == 0 {
+			game.Finished, game.Winner = true, player.ID
+			return nil
+		}
+		game.Turn = (game.Turn + 1) % len(game.Players)
+	case entity.CasualMoveDraw:
+		if _, _, err := drawToHand(ctx, c.deck, game, idx); err != nil {
+			return err
+		}
+		game.Message = fmt.Sprintf("%s drew a card", move.Player)
+	case entity.CasualMovePass:
+		game.Passes++
+		game.Message = fmt.Sprintf("%s passed", move.Player)
+		game.Turn = (game.Turn + 1) % len(game.Players)
+
+		if game.Passes >= len(game.Players) {
+			game.Finished = true
+			fewest := -1
+			for _, p := range game.Players {
+				if fewest < 0 || len(p.Hand) < fewest {
+					fewest = len(p.Hand)
+					game.Winner = p.ID
+				}
+			}
+		}
+	}
+	game.Round++
+
+	return nil
+}
+
+// BotMove plays a matching card when possible, saving the
+// eights for last and naming the suit the bot holds most.
+func (c *CrazyEights) BotMove(game entity.CasualGame, player string) (entity.CasualMove, bool) {
+	moves := c.LegalMoves(game, player)
+	if len(moves) == 0 {
+		return entity.CasualMove{}, false
+	}
+
+	held := map[string]int{}
+	for _, card := range game.Players[casualPlayerIndex(game, player)].Hand {
+		if card.Value != "8" {
+			held[card.Suit]++
+		}
+	}
+
+	best := moves[0]
+	if best.Suit == "" {
+		return best, true
+	}
+	for _, m := range moves {
+		if m.Card == best.Card && held[m.Suit] > held[best.Suit] {
+			best = m
+		}
+	}
+
+	return best, true
+}
@@ -1,14 +1,22 @@
 package usecase
 
 import (
+	"context"
+	crand "crypto/rand"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math/big"
 	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/lualfe/card-game/internal/usecase/repo"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/lualfe/card-game/internal/entity"
 )
@@ -16,30 +24,137 @@ import (
 var (
 	// DeckNotFoundErr happens when a deck can't be found in the repo.
 	DeckNotFoundErr = errors.New("deck not found")
+	// DeckInvalidDealErr happens when a deal is asked with invalid players or amount.
+	DeckInvalidDealErr = errors.New("invalid deal")
+	// DeckNotEnoughCardsErr happens when a deck has fewer cards than a deal needs.
+	DeckNotEnoughCardsErr = errors.New("not enough cards in the deck")
+	// DeckInvalidReturnErr happens when returned cards weren't drawn from the deck.
+	DeckInvalidReturnErr = errors.New("invalid return")
+	// DeckVersionNotFoundErr happens when a deck has no event with the asked version.
+	DeckVersionNotFoundErr = errors.New("deck version not found")
+	// DeckLimitErr happens when a new deck would go over the maximum number of decks.
+	DeckLimitErr = errors.New("too many decks")
+	// DeckForbiddenErr happens when a deck is reached by a caller other than its owner or an admin.
+	DeckForbiddenErr = errors.New("deck belongs to another key")
 )
 
-// Deck is a use case to manage the game deck.
+// Shuffle strategies of the decks.
+const (
+	// DeckShuffleMath shuffles with math/rand.
+	DeckShuffleMath = "math"
+	// DeckShuffleCrypto shuffles with crypto/rand, so the
+	// order of a deck can't be guessed from earlier ones.
+	DeckShuffleCrypto = "crypto"
+)
+
+// DeckOptions bounds the decks of a Deck. Zero values mean
+// no bound and the DeckShuffleMath strategy.
+type DeckOptions struct {
+	// TTL is how long a deck is kept after its last change.
+	// Expire removes the decks past it.
+	TTL time.Duration
+	// MaxDecks is how many decks each tenant can keep,
+	// whatever its own limits.
+	MaxDecks int
+	// Shuffle is the shuffle strategy of new decks.
+	Shuffle string
+}
+
+// Deck is a use case to manage the game deck. Every
+// operation is kept as an event next to the deck state.
+// Decks are kept within the tenant of the principal of
+// the calls, and held to the limits of the tenant in
+// their context.
 type Deck struct {
-	deckRepo DeckRepo
-	shuffler func([]entity.Card)
+	deckRepo  DeckRepo
+	eventRepo DeckEventRepo
+	shuffler  func([]entity.Card)
+	ttl       time.Duration
+	maxDecks  int
+
+	// swept is when RunExpiry last swept, in Unix
+	// nanoseconds, starting from when d was created.
+	swept int64
+
+	// creating holds concurrent calls to New of a tenant
+	// between counting its decks and saving the new one, so
+	// they keep to its limits, leaving the other tenants
+	// free. Every other operation relies on the store to
+	// change one deck at a time.
+	mu       sync.Mutex
+	creating map[string]*tenantLock
+}
+
+// tenantLock is held by the calls to New of a tenant, and
+// counts the calls holding or waiting for it, so it is
+// dropped once none is left.
+type tenantLock struct {
+	sync.Mutex
+	refs int
 }
 
 // NewDeckManager creates a new Deck.
-func NewDeckManager(store DeckRepo) *Deck {
+func NewDeckManager(store DeckRepo, events DeckEventRepo, opts DeckOptions) *Deck {
 	return &Deck{
-		deckRepo: store,
-		shuffler: func(cards []entity.Card) {
-			rand.Seed(time.Now().UnixNano())
-			rand.Shuffle(len(cards), func(i, j int) {
-				cards[i], cards[j] = cards[j], cards[i]
-			})
-		},
+		deckRepo:  store,
+		eventRepo: events,
+		shuffler:  deckShuffler(opts.Shuffle),
+		ttl:       opts.TTL,
+		maxDecks:  opts.MaxDecks,
+		swept:     time.Now().UnixNano(),
+	}
+}
+
+// deckShuffler returns the shuffle of a strategy, the
+// DeckShuffleMath one when it's empty.
+func deckShuffler(strategy string) func([]entity.Card) {
+	if strategy == DeckShuffleCrypto {
+		return cryptoShuffle
+	}
+	return mathShuffle
+}
+
+func mathShuffle(cards []entity.Card) {
+	rand.Seed(time.Now().UnixNano())
+	rand.Shuffle(len(cards), func(i, j int) {
+		cards[i], cards[j] = cards[j], cards[i]
+	})
+}
+
+func cryptoShuffle(cards []entity.Card) {
+	for i := len(cards) - 1; i > 0; i-- {
+		n, err := crand.Int(crand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			panic(fmt.Sprintf("reading crypto/rand: %v", err))
+		}
+		j := n.Int64()
+		cards[i], cards[j] = cards[j], cards[i]
 	}
 }
 
 // New generates a new entity.Deck.
-func (d *Deck) New(shuffle bool, cardCodes []string) entity.Deck {
-	deckCards := entity.DefaultCards
+func (d *Deck) New(ctx context.Context, shuffle bool, cardCodes []string) (entity.Deck, error) {
+	tenant := deckTenant(ctx)
+	t, _ := TenantFrom(ctx)
+	limits := t.Limits
+
+	if limits.MaxDecks > 0 || d.maxDecks > 0 {
+		unlock := d.lockTenant(tenant)
+		defer unlock()
+
+		n, err := d.deckRepo.Count(ctx, tenant)
+		if err != nil {
+			return entity.Deck{}, err
+		}
+		if max := limits.MaxDecks; max > 0 && n >= max {
+			return entity.Deck{}, fmt.Errorf("%w: tenant %s keeps %d of its %d decks", TenantQuotaErr, tenant, n, max)
+		}
+		if d.maxDecks > 0 && n >= d.maxDecks {
+			return entity.Deck{}, fmt.Errorf("%w: the store holds %d decks", DeckLimitErr, n)
+		}
+	}
+
+	deckCards := append([]entity.Card{}, entity.DefaultCards...)
 
 	if cardCodes != nil && len(cardCodes) > 0 {
 		deckCards = []entity.Card{}
@@ -52,8 +167,30 @@ func (d *Deck) New(shuffle bool, cardCodes []string) entity.Deck {
 		}
 	}
 
+	if max := limits.MaxCards; max > 0 && len(deckCards) > max {
+		return entity.Deck{}, fmt.Errorf("%w: a deck of tenant %s can have up to %d cards, not %d", TenantQuotaErr, tenant, max, len(deckCards))
+	}
+
+	// Decks are owned by whoever creates them, if anyone.
+	owner, _ := PrincipalFrom(ctx)
+
+	events := []entity.DeckEvent{{
+		Type:   entity.DeckEventCreated,
+		Cards:  append([]entity.Card{}, deckCards...),
+		Owner:  owner.ID,
+		Tenant: tenant,
+	}}
+
 	if shuffle {
+		// The span comes from the tracer of the caller's span,
+		// so shuffles are only traced within a trace.
+		_, span := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName).Start(ctx, "Deck.shuffle")
 		d.shuffler(deckCards)
+		span.End()
+		events = append(events, entity.DeckEvent{
+			Type:  entity.DeckEventShuffled,
+			Cards: append([]entity.Card{}, deckCards...),
+		})
 	}
 
 	deck := entity.Deck{
@@ -61,17 +198,48 @@ func (d *Deck) New(shuffle bool, cardCodes []string) entity.Deck {
 		Shuffled:  shuffle,
 		Remaining: len(deckCards),
 		Cards:     deckCards,
+		Owner:     owner.ID,
+		Tenant:    tenant,
 	}
 
-	d.deckRepo.Save(deck)
+	return d.record(ctx, deck, events...)
+}
 
-	return deck
+// lockTenant holds the calls to New of tenant until the
+// returned func is called.
+func (d *Deck) lockTenant(tenant string) func() {
+	d.mu.Lock()
+	if d.creating == nil {
+		d.creating = make(map[string]*tenantLock)
+	}
+	l, ok := d.creating[tenant]
+	if !ok {
+		l = &tenantLock{}
+		d.creating[tenant] = l
+	}
+	l.refs++
+	d.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		d.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(d.creating, tenant)
+		}
+		d.mu.Unlock()
+	}
 }
 
 // Open returns a deck or an error in case the
 // deck can't be found.
-func (d *Deck) Open(id string) (entity.Deck, error) {
-	deck, err := d.deckRepo.Get(id)
+func (d *Deck) Open(ctx context.Context, id string) (entity.Deck, error) {
+	return d.open(ctx, id)
+}
+
+func (d *Deck) open(ctx context.Context, id string) (entity.Deck, error) {
+	deck, err := d.deckRepo.Get(ctx, deckTenant(ctx), id)
 	if err != nil {
 		if errors.Is(err, repo.DeckNotFoundErr) {
 			return entity.Deck{}, fmt.Errorf("%w with id %s", DeckNotFoundErr, id)
@@ -82,29 +250,483 @@ func (d *Deck) Open(id string) (entity.Deck, error) {
 	return deck, nil
 }
 
-// DrawCards gets cards from the top of the deck.
-func (d *Deck) DrawCards(id string, amount int) ([]entity.Card, error) {
-	deck, err := d.deckRepo.Get(id)
+// DrawCards gets cards from the top of the deck. The
+// cards leave the deck in a single store update.
+func (d *Deck) DrawCards(ctx context.Context, id string, amount int) ([]entity.Card, error) {
+	var cards []entity.Card
+	_, err := d.update(ctx, id, func(deck *entity.Deck) ([]entity.DeckEvent, error) {
+		n := amount
+		if n > deck.Remaining {
+			n = deck.Remaining
+		}
+
+		if n < 0 {
+			n = 1
+		}
+
+		cards = deck.Cards[:n]
+		deck.Cards = deck.Cards[n:]
+		deck.Remaining = len(deck.Cards)
+
+		if len(cards) == 0 {
+			return nil, nil
+		}
+		return stampDeckEvents(ctx, deck, entity.DeckEvent{
+			Type:  entity.DeckEventDrawn,
+			Cards: append([]entity.Card{}, cards...),
+		}), nil
+	})
 	if err != nil {
-		if errors.Is(err, repo.DeckNotFoundErr) {
+		return nil, err
+	}
+
+	return cards, nil
+}
+
+// Deal deals amount cards to each player in turn from the
+// top of the deck, into hands kept with the deck. It returns
+// the tokens of the players getting their first hand.
+func (d *Deck) Deal(ctx context.Context, id string, players []string, amount int) (entity.Deck, map[string]string, error) {
+	if len(players) == 0 || amount < 1 {
+		return entity.Deck{}, nil, fmt.Errorf("%w: at least one player and one card are needed", DeckInvalidDealErr)
+	}
+	seen := make(map[string]bool, len(players))
+	for _, p := range players {
+		if p == "" || seen[p] {
+			return entity.Deck{}, nil, fmt.Errorf("%w: player ids must be unique and not empty", DeckInvalidDealErr)
+		}
+		seen[p] = true
+	}
+
+	var tokens map[string]string
+	deck, err := d.update(ctx, id, func(deck *entity.Deck) ([]entity.DeckEvent, error) {
+		need := amount * len(players)
+		if need > deck.Remaining {
+			return nil, fmt.Errorf("%w: deal needs %d cards, deck %s has %d", DeckNotEnoughCardsErr, need, id, deck.Remaining)
+		}
+
+		hands := append([]entity.Hand{}, deck.Hands...)
+		tokens = make(map[string]string)
+		seats := make([]int, len(players))
+		for i, p := range players {
+			seats[i] = -1
+			for h := range hands {
+				if hands[h].PlayerID == p {
+					seats[i] = h
+				}
+			}
+			if seats[i] < 0 {
+				token := uuid.New().String()
+				hands = append(hands, entity.Hand{PlayerID: p, Token: token})
+				tokens[p] = token
+				seats[i] = len(hands) - 1
+			}
+			hands[seats[i]].Cards = append([]entity.Card{}, hands[seats[i]].Cards...)
+		}
+
+		moved := make([][]entity.Card, len(players))
+		for i, c := range deck.Cards[:need] {
+			h := seats[i%len(players)]
+			hands[h].Cards = append(hands[h].Cards, c)
+			moved[i%len(players)] = append(moved[i%len(players)], c)
+		}
+
+		deck.Cards = deck.Cards[need:]
+		deck.Remaining = len(deck.Cards)
+		deck.Hands = hands
+
+		events := make([]entity.DeckEvent, len(players))
+		for i := range players {
+			hand := hands[seats[i]]
+			events[i] = entity.DeckEvent{
+				Type:     entity.DeckEventMoved,
+				Cards:    moved[i],
+				PlayerID: hand.PlayerID,
+				Token:    hand.Token,
+			}
+		}
+		return stampDeckEvents(ctx, deck, events...), nil
+	})
+	if err != nil {
+		return entity.Deck{}, nil, err
+	}
+
+	return deck, tokens, nil
+}
+
+// deckReturnRetries bounds how many times Return reads the
+// history of a deck again when the deck changes between
+// the read and its update.
+const deckReturnRetries = 10
+
+// errDeckMoved happens when a deck changed since its
+// history was read.
+var errDeckMoved = errors.New("deck changed since its events were read")
+
+// Return puts drawn cards back at the bottom of the deck.
+// Cards dealt into hands can't be returned. The drawn
+// cards come from the history of the deck, read before
+// the update; the update starts over if the deck moved
+// past that history in between.
+func (d *Deck) Return(ctx context.Context, id string, cardCodes []string) (entity.Deck, error) {
+	if len(cardCodes) == 0 {
+		return entity.Deck{}, fmt.Errorf("%w: no cards to return", DeckInvalidReturnErr)
+	}
+
+	for i := 0; i < deckReturnRetries; i++ {
+		events, err := d.events(ctx, id)
+		if err != nil {
+			return entity.Deck{}, err
+		}
+
+		var drawn []entity.Card
+		for _, e := range events {
+			switch e.Type {
+			case entity.DeckEventDrawn:
+				drawn = append(drawn, e.Cards...)
+			case entity.DeckEventReturned:
+				drawn = removeCards(drawn, e.Cards)
+			}
+		}
+		version := events[len(events)-1].Version
+
+		deck, err := d.update(ctx, id, func(deck *entity.Deck) ([]entity.DeckEvent, error) {
+			if deck.Version != version {
+				return nil, errDeckMoved
+			}
+
+			out := drawn
+			var returned []entity.Card
+			for _, code := range cardCodes {
+				var c entity.Card
+				var ok bool
+				if out, c, ok = removeCard(out, code); !ok {
+					return nil, fmt.Errorf("%w: %s wasn't drawn from deck %s", DeckInvalidReturnErr, code, id)
+				}
+				returned = append(returned, c)
+			}
+
+			deck.Cards = append(append([]entity.Card{}, deck.Cards...), returned...)
+			deck.Remaining = len(deck.Cards)
+
+			return stampDeckEvents(ctx, deck, entity.DeckEvent{
+				Type:  entity.DeckEventReturned,
+				Cards: returned,
+			}), nil
+		})
+		if errors.Is(err, errDeckMoved) {
+			continue
+		}
+		return deck, err
+	}
+
+	return entity.Deck{}, fmt.Errorf("%w: deck %s", repo.DeckConflictErr, id)
+}
+
+// Events returns every event of a deck, oldest first.
+func (d *Deck) Events(ctx context.Context, id string) ([]entity.DeckEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return d.events(ctx, id)
+}
+
+// events returns the events of a deck of the tenant of
+// ctx. The history of another tenant's deck isn't found.
+func (d *Deck) events(ctx context.Context, id string) ([]entity.DeckEvent, error) {
+	events, err := d.eventRepo.Events(ctx, id)
+	if err != nil {
+		if errors.Is(err, repo.DeckEventsNotFoundErr) {
 			return nil, fmt.Errorf("%w with id %s", DeckNotFoundErr, id)
 		}
 		return nil, err
 	}
+	if deckEventsTenant(events) != deckTenant(ctx) {
+		return nil, fmt.Errorf("%w with id %s", DeckNotFoundErr, id)
+	}
 
-	if amount > deck.Remaining {
-		amount = deck.Remaining
+	return events, nil
+}
+
+// At rebuilds a deck as it was right after the event
+// with the given version.
+func (d *Deck) At(ctx context.Context, id string, version int) (entity.Deck, error) {
+	if err := ctx.Err(); err != nil {
+		return entity.Deck{}, err
 	}
 
-	if amount < 0 {
-		amount = 1
+	events, err := d.events(ctx, id)
+	if err != nil {
+		return entity.Deck{}, err
 	}
 
-	cards := deck.Cards[:amount]
-	deck.Cards = deck.Cards[amount:]
-	deck.Remaining = len(deck.Cards)
+	if version < 1 || version > len(events) {
+		return entity.Deck{}, fmt.Errorf("%w: deck %s has versions 1 to %d", DeckVersionNotFoundErr, id, len(events))
+	}
 
-	d.deckRepo.Save(deck)
+	return foldDeckEvents(events[:version]), nil
+}
 
-	return cards, nil
+// Delete removes a deck. Its events are kept, as for the
+// decks past their TTL, so its history can still be read.
+func (d *Deck) Delete(ctx context.Context, id string) error {
+	if err := d.deckRepo.Delete(ctx, deckTenant(ctx), id); err != nil {
+		if errors.Is(err, repo.DeckNotFoundErr) {
+			return fmt.Errorf("%w with id %s", DeckNotFoundErr, id)
+		}
+		return err
+	}
+	return nil
+}
+
+// Snapshot copies every deck and deck event. The decks
+// are read before the events, and each deck is stored with
+// its events, so every deck has the events up to its
+// version. Operations running meanwhile can leave events
+// past the version of their deck; the WAL replays those
+// decks when the snapshot is restored.
+func (d *Deck) Snapshot(ctx context.Context) (entity.DeckSnapshot, error) {
+	decks, err := d.deckRepo.All(ctx)
+	if err != nil {
+		return entity.DeckSnapshot{}, err
+	}
+
+	events, err := d.eventRepo.All(ctx)
+	if err != nil {
+		return entity.DeckSnapshot{}, err
+	}
+
+	snapshot := entity.DeckSnapshot{
+		Taken:  time.Now().UTC(),
+		Decks:  decks,
+		Events: events,
+	}
+
+	sort.Slice(snapshot.Decks, func(i, j int) bool {
+		a, b := snapshot.Decks[i], snapshot.Decks[j]
+		if a.Tenant != b.Tenant {
+			return a.Tenant < b.Tenant
+		}
+		return a.ID < b.ID
+	})
+	sort.SliceStable(snapshot.Events, func(i, j int) bool {
+		a, b := snapshot.Events[i], snapshot.Events[j]
+		if a.DeckID != b.DeckID {
+			return a.DeckID < b.DeckID
+		}
+		return a.Version < b.Version
+	})
+
+	return snapshot, nil
+}
+
+// Expire removes the decks left unchanged for longer than
+// the TTL and returns how many it removed. Their events are
+// kept, so their history can still be read.
+func (d *Deck) Expire(ctx context.Context) (int, error) {
+	if d.ttl <= 0 {
+		return 0, nil
+	}
+
+	decks, err := d.deckRepo.All(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	events, err := d.eventRepo.All(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	last := make(map[string]entity.DeckEvent)
+	for _, e := range events {
+		if e.Time.After(last[e.DeckID].Time) {
+			last[e.DeckID] = e
+		}
+	}
+
+	cutoff := time.Now().Add(-d.ttl)
+	var n int
+	for _, deck := range decks {
+		changed, ok := last[deck.ID]
+		if !ok || changed.Time.After(cutoff) {
+			continue
+		}
+		// The deck is left alone when an operation changed
+		// it since the events were read.
+		current, err := d.deckRepo.Get(ctx, deck.Tenant, deck.ID)
+		if errors.Is(err, repo.DeckNotFoundErr) {
+			continue
+		}
+		if err != nil {
+			return n, err
+		}
+		if current.Version != changed.Version {
+			continue
+		}
+		if err := d.deckRepo.Delete(ctx, deck.Tenant, deck.ID); err != nil {
+			if errors.Is(err, repo.DeckNotFoundErr) {
+				continue
+			}
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// RunExpiry calls Expire every interval until ctx is done.
+func (d *Deck) RunExpiry(ctx context.Context, interval time.Duration) {
+	atomic.StoreInt64(&d.swept, time.Now().UnixNano())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := d.Expire(ctx)
+			atomic.StoreInt64(&d.swept, time.Now().UnixNano())
+			if err != nil {
+				slog.Error("deck expiry", slog.Any("err", err))
+			}
+			if n > 0 {
+				slog.Info("deck expiry: removed decks", slog.Int("decks", n))
+			}
+		}
+	}
+}
+
+// LastExpiry returns when RunExpiry last swept the decks,
+// failing or not.
+func (d *Deck) LastExpiry() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&d.swept))
+}
+
+// record saves the new state of the deck along with the
+// events of the operation, numbered after its last version.
+func (d *Deck) record(ctx context.Context, deck entity.Deck, events ...entity.DeckEvent) (entity.Deck, error) {
+	stamped := stampDeckEvents(ctx, &deck, events...)
+
+	if err := d.deckRepo.Save(ctx, deck, stamped...); err != nil {
+		return entity.Deck{}, err
+	}
+
+	return deck, nil
+}
+
+// update changes a deck of the tenant of ctx with fn,
+// storing the events fn returns with the changed deck.
+func (d *Deck) update(ctx context.Context, id string, fn func(deck *entity.Deck) ([]entity.DeckEvent, error)) (entity.Deck, error) {
+	deck, err := d.deckRepo.Update(ctx, deckTenant(ctx), id, fn)
+	if err != nil {
+		if errors.Is(err, repo.DeckNotFoundErr) {
+			return entity.Deck{}, fmt.Errorf("%w with id %s", DeckNotFoundErr, id)
+		}
+		return entity.Deck{}, err
+	}
+	return deck, nil
+}
+
+// stampDeckEvents numbers the events after the last
+// version of the deck, moving the deck to the last one.
+// The principal of ctx, if any, is their actor.
+func stampDeckEvents(ctx context.Context, deck *entity.Deck, events ...entity.DeckEvent) []entity.DeckEvent {
+	now := time.Now().UTC()
+	p, _ := PrincipalFrom(ctx)
+	stamped := make([]entity.DeckEvent, len(events))
+	for i, e := range events {
+		deck.Version++
+		e.DeckID = deck.ID
+		e.Version = deck.Version
+		e.Time = now
+		e.Actor = p.ID
+		stamped[i] = e
+	}
+	return stamped
+}
+
+// deckTenant returns the tenant the decks of ctx are kept
+// in: the principal's, if any.
+func deckTenant(ctx context.Context) string {
+	p, _ := PrincipalFrom(ctx)
+	return p.Tenant
+}
+
+func deckEventsTenant(events []entity.DeckEvent) string {
+	for _, e := range events {
+		if e.Type == entity.DeckEventCreated {
+			return e.Tenant
+		}
+	}
+	return ""
+}
+
+// foldDeckEvents rebuilds a deck by applying its events in order.
+func foldDeckEvents(events []entity.DeckEvent) entity.Deck {
+	var deck entity.Deck
+	for _, e := range events {
+		deck.ID = e.DeckID
+		deck.Version = e.Version
+
+		switch e.Type {
+		case entity.DeckEventCreated:
+			deck.Cards = append([]entity.Card{}, e.Cards...)
+			deck.Owner = e.Owner
+			deck.Tenant = e.Tenant
+		case entity.DeckEventShuffled:
+			deck.Shuffled = true
+			deck.Cards = append([]entity.Card{}, e.Cards...)
+		case entity.DeckEventDrawn:
+			deck.Cards = removeCards(deck.Cards, e.Cards)
+		case entity.DeckEventReturned:
+			deck.Cards = append(append([]entity.Card{}, deck.Cards...), e.Cards...)
+		case entity.DeckEventMoved:
+			deck.Cards = removeCards(deck.Cards, e.Cards)
+
+			hands := append([]entity.Hand{}, deck.Hands...)
+			h := -1
+			for i := range hands {
+				if hands[i].PlayerID == e.PlayerID {
+					h = i
+				}
+			}
+			if h < 0 {
+				hands = append(hands, entity.Hand{PlayerID: e.PlayerID, Token: e.Token})
+				h = len(hands) - 1
+			}
+			hands[h].Cards = append(append([]entity.Card{}, hands[h].Cards...), e.Cards...)
+			deck.Hands = hands
+		}
+
+		deck.Remaining = len(deck.Cards)
+	}
+
+	return deck
+}
+
+// removeCards takes the first card with each code out of
+// the cards, leaving the given slice untouched.
+func removeCards(cards, remove []entity.Card) []entity.Card {
+	rest := append([]entity.Card{}, cards...)
+	for _, c := range remove {
+		rest, _, _ = removeCard(rest, c.Code)
+	}
+	return rest
+}
+
+// defaultCardCodes returns the codes of the default cards
+// repeated for the given number of decks.
+func defaultCardCodes(decks int) []string {
+	codes := make([]string, 0, decks*len(entity.DefaultCards))
+	for i := 0; i < decks; i++ {
+		for _, c := range entity.DefaultCards {
+			codes = append(codes, c.Code)
+		}
+	}
+	return codes
 }
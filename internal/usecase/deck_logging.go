@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// DeckLogging is a DeckManager logging the decks created
+// and deleted and the cards drawn. Failures other than a deck not
+// being found are logged as errors.
+type DeckLogging struct {
+	DeckManager
+
+	log *slog.Logger
+}
+
+// NewDeckLogging wraps deck, logging to logger.
+func NewDeckLogging(deck DeckManager, logger *slog.Logger) *DeckLogging {
+	return &DeckLogging{DeckManager: deck, log: logger}
+}
+
+// New creates a deck, logging its ID.
+func (l *DeckLogging) New(ctx context.Context, shuffle bool, cardCodes []string) (entity.Deck, error) {
+	deck, err := l.DeckManager.New(ctx, shuffle, cardCodes)
+	if err != nil {
+		l.log.ErrorContext(ctx, "creating deck", slog.Any("err", err))
+		return entity.Deck{}, err
+	}
+
+	l.log.InfoContext(ctx, "deck created",
+		slog.String("deck_id", deck.ID),
+		slog.Bool("shuffled", deck.Shuffled),
+		slog.Int("cards", deck.Remaining),
+	)
+	return deck, nil
+}
+
+// DrawCards draws from a deck, logging how many cards
+// left it.
+func (l *DeckLogging) DrawCards(ctx context.Context, id string, amount int) ([]entity.Card, error) {
+	cards, err := l.DeckManager.DrawCards(ctx, id, amount)
+	if err != nil {
+		if !errors.Is(err, DeckNotFoundErr) {
+			l.log.ErrorContext(ctx, "drawing cards", slog.String("deck_id", id), slog.Any("err", err))
+		}
+		return nil, err
+	}
+
+	l.log.InfoContext(ctx, "cards drawn",
+		slog.String("deck_id", id),
+		slog.Int("amount", amount),
+		slog.Int("drawn", len(cards)),
+	)
+	return cards, nil
+}
+
+// Delete removes a deck, logging its ID.
+func (l *DeckLogging) Delete(ctx context.Context, id string) error {
+	if err := l.DeckManager.Delete(ctx, id); err != nil {
+		if !errors.Is(err, DeckNotFoundErr) {
+			l.log.ErrorContext(ctx, "deleting deck", slog.String("deck_id", id), slog.Any("err", err))
+		}
+		return err
+	}
+
+	l.log.InfoContext(ctx, "deck deleted", slog.String("deck_id", id))
+	return nil
+}
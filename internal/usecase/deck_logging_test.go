@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestDeckLogging(t *testing.T) {
+	var buf bytes.Buffer
+	d := NewDeckLogging(newMemoryDeckManager(DeckOptions{}), slog.New(slog.NewTextHandler(&buf, nil)))
+
+	deck, err := d.New(context.Background(), true, []string{"AS", "2S"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.DrawCards(context.Background(), deck.ID, 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.DrawCards(context.Background(), "missing", 1); err == nil {
+		t.Fatal("DeckLogging.DrawCards() | got nil error for a missing deck")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := d.DrawCards(ctx, deck.ID, 1); err == nil {
+		t.Fatal("DeckLogging.DrawCards() | got nil error with a canceled context")
+	}
+
+	want := []string{
+		`level=INFO msg="deck created" deck_id=` + deck.ID + ` shuffled=true cards=2`,
+		`level=INFO msg="cards drawn" deck_id=` + deck.ID + ` amount=3 drawn=2`,
+		`level=ERROR msg="drawing cards" deck_id=` + deck.ID + ` err="context canceled"`,
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("DeckLogging | got logs\n%s\nwant %d lines", buf.String(), len(want))
+	}
+	for i, w := range want {
+		if !strings.Contains(lines[i], w) {
+			t.Errorf("DeckLogging | got line %q, want %q", lines[i], w)
+		}
+	}
+}
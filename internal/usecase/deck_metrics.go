@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+// deckCountTimeout bounds the store count behind the live
+// deck gauge, so a slow store doesn't hold up a scrape.
+const deckCountTimeout = time.Second
+
+// DeckMetrics is a DeckManager recording what happens to
+// the decks as Prometheus metrics.
+type DeckMetrics struct {
+	DeckManager
+
+	created *prometheus.CounterVec
+	drawn   prometheus.Counter
+	empty   prometheus.Counter
+}
+
+// NewDeckMetrics wraps deck, registering its metrics with
+// reg. The live decks of the default tenant and of each
+// tenant of tenants are counted in store at each scrape.
+func NewDeckMetrics(deck DeckManager, store DeckRepo, tenants TenantRepo, reg prometheus.Registerer) *DeckMetrics {
+	m := &DeckMetrics{
+		DeckManager: deck,
+		created: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "deck_created_total",
+			Help: "Decks created, by type (standard or custom) and whether they were shuffled.",
+		}, []string{"type", "shuffled"}),
+		drawn: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "deck_cards_drawn_total",
+			Help: "Cards drawn from decks.",
+		}),
+		empty: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "deck_empty_draws_total",
+			Help: "Draws from decks with no cards left.",
+		}),
+	}
+	live := &deckLive{
+		store:   store,
+		tenants: tenants,
+		desc:    prometheus.NewDesc("deck_live", "Decks in the store, by tenant.", []string{"tenant"}, nil),
+	}
+
+	reg.MustRegister(m.created, m.drawn, m.empty, live)
+	return m
+}
+
+// deckLive is the live deck gauge, one per tenant. A
+// tenant whose decks can't be counted gets NaN.
+type deckLive struct {
+	store   DeckRepo
+	tenants TenantRepo
+	desc    *prometheus.Desc
+}
+
+func (l *deckLive) Describe(ch chan<- *prometheus.Desc) {
+	ch <- l.desc
+}
+
+func (l *deckLive) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), deckCountTimeout)
+	defer cancel()
+
+	names := []string{""}
+	if l.tenants != nil {
+		tenants, err := l.tenants.All(ctx)
+		if err != nil {
+			slog.Warn("deck metrics: listing tenants", slog.Any("err", err))
+		}
+		for _, t := range tenants {
+			names = append(names, t.Name)
+		}
+	}
+
+	for _, name := range names {
+		v := math.NaN()
+		if n, err := l.store.Count(ctx, name); err == nil {
+			v = float64(n)
+		}
+		ch <- prometheus.MustNewConstMetric(l.desc, prometheus.GaugeValue, v, name)
+	}
+}
+
+// New creates a deck, counting it by type.
+func (m *DeckMetrics) New(ctx context.Context, shuffle bool, cardCodes []string) (entity.Deck, error) {
+	deck, err := m.DeckManager.New(ctx, shuffle, cardCodes)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+
+	kind := "standard"
+	if len(cardCodes) > 0 {
+		kind = "custom"
+	}
+	m.created.WithLabelValues(kind, strconv.FormatBool(shuffle)).Inc()
+
+	return deck, nil
+}
+
+// DrawCards draws from a deck, counting the cards drawn
+// and the draws finding the deck empty.
+func (m *DeckMetrics) DrawCards(ctx context.Context, id string, amount int) ([]entity.Card, error) {
+	cards, err := m.DeckManager.DrawCards(ctx, id, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	m.drawn.Add(float64(len(cards)))
+	if len(cards) == 0 {
+		m.empty.Inc()
+	}
+
+	return cards, nil
+}
+
+// DeckRepoMetrics is a DeckRepo recording the latency and
+// errors of every store call as Prometheus metrics. A deck
+// not being found isn't an error of the store.
+type DeckRepoMetrics struct {
+	store DeckRepo
+
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// NewDeckRepoMetrics wraps store, registering its metrics
+// with reg.
+func NewDeckRepoMetrics(store DeckRepo, reg prometheus.Registerer) *DeckRepoMetrics {
+	m := &DeckRepoMetrics{
+		store: store,
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "deck_store_operation_duration_seconds",
+			Help:    "Latency of the deck store calls, by operation.",
+			Buckets: []float64{.0001, .0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "deck_store_errors_total",
+			Help: "Failed deck store calls, by operation.",
+		}, []string{"operation"}),
+	}
+
+	reg.MustRegister(m.duration, m.errors)
+	return m
+}
+
+func (m *DeckRepoMetrics) observe(op string, start time.Time, err error) {
+	m.duration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+	if err != nil && !errors.Is(err, repo.DeckNotFoundErr) {
+		m.errors.WithLabelValues(op).Inc()
+	}
+}
+
+// Save saves a deck to the store.
+func (m *DeckRepoMetrics) Save(ctx context.Context, deck entity.Deck, events ...entity.DeckEvent) (err error) {
+	defer func(start time.Time) { m.observe("save", start, err) }(time.Now())
+	return m.store.Save(ctx, deck, events...)
+}
+
+// Get retrieves a deck from its ID.
+func (m *DeckRepoMetrics) Get(ctx context.Context, tenant, id string) (deck entity.Deck, err error) {
+	defer func(start time.Time) { m.observe("get", start, err) }(time.Now())
+	return m.store.Get(ctx, tenant, id)
+}
+
+// Update changes a deck with fn and saves it. Errors of fn,
+// such as an empty deck, aren't errors of the store.
+func (m *DeckRepoMetrics) Update(ctx context.Context, tenant, id string, fn func(deck *entity.Deck) ([]entity.DeckEvent, error)) (deck entity.Deck, err error) {
+	var fnErr error
+	defer func(start time.Time) {
+		storeErr := err
+		if fnErr != nil && errors.Is(err, fnErr) {
+			storeErr = nil
+		}
+		m.observe("update", start, storeErr)
+	}(time.Now())
+
+	return m.store.Update(ctx, tenant, id, func(deck *entity.Deck) ([]entity.DeckEvent, error) {
+		events, err := fn(deck)
+		fnErr = err
+		return events, err
+	})
+}
+
+// All returns every deck in the store.
+func (m *DeckRepoMetrics) All(ctx context.Context) (decks []entity.Deck, err error) {
+	defer func(start time.Time) { m.observe("all", start, err) }(time.Now())
+	return m.store.All(ctx)
+}
+
+// Count returns how many decks a tenant holds.
+func (m *DeckRepoMetrics) Count(ctx context.Context, tenant string) (n int, err error) {
+	defer func(start time.Time) { m.observe("count", start, err) }(time.Now())
+	return m.store.Count(ctx, tenant)
+}
+
+// Delete removes a deck from the store.
+func (m *DeckRepoMetrics) Delete(ctx context.Context, tenant, id string) (err error) {
+	defer func(start time.Time) { m.observe("delete", start, err) }(time.Now())
+	return m.store.Delete(ctx, tenant, id)
+}
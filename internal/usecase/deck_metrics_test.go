@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+func TestDeckMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	store := repo.NewDeck()
+	tenants := repo.NewTenant()
+	for _, name := range []string{"acme", "globex"} {
+		if err := tenants.Save(context.Background(), entity.Tenant{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := NewDeckMetrics(NewDeckManager(store, store.Events(), DeckOptions{}), store, tenants, reg)
+
+	if _, err := m.New(context.Background(), true, nil); err != nil {
+		t.Fatal(err)
+	}
+	custom, err := m.New(context.Background(), false, []string{"AS", "2S"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, amount := range []int{3, 1} {
+		if _, err := m.DrawCards(context.Background(), custom.ID, amount); err != nil {
+			t.Fatal(err)
+		}
+	}
+	acme := WithPrincipal(context.Background(), entity.Principal{ID: "ci", Tenant: "acme"})
+	if _, err := m.New(acme, false, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `
+# HELP deck_cards_drawn_total Cards drawn from decks.
+# TYPE deck_cards_drawn_total counter
+deck_cards_drawn_total 2
+# HELP deck_created_total Decks created, by type (standard or custom) and whether they were shuffled.
+# TYPE deck_created_total counter
+deck_created_total{shuffled="false",type="custom"} 1
+deck_created_total{shuffled="false",type="standard"} 1
+deck_created_total{shuffled="true",type="standard"} 1
+# HELP deck_empty_draws_total Draws from decks with no cards left.
+# TYPE deck_empty_draws_total counter
+deck_empty_draws_total 1
+# HELP deck_live Decks in the store, by tenant.
+# TYPE deck_live gauge
+deck_live{tenant=""} 2
+deck_live{tenant="acme"} 1
+deck_live{tenant="globex"} 0
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want)); err != nil {
+		t.Errorf("DeckMetrics | %v", err)
+	}
+}
+
+func TestDeckMetrics_LiveError(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	NewDeckMetrics(newMemoryDeckManager(DeckOptions{}), &failingCountStore{}, nil, reg)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() != "deck_live" {
+			continue
+		}
+		if got := f.GetMetric()[0].GetGauge().GetValue(); !math.IsNaN(got) {
+			t.Errorf("deck_live | got %v when the store fails, want NaN", got)
+		}
+		return
+	}
+	t.Error("deck_live | not gathered")
+}
+
+type failingCountStore struct {
+	stubDeckStore
+}
+
+func (s *failingCountStore) Count(context.Context, string) (int, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestDeckRepoMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	memory := repo.NewDeck()
+	store := NewDeckRepoMetrics(memory, reg)
+	d := NewDeckManager(store, memory.Events(), DeckOptions{})
+
+	deck, err := d.New(context.Background(), false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.DrawCards(context.Background(), deck.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+	// Running out of cards is an error of the deal, not of
+	// the store.
+	if _, _, err := d.Deal(context.Background(), deck.ID, []string{"a"}, 52); !errors.Is(err, DeckNotEnoughCardsErr) {
+		t.Fatalf("Deck.Deal() | got error %v, want %v", err, DeckNotEnoughCardsErr)
+	}
+	if _, err := d.Open(context.Background(), "missing"); !errors.Is(err, DeckNotFoundErr) {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := d.Open(ctx, deck.ID); err == nil {
+		t.Fatal("Deck.Open() | got nil error with a canceled context")
+	}
+	if _, err := d.DrawCards(ctx, deck.ID, 1); err == nil {
+		t.Fatal("Deck.DrawCards() | got nil error with a canceled context")
+	}
+
+	if n := testutil.CollectAndCount(reg, "deck_store_operation_duration_seconds"); n != 3 {
+		t.Errorf("deck_store_operation_duration_seconds | got %d series, want save, update and get", n)
+	}
+	want := `
+# HELP deck_store_errors_total Failed deck store calls, by operation.
+# TYPE deck_store_errors_total counter
+deck_store_errors_total{operation="get"} 1
+deck_store_errors_total{operation="update"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "deck_store_errors_total"); err != nil {
+		t.Errorf("deck_store_errors_total | %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// DeckOwnership is a DeckManager only letting the owner of
+// a deck, or an admin, reach it. Calls made without a
+// principal, when authentication is off, reach every deck.
+type DeckOwnership struct {
+	DeckManager
+}
+
+// NewDeckOwnership wraps deck.
+func NewDeckOwnership(deck DeckManager) *DeckOwnership {
+	return &DeckOwnership{DeckManager: deck}
+}
+
+// Open returns a deck of the caller.
+func (o *DeckOwnership) Open(ctx context.Context, id string) (entity.Deck, error) {
+	deck, err := o.DeckManager.Open(ctx, id)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	if err := checkDeckOwner(ctx, id, deck.Owner); err != nil {
+		return entity.Deck{}, err
+	}
+	return deck, nil
+}
+
+// DrawCards draws cards from a deck of the caller.
+func (o *DeckOwnership) DrawCards(ctx context.Context, id string, amount int) ([]entity.Card, error) {
+	if _, err := o.Open(ctx, id); err != nil {
+		return nil, err
+	}
+	return o.DeckManager.DrawCards(ctx, id, amount)
+}
+
+// Deal deals cards from a deck of the caller.
+func (o *DeckOwnership) Deal(ctx context.Context, id string, players []string, amount int) (entity.Deck, map[string]string, error) {
+	if _, err := o.Open(ctx, id); err != nil {
+		return entity.Deck{}, nil, err
+	}
+	return o.DeckManager.Deal(ctx, id, players, amount)
+}
+
+// Return puts drawn cards back in a deck of the caller.
+func (o *DeckOwnership) Return(ctx context.Context, id string, cardCodes []string) (entity.Deck, error) {
+	if _, err := o.Open(ctx, id); err != nil {
+		return entity.Deck{}, err
+	}
+	return o.DeckManager.Return(ctx, id, cardCodes)
+}
+
+// Events returns the history of a deck of the caller. The
+// owner is read from the history, which outlives the deck.
+func (o *DeckOwnership) Events(ctx context.Context, id string) ([]entity.DeckEvent, error) {
+	events, err := o.DeckManager.Events(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDeckOwner(ctx, id, deckEventsOwner(events)); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// At returns a deck of the caller as it was at a version.
+// The cards left in it, whose order gives away the hands
+// dealt after that version, are only shown to its owner.
+func (o *DeckOwnership) At(ctx context.Context, id string, version int) (entity.Deck, error) {
+	events, err := o.Events(ctx, id)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	deck, err := o.DeckManager.At(ctx, id, version)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	if p, ok := PrincipalFrom(ctx); !ok || p.ID != deckEventsOwner(events) {
+		deck.Cards = nil
+	}
+	return deck, nil
+}
+
+// Delete removes a deck of the caller.
+func (o *DeckOwnership) Delete(ctx context.Context, id string) error {
+	if _, err := o.Open(ctx, id); err != nil {
+		return err
+	}
+	return o.DeckManager.Delete(ctx, id)
+}
+
+// checkDeckOwner fails unless the principal of ctx, if
+// any, owns the deck or is an admin. Decks created with
+// authentication off have no owner, so only admins reach
+// them once it's on.
+func checkDeckOwner(ctx context.Context, id, owner string) error {
+	p, ok := PrincipalFrom(ctx)
+	if !ok || p.Admin || (owner != "" && owner == p.ID) {
+		return nil
+	}
+	return fmt.Errorf("%w: deck %s", DeckForbiddenErr, id)
+}
+
+func deckEventsOwner(events []entity.DeckEvent) string {
+	for _, e := range events {
+		if e.Type == entity.DeckEventCreated {
+			return e.Owner
+		}
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func TestDeckOwnership(t *testing.T) {
+	var (
+		alice = WithPrincipal(context.Background(), entity.Principal{ID: "alice"})
+		bob   = WithPrincipal(context.Background(), entity.Principal{ID: "bob"})
+		admin = WithPrincipal(context.Background(), entity.Principal{ID: "root", Admin: true})
+		anon  = context.Background()
+	)
+
+	dm := newMemoryDeckManager(DeckOptions{})
+	decks := NewDeckOwnership(dm)
+	deck, err := decks.New(alice, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deck.Owner != "alice" {
+		t.Fatalf("DeckOwnership.New() | got owner %q, want %q", deck.Owner, "alice")
+	}
+
+	calls := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{"Open", func(ctx context.Context) error { _, err := decks.Open(ctx, deck.ID); return err }},
+		{"DrawCards", func(ctx context.Context) error { _, err := decks.DrawCards(ctx, deck.ID, 1); return err }},
+		{"Deal", func(ctx context.Context) error { _, _, err := decks.Deal(ctx, deck.ID, []string{"p"}, 1); return err }},
+		{"Events", func(ctx context.Context) error { _, err := decks.Events(ctx, deck.ID); return err }},
+		{"At", func(ctx context.Context) error { _, err := decks.At(ctx, deck.ID, 1); return err }},
+	}
+	for _, c := range calls {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.call(bob); !errors.Is(err, DeckForbiddenErr) {
+				t.Errorf("DeckOwnership.%s() | got error %v for another key, want %v", c.name, err, DeckForbiddenErr)
+			}
+			for who, ctx := range map[string]context.Context{"owner": alice, "admin": admin, "no principal": anon} {
+				if err := c.call(ctx); err != nil {
+					t.Errorf("DeckOwnership.%s() | got error %v for the %s, want nil", c.name, err, who)
+				}
+			}
+		})
+	}
+
+	if err := decks.Delete(bob, deck.ID); !errors.Is(err, DeckForbiddenErr) {
+		t.Errorf("DeckOwnership.Delete() | got error %v for another key, want %v", err, DeckForbiddenErr)
+	}
+	if err := decks.Delete(alice, deck.ID); err != nil {
+		t.Fatalf("DeckOwnership.Delete() | got error %v for the owner, want nil", err)
+	}
+	if _, err := decks.Open(alice, deck.ID); !errors.Is(err, DeckNotFoundErr) {
+		t.Errorf("DeckOwnership.Open() | got error %v after deleting, want %v", err, DeckNotFoundErr)
+	}
+
+	// The history outlives the deck, and stays its owner's.
+	if _, err := decks.Events(bob, deck.ID); !errors.Is(err, DeckForbiddenErr) {
+		t.Errorf("DeckOwnership.Events() | got error %v for another key after deleting, want %v", err, DeckForbiddenErr)
+	}
+	at, err := decks.At(alice, deck.ID, 1)
+	if err != nil {
+		t.Fatalf("DeckOwnership.At() | got error %v for the owner after deleting, want nil", err)
+	}
+	if at.Owner != "alice" {
+		t.Errorf("DeckOwnership.At() | got owner %q, want %q", at.Owner, "alice")
+	}
+	if len(at.Cards) != at.Remaining || at.Remaining == 0 {
+		t.Errorf("DeckOwnership.At() | got %d of %d cards for the owner, want all", len(at.Cards), at.Remaining)
+	}
+
+	// The order of the cards left gives away the hands
+	// dealt later, so only the owner sees it.
+	at, err = decks.At(admin, deck.ID, 1)
+	if err != nil {
+		t.Fatalf("DeckOwnership.At() | got error %v for an admin, want nil", err)
+	}
+	if at.Cards != nil || at.Remaining == 0 {
+		t.Errorf("DeckOwnership.At() | got cards %v of %d for an admin, want none", at.Cards, at.Remaining)
+	}
+}
+
+func TestDeckOwnership_Unowned(t *testing.T) {
+	dm := newMemoryDeckManager(DeckOptions{})
+	decks := NewDeckOwnership(dm)
+	deck, err := decks.New(context.Background(), false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Decks created with authentication off are only
+	// reached by admins once it's on.
+	alice := WithPrincipal(context.Background(), entity.Principal{ID: "alice"})
+	if _, err := decks.Open(alice, deck.ID); !errors.Is(err, DeckForbiddenErr) {
+		t.Errorf("DeckOwnership.Open() | got error %v, want %v", err, DeckForbiddenErr)
+	}
+	admin := WithPrincipal(context.Background(), entity.Principal{ID: "root", Admin: true})
+	if _, err := decks.Open(admin, deck.ID); err != nil {
+		t.Errorf("DeckOwnership.Open() | got error %v for an admin, want nil", err)
+	}
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// DeckSnapshots is a use case to snapshot the deck store,
+// on demand or periodically. When the store is under a
+// write-ahead log, every snapshot compacts it.
+type DeckSnapshots struct {
+	deck  *Deck
+	store DeckSnapshotRepo
+	wal   DeckWALRepo
+
+	// taking lets one snapshot be taken at a time, so an
+	// older one can't overwrite a newer one, nor compact the
+	// log past what the one written covers.
+	taking sync.Mutex
+
+	// taken is when the last snapshot was written, in Unix
+	// nanoseconds, starting from when s was created.
+	taken int64
+}
+
+// NewDeckSnapshots creates a new DeckSnapshots. The wal
+// may be nil.
+func NewDeckSnapshots(deck *Deck, store DeckSnapshotRepo, wal DeckWALRepo) *DeckSnapshots {
+	return &DeckSnapshots{
+		deck:  deck,
+		store: store,
+		wal:   wal,
+		taken: time.Now().UnixNano(),
+	}
+}
+
+// Take writes a snapshot of every deck and deck event.
+// Snapshots taken at once, from the admin route, Run and
+// shutdown, wait for each other.
+func (s *DeckSnapshots) Take(ctx context.Context) (entity.DeckSnapshot, error) {
+	s.taking.Lock()
+	defer s.taking.Unlock()
+
+	// Records logged before the rotation are all in the
+	// snapshot, so their segments go once it's written.
+	var segment int
+	if s.wal != nil {
+		var err error
+		if segment, err = s.wal.Rotate(); err != nil {
+			return entity.DeckSnapshot{}, err
+		}
+	}
+
+	snapshot, err := s.deck.Snapshot(ctx)
+	if err != nil {
+		return entity.DeckSnapshot{}, err
+	}
+	if err := s.store.Write(snapshot); err != nil {
+		return entity.DeckSnapshot{}, err
+	}
+
+	if s.wal != nil {
+		if err := s.wal.Compact(segment); err != nil {
+			return entity.DeckSnapshot{}, err
+		}
+	}
+
+	atomic.StoreInt64(&s.taken, time.Now().UnixNano())
+	return snapshot, nil
+}
+
+// LastTaken returns when the last snapshot was written.
+func (s *DeckSnapshots) LastTaken() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&s.taken))
+}
+
+// Run takes a snapshot every interval until the context
+// is done. Failed snapshots are logged and retried on the
+// next tick.
+func (s *DeckSnapshots) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Take(ctx); err != nil {
+				slog.Error("deck snapshot", slog.Any("err", err))
+			}
+		}
+	}
+}
+
+// RestoreDecks fills empty deck and event stores with a
+// snapshot. It's meant to run at startup, before the
+// stores are in use.
+func RestoreDecks(ctx context.Context, snapshot entity.DeckSnapshot, decks DeckRepo, events DeckEventRepo) error {
+	for _, d := range snapshot.Decks {
+		if err := decks.Save(ctx, d); err != nil {
+			return fmt.Errorf("restoring deck %s: %w", d.ID, err)
+		}
+	}
+	for _, e := range snapshot.Events {
+		if err := events.Append(ctx, e); err != nil {
+			return fmt.Errorf("restoring deck %s version %d: %w", e.DeckID, e.Version, err)
+		}
+	}
+	return nil
+}
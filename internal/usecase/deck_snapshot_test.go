@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+type stubDeckSnapshotRepo struct {
+	write func(snapshot entity.DeckSnapshot) error
+}
+
+func (s *stubDeckSnapshotRepo) Write(snapshot entity.DeckSnapshot) error {
+	return s.write(snapshot)
+}
+
+func (s *stubDeckSnapshotRepo) Read() (entity.DeckSnapshot, error) {
+	return entity.DeckSnapshot{}, repo.DeckSnapshotNotFoundErr
+}
+
+func TestDeckSnapshots_TakeRestore(t *testing.T) {
+	dm := newMemoryDeckManager(DeckOptions{})
+	dealt, err := dm.New(context.Background(), true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := dm.Deal(context.Background(), dealt.ID, []string{"alice", "bob"}, 2); err != nil {
+		t.Fatal(err)
+	}
+	drawn, err := dm.New(context.Background(), false, []string{"AS", "KH"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dm.DrawCards(context.Background(), drawn.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	var written entity.DeckSnapshot
+	snapshots := NewDeckSnapshots(dm, &stubDeckSnapshotRepo{
+		write: func(snapshot entity.DeckSnapshot) error {
+			written = snapshot
+			return nil
+		},
+	}, nil)
+	got, err := snapshots.Take(context.Background())
+	if err != nil {
+		t.Fatalf("DeckSnapshots.Take() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(got, written); diff != "" {
+		t.Errorf("DeckSnapshots.Take() | returned snapshot differs from the written one (-got +want):\n%s", diff)
+	}
+	if len(got.Decks) != 2 || len(got.Events) != 6 {
+		t.Errorf("DeckSnapshots.Take() | got %d decks and %d events, want 2 and 6", len(got.Decks), len(got.Events))
+	}
+
+	decks := repo.NewDeck()
+	if err := RestoreDecks(context.Background(), got, decks, decks.Events()); err != nil {
+		t.Fatalf("RestoreDecks() | got error %v, want nil", err)
+	}
+	restored := NewDeckManager(decks, decks.Events(), DeckOptions{})
+
+	for _, id := range []string{dealt.ID, drawn.ID} {
+		want, _ := dm.Open(context.Background(), id)
+		deck, err := restored.Open(context.Background(), id)
+		if err != nil {
+			t.Fatalf("Deck.Open() | got error %v after restore", err)
+		}
+		if diff := cmp.Diff(deck, want); diff != "" {
+			t.Errorf("RestoreDecks() | deck differs (-got +want):\n%s", diff)
+		}
+
+		wantEvents, _ := dm.Events(context.Background(), id)
+		gotEvents, _ := restored.Events(context.Background(), id)
+		if diff := cmp.Diff(gotEvents, wantEvents); diff != "" {
+			t.Errorf("RestoreDecks() | events differ (-got +want):\n%s", diff)
+		}
+	}
+}
+
+func TestDeckSnapshots_Take_Error(t *testing.T) {
+	writeErr := errors.New("disk full")
+	snapshots := NewDeckSnapshots(newMemoryDeckManager(DeckOptions{}), &stubDeckSnapshotRepo{
+		write: func(entity.DeckSnapshot) error { return writeErr },
+	}, nil)
+
+	if _, err := snapshots.Take(context.Background()); !errors.Is(err, writeErr) {
+		t.Errorf("DeckSnapshots.Take() | got error %v, want %v", err, writeErr)
+	}
+}
+
+type stubDeckWALRepo struct {
+	calls []string
+}
+
+func (s *stubDeckWALRepo) Rotate() (int, error) {
+	s.calls = append(s.calls, "rotate")
+	return 4, nil
+}
+
+func (s *stubDeckWALRepo) Compact(segment int) error {
+	s.calls = append(s.calls, fmt.Sprintf("compact %d", segment))
+	return nil
+}
+
+func TestDeckSnapshots_Take_Concurrent(t *testing.T) {
+	var writing, overlaps int32
+	snapshots := NewDeckSnapshots(newMemoryDeckManager(DeckOptions{}), &stubDeckSnapshotRepo{
+		write: func(entity.DeckSnapshot) error {
+			if atomic.AddInt32(&writing, 1) > 1 {
+				atomic.AddInt32(&overlaps, 1)
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&writing, -1)
+			return nil
+		},
+	}, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := snapshots.Take(context.Background()); err != nil {
+				t.Errorf("DeckSnapshots.Take() | got error %v, want nil", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if overlaps != 0 {
+		t.Errorf("DeckSnapshots.Take() | got %d snapshots written at once, want one at a time", overlaps)
+	}
+}
+
+func TestDeckSnapshots_Take_WAL(t *testing.T) {
+	tests := []struct {
+		name     string
+		writeErr error
+		want     []string
+	}{
+		{
+			name: "Compacts After Writing",
+			want: []string{"rotate", "write", "compact 4"},
+		},
+		{
+			name:     "Keeps Segments On Error",
+			writeErr: errors.New("disk full"),
+			want:     []string{"rotate", "write"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wal := &stubDeckWALRepo{}
+			snapshots := NewDeckSnapshots(newMemoryDeckManager(DeckOptions{}), &stubDeckSnapshotRepo{
+				write: func(entity.DeckSnapshot) error {
+					wal.calls = append(wal.calls, "write")
+					return tt.writeErr
+				},
+			}, wal)
+
+			snapshots.Take(context.Background())
+			if diff := cmp.Diff(wal.calls, tt.want); diff != "" {
+				t.Errorf("DeckSnapshots.Take() | (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
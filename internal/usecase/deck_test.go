@@ -1,8 +1,14 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/lualfe/card-game/internal/usecase/repo"
 
@@ -15,11 +21,33 @@ type stubDeckStore struct {
 	get func(id string) (entity.Deck, error)
 }
 
-func (s *stubDeckStore) Get(id string) (entity.Deck, error) {
+func (s *stubDeckStore) Get(_ context.Context, _, id string) (entity.Deck, error) {
 	return s.get(id)
 }
 
-func (s *stubDeckStore) Save(_ entity.Deck) {}
+func (s *stubDeckStore) Save(context.Context, entity.Deck, ...entity.DeckEvent) error { return nil }
+
+func (s *stubDeckStore) Update(_ context.Context, _, id string, fn func(deck *entity.Deck) ([]entity.DeckEvent, error)) (entity.Deck, error) {
+	deck, err := s.get(id)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	_, err = fn(&deck)
+	return deck, err
+}
+
+func (s *stubDeckStore) All(context.Context) ([]entity.Deck, error) { return nil, nil }
+
+func (s *stubDeckStore) Count(context.Context, string) (int, error) { return 0, nil }
+
+func (s *stubDeckStore) Delete(context.Context, string, string) error { return nil }
+
+// newMemoryDeckManager creates a Deck on new in-memory
+// stores.
+func newMemoryDeckManager(opts DeckOptions) *Deck {
+	store := repo.NewDeck()
+	return NewDeckManager(store, store.Events(), opts)
+}
 
 func TestDeck_New(t *testing.T) {
 	customDeck := []entity.Card{
@@ -62,6 +90,7 @@ func TestDeck_New(t *testing.T) {
 			name: "Shuffled Default Cards",
 			want: entity.Deck{
 				Shuffled:  true,
+				Version:   2,
 				Remaining: 52,
 				Cards:     entity.DefaultCards,
 			},
@@ -70,6 +99,7 @@ func TestDeck_New(t *testing.T) {
 			name: "Not Shuffled Default Cards",
 			want: entity.Deck{
 				Shuffled:  false,
+				Version:   1,
 				Remaining: 52,
 				Cards:     entity.DefaultCards,
 			},
@@ -85,6 +115,7 @@ func TestDeck_New(t *testing.T) {
 			}(),
 			want: entity.Deck{
 				Shuffled:  true,
+				Version:   2,
 				Remaining: len(customDeck),
 				Cards:     customDeck,
 			},
@@ -100,6 +131,7 @@ func TestDeck_New(t *testing.T) {
 			}(),
 			want: entity.Deck{
 				Shuffled:  true,
+				Version:   2,
 				Remaining: 2,
 				Cards:     nonexistentCards[:len(nonexistentCards)-1],
 			},
@@ -109,13 +141,17 @@ func TestDeck_New(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			shufflerCalled := false
 			d := &Deck{
-				deckRepo: &stubDeckStore{},
+				deckRepo:  &stubDeckStore{},
+				eventRepo: repo.NewDeckEvents(),
 				shuffler: func(cards []entity.Card) {
 					shufflerCalled = true
 				},
 			}
 
-			got := d.New(tt.want.Shuffled, tt.cardCodes)
+			got, err := d.New(context.Background(), tt.want.Shuffled, tt.cardCodes)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if got.ID == "" {
 				t.Error("Deck.New() | got empty ID")
 			}
@@ -172,7 +208,7 @@ func TestDeck_Open(t *testing.T) {
 				},
 			}
 
-			got, err := d.Open(tt.want.ID)
+			got, err := d.Open(context.Background(), tt.want.ID)
 			if tt.wantErr == nil {
 				if err != nil {
 					t.Errorf("Deck.Open() | got error %v, want nil", err)
@@ -234,6 +270,7 @@ func TestDeck_DrawCards(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			d := &Deck{
+				eventRepo: repo.NewDeckEvents(),
 				deckRepo: &stubDeckStore{
 					get: func(id string) (entity.Deck, error) {
 						if tt.wantErr != nil {
@@ -248,7 +285,7 @@ func TestDeck_DrawCards(t *testing.T) {
 					},
 				},
 			}
-			got, err := d.DrawCards(tt.deckID, tt.amount)
+			got, err := d.DrawCards(context.Background(), tt.deckID, tt.amount)
 			if tt.wantErr == nil {
 				if err != nil {
 					t.Errorf("Deck.DrawCards() | error = %v, wantErr %v", err, tt.wantErr)
@@ -269,3 +306,458 @@ func TestDeck_DrawCards(t *testing.T) {
 		})
 	}
 }
+
+func TestDeck_Deal(t *testing.T) {
+	store := repo.NewDeck()
+	d := &Deck{deckRepo: store, eventRepo: store.Events()}
+	deck, err := d.New(context.Background(), false, []string{"AS", "2S", "3S", "4S", "5S", "6S", "7S"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, tokens, err := d.Deal(context.Background(), deck.ID, []string{"a", "b"}, 2)
+	if err != nil {
+		t.Fatalf("Deck.Deal() | got error %v, want nil", err)
+	}
+	if len(tokens) != 2 || tokens["a"] == "" || tokens["a"] == tokens["b"] {
+		t.Errorf("Deck.Deal() | got tokens %v, want one per player", tokens)
+	}
+
+	var hands [][]string
+	for _, h := range got.Hands {
+		var codes []string
+		for _, c := range h.Cards {
+			codes = append(codes, c.Code)
+		}
+		hands = append(hands, codes)
+	}
+	if diff := cmp.Diff(hands, [][]string{{"AS", "3S"}, {"2S", "4S"}}); diff != "" {
+		t.Errorf("Deck.Deal() | hands (-got +want):\n%s", diff)
+	}
+	if got.Remaining != 3 {
+		t.Errorf("Deck.Deal() | got remaining %d, want 3", got.Remaining)
+	}
+
+	// Dealing again adds to the existing hands.
+	got, tokens, err = d.Deal(context.Background(), deck.ID, []string{"b", "c"}, 1)
+	if err != nil {
+		t.Fatalf("Deck.Deal() | got error %v, want nil", err)
+	}
+	if len(tokens) != 1 || tokens["c"] == "" {
+		t.Errorf("Deck.Deal() | got tokens %v, want only c", tokens)
+	}
+	if len(got.Hands) != 3 || len(got.Hands[1].Cards) != 3 || got.Hands[2].Cards[0].Code != "6S" {
+		t.Errorf("Deck.Deal() | got hands %+v", got.Hands)
+	}
+
+	tests := []struct {
+		name    string
+		players []string
+		amount  int
+		wantErr error
+	}{
+		{name: "No Players", amount: 1, wantErr: DeckInvalidDealErr},
+		{name: "Duplicated Players", players: []string{"a", "a"}, amount: 1, wantErr: DeckInvalidDealErr},
+		{name: "No Cards", players: []string{"a"}, amount: 0, wantErr: DeckInvalidDealErr},
+		{name: "Not Enough Cards", players: []string{"a", "b"}, amount: 1, wantErr: DeckNotEnoughCardsErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := d.Deal(context.Background(), deck.ID, tt.players, tt.amount); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Deck.Deal() | got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeck_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	d := newMemoryDeckManager(DeckOptions{})
+	deck, err := d.New(ctx, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			cards, err := d.DrawCards(ctx, deck.ID, 2)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if _, _, err := d.Deal(ctx, deck.ID, []string{fmt.Sprintf("p%d", w)}, 1); err != nil {
+				errs <- err
+				return
+			}
+			// Returns losing every retry to the other workers
+			// give up, which is fine here.
+			if _, err := d.Return(ctx, deck.ID, []string{cards[0].Code}); err != nil && !errors.Is(err, repo.DeckConflictErr) {
+				errs <- err
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Deck | got error %v, want nil", err)
+	}
+
+	got, err := d.Open(ctx, deck.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := d.Events(ctx, deck.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, e := range events {
+		if e.Version != i+1 {
+			t.Fatalf("Deck.Events() | got version %d at %d, want %d", e.Version, i, i+1)
+		}
+	}
+	if diff := cmp.Diff(got, foldDeckEvents(events)); diff != "" {
+		t.Errorf("Deck.Events() | folded events don't match the deck (-got +want):\n%s", diff)
+	}
+}
+
+func TestDeck_Events(t *testing.T) {
+	store := repo.NewDeck()
+	d := &Deck{
+		deckRepo:  store,
+		eventRepo: store.Events(),
+		shuffler: func(cards []entity.Card) {
+			for i, j := 0, len(cards)-1; i < j; i, j = i+1, j-1 {
+				cards[i], cards[j] = cards[j], cards[i]
+			}
+		},
+	}
+	deck, err := d.New(context.Background(), true, []string{"AS", "2S", "3S", "4S", "5S", "6S"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := d.DrawCards(context.Background(), deck.ID, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := d.Deal(context.Background(), deck.ID, []string{"a", "b"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Return(context.Background(), deck.ID, []string{"5S"}); err != nil {
+		t.Fatalf("Deck.Return() | got error %v, want nil", err)
+	}
+
+	events, err := d.Events(context.Background(), deck.ID)
+	if err != nil {
+		t.Fatalf("Deck.Events() | got error %v, want nil", err)
+	}
+	var types []string
+	for i, e := range events {
+		types = append(types, e.Type)
+		if e.Version != i+1 {
+			t.Errorf("Deck.Events() | event %d has version %d", i, e.Version)
+		}
+	}
+	wantTypes := []string{
+		entity.DeckEventCreated,
+		entity.DeckEventShuffled,
+		entity.DeckEventDrawn,
+		entity.DeckEventMoved,
+		entity.DeckEventMoved,
+		entity.DeckEventReturned,
+	}
+	if diff := cmp.Diff(types, wantTypes); diff != "" {
+		t.Errorf("Deck.Events() | types (-got +want):\n%s", diff)
+	}
+
+	current, err := d.Open(context.Background(), deck.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	folded, err := d.At(context.Background(), deck.ID, len(events))
+	if err != nil {
+		t.Fatalf("Deck.At() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(folded, current); diff != "" {
+		t.Errorf("Deck.At() | folded events differ from the deck (-got +want):\n%s", diff)
+	}
+
+	drawn, err := d.At(context.Background(), deck.ID, 3)
+	if err != nil {
+		t.Fatalf("Deck.At() | got error %v, want nil", err)
+	}
+	want := entity.Deck{
+		ID:        deck.ID,
+		Shuffled:  true,
+		Remaining: 4,
+		Cards:     cardsByCodes("4S 3S 2S AS"),
+		Version:   3,
+	}
+	if diff := cmp.Diff(drawn, want); diff != "" {
+		t.Errorf("Deck.At() | (-got +want):\n%s", diff)
+	}
+
+	if _, err := d.At(context.Background(), deck.ID, 7); !errors.Is(err, DeckVersionNotFoundErr) {
+		t.Errorf("Deck.At() | got error %v, want %v", err, DeckVersionNotFoundErr)
+	}
+	if _, err := d.Events(context.Background(), "other"); !errors.Is(err, DeckNotFoundErr) {
+		t.Errorf("Deck.Events() | got error %v, want %v", err, DeckNotFoundErr)
+	}
+
+	// 5S is back in the deck and 4S went to a hand.
+	for _, code := range []string{"5S", "4S", "AS"} {
+		if _, err := d.Return(context.Background(), deck.ID, []string{code}); !errors.Is(err, DeckInvalidReturnErr) {
+			t.Errorf("Deck.Return(%s) | got error %v, want %v", code, err, DeckInvalidReturnErr)
+		}
+	}
+}
+
+func TestDeck_Events_Actor(t *testing.T) {
+	d := newMemoryDeckManager(DeckOptions{})
+	alice := WithPrincipal(context.Background(), entity.Principal{ID: "alice"})
+	bob := WithPrincipal(context.Background(), entity.Principal{ID: "bob", Admin: true})
+
+	deck, err := d.New(alice, false, []string{"AS", "2S"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.DrawCards(bob, deck.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.DrawCards(context.Background(), deck.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := d.Events(context.Background(), deck.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actors []string
+	for _, e := range events {
+		actors = append(actors, e.Actor)
+	}
+	if diff := cmp.Diff(actors, []string{"alice", "bob", ""}); diff != "" {
+		t.Errorf("Deck.Events() | actors (-got +want):\n%s", diff)
+	}
+}
+
+func TestDeck_Canceled(t *testing.T) {
+	store := repo.NewDeck()
+	d := NewDeckManager(store, store.Events(), DeckOptions{})
+	deck, err := d.New(context.Background(), false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := d.New(ctx, false, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Deck.New() | got error %v, want %v", err, context.Canceled)
+	}
+	if _, err := d.DrawCards(ctx, deck.ID, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Deck.DrawCards() | got error %v, want %v", err, context.Canceled)
+	}
+	if _, _, err := d.Deal(ctx, deck.ID, []string{"a"}, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Deck.Deal() | got error %v, want %v", err, context.Canceled)
+	}
+
+	// Nothing the canceled calls did was kept.
+	if events, _ := store.Events().All(context.Background()); len(events) != 1 || events[0].DeckID != deck.ID {
+		t.Errorf("Deck | got events %v, want only the first deck created", events)
+	}
+}
+
+func TestDeck_MaxDecks(t *testing.T) {
+	d := newMemoryDeckManager(DeckOptions{MaxDecks: 2})
+	for i := 0; i < 2; i++ {
+		if _, err := d.New(context.Background(), false, nil); err != nil {
+			t.Fatalf("Deck.New() | got error %v, want nil", err)
+		}
+	}
+
+	if _, err := d.New(context.Background(), false, nil); !errors.Is(err, DeckLimitErr) {
+		t.Errorf("Deck.New() | got error %v, want %v", err, DeckLimitErr)
+	}
+}
+
+func TestDeck_Tenants(t *testing.T) {
+	d := newMemoryDeckManager(DeckOptions{})
+	acme := WithPrincipal(context.Background(), entity.Principal{ID: "alice", Tenant: "acme"})
+	globex := WithPrincipal(context.Background(), entity.Principal{ID: "alice", Tenant: "globex"})
+
+	deck, err := d.New(acme, false, nil)
+	if err != nil {
+		t.Fatalf("Deck.New() | got error %v, want nil", err)
+	}
+	if deck.Tenant != "acme" {
+		t.Errorf("Deck.New() | got tenant %q, want acme", deck.Tenant)
+	}
+	if _, err := d.Open(acme, deck.ID); err != nil {
+		t.Errorf("Deck.Open() | got error %v within the tenant, want nil", err)
+	}
+
+	// Another tenant, or the default one, can't tell the
+	// deck exists.
+	for name, ctx := range map[string]context.Context{"globex": globex, "default": context.Background()} {
+		if _, err := d.Open(ctx, deck.ID); !errors.Is(err, DeckNotFoundErr) {
+			t.Errorf("Deck.Open() | got error %v from %s, want %v", err, name, DeckNotFoundErr)
+		}
+		if _, err := d.DrawCards(ctx, deck.ID, 1); !errors.Is(err, DeckNotFoundErr) {
+			t.Errorf("Deck.DrawCards() | got error %v from %s, want %v", err, name, DeckNotFoundErr)
+		}
+		if _, err := d.Events(ctx, deck.ID); !errors.Is(err, DeckNotFoundErr) {
+			t.Errorf("Deck.Events() | got error %v from %s, want %v", err, name, DeckNotFoundErr)
+		}
+		if _, err := d.At(ctx, deck.ID, 1); !errors.Is(err, DeckNotFoundErr) {
+			t.Errorf("Deck.At() | got error %v from %s, want %v", err, name, DeckNotFoundErr)
+		}
+		if err := d.Delete(ctx, deck.ID); !errors.Is(err, DeckNotFoundErr) {
+			t.Errorf("Deck.Delete() | got error %v from %s, want %v", err, name, DeckNotFoundErr)
+		}
+	}
+
+	if at, err := d.At(acme, deck.ID, 1); err != nil || at.Tenant != "acme" {
+		t.Errorf("Deck.At() | got tenant %q and error %v, want acme and nil", at.Tenant, err)
+	}
+}
+
+func TestDeck_TenantLimits(t *testing.T) {
+	d := newMemoryDeckManager(DeckOptions{MaxDecks: 3})
+	ctx := WithPrincipal(context.Background(), entity.Principal{ID: "alice", Tenant: "acme"})
+	ctx = WithTenant(ctx, entity.Tenant{Name: "acme", Limits: entity.TenantLimits{MaxDecks: 1, MaxCards: 52}})
+
+	if _, err := d.New(ctx, false, defaultCardCodes(2)); !errors.Is(err, TenantQuotaErr) {
+		t.Errorf("Deck.New() | got error %v for 104 cards, want %v", err, TenantQuotaErr)
+	}
+	if _, err := d.New(ctx, false, nil); err != nil {
+		t.Fatalf("Deck.New() | got error %v, want nil", err)
+	}
+	if _, err := d.New(ctx, false, nil); !errors.Is(err, TenantQuotaErr) {
+		t.Errorf("Deck.New() | got error %v over the tenant's decks, want %v", err, TenantQuotaErr)
+	}
+
+	// The decks of a tenant don't count against the others.
+	if _, err := d.New(context.Background(), false, nil); err != nil {
+		t.Errorf("Deck.New() | got error %v for the default tenant, want nil", err)
+	}
+}
+
+// countBlocker holds the counts of a tenant's decks until
+// release is closed.
+type countBlocker struct {
+	*repo.Deck
+	tenant  string
+	counted chan struct{}
+	release chan struct{}
+}
+
+func (c *countBlocker) Count(ctx context.Context, tenant string) (int, error) {
+	if tenant == c.tenant {
+		c.counted <- struct{}{}
+		<-c.release
+	}
+	return c.Deck.Count(ctx, tenant)
+}
+
+func TestDeck_TenantLimits_Concurrent(t *testing.T) {
+	store := repo.NewDeck()
+	d := NewDeckManager(store, store.Events(), DeckOptions{MaxDecks: 5})
+	ctx := WithPrincipal(context.Background(), entity.Principal{ID: "alice", Tenant: "acme"})
+
+	var (
+		wg      sync.WaitGroup
+		created int32
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := d.New(ctx, false, nil); err == nil {
+				atomic.AddInt32(&created, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if created != 5 {
+		t.Errorf("Deck.New() | got %d decks created, want 5", created)
+	}
+	if len(d.creating) != 0 {
+		t.Errorf("Deck.New() | got %d tenant locks left, want 0", len(d.creating))
+	}
+
+	// A tenant counting its decks doesn't hold the others.
+	blocker := &countBlocker{Deck: repo.NewDeck(), tenant: "acme", counted: make(chan struct{}), release: make(chan struct{})}
+	d = NewDeckManager(blocker, blocker.Events(), DeckOptions{MaxDecks: 5})
+	done := make(chan error)
+	go func() {
+		_, err := d.New(ctx, false, nil)
+		done <- err
+	}()
+	<-blocker.counted
+
+	globex := WithPrincipal(context.Background(), entity.Principal{ID: "bob", Tenant: "globex"})
+	if _, err := d.New(globex, false, nil); err != nil {
+		t.Errorf("Deck.New() | got error %v for another tenant, want nil", err)
+	}
+	close(blocker.release)
+	if err := <-done; err != nil {
+		t.Errorf("Deck.New() | got error %v, want nil", err)
+	}
+}
+
+func TestDeck_Expire(t *testing.T) {
+	store := repo.NewDeck()
+	d := NewDeckManager(store, store.Events(), DeckOptions{TTL: time.Hour})
+	old := entity.Deck{ID: "old", Version: 1}
+	err := store.Save(context.Background(), old, entity.DeckEvent{
+		DeckID:  old.ID,
+		Version: 1,
+		Type:    entity.DeckEventCreated,
+		Time:    time.Now().Add(-2 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh, err := d.New(context.Background(), false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := d.Expire(context.Background())
+	if err != nil {
+		t.Fatalf("Deck.Expire() | got error %v, want nil", err)
+	}
+	if n != 1 {
+		t.Errorf("Deck.Expire() | got %d decks removed, want 1", n)
+	}
+	if _, err := d.Open(context.Background(), old.ID); !errors.Is(err, DeckNotFoundErr) {
+		t.Errorf("Deck.Open() | got error %v for an expired deck, want %v", err, DeckNotFoundErr)
+	}
+	if _, err := d.Open(context.Background(), fresh.ID); err != nil {
+		t.Errorf("Deck.Open() | got error %v for a fresh deck, want nil", err)
+	}
+	if _, err := d.Events(context.Background(), old.ID); err != nil {
+		t.Errorf("Deck.Events() | got error %v for an expired deck, want nil", err)
+	}
+}
+
+func TestCryptoShuffle(t *testing.T) {
+	cards := append([]entity.Card{}, entity.DefaultCards...)
+	cryptoShuffle(cards)
+
+	if cmp.Equal(cards, entity.DefaultCards) {
+		t.Error("cryptoShuffle() | cards kept their order")
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].Code < cards[j].Code })
+	want := append([]entity.Card{}, entity.DefaultCards...)
+	sort.Slice(want, func(i, j int) bool { return want[i].Code < want[j].Code })
+	if diff := cmp.Diff(cards, want); diff != "" {
+		t.Errorf("cryptoShuffle() | cards changed (-got +want):\n%s", diff)
+	}
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+// tracerName names the spans of the use cases.
+const tracerName = "github.com/lualfe/card-game/internal/usecase"
+
+// Span attributes of the decks.
+const (
+	deckIDKey     = attribute.Key("deck.id")
+	deckAmountKey = attribute.Key("deck.amount")
+	deckCardsKey  = attribute.Key("deck.cards")
+	deckTenantKey = attribute.Key("deck.tenant")
+)
+
+// DeckTracing is a DeckManager starting a span for every
+// call.
+type DeckTracing struct {
+	deck   DeckManager
+	tracer trace.Tracer
+}
+
+// NewDeckTracing wraps deck, starting its spans with a
+// tracer of tp.
+func NewDeckTracing(deck DeckManager, tp trace.TracerProvider) *DeckTracing {
+	return &DeckTracing{deck: deck, tracer: tp.Tracer(tracerName)}
+}
+
+// New creates a deck.
+func (t *DeckTracing) New(ctx context.Context, shuffle bool, cardCodes []string) (deck entity.Deck, err error) {
+	ctx, span := t.tracer.Start(ctx, "DeckManager.New", trace.WithAttributes(
+		attribute.Bool("deck.shuffled", shuffle),
+		deckCardsKey.Int(len(cardCodes)),
+	))
+	defer func() {
+		span.SetAttributes(deckIDKey.String(deck.ID))
+		endSpan(span, err)
+	}()
+	return t.deck.New(ctx, shuffle, cardCodes)
+}
+
+// Open returns a deck.
+func (t *DeckTracing) Open(ctx context.Context, id string) (deck entity.Deck, err error) {
+	ctx, span := t.tracer.Start(ctx, "DeckManager.Open", trace.WithAttributes(deckIDKey.String(id)))
+	defer func() { endSpan(span, err) }()
+	return t.deck.Open(ctx, id)
+}
+
+// DrawCards draws cards from a deck.
+func (t *DeckTracing) DrawCards(ctx context.Context, id string, amount int) (cards []entity.Card, err error) {
+	ctx, span := t.tracer.Start(ctx, "DeckManager.DrawCards", trace.WithAttributes(
+		deckIDKey.String(id),
+		deckAmountKey.Int(amount),
+	))
+	defer func() {
+		span.SetAttributes(deckCardsKey.Int(len(cards)))
+		endSpan(span, err)
+	}()
+	return t.deck.DrawCards(ctx, id, amount)
+}
+
+// Deal deals cards from a deck to players.
+func (t *DeckTracing) Deal(ctx context.Context, id string, players []string, amount int) (deck entity.Deck, hands map[string]string, err error) {
+	ctx, span := t.tracer.Start(ctx, "DeckManager.Deal", trace.WithAttributes(
+		deckIDKey.String(id),
+		deckAmountKey.Int(amount),
+		attribute.Int("deck.players", len(players)),
+	))
+	defer func() { endSpan(span, err) }()
+	return t.deck.Deal(ctx, id, players, amount)
+}
+
+// Return puts drawn cards back in a deck.
+func (t *DeckTracing) Return(ctx context.Context, id string, cardCodes []string) (deck entity.Deck, err error) {
+	ctx, span := t.tracer.Start(ctx, "DeckManager.Return", trace.WithAttributes(
+		deckIDKey.String(id),
+		deckCardsKey.Int(len(cardCodes)),
+	))
+	defer func() { endSpan(span, err) }()
+	return t.deck.Return(ctx, id, cardCodes)
+}
+
+// Events returns the history of a deck.
+func (t *DeckTracing) Events(ctx context.Context, id string) (events []entity.DeckEvent, err error) {
+	ctx, span := t.tracer.Start(ctx, "DeckManager.Events", trace.WithAttributes(deckIDKey.String(id)))
+	defer func() { endSpan(span, err) }()
+	return t.deck.Events(ctx, id)
+}
+
+// At returns a deck as it was at a version.
+func (t *DeckTracing) At(ctx context.Context, id string, version int) (deck entity.Deck, err error) {
+	ctx, span := t.tracer.Start(ctx, "DeckManager.At", trace.WithAttributes(
+		deckIDKey.String(id),
+		attribute.Int("deck.version", version),
+	))
+	defer func() { endSpan(span, err) }()
+	return t.deck.At(ctx, id, version)
+}
+
+// Delete removes a deck.
+func (t *DeckTracing) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := t.tracer.Start(ctx, "DeckManager.Delete", trace.WithAttributes(deckIDKey.String(id)))
+	defer func() { endSpan(span, err) }()
+	return t.deck.Delete(ctx, id)
+}
+
+// DeckRepoTracing is a DeckRepo starting a span for every
+// store call.
+type DeckRepoTracing struct {
+	store  DeckRepo
+	tracer trace.Tracer
+}
+
+// NewDeckRepoTracing wraps store, starting its spans with a
+// tracer of tp.
+func NewDeckRepoTracing(store DeckRepo, tp trace.TracerProvider) *DeckRepoTracing {
+	return &DeckRepoTracing{store: store, tracer: tp.Tracer(tracerName)}
+}
+
+func (t *DeckRepoTracing) start(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, "DeckRepo."+op, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// Save saves a deck to the store.
+func (t *DeckRepoTracing) Save(ctx context.Context, deck entity.Deck, events ...entity.DeckEvent) (err error) {
+	ctx, span := t.start(ctx, "Save", deckIDKey.String(deck.ID), deckTenantKey.String(deck.Tenant))
+	defer func() { endSpan(span, err) }()
+	return t.store.Save(ctx, deck, events...)
+}
+
+// Get retrieves a deck from its ID.
+func (t *DeckRepoTracing) Get(ctx context.Context, tenant, id string) (deck entity.Deck, err error) {
+	ctx, span := t.start(ctx, "Get", deckIDKey.String(id), deckTenantKey.String(tenant))
+	defer func() { endSpan(span, err) }()
+	return t.store.Get(ctx, tenant, id)
+}
+
+// Update changes a deck with fn and saves it.
+func (t *DeckRepoTracing) Update(ctx context.Context, tenant, id string, fn func(deck *entity.Deck) ([]entity.DeckEvent, error)) (deck entity.Deck, err error) {
+	ctx, span := t.start(ctx, "Update", deckIDKey.String(id), deckTenantKey.String(tenant))
+	defer func() { endSpan(span, err) }()
+	return t.store.Update(ctx, tenant, id, fn)
+}
+
+// All returns every deck in the store.
+func (t *DeckRepoTracing) All(ctx context.Context) (decks []entity.Deck, err error) {
+	ctx, span := t.start(ctx, "All")
+	defer func() {
+		span.SetAttributes(attribute.Int("deck.count", len(decks)))
+		endSpan(span, err)
+	}()
+	return t.store.All(ctx)
+}
+
+// Count returns how many decks a tenant holds.
+func (t *DeckRepoTracing) Count(ctx context.Context, tenant string) (n int, err error) {
+	ctx, span := t.start(ctx, "Count", deckTenantKey.String(tenant))
+	defer func() { endSpan(span, err) }()
+	return t.store.Count(ctx, tenant)
+}
+
+// Delete removes a deck from the store.
+func (t *DeckRepoTracing) Delete(ctx context.Context, tenant, id string) (err error) {
+	ctx, span := t.start(ctx, "Delete", deckIDKey.String(id), deckTenantKey.String(tenant))
+	defer func() { endSpan(span, err) }()
+	return t.store.Delete(ctx, tenant, id)
+}
+
+// endSpan ends span, marking it failed by err. As for the
+// metrics, a deck not being found isn't a failure.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		if !errors.Is(err, DeckNotFoundErr) && !errors.Is(err, repo.DeckNotFoundErr) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+func TestDeckTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	memory := repo.NewDeck()
+	store := NewDeckRepoTracing(memory, tp)
+	d := NewDeckTracing(NewDeckManager(store, memory.Events(), DeckOptions{}), tp)
+
+	deck, err := d.New(context.Background(), true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.DrawCards(context.Background(), deck.ID, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	// Spans end before their parents.
+	want := []struct {
+		name   string
+		parent string
+	}{
+		{name: "Deck.shuffle", parent: "DeckManager.New"},
+		{name: "DeckRepo.Save", parent: "DeckManager.New"},
+		{name: "DeckManager.New"},
+		{name: "DeckRepo.Update", parent: "DeckManager.DrawCards"},
+		{name: "DeckManager.DrawCards"},
+	}
+	spans := recorder.Ended()
+	if len(spans) != len(want) {
+		t.Fatalf("DeckTracing | got %d spans, want %d", len(spans), len(want))
+	}
+	ids := map[string]string{}
+	for _, s := range spans {
+		ids[s.SpanContext().SpanID().String()] = s.Name()
+	}
+	for i, s := range spans {
+		parent := ""
+		if s.Parent().IsValid() {
+			parent = ids[s.Parent().SpanID().String()]
+		}
+		if s.Name() != want[i].name || parent != want[i].parent {
+			t.Errorf("DeckTracing | got span %d %s with parent %q, want %s with parent %q", i, s.Name(), parent, want[i].name, want[i].parent)
+		}
+	}
+}
+
+func TestDeckTracing_Errors(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	memory := repo.NewDeck()
+	store := NewDeckRepoTracing(memory, tp)
+	d := NewDeckTracing(NewDeckManager(store, memory.Events(), DeckOptions{}), tp)
+
+	if _, err := d.Open(context.Background(), "missing"); !errors.Is(err, DeckNotFoundErr) {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := d.Open(ctx, "missing"); err == nil {
+		t.Fatal("DeckTracing.Open() | got nil error with a canceled context")
+	}
+
+	want := []codes.Code{codes.Unset, codes.Unset, codes.Error, codes.Error}
+	spans := recorder.Ended()
+	if len(spans) != len(want) {
+		t.Fatalf("DeckTracing | got %d spans, want %d", len(spans), len(want))
+	}
+	for i, s := range spans {
+		if s.Status().Code != want[i] {
+			t.Errorf("DeckTracing | got span %d %s with status %v, want %v", i, s.Name(), s.Status().Code, want[i])
+		}
+		if len(s.Events()) != 1 {
+			t.Errorf("DeckTracing | got span %d %s with %d events, want the error", i, s.Name(), len(s.Events()))
+		}
+	}
+}
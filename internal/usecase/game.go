@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// GameForbiddenErr happens when a game is reached by a
+// caller other than its owner or an admin.
+var GameForbiddenErr = errors.New("game belongs to another key")
+
+// checkGameOwner fails unless the principal of ctx, if
+// any, owns the game or is an admin, as checkDeckOwner
+// does for decks. The games are kept within the tenant of
+// their owner, so other tenants don't find them at all.
+func checkGameOwner(ctx context.Context, game, id, owner string) error {
+	p, ok := PrincipalFrom(ctx)
+	if !ok || p.Admin || (owner != "" && owner == p.ID) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s %s", GameForbiddenErr, game, id)
+}
+
+// gameOwner returns who owns the games created with ctx,
+// if anyone.
+func gameOwner(ctx context.Context) string {
+	p, _ := PrincipalFrom(ctx)
+	return p.ID
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+func TestGame_Owner(t *testing.T) {
+	var (
+		alice = WithPrincipal(context.Background(), entity.Principal{ID: "alice", Tenant: "acme"})
+		bob   = WithPrincipal(context.Background(), entity.Principal{ID: "bob", Tenant: "acme"})
+		admin = WithPrincipal(context.Background(), entity.Principal{ID: "root", Tenant: "acme", Admin: true})
+		other = WithPrincipal(context.Background(), entity.Principal{ID: "alice", Tenant: "globex"})
+	)
+
+	k := NewKlondikeManager(&stubShoe{codes: klondikeCodes(42)}, repo.NewKlondikeGame(), KlondikeBudget{})
+	game, err := k.New(alice, 42, 1)
+	if err != nil {
+		t.Fatalf("Klondike.New() | got error %v, want nil", err)
+	}
+	if game.Owner != "alice" || game.Tenant != "acme" {
+		t.Errorf("Klondike.New() | got owner %q in tenant %q, want alice in acme", game.Owner, game.Tenant)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{name: "owner", ctx: alice},
+		{name: "admin", ctx: admin},
+		{name: "another key", ctx: bob, want: GameForbiddenErr},
+		{name: "another tenant", ctx: other, want: KlondikeGameNotFoundErr},
+		{name: "default tenant", ctx: context.Background(), want: KlondikeGameNotFoundErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := k.Game(tt.ctx, game.ID); !errors.Is(err, tt.want) {
+				t.Errorf("Klondike.Game() | got error %v, want %v", err, tt.want)
+			}
+			if _, err := k.Undo(tt.ctx, game.ID); tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Klondike.Undo() | got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
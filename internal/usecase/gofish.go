@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// GoFish holds the rules of Go Fish. Players ask each other
+// for a value they hold and go fishing in the stock when the
+// other player has none. Four cards of a value make a book.
+type GoFish struct {
+	deck DeckManager
+}
+
+// Players returns how many players can play.
+func (g *GoFish) Players() (int, int) {
+	return 2, 6
+}
+
+// Deal gives seven cards to each player, or five
+// when there are more than three players.
+func (g *GoFish) Deal(ctx context.Context, game *entity.CasualGame) error {
+	size := 7
+	if len(game.Players) > 3 {
+		size = 5
+	}
+	if err := dealHands(ctx, g.deck, game, size); err != nil {
+		return err
+	}
+
+	for i := range game.Players {
+		collectBooks(&game.Players[i])
+	}
+
+	return g.prepareTurn(ctx, game)
+}
+
+// LegalMoves lists the moves of a player: asking any other
+// player holding cards for a value in the player's hand.
+func (g *GoFish) LegalMoves(game entity.CasualGame, player string) []entity.CasualMove {
+	if game.Finished || game.Players[game.Turn].ID != player {
+		return nil
+	}
+
+	var moves []entity.CasualMove
+	seen := map[string]bool{}
+	for _, c := range game.Players[game.Turn].Hand {
+		if seen[c.Value] {
+			continue
+		}
+		seen[c.Value] = true
+
+		for _, other := range game.Players {
+			if other.ID != player && len(other.Hand) > 0 {
+				moves = append(moves, entity.CasualMove{
+					Player: player,
+					Action: entity.CasualMoveAsk,
+					Target: other.ID,
+					Value:  c.Value,
+				})
+			}
+		}
+	}
+
+	return moves
+}
+
+// Play asks the target for a value. The player goes
+// again after getting the value from the target or
+// fishing it from the stock.
+func (g *GoFish) Play(ctx context.Context, game *entity.CasualGame, move entity.CasualMove) error {
+	asker := casualPlayerIndex(*game, move.Player)
+	target := casualPlayerIndex(*game, move.Target)
+
+	var taken, kept []entity.Card
+	for _, c := range game.Players[target].Hand {
+		if c.Value == move.Value {
+			taken = append(taken, c)
+		} else {
+			kept = append(kept, c)
+		}
+	}
+
+	again := false
+	if len(taken) > 0 {
+		game.Players[target].Hand = kept
+		game.Players[asker].Hand = append(append([]entity.Card{}, game.Players[asker].Hand...), taken...)
+		game.Message = fmt.Sprintf("%s took %d %s from %s", move.Player, len(taken), move.Value, move.Target)
+		again = true
+	} else {
+		card, ok, err := drawToHand(ctx, g.deck, game, asker)
+		if err != nil {
+			return err
+		}
+		game.Message = fmt.Sprintf("%s went fishing", move.Player)
+		if ok && card.Value == move.Value {
+			game.Message = fmt.Sprintf("%s fished the %s asked for", move.Player, move.Value)
+			again = true
+		}
+	}
+	collectBooks(&game.Players[asker])
+
+	if !again {
+		game.Turn = (game.Turn + 1) % len(game.Players)
+	}
+	game.Round++
+
+	return g.prepareTurn(ctx, game)
+}
+
+// BotMove asks for the value the bot holds the most of.
+// The player asked rotates every round, so the bot doesn't
+// keep asking someone who has already said no.
+func (g *GoFish) BotMove(game entity.CasualGame, player string) (entity.CasualMove, bool) {
+	moves := g.LegalMoves(game, player)
+	if len(moves) == 0 {
+		return entity.CasualMove{}, false
+	}
+
+	held := map[string]int{}
+	most := 0
+	for _, c := range game.Players[casualPlayerIndex(game, player)].Hand {
+		held[c.Value]++
+		if held[c.Value] > most {
+			most = held[c.Value]
+		}
+	}
+
+	var best []entity.CasualMove
+	for _, m := range moves {
+		if held[m.Value] == most {
+			best = append(best, m)
+		}
+	}
+
+	return best[game.Round%len(best)], true
+}
+
+// prepareTurn makes sure the player to move has cards,
+// drawing from the stock or skipping players with empty
+// hands, and finishes the game when every book is made.
+func (g *GoFish) prepareTurn(ctx context.Context, game *entity.CasualGame) error {
+	for skipped := 0; skipped <= len(game.Players); skipped++ {
+		books := 0
+		for _, p := range game.Players {
+			books += len(p.Books)
+		}
+		if books == len(entity.DefaultCards)/4 {
+			g.finish(game)
+			return nil
+		}
+
+		if len(game.Players[game.Turn].Hand) > 0 {
+			return nil
+		}
+
+		_, ok, err := drawToHand(ctx, g.deck, game, game.Turn)
+		if err != nil {
+			return err
+		}
+		if ok {
+			collectBooks(&game.Players[game.Turn])
+			if len(game.Players[game.Turn].Hand) > 0 {
+				return nil
+			}
+			continue
+		}
+
+		game.Turn = (game.Turn + 1) % len(game.Players)
+	}
+
+	g.finish(game)
+	return nil
+}
+
+func (g *GoFish) finish(game *entity.CasualGame) {
+	game.Finished = true
+	most := -1
+	for _, p := range game.Players {
+		switch {
+		case len(p.Books) > most:
+			most = len(p.Books)
+			game.Winner = p.ID
+		case len(p.Books) == most:
+			// A tie has no single winner.
+			game.Winner = ""
+		}
+	}
+}
+
+// collectBooks moves every complete set of four
+// values from the hand to the books.
+func collectBooks(player *entity.CasualPlayer) {
+	counts := map[string]int{}
+	for _, c := range player.Hand {
+		counts[c.Value]++
+	}
+
+	var hand []entity.Card
+	for _, c := range player.Hand {
+		if counts[c.Value] < 4 {
+			hand = append(hand, c)
+		}
+	}
+	for _, c := range player.Hand {
+		if counts[c.Value] == 4 {
+			player.Books = append(player.Books, c.Value)
+			counts[c.Value] = 0
+		}
+	}
+	player.Hand = hand
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// healthCheckTimeout bounds each health check, so a hung
+// store reports as down rather than hanging the probe.
+const healthCheckTimeout = 2 * time.Second
+
+// HealthShuttingDownErr is the readiness error once the
+// application is shutting down.
+var HealthShuttingDownErr = errors.New("shutting down")
+
+// HealthCheck tells whether a part of the application
+// works, returning why when it doesn't.
+type HealthCheck func(ctx context.Context) error
+
+type namedHealthCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// Health is a use case to probe the application. Each
+// part of it adds the checks telling whether it's alive,
+// and whether it can take requests.
+type Health struct {
+	mu        sync.RWMutex
+	liveness  []namedHealthCheck
+	readiness []namedHealthCheck
+
+	draining int32
+}
+
+// NewHealth creates a new Health.
+func NewHealth() *Health {
+	return &Health{}
+}
+
+// AddLiveness adds a check that fails when the application
+// is broken and needs a restart.
+func (h *Health) AddLiveness(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.liveness = append(h.liveness, namedHealthCheck{name: name, check: check})
+}
+
+// AddReadiness adds a check that fails while the
+// application can't take requests.
+func (h *Health) AddReadiness(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.readiness = append(h.readiness, namedHealthCheck{name: name, check: check})
+}
+
+// Drain makes the application not ready from now on, so
+// it's taken out of rotation while shutting down.
+func (h *Health) Drain() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+// Live runs the liveness checks.
+func (h *Health) Live(ctx context.Context) entity.HealthReport {
+	h.mu.RLock()
+	checks := append([]namedHealthCheck{}, h.liveness...)
+	h.mu.RUnlock()
+
+	return runHealthChecks(ctx, checks)
+}
+
+// Ready runs the readiness checks, which fail once the
+// application is draining.
+func (h *Health) Ready(ctx context.Context) entity.HealthReport {
+	h.mu.RLock()
+	checks := append([]namedHealthCheck{{name: "shutdown", check: h.checkDraining}}, h.readiness...)
+	h.mu.RUnlock()
+
+	return runHealthChecks(ctx, checks)
+}
+
+func (h *Health) checkDraining(context.Context) error {
+	if atomic.LoadInt32(&h.draining) == 1 {
+		return HealthShuttingDownErr
+	}
+	return nil
+}
+
+// runHealthChecks runs the checks at the same time and
+// reports them in the order given.
+func runHealthChecks(ctx context.Context, checks []namedHealthCheck) entity.HealthReport {
+	report := entity.HealthReport{
+		Status: entity.HealthUp,
+		Checks: make([]entity.HealthCheckResult, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c namedHealthCheck) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := c.check(ctx)
+			result := entity.HealthCheckResult{
+				Name:     c.name,
+				Status:   entity.HealthUp,
+				Duration: time.Since(start),
+			}
+			if err != nil {
+				result.Status = entity.HealthDown
+				result.Error = err.Error()
+			}
+			report.Checks[i] = result
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, c := range report.Checks {
+		if c.Status != entity.HealthUp {
+			report.Status = entity.HealthDown
+		}
+	}
+	return report
+}
+
+// HealthFresh is a check failing once the time given by
+// last is more than maxAge ago, for jobs expected to run
+// every so often.
+func HealthFresh(last func() time.Time, maxAge time.Duration) HealthCheck {
+	return func(context.Context) error {
+		if age := time.Since(last()); age > maxAge {
+			return fmt.Errorf("last run %s ago, expected every %s", age.Round(time.Second), maxAge)
+		}
+		return nil
+	}
+}
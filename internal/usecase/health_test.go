@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func TestHealth(t *testing.T) {
+	h := NewHealth()
+	h.AddLiveness("job", func(context.Context) error { return nil })
+	h.AddReadiness("store", func(context.Context) error { return errors.New("connection refused") })
+	h.AddReadiness("cache", func(context.Context) error { return nil })
+
+	live := h.Live(context.Background())
+	if live.Status != entity.HealthUp || len(live.Checks) != 1 {
+		t.Errorf("Health.Live() | got %+v, want one passing check", live)
+	}
+
+	ready := h.Ready(context.Background())
+	if ready.Status != entity.HealthDown {
+		t.Errorf("Health.Ready() | got status %s, want %s", ready.Status, entity.HealthDown)
+	}
+	var got []string
+	for _, c := range ready.Checks {
+		got = append(got, c.Name+" "+c.Status+" "+c.Error)
+	}
+	want := []string{"shutdown up ", "store down connection refused", "cache up "}
+	if len(got) != len(want) {
+		t.Fatalf("Health.Ready() | got checks %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Health.Ready() | got check %q, want %q", got[i], want[i])
+		}
+	}
+}
+
+func TestHealth_Drain(t *testing.T) {
+	h := NewHealth()
+	if got := h.Ready(context.Background()); got.Status != entity.HealthUp {
+		t.Fatalf("Health.Ready() | got status %s before draining, want %s", got.Status, entity.HealthUp)
+	}
+
+	h.Drain()
+	got := h.Ready(context.Background())
+	if got.Status != entity.HealthDown || got.Checks[0].Error != HealthShuttingDownErr.Error() {
+		t.Errorf("Health.Ready() | got %+v while draining, want the shutdown check down", got)
+	}
+	if live := h.Live(context.Background()); live.Status != entity.HealthUp {
+		t.Errorf("Health.Live() | got status %s while draining, want %s", live.Status, entity.HealthUp)
+	}
+}
+
+func TestHealth_Timeout(t *testing.T) {
+	h := NewHealth()
+	h.AddReadiness("hung", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if got := h.Ready(ctx); got.Status != entity.HealthDown || got.Checks[1].Error != context.DeadlineExceeded.Error() {
+		t.Errorf("Health.Ready() | got %+v, want the hung check down", got)
+	}
+}
+
+func TestHealthFresh(t *testing.T) {
+	last := time.Now()
+	check := HealthFresh(func() time.Time { return last }, time.Minute)
+
+	if err := check(context.Background()); err != nil {
+		t.Errorf("HealthFresh() | got error %v for a fresh run, want nil", err)
+	}
+	last = time.Now().Add(-2 * time.Minute)
+	if err := check(context.Background()); err == nil {
+		t.Error("HealthFresh() | got nil for a stale run, want an error")
+	}
+}
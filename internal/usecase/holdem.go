@@ -0,0 +1,599 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+var (
+	// HoldemTableNotFoundErr happens when a Hold'em table can't be found in the repo.
+	HoldemTableNotFoundErr = errors.New("holdem table not found")
+	// HoldemPlayerNotFoundErr happens when a player token doesn't match any seat.
+	HoldemPlayerNotFoundErr = errors.New("holdem player not found")
+	// HoldemIllegalActionErr happens when an action is not allowed in the current table state.
+	HoldemIllegalActionErr = errors.New("illegal holdem action")
+	// HoldemInvalidOptionsErr happens when a table or seat is created with invalid options.
+	HoldemInvalidOptionsErr = errors.New("invalid holdem options")
+)
+
+// Hold'em player actions.
+const (
+	HoldemFold  = "fold"
+	HoldemCheck = "check"
+	HoldemCall  = "call"
+	HoldemBet   = "bet"
+	HoldemRaise = "raise"
+	HoldemAllIn = "allin"
+)
+
+const maxHoldemSeats = 9
+
+// Holdem is a use case to run no-limit Texas Hold'em tables.
+type Holdem struct {
+	deck      DeckManager
+	tableRepo HoldemRepo
+}
+
+// NewHoldemManager creates a new Holdem.
+func NewHoldemManager(deck DeckManager, store HoldemRepo) *Holdem {
+	return &Holdem{
+		deck:      deck,
+		tableRepo: store,
+	}
+}
+
+// NewTable creates an empty table with the given blinds.
+func (h *Holdem) NewTable(ctx context.Context, smallBlind, bigBlind int) (entity.HoldemTable, error) {
+	if smallBlind <= 0 || bigBlind < smallBlind {
+		return entity.HoldemTable{}, fmt.Errorf("%w: blinds must be positive and the big blind at least the small blind", HoldemInvalidOptionsErr)
+	}
+
+	table := entity.HoldemTable{
+		ID:         uuid.New().String(),
+		SmallBlind: smallBlind,
+		BigBlind:   bigBlind,
+		Button:     -1,
+		ToAct:      -1,
+		Street:     entity.HoldemStreetWaiting,
+		Owner:      gameOwner(ctx),
+		Tenant:     deckTenant(ctx),
+	}
+
+	h.tableRepo.Save(table)
+
+	return table, nil
+}
+
+// Table returns a table or an error in case the
+// table can't be found.
+func (h *Holdem) Table(ctx context.Context, id string) (entity.HoldemTable, error) {
+	return h.table(ctx, id)
+}
+
+// Sit seats a player with the given buy-in. The returned
+// token identifies the player in further actions.
+func (h *Holdem) Sit(ctx context.Context, id, playerID string, buyIn int) (entity.HoldemTable, string, error) {
+	if playerID == "" || buyIn <= 0 {
+		return entity.HoldemTable{}, "", fmt.Errorf("%w: a player id and a positive buy-in are required", HoldemInvalidOptionsErr)
+	}
+
+	seat := entity.HoldemSeat{
+		PlayerID: playerID,
+		Token:    uuid.New().String(),
+		Stack:    buyIn,
+	}
+	table, err := h.update(ctx, id, func(table *entity.HoldemTable) error {
+		if len(table.Seats) >= maxHoldemSeats {
+			return fmt.Errorf("%w: table is full", HoldemIllegalActionErr)
+		}
+		for _, s := range table.Seats {
+			if s.PlayerID == playerID {
+				return fmt.Errorf("%w: player %s is already seated", HoldemIllegalActionErr, playerID)
+			}
+		}
+
+		table.Seats = append(table.Seats, seat)
+		return nil
+	})
+	if err != nil {
+		return entity.HoldemTable{}, "", err
+	}
+
+	return table, seat.Token, nil
+}
+
+// StartHand moves the button, opens a new deck for the
+// table, posts the blinds and deals the hole cards.
+func (h *Holdem) StartHand(ctx context.Context, id string) (entity.HoldemTable, error) {
+	return h.update(ctx, id, func(table *entity.HoldemTable) error {
+		if table.Street != entity.HoldemStreetWaiting {
+			return fmt.Errorf("%w: hand already in progress", HoldemIllegalActionErr)
+		}
+
+		players := 0
+		for i := range table.Seats {
+			s := &table.Seats[i]
+			*s = entity.HoldemSeat{
+				PlayerID: s.PlayerID,
+				Token:    s.Token,
+				Stack:    s.Stack,
+				InHand:   s.Stack > 0,
+			}
+			if s.InHand {
+				players++
+			}
+		}
+		if players < 2 {
+			return fmt.Errorf("%w: at least two players with chips are needed", HoldemIllegalActionErr)
+		}
+
+		// The deck of the last hand makes way for the new one,
+		// so it doesn't count against the decks the tenant may
+		// keep.
+		if table.DeckID != "" {
+			if err := h.deck.Delete(ctx, table.DeckID); err != nil && !errors.Is(err, DeckNotFoundErr) {
+				return err
+			}
+		}
+		deck, err := h.deck.New(ctx, true, defaultCardCodes(1))
+		if err != nil {
+			return err
+		}
+		table.DeckID = deck.ID
+		table.HandNumber++
+		table.Board = nil
+		table.Burned = nil
+		table.Winners = nil
+		table.Showdown = false
+		table.Button = nextInHand(*table, table.Button)
+
+		// Heads-up the button posts the small blind.
+		sb := nextInHand(*table, table.Button)
+		if players == 2 {
+			sb = table.Button
+		}
+		bb := nextInHand(*table, sb)
+		payChips(&table.Seats[sb], table.SmallBlind)
+		payChips(&table.Seats[bb], table.BigBlind)
+		table.CurrentBet = table.BigBlind
+		table.MinRaise = table.BigBlind
+
+		for round := 0; round < 2; round++ {
+			seat := table.Button
+			for i := 0; i < players; i++ {
+				seat = nextInHand(*table, seat)
+				card, err := h.draw(ctx, table.DeckID)
+				if err != nil {
+					return err
+				}
+				table.Seats[seat].Hole = append(table.Seats[seat].Hole, card)
+			}
+		}
+
+		table.Street = entity.HoldemStreetPreflop
+		table.ToAct = bb
+		return h.progress(ctx, table)
+	})
+}
+
+// Act plays an action for the player owning the token.
+// For bets and raises the amount is the total the player
+// is raising to in the current betting round.
+func (h *Holdem) Act(ctx context.Context, id, token, action string, amount int) (entity.HoldemTable, error) {
+	return h.update(ctx, id, func(table *entity.HoldemTable) error {
+		idx := -1
+		for i, s := range table.Seats {
+			if s.Token == token {
+				idx = i
+			}
+		}
+		if idx < 0 {
+			return HoldemPlayerNotFoundErr
+		}
+
+		if table.Street == entity.HoldemStreetWaiting {
+			return fmt.Errorf("%w: no hand in progress", HoldemIllegalActionErr)
+		}
+		if idx != table.ToAct {
+			return fmt.Errorf("%w: it is not %s's turn", HoldemIllegalActionErr, table.Seats[idx].PlayerID)
+		}
+
+		seat := &table.Seats[idx]
+		switch action {
+		case HoldemFold:
+			seat.Folded = true
+		case HoldemCheck:
+			if seat.Bet != table.CurrentBet {
+				return fmt.Errorf("%w: can't check facing a bet of %d", HoldemIllegalActionErr, table.CurrentBet)
+			}
+		case HoldemCall:
+			payChips(seat, table.CurrentBet-seat.Bet)
+		case HoldemBet, HoldemRaise, HoldemAllIn:
+			if action == HoldemAllIn {
+				amount = seat.Bet + seat.Stack
+			}
+			if err := raiseTo(table, idx, amount); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: unknown action %q", HoldemIllegalActionErr, action)
+		}
+		seat.Acted = true
+
+		return h.progress(ctx, table)
+	})
+}
+
+// raiseTo puts the seat's bet for the round at amount.
+// An all-in for less than a call is treated as a call, and
+// one for less than a full raise raises the bet without
+// reopening the betting to the seats that acted.
+func raiseTo(table *entity.HoldemTable, idx, amount int) error {
+	seat := &table.Seats[idx]
+	allIn := amount == seat.Bet+seat.Stack
+
+	if amount > seat.Bet+seat.Stack {
+		return fmt.Errorf("%w: can't bet more than the stack", HoldemIllegalActionErr)
+	}
+	if amount <= table.CurrentBet {
+		if !allIn {
+			return fmt.Errorf("%w: raise must be over the current bet of %d", HoldemIllegalActionErr, table.CurrentBet)
+		}
+		payChips(seat, amount-seat.Bet)
+		return nil
+	}
+	raise := amount - table.CurrentBet
+	full := raise >= table.MinRaise
+	if !full && !allIn {
+		return fmt.Errorf("%w: minimum raise is to %d", HoldemIllegalActionErr, table.CurrentBet+table.MinRaise)
+	}
+	// A seat that acted is only asked again when an all-in
+	// short of a full raise went over its bet, which doesn't
+	// reopen the betting: it can call or fold.
+	if seat.Acted {
+		return fmt.Errorf("%w: an all-in short of a full raise doesn't reopen the betting", HoldemIllegalActionErr)
+	}
+
+	table.CurrentBet = amount
+	payChips(seat, amount-seat.Bet)
+	if !full {
+		return nil
+	}
+
+	// Everyone else has to respond to a full raise.
+	table.MinRaise = raise
+	for i := range table.Seats {
+		if i != idx {
+			table.Seats[i].Acted = false
+		}
+	}
+
+	return nil
+}
+
+// progress moves the hand forward after an action: to the
+// next player, the next street or the end of the hand.
+func (h *Holdem) progress(ctx context.Context, table *entity.HoldemTable) error {
+	table.Pots = holdemPots(table.Seats)
+
+	live := 0
+	for _, s := range table.Seats {
+		if s.InHand && !s.Folded {
+			live++
+		}
+	}
+	if live == 1 {
+		awardUncontested(table)
+		return nil
+	}
+
+	if !roundComplete(*table) {
+		table.ToAct = nextToAct(*table, table.ToAct)
+		return nil
+	}
+
+	for i := range table.Seats {
+		table.Seats[i].Bet = 0
+		table.Seats[i].Acted = false
+	}
+	table.CurrentBet = 0
+	table.MinRaise = table.BigBlind
+
+	canAct := 0
+	for _, s := range table.Seats {
+		if s.InHand && !s.Folded && !s.AllIn {
+			canAct++
+		}
+	}
+
+	// With at most one player left to act there is no more
+	// betting, so the board is run out.
+	if canAct <= 1 || table.Street == entity.HoldemStreetRiver {
+		for table.Street != entity.HoldemStreetRiver {
+			if err := h.dealStreet(ctx, table); err != nil {
+				return err
+			}
+		}
+		h.showdown(table)
+		return nil
+	}
+
+	if err := h.dealStreet(ctx, table); err != nil {
+		return err
+	}
+	table.ToAct = nextToAct(*table, table.Button)
+
+	return nil
+}
+
+// dealStreet burns a card and deals the board cards of
+// the next street.
+func (h *Holdem) dealStreet(ctx context.Context, table *entity.HoldemTable) error {
+	next, cards := entity.HoldemStreetFlop, 3
+	switch table.Street {
+	case entity.HoldemStreetFlop:
+		next, cards = entity.HoldemStreetTurn, 1
+	case entity.HoldemStreetTurn:
+		next, cards = entity.HoldemStreetRiver, 1
+	}
+
+	burn, err := h.draw(ctx, table.DeckID)
+	if err != nil {
+		return err
+	}
+	table.Burned = append(table.Burned, burn)
+
+	for i := 0; i < cards; i++ {
+		card, err := h.draw(ctx, table.DeckID)
+		if err != nil {
+			return err
+		}
+		table.Board = append(table.Board, card)
+	}
+	table.Street = next
+
+	return nil
+}
+
+// showdown pays every pot to the best hands among
+// its eligible players.
+func (h *Holdem) showdown(table *entity.HoldemTable) {
+	hands := map[string]PokerHand{}
+	for _, s := range table.Seats {
+		if s.InHand && !s.Folded {
+			hands[s.PlayerID] = EvaluatePokerHand(append(append([]entity.Card{}, s.Hole...), table.Board...))
+		}
+	}
+
+	won := map[string]int{}
+	for _, pot := range holdemPots(table.Seats) {
+		best := -1
+		var winners []string
+		for _, p := range pot.Eligible {
+			switch score := hands[p].Score; {
+			case score > best:
+				best = score
+				winners = []string{p}
+			case score == best:
+				winners = append(winners, p)
+			}
+		}
+
+		// Odd chips go to the first winners left of the button.
+		winners = orderFromButton(*table, winners)
+		share, odd := pot.Amount/len(winners), pot.Amount%len(winners)
+		for i, p := range winners {
+			won[p] += share
+			if i < odd {
+				won[p]++
+			}
+		}
+	}
+
+	table.Showdown = true
+	for i := range table.Seats {
+		s := &table.Seats[i]
+		if amount, ok := won[s.PlayerID]; ok {
+			s.Stack += amount
+			table.Winners = append(table.Winners, entity.HoldemWinner{
+				PlayerID: s.PlayerID,
+				Amount:   amount,
+				Hand:     hands[s.PlayerID].Name,
+			})
+		}
+	}
+	endHand(table)
+}
+
+// awardUncontested pays everything to the only
+// player who didn't fold.
+func awardUncontested(table *entity.HoldemTable) {
+	total := 0
+	winner := -1
+	for i, s := range table.Seats {
+		total += s.Committed
+		if s.InHand && !s.Folded {
+			winner = i
+		}
+	}
+
+	table.Seats[winner].Stack += total
+	table.Winners = []entity.HoldemWinner{{
+		PlayerID: table.Seats[winner].PlayerID,
+		Amount:   total,
+	}}
+	endHand(table)
+}
+
+func endHand(table *entity.HoldemTable) {
+	for i := range table.Seats {
+		table.Seats[i].Bet = 0
+	}
+	table.Pots = nil
+	table.CurrentBet = 0
+	table.ToAct = -1
+	table.Street = entity.HoldemStreetWaiting
+}
+
+// holdemPots splits the chips committed in the hand into a
+// main pot and side pots, one per all-in level.
+func holdemPots(seats []entity.HoldemSeat) []entity.HoldemPot {
+	var levels []int
+	seen := map[int]bool{}
+	for _, s := range seats {
+		if s.InHand && !s.Folded && s.Committed > 0 && !seen[s.Committed] {
+			seen[s.Committed] = true
+			levels = append(levels, s.Committed)
+		}
+	}
+	sort.Ints(levels)
+
+	var pots []entity.HoldemPot
+	prev := 0
+	for i, level := range levels {
+		// Chips over the highest live level belong to the last pot.
+		top := level
+		if i == len(levels)-1 {
+			top = int(^uint(0) >> 1)
+		}
+
+		pot := entity.HoldemPot{}
+		for _, s := range seats {
+			if s.Committed > prev {
+				c := s.Committed
+				if c > top {
+					c = top
+				}
+				pot.Amount += c - prev
+			}
+			if s.InHand && !s.Folded && s.Committed >= level {
+				pot.Eligible = append(pot.Eligible, s.PlayerID)
+			}
+		}
+		if pot.Amount > 0 {
+			pots = append(pots, pot)
+		}
+		prev = level
+	}
+
+	return pots
+}
+
+func roundComplete(table entity.HoldemTable) bool {
+	for _, s := range table.Seats {
+		if !s.InHand || s.Folded || s.AllIn {
+			continue
+		}
+		if !s.Acted || s.Bet != table.CurrentBet {
+			return false
+		}
+	}
+	return true
+}
+
+func nextInHand(table entity.HoldemTable, from int) int {
+	n := len(table.Seats)
+	for i := 1; i <= n; i++ {
+		idx := ((from+i)%n + n) % n
+		if table.Seats[idx].InHand {
+			return idx
+		}
+	}
+	return -1
+}
+
+func nextToAct(table entity.HoldemTable, from int) int {
+	n := len(table.Seats)
+	for i := 1; i <= n; i++ {
+		idx := (from + i) % n
+		s := table.Seats[idx]
+		if s.InHand && !s.Folded && !s.AllIn {
+			return idx
+		}
+	}
+	return -1
+}
+
+func orderFromButton(table entity.HoldemTable, players []string) []string {
+	var ordered []string
+	seat := table.Button
+	for i := 0; i < len(table.Seats); i++ {
+		seat = (seat + 1) % len(table.Seats)
+		for _, p := range players {
+			if table.Seats[seat].PlayerID == p {
+				ordered = append(ordered, p)
+			}
+		}
+	}
+	return ordered
+}
+
+// payChips moves chips from the stack to the bet,
+// going all-in when the stack can't cover it.
+func payChips(seat *entity.HoldemSeat, amount int) {
+	if amount > seat.Stack {
+		amount = seat.Stack
+	}
+	seat.Stack -= amount
+	seat.Bet += amount
+	seat.Committed += amount
+	if seat.Stack == 0 {
+		seat.AllIn = true
+	}
+}
+
+func (h *Holdem) draw(ctx context.Context, deckID string) (entity.Card, error) {
+	cards, err := h.deck.DrawCards(ctx, deckID, 1)
+	if err != nil {
+		return entity.Card{}, err
+	}
+	if len(cards) == 0 {
+		return entity.Card{}, fmt.Errorf("deck %s has no cards", deckID)
+	}
+	return cards[0], nil
+}
+
+// table returns a table of the tenant of ctx, once the
+// caller is found to own it.
+func (h *Holdem) table(ctx context.Context, id string) (entity.HoldemTable, error) {
+	table, err := h.tableRepo.Get(deckTenant(ctx), id)
+	if err != nil {
+		if errors.Is(err, repo.HoldemTableNotFoundErr) {
+			return entity.HoldemTable{}, fmt.Errorf("%w with id %s", HoldemTableNotFoundErr, id)
+		}
+		return entity.HoldemTable{}, err
+	}
+	if err := checkGameOwner(ctx, "holdem table", id, table.Owner); err != nil {
+		return entity.HoldemTable{}, err
+	}
+
+	return table, nil
+}
+
+// update runs fn on a table of the tenant of ctx, once the
+// caller is found to own it, and saves the table when fn
+// succeeds. Other changes to the table wait meanwhile.
+func (h *Holdem) update(ctx context.Context, id string, fn func(table *entity.HoldemTable) error) (entity.HoldemTable, error) {
+	table, err := h.tableRepo.Update(deckTenant(ctx), id, func(table *entity.HoldemTable) error {
+		if err := checkGameOwner(ctx, "holdem table", id, table.Owner); err != nil {
+			return err
+		}
+
+		// Seats are changed in place, so they can't share
+		// memory with what is in the store.
+		table.Seats = append([]entity.HoldemSeat(nil), table.Seats...)
+
+		return fn(table)
+	})
+	if errors.Is(err, repo.HoldemTableNotFoundErr) {
+		return entity.HoldemTable{}, fmt.Errorf("%w with id %s", HoldemTableNotFoundErr, id)
+	}
+	return table, err
+}
@@ -0,0 +1,307 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+func newTestHoldem(t *testing.T, stacks map[string]int, order []string, codes ...string) (*Holdem, string, map[string]string) {
+	t.Helper()
+	h := NewHoldemManager(&stubShoe{codes: codes}, repo.NewHoldemTable())
+	table, err := h.NewTable(context.Background(), 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens := map[string]string{}
+	for _, p := range order {
+		_, token, err := h.Sit(context.Background(), table.ID, p, stacks[p])
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens[p] = token
+	}
+
+	return h, table.ID, tokens
+}
+
+func stacksOf(table entity.HoldemTable) map[string]int {
+	stacks := map[string]int{}
+	for _, s := range table.Seats {
+		stacks[s.PlayerID] = s.Stack
+	}
+	return stacks
+}
+
+func TestHoldem_FoldPreflop(t *testing.T) {
+	h, id, tokens := newTestHoldem(t, map[string]int{"a": 100, "b": 100}, []string{"a", "b"},
+		"2S", "3S", "4S", "5S")
+
+	table, err := h.StartHand(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Heads-up the button posts the small blind and acts first.
+	if table.Button != 0 || table.ToAct != 0 {
+		t.Fatalf("Holdem.StartHand() | got button %d and to act %d, want 0 and 0", table.Button, table.ToAct)
+	}
+	if diff := cmp.Diff(table.Seats[1].Hole, cardsByCodes("2S 4S")); diff != "" {
+		t.Errorf("Holdem.StartHand() | big blind hole (-got +want):\n%s", diff)
+	}
+
+	table, err = h.Act(context.Background(), id, tokens["a"], HoldemFold, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if table.Street != entity.HoldemStreetWaiting {
+		t.Errorf("Holdem.Act() | got street %s, want %s", table.Street, entity.HoldemStreetWaiting)
+	}
+	if diff := cmp.Diff(stacksOf(table), map[string]int{"a": 99, "b": 101}); diff != "" {
+		t.Errorf("Holdem.Act() | stacks (-got +want):\n%s", diff)
+	}
+}
+
+func TestHoldem_SidePots(t *testing.T) {
+	// Hole cards go b, c, a twice, then burn and board cards.
+	h, id, tokens := newTestHoldem(t, map[string]int{"a": 50, "b": 100, "c": 100}, []string{"a", "b", "c"},
+		"KS", "2S", "AS", "KD", "7D", "AD",
+		"3C", "9H", "5C", "JD",
+		"6C", "3H",
+		"10C", "8S")
+
+	if _, err := h.StartHand(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+
+	actions := []struct {
+		player string
+		action string
+		amount int
+	}{
+		{"a", HoldemAllIn, 0},
+		{"b", HoldemCall, 0},
+		{"c", HoldemRaise, 100},
+		{"b", HoldemCall, 0},
+	}
+	var table entity.HoldemTable
+	var err error
+	for _, a := range actions {
+		if table, err = h.Act(context.Background(), id, tokens[a.player], a.action, a.amount); err != nil {
+			t.Fatalf("Holdem.Act(%s, %s) | got error %v, want nil", a.player, a.action, err)
+		}
+	}
+
+	if !table.Showdown || len(table.Board) != 5 || len(table.Burned) != 3 {
+		t.Fatalf("Holdem.Act() | got showdown %v with %d board and %d burned cards", table.Showdown, len(table.Board), len(table.Burned))
+	}
+	if diff := cmp.Diff(stacksOf(table), map[string]int{"a": 150, "b": 100, "c": 0}); diff != "" {
+		t.Errorf("Holdem.Act() | stacks (-got +want):\n%s", diff)
+	}
+	want := []entity.HoldemWinner{
+		{PlayerID: "a", Amount: 150, Hand: "PAIR"},
+		{PlayerID: "b", Amount: 100, Hand: "PAIR"},
+	}
+	if diff := cmp.Diff(table.Winners, want); diff != "" {
+		t.Errorf("Holdem.Act() | winners (-got +want):\n%s", diff)
+	}
+}
+
+func TestHoldem_ShortAllIn(t *testing.T) {
+	h, id, tokens := newTestHoldem(t, map[string]int{"a": 100, "b": 100, "c": 15}, []string{"a", "b", "c"},
+		defaultCardCodes(1)...)
+	if _, err := h.StartHand(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+
+	// a raises by 8 and c's all-in raises by 5 more, short
+	// of a full raise.
+	for _, a := range []struct {
+		player string
+		action string
+		amount int
+	}{
+		{"a", HoldemRaise, 10},
+		{"b", HoldemCall, 0},
+		{"c", HoldemAllIn, 0},
+	} {
+		if _, err := h.Act(context.Background(), id, tokens[a.player], a.action, a.amount); err != nil {
+			t.Fatalf("Holdem.Act(%s, %s) | got error %v, want nil", a.player, a.action, err)
+		}
+	}
+
+	// The betting isn't reopened: a can only call or fold.
+	if _, err := h.Act(context.Background(), id, tokens["a"], HoldemRaise, 30); !errors.Is(err, HoldemIllegalActionErr) {
+		t.Errorf("Holdem.Act() | got error %v raising after a short all-in, want %v", err, HoldemIllegalActionErr)
+	}
+	table, err := h.Act(context.Background(), id, tokens["a"], HoldemCall, 0)
+	if err != nil {
+		t.Fatalf("Holdem.Act() | got error %v calling, want nil", err)
+	}
+	if table.CurrentBet != 15 || table.MinRaise != 8 {
+		t.Errorf("Holdem.Act() | got bet %d and min raise %d, want 15 and 8", table.CurrentBet, table.MinRaise)
+	}
+	if table, err = h.Act(context.Background(), id, tokens["b"], HoldemCall, 0); err != nil {
+		t.Fatalf("Holdem.Act() | got error %v calling, want nil", err)
+	}
+	if table.Street != entity.HoldemStreetFlop {
+		t.Errorf("Holdem.Act() | got street %s, want %s", table.Street, entity.HoldemStreetFlop)
+	}
+}
+
+func TestHoldem_IllegalActions(t *testing.T) {
+	h, id, tokens := newTestHoldem(t, map[string]int{"a": 100, "b": 100, "c": 100}, []string{"a", "b", "c"},
+		defaultCardCodes(1)...)
+
+	if _, err := h.Act(context.Background(), id, tokens["a"], HoldemCheck, 0); !errors.Is(err, HoldemIllegalActionErr) {
+		t.Errorf("Holdem.Act() | got error %v, want %v", err, HoldemIllegalActionErr)
+	}
+	if _, err := h.StartHand(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		action  string
+		amount  int
+		wantErr error
+	}{
+		{name: "Unknown Token", token: "bad", action: HoldemFold, wantErr: HoldemPlayerNotFoundErr},
+		{name: "Out Of Turn", token: tokens["b"], action: HoldemFold, wantErr: HoldemIllegalActionErr},
+		{name: "Check Facing Bet", token: tokens["a"], action: HoldemCheck, wantErr: HoldemIllegalActionErr},
+		{name: "Raise Too Small", token: tokens["a"], action: HoldemRaise, amount: 3, wantErr: HoldemIllegalActionErr},
+		{name: "Raise Over Stack", token: tokens["a"], action: HoldemRaise, amount: 101, wantErr: HoldemIllegalActionErr},
+		{name: "Unknown Action", token: tokens["a"], action: "dance", wantErr: HoldemIllegalActionErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := h.Act(context.Background(), id, tt.token, tt.action, tt.amount); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Holdem.Act() | got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHoldem_ChipsAreConserved(t *testing.T) {
+	h, id, tokens := newTestHoldem(t, map[string]int{"a": 100, "b": 100, "c": 100}, []string{"a", "b", "c"},
+		append(defaultCardCodes(1), defaultCardCodes(1)...)...)
+
+	for hand := 0; hand < 2; hand++ {
+		table, err := h.StartHand(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for table.Street != entity.HoldemStreetWaiting {
+			player := table.Seats[table.ToAct].PlayerID
+			action := HoldemCall
+			if table.Seats[table.ToAct].Bet == table.CurrentBet {
+				action = HoldemCheck
+			}
+			if table, err = h.Act(context.Background(), id, tokens[player], action, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		total := 0
+		for _, s := range table.Seats {
+			total += s.Stack
+		}
+		if total != 300 {
+			t.Fatalf("Holdem | got %d chips after hand %d, want 300", total, hand+1)
+		}
+	}
+}
+
+func TestHoldem_StartHand_DeletesLastDeck(t *testing.T) {
+	shoe := &stubShoe{codes: append(defaultCardCodes(1), defaultCardCodes(1)...)}
+	h := NewHoldemManager(shoe, repo.NewHoldemTable())
+	table, err := h.NewTable(context.Background(), 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := map[string]string{}
+	for _, p := range []string{"a", "b"} {
+		if _, tokens[p], err = h.Sit(context.Background(), table.ID, p, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for hand := 0; hand < 2; hand++ {
+		if table, err = h.StartHand(context.Background(), table.ID); err != nil {
+			t.Fatal(err)
+		}
+		if table, err = h.Act(context.Background(), table.ID, tokens[table.Seats[table.ToAct].PlayerID], HoldemFold, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if diff := cmp.Diff(shoe.deleted, []string{"shoe"}); diff != "" {
+		t.Errorf("Holdem.StartHand() | deleted decks (-got +want):\n%s", diff)
+	}
+}
+
+func Test_holdemPots(t *testing.T) {
+	seats := []entity.HoldemSeat{
+		{PlayerID: "a", Committed: 20, InHand: true, AllIn: true},
+		{PlayerID: "b", Committed: 50, InHand: true},
+		{PlayerID: "c", Committed: 50, InHand: true},
+		{PlayerID: "d", Committed: 30, InHand: true, Folded: true},
+	}
+
+	want := []entity.HoldemPot{
+		{Amount: 80, Eligible: []string{"a", "b", "c"}},
+		{Amount: 70, Eligible: []string{"b", "c"}},
+	}
+	if diff := cmp.Diff(holdemPots(seats), want); diff != "" {
+		t.Errorf("holdemPots() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestHoldem_Sit_Concurrent(t *testing.T) {
+	h := NewHoldemManager(&stubShoe{}, repo.NewHoldemTable())
+	table, err := h.NewTable(context.Background(), 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Players sitting at once all get a seat, and one too
+	// many is turned away.
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		full int
+	)
+	for i := 0; i <= maxHoldemSeats; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _, err := h.Sit(context.Background(), table.ID, fmt.Sprintf("p%d", i), 100)
+			if errors.Is(err, HoldemIllegalActionErr) {
+				mu.Lock()
+				full++
+				mu.Unlock()
+			} else if err != nil {
+				t.Errorf("Holdem.Sit() | got error %v, want nil", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	got, err := h.Table(context.Background(), table.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Seats) != maxHoldemSeats || full != 1 {
+		t.Errorf("Holdem.Sit() | got %d seats and %d turned away, want %d and 1", len(got.Seats), full, maxHoldemSeats)
+	}
+}
@@ -1,16 +1,243 @@
 package usecase
 
-import "github.com/lualfe/card-game/internal/entity"
+import (
+	"context"
+	"crypto"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
 
 // DeckManager is the interface for deck operations.
 type DeckManager interface {
-	New(shuffle bool, cardCodes []string) entity.Deck
-	Open(id string) (entity.Deck, error)
-	DrawCards(id string, amount int) ([]entity.Card, error)
+	New(ctx context.Context, shuffle bool, cardCodes []string) (entity.Deck, error)
+	Open(ctx context.Context, id string) (entity.Deck, error)
+	DrawCards(ctx context.Context, id string, amount int) ([]entity.Card, error)
+	Deal(ctx context.Context, id string, players []string, amount int) (entity.Deck, map[string]string, error)
+	Return(ctx context.Context, id string, cardCodes []string) (entity.Deck, error)
+	Events(ctx context.Context, id string) ([]entity.DeckEvent, error)
+	At(ctx context.Context, id string, version int) (entity.Deck, error)
+	Delete(ctx context.Context, id string) error
 }
 
-// DeckRepo is the interface for the deck store.
+// DeckRepo is the interface for the deck store. Decks are
+// kept apart by tenant: a deck is only found, counted,
+// changed or removed within the tenant it was saved with,
+// so one tenant can't reach the decks of another. All,
+// for snapshots and expiry, returns the decks of every
+// tenant. Every call gives up with the context error once
+// ctx is done. Save and Update write the deck and the
+// events leading to it at once, so the events kept are
+// always those of the deck state kept. Update may run fn
+// more than once when a concurrent change to the deck
+// makes the store retry it.
 type DeckRepo interface {
-	Save(deck entity.Deck)
-	Get(id string) (entity.Deck, error)
+	Save(ctx context.Context, deck entity.Deck, events ...entity.DeckEvent) error
+	Get(ctx context.Context, tenant, id string) (entity.Deck, error)
+	Update(ctx context.Context, tenant, id string, fn func(deck *entity.Deck) ([]entity.DeckEvent, error)) (entity.Deck, error)
+	All(ctx context.Context) ([]entity.Deck, error)
+	Count(ctx context.Context, tenant string) (int, error)
+	Delete(ctx context.Context, tenant, id string) error
+}
+
+// DeckEventRepo is the interface for the deck event store.
+// The events of the operations are written by the deck
+// store; Append is for filling the store from a snapshot.
+type DeckEventRepo interface {
+	Append(ctx context.Context, event entity.DeckEvent) error
+	Events(ctx context.Context, deckID string) ([]entity.DeckEvent, error)
+	All(ctx context.Context) ([]entity.DeckEvent, error)
+}
+
+// DeckSnapshotManager is the interface for deck store snapshots.
+type DeckSnapshotManager interface {
+	Take(ctx context.Context) (entity.DeckSnapshot, error)
+}
+
+// DeckSnapshotRepo is the interface for where the deck
+// store snapshot is kept.
+type DeckSnapshotRepo interface {
+	Write(snapshot entity.DeckSnapshot) error
+	Read() (entity.DeckSnapshot, error)
+}
+
+// DeckWALRepo is the interface for the write-ahead log
+// under the deck store, which snapshots compact.
+type DeckWALRepo interface {
+	Rotate() (int, error)
+	Compact(segment int) error
+}
+
+// APIKeyManager is the interface for API key operations.
+type APIKeyManager interface {
+	Authenticate(ctx context.Context, key string) (entity.Principal, error)
+	Create(ctx context.Context, name string, admin bool, tenant string) (entity.APIKey, string, error)
+	Keys(ctx context.Context) ([]entity.APIKey, error)
+	Revoke(ctx context.Context, name string) error
+}
+
+// APIKeyRepo is the interface for the API key store. Keys
+// are found by the hash of their secret and removed by
+// name, which Save refuses to reuse.
+type APIKeyRepo interface {
+	Save(ctx context.Context, key entity.APIKey) error
+	Get(ctx context.Context, hash string) (entity.APIKey, error)
+	All(ctx context.Context) ([]entity.APIKey, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// TenantManager is the interface for tenant operations.
+type TenantManager interface {
+	Create(ctx context.Context, name string, limits entity.TenantLimits) (entity.Tenant, error)
+	Tenant(ctx context.Context, name string) (entity.Tenant, error)
+	Tenants(ctx context.Context) ([]entity.Tenant, error)
+	Usage(ctx context.Context, name string) (entity.TenantUsage, error)
+	Allow(tenant entity.Tenant) (time.Duration, bool)
+}
+
+// TenantRepo is the interface for the tenant store, which
+// Save refuses to save a tenant twice to.
+type TenantRepo interface {
+	Save(ctx context.Context, tenant entity.Tenant) error
+	Get(ctx context.Context, name string) (entity.Tenant, error)
+	All(ctx context.Context) ([]entity.Tenant, error)
+}
+
+// RateLimiter is the interface for holding callers to the
+// rate limits of the budgets.
+type RateLimiter interface {
+	Take(ctx context.Context, budget RateLimitBudget, key string) entity.RateLimitStatus
+}
+
+// RateLimitRepo is the interface for the token buckets of
+// the rate limits. Take takes a request out of the bucket
+// of key at now, if it holds one; a bucket never seen
+// before starts full. Limits must have a positive rate
+// and burst.
+type RateLimitRepo interface {
+	Take(ctx context.Context, key string, limit entity.RateLimit, now time.Time) (entity.RateLimitStatus, error)
+}
+
+// TokenManager is the interface for bearer token operations.
+type TokenManager interface {
+	Authenticate(ctx context.Context, token string) (entity.Principal, error)
+}
+
+// TokenKeyRepo is the interface for the public keys bearer
+// tokens are signed with, found by key ID.
+type TokenKeyRepo interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// HealthManager is the interface for probing the application.
+type HealthManager interface {
+	Live(ctx context.Context) entity.HealthReport
+	Ready(ctx context.Context) entity.HealthReport
+}
+
+// BlackjackManager is the interface for blackjack table operations.
+type BlackjackManager interface {
+	NewTable(ctx context.Context, opts BlackjackOptions) (entity.BlackjackTable, error)
+	Table(ctx context.Context, id string) (entity.BlackjackTable, error)
+	Deal(ctx context.Context, id string, bet int) (entity.BlackjackTable, error)
+	Hit(ctx context.Context, id string) (entity.BlackjackTable, error)
+	Stand(ctx context.Context, id string) (entity.BlackjackTable, error)
+	Double(ctx context.Context, id string) (entity.BlackjackTable, error)
+	Split(ctx context.Context, id string) (entity.BlackjackTable, error)
+	Surrender(ctx context.Context, id string) (entity.BlackjackTable, error)
+}
+
+// BlackjackRepo is the interface for the blackjack table store.
+// Update runs fn on a table and saves it when fn succeeds,
+// one update of a table at a time.
+type BlackjackRepo interface {
+	Save(table entity.BlackjackTable)
+	Get(tenant, id string) (entity.BlackjackTable, error)
+	Update(tenant, id string, fn func(table *entity.BlackjackTable) error) (entity.BlackjackTable, error)
+}
+
+// HoldemManager is the interface for Hold'em table operations.
+type HoldemManager interface {
+	NewTable(ctx context.Context, smallBlind, bigBlind int) (entity.HoldemTable, error)
+	Table(ctx context.Context, id string) (entity.HoldemTable, error)
+	Sit(ctx context.Context, id, playerID string, buyIn int) (entity.HoldemTable, string, error)
+	StartHand(ctx context.Context, id string) (entity.HoldemTable, error)
+	Act(ctx context.Context, id, token, action string, amount int) (entity.HoldemTable, error)
+}
+
+// HoldemRepo is the interface for the Hold'em table store.
+// Update runs fn on a table and saves it when fn succeeds,
+// one update of a table at a time.
+type HoldemRepo interface {
+	Save(table entity.HoldemTable)
+	Get(tenant, id string) (entity.HoldemTable, error)
+	Update(tenant, id string, fn func(table *entity.HoldemTable) error) (entity.HoldemTable, error)
+}
+
+// CardGame is the lifecycle shared by the casual card games.
+// Each game keeps its own rules behind it.
+type CardGame interface {
+	Players() (min, max int)
+	Deal(ctx context.Context, game *entity.CasualGame) error
+	LegalMoves(game entity.CasualGame, player string) []entity.CasualMove
+	Play(ctx context.Context, game *entity.CasualGame, move entity.CasualMove) error
+	BotMove(game entity.CasualGame, player string) (entity.CasualMove, bool)
+}
+
+// CasualGameManager is the interface for casual card game operations.
+type CasualGameManager interface {
+	New(ctx context.Context, kind string, players []string) (entity.CasualGame, error)
+	Game(ctx context.Context, id string) (entity.CasualGame, error)
+	LegalMoves(ctx context.Context, id, token string) ([]entity.CasualMove, error)
+	Play(ctx context.Context, id, token string, move entity.CasualMove) (entity.CasualGame, error)
+	PlayBot(ctx context.Context, id string) (entity.CasualGame, error)
+}
+
+// CasualGameRepo is the interface for the casual card game store.
+// Update runs fn on a game and saves it when fn succeeds,
+// one update of a game at a time.
+type CasualGameRepo interface {
+	Save(game entity.CasualGame)
+	Get(tenant, id string) (entity.CasualGame, error)
+	Update(tenant, id string, fn func(game *entity.CasualGame) error) (entity.CasualGame, error)
+}
+
+// KlondikeManager is the interface for Klondike solitaire operations.
+type KlondikeManager interface {
+	New(ctx context.Context, seed int64, drawCount int) (entity.KlondikeGame, error)
+	Game(ctx context.Context, id string) (entity.KlondikeGame, error)
+	Move(ctx context.Context, id string, move entity.KlondikeMove) (entity.KlondikeGame, error)
+	Undo(ctx context.Context, id string) (entity.KlondikeGame, error)
+	Solve(seed int64, drawCount int) (KlondikeSolution, error)
+	DailySeed(ctx context.Context, day time.Time, drawCount int) (KlondikeSolution, error)
+}
+
+// KlondikeRepo is the interface for the Klondike game store.
+// Update runs fn on a game and saves it when fn succeeds,
+// one update of a game at a time.
+type KlondikeRepo interface {
+	Save(game entity.KlondikeGame)
+	Get(tenant, id string) (entity.KlondikeGame, error)
+	Update(tenant, id string, fn func(game *entity.KlondikeGame) error) (entity.KlondikeGame, error)
+}
+
+// BridgeManager is the interface for bridge dealing operations.
+type BridgeManager interface {
+	NewDeal(ctx context.Context, board int, constraints []BridgeConstraint) (entity.BridgeDeal, error)
+	Deal(ctx context.Context, id string) (entity.BridgeDeal, error)
+	ExportPBN(ctx context.Context, id string) (string, error)
+	ImportPBN(ctx context.Context, pbn string) ([]entity.BridgeDeal, error)
+}
+
+// BridgeRepo is the interface for the bridge deal store.
+type BridgeRepo interface {
+	Save(deal entity.BridgeDeal)
+	Get(tenant, id string) (entity.BridgeDeal, error)
+}
+
+// AuditSink is the interface for where audit records are written.
+type AuditSink interface {
+	Append(record entity.AuditRecord) error
+	Last(deckID string) (entity.AuditRecord, bool, error)
+	Records() ([]entity.AuditRecord, error)
 }
@@ -0,0 +1,539 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+var (
+	// KlondikeGameNotFoundErr happens when a Klondike game can't be found in the repo.
+	KlondikeGameNotFoundErr = errors.New("klondike game not found")
+	// KlondikeIllegalMoveErr happens when a move breaks the Klondike rules.
+	KlondikeIllegalMoveErr = errors.New("illegal klondike move")
+	// KlondikeInvalidOptionsErr happens when a game is created with invalid options.
+	KlondikeInvalidOptionsErr = errors.New("invalid klondike options")
+	// KlondikeNoDailyDealErr happens when no winnable deal is found for a day.
+	KlondikeNoDailyDealErr = errors.New("no winnable daily deal found")
+	// KlondikeSolverBusyErr happens when every solver slot is taken.
+	KlondikeSolverBusyErr = errors.New("klondike solver busy")
+)
+
+const (
+	klondikeColumns = 7
+	// klondikeDailyTries is how many seeds are tried
+	// for a day before giving up.
+	klondikeDailyTries = 50
+	// klondikeDailyCached bounds how many daily deals are
+	// kept. Past it, one of them is dropped and solved
+	// again when it's asked for.
+	klondikeDailyCached = 64
+)
+
+// klondikeSuits is the order of the foundations.
+var klondikeSuits = []string{"SPADES", "DIAMONDS", "CLUBS", "HEARTS"}
+
+// Klondike is a use case to play Klondike solitaire.
+type Klondike struct {
+	deck     DeckManager
+	gameRepo KlondikeRepo
+	budget   KlondikeBudget
+	solver   func(state entity.KlondikeState, drawCount int, budget KlondikeBudget) KlondikeSolution
+
+	// solving holds a slot for each solver running, one per
+	// CPU, as solves are bound by them.
+	solving chan struct{}
+
+	mu    sync.Mutex
+	daily map[klondikeDay]*klondikeDailyCall
+}
+
+// klondikeDay is a daily deal: the day, as YYYY-MM-DD,
+// and the draw count.
+type klondikeDay struct {
+	date      string
+	drawCount int
+}
+
+// klondikeDailyCall is the search for a daily deal. Calls
+// asking for the same deal while it runs wait for it.
+type klondikeDailyCall struct {
+	done     chan struct{}
+	solution KlondikeSolution
+	err      error
+}
+
+// NewKlondikeManager creates a new Klondike. The budget
+// bounds every solver run.
+func NewKlondikeManager(deck DeckManager, store KlondikeRepo, budget KlondikeBudget) *Klondike {
+	return &Klondike{
+		deck:     deck,
+		gameRepo: store,
+		budget:   budget,
+		solver:   solveKlondike,
+		solving:  make(chan struct{}, runtime.GOMAXPROCS(0)),
+		daily:    make(map[klondikeDay]*klondikeDailyCall),
+	}
+}
+
+// New deals a game from the given seed. The same seed
+// and draw count always deal the same game.
+func (k *Klondike) New(ctx context.Context, seed int64, drawCount int) (entity.KlondikeGame, error) {
+	if drawCount != 1 && drawCount != 3 {
+		return entity.KlondikeGame{}, fmt.Errorf("%w: draw count must be 1 or 3", KlondikeInvalidOptionsErr)
+	}
+
+	codes := klondikeCodes(seed)
+	deck, err := k.deck.New(ctx, false, codes)
+	if err != nil {
+		return entity.KlondikeGame{}, err
+	}
+
+	// The game keeps the cards, so the deck is deleted once
+	// drained rather than left counting against the decks
+	// the tenant may keep. Its history is still kept.
+	cards, err := k.deck.DrawCards(ctx, deck.ID, len(codes))
+	if delErr := k.deck.Delete(ctx, deck.ID); delErr != nil && err == nil {
+		err = delErr
+	}
+	if err != nil {
+		return entity.KlondikeGame{}, err
+	}
+	if len(cards) != len(codes) {
+		return entity.KlondikeGame{}, fmt.Errorf("deck %s has %d cards, want %d", deck.ID, len(cards), len(codes))
+	}
+
+	game := entity.KlondikeGame{
+		ID:            uuid.New().String(),
+		DeckID:        deck.ID,
+		Seed:          seed,
+		DrawCount:     drawCount,
+		KlondikeState: dealKlondike(cards),
+		Owner:         gameOwner(ctx),
+		Tenant:        deckTenant(ctx),
+	}
+
+	k.gameRepo.Save(game)
+
+	return game, nil
+}
+
+// Game returns a game of the tenant of ctx owned by the
+// caller, or an error in case the game can't be found.
+func (k *Klondike) Game(ctx context.Context, id string) (entity.KlondikeGame, error) {
+	game, err := k.gameRepo.Get(deckTenant(ctx), id)
+	if err != nil {
+		if errors.Is(err, repo.KlondikeGameNotFoundErr) {
+			return entity.KlondikeGame{}, fmt.Errorf("%w with id %s", KlondikeGameNotFoundErr, id)
+		}
+		return entity.KlondikeGame{}, err
+	}
+	if err := checkGameOwner(ctx, "klondike game", id, game.Owner); err != nil {
+		return entity.KlondikeGame{}, err
+	}
+
+	return game, nil
+}
+
+// Move plays a move, keeping the previous state to undo it.
+func (k *Klondike) Move(ctx context.Context, id string, move entity.KlondikeMove) (entity.KlondikeGame, error) {
+	return k.update(ctx, id, func(game *entity.KlondikeGame) error {
+		if game.Won {
+			return fmt.Errorf("%w: game is already won", KlondikeIllegalMoveErr)
+		}
+
+		next, err := applyKlondikeMove(game.KlondikeState, game.DrawCount, move)
+		if err != nil {
+			return err
+		}
+
+		game.History = append(append([]entity.KlondikeState{}, game.History...), game.KlondikeState)
+		game.KlondikeState = next
+		game.Won = klondikeWon(next)
+		return nil
+	})
+}
+
+// Undo takes back the last move.
+func (k *Klondike) Undo(ctx context.Context, id string) (entity.KlondikeGame, error) {
+	return k.update(ctx, id, func(game *entity.KlondikeGame) error {
+		if len(game.History) == 0 {
+			return fmt.Errorf("%w: nothing to undo", KlondikeIllegalMoveErr)
+		}
+
+		game.KlondikeState = game.History[len(game.History)-1]
+		game.History = game.History[:len(game.History)-1]
+		game.Won = false
+		return nil
+	})
+}
+
+// update runs fn on a game of the tenant of ctx, once the
+// caller is found to own it, and saves the game when fn
+// succeeds. Other changes to the game wait meanwhile.
+func (k *Klondike) update(ctx context.Context, id string, fn func(game *entity.KlondikeGame) error) (entity.KlondikeGame, error) {
+	game, err := k.gameRepo.Update(deckTenant(ctx), id, func(game *entity.KlondikeGame) error {
+		if err := checkGameOwner(ctx, "klondike game", id, game.Owner); err != nil {
+			return err
+		}
+		return fn(game)
+	})
+	if errors.Is(err, repo.KlondikeGameNotFoundErr) {
+		return entity.KlondikeGame{}, fmt.Errorf("%w with id %s", KlondikeGameNotFoundErr, id)
+	}
+	return game, err
+}
+
+// Solve tells whether the deal of a seed can be won
+// within the solver budget. It fails right away when every
+// solver slot is taken.
+func (k *Klondike) Solve(seed int64, drawCount int) (KlondikeSolution, error) {
+	if drawCount != 1 && drawCount != 3 {
+		return KlondikeSolution{}, fmt.Errorf("%w: draw count must be 1 or 3", KlondikeInvalidOptionsErr)
+	}
+
+	if !k.acquireSolver() {
+		return KlondikeSolution{}, KlondikeSolverBusyErr
+	}
+	defer k.releaseSolver()
+
+	return k.solve(seed, drawCount, k.budget), nil
+}
+
+func (k *Klondike) solve(seed int64, drawCount int, budget KlondikeBudget) KlondikeSolution {
+	var cards []entity.Card
+	for _, code := range klondikeCodes(seed) {
+		for _, c := range entity.DefaultCards {
+			if c.Code == code {
+				cards = append(cards, c)
+			}
+		}
+	}
+
+	solution := k.solver(dealKlondike(cards), drawCount, budget)
+	solution.Seed = seed
+	solution.DrawCount = drawCount
+
+	return solution
+}
+
+func (k *Klondike) acquireSolver() bool {
+	select {
+	case k.solving <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (k *Klondike) releaseSolver() {
+	<-k.solving
+}
+
+// DailySeed finds the first seed of the day that the
+// solver proves winnable. Each deal is searched once,
+// however many calls ask for it meanwhile, and kept once
+// found. The search takes a single solver slot, and stops
+// when ctx is done.
+func (k *Klondike) DailySeed(ctx context.Context, day time.Time, drawCount int) (KlondikeSolution, error) {
+	if drawCount != 1 && drawCount != 3 {
+		return KlondikeSolution{}, fmt.Errorf("%w: draw count must be 1 or 3", KlondikeInvalidOptionsErr)
+	}
+	key := klondikeDay{date: day.Format("2006-01-02"), drawCount: drawCount}
+
+	for {
+		k.mu.Lock()
+		call, ok := k.daily[key]
+		if ok {
+			k.mu.Unlock()
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				return KlondikeSolution{}, ctx.Err()
+			}
+			// A search stopped by the context of the call that
+			// started it is started again by the others.
+			if errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded) {
+				continue
+			}
+			return call.solution, call.err
+		}
+		if len(k.daily) >= klondikeDailyCached {
+			for d, c := range k.daily {
+				select {
+				case <-c.done:
+					delete(k.daily, d)
+				default:
+					// Still searched, with calls waiting on it.
+					continue
+				}
+				break
+			}
+		}
+		call = &klondikeDailyCall{done: make(chan struct{})}
+		k.daily[key] = call
+		k.mu.Unlock()
+
+		call.solution, call.err = k.dailySeed(ctx, day, drawCount)
+		if call.err != nil {
+			// Failures aren't kept: a later search may be given
+			// a free solver, or more time.
+			k.mu.Lock()
+			delete(k.daily, key)
+			k.mu.Unlock()
+		}
+		close(call.done)
+
+		return call.solution, call.err
+	}
+}
+
+// dailySeed tries the seeds of the day in turn, each solve
+// ending by the deadline of ctx at the latest.
+func (k *Klondike) dailySeed(ctx context.Context, day time.Time, drawCount int) (KlondikeSolution, error) {
+	if !k.acquireSolver() {
+		return KlondikeSolution{}, KlondikeSolverBusyErr
+	}
+	defer k.releaseSolver()
+
+	base := int64(day.Year()*10000+int(day.Month())*100+day.Day()) * 1000
+	for i := int64(0); i < klondikeDailyTries; i++ {
+		if err := ctx.Err(); err != nil {
+			return KlondikeSolution{}, err
+		}
+		budget := k.budget
+		if deadline, ok := ctx.Deadline(); ok {
+			left := time.Until(deadline)
+			if left <= 0 {
+				return KlondikeSolution{}, context.DeadlineExceeded
+			}
+			if budget.Timeout <= 0 || left < budget.Timeout {
+				budget.Timeout = left
+			}
+		}
+
+		if solution := k.solve(base+i, drawCount, budget); solution.Result == KlondikeWinnable {
+			return solution, nil
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return KlondikeSolution{}, err
+	}
+
+	return KlondikeSolution{}, fmt.Errorf("%w for %s", KlondikeNoDailyDealErr, day.Format("2006-01-02"))
+}
+
+// klondikeCodes returns the card codes in the order
+// given by the seed.
+func klondikeCodes(seed int64) []string {
+	codes := defaultCardCodes(1)
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(codes), func(i, j int) {
+		codes[i], codes[j] = codes[j], codes[i]
+	})
+	return codes
+}
+
+// dealKlondike lays out the tableau row by row, with the
+// last card of each pile face up, and leaves the rest of
+// the cards in the stock.
+func dealKlondike(cards []entity.Card) entity.KlondikeState {
+	state := entity.KlondikeState{
+		Foundations: make([][]entity.Card, len(klondikeSuits)),
+		Tableau:     make([]entity.KlondikeStack, klondikeColumns),
+	}
+
+	n := 0
+	for row := 0; row < klondikeColumns; row++ {
+		for col := row; col < klondikeColumns; col++ {
+			state.Tableau[col].Cards = append(state.Tableau[col].Cards, cards[n])
+			n++
+		}
+	}
+	for i := range state.Tableau {
+		state.Tableau[i].FaceDown = len(state.Tableau[i].Cards) - 1
+	}
+
+	// The next card of the deck is the top of the stock.
+	for i := len(cards) - 1; i >= n; i-- {
+		state.Stock = append(state.Stock, cards[i])
+	}
+
+	return state
+}
+
+// applyKlondikeMove returns the state after the move,
+// leaving the given state untouched.
+func applyKlondikeMove(state entity.KlondikeState, drawCount int, move entity.KlondikeMove) (entity.KlondikeState, error) {
+	s := cloneKlondikeState(state)
+
+	switch move.Action {
+	case entity.KlondikeActionDraw:
+		if len(s.Stock) == 0 {
+			if len(s.Waste) == 0 {
+				return entity.KlondikeState{}, fmt.Errorf("%w: stock and waste are empty", KlondikeIllegalMoveErr)
+			}
+			for i := len(s.Waste) - 1; i >= 0; i-- {
+				s.Stock = append(s.Stock, s.Waste[i])
+			}
+			s.Waste = nil
+			break
+		}
+		for i := 0; i < drawCount && len(s.Stock) > 0; i++ {
+			s.Waste = append(s.Waste, s.Stock[len(s.Stock)-1])
+			s.Stock = s.Stock[:len(s.Stock)-1]
+		}
+	case entity.KlondikeActionMove:
+		if err := moveKlondikeCards(&s, move); err != nil {
+			return entity.KlondikeState{}, err
+		}
+	default:
+		return entity.KlondikeState{}, fmt.Errorf("%w: unknown action %q", KlondikeIllegalMoveErr, move.Action)
+	}
+	s.Moves++
+
+	return s, nil
+}
+
+func moveKlondikeCards(s *entity.KlondikeState, move entity.KlondikeMove) error {
+	count := move.Count
+	if count == 0 {
+		count = 1
+	}
+
+	var cards []entity.Card
+	switch move.From {
+	case entity.KlondikePileWaste:
+		if len(s.Waste) == 0 || count != 1 {
+			return fmt.Errorf("%w: only the top waste card can move", KlondikeIllegalMoveErr)
+		}
+		cards = s.Waste[len(s.Waste)-1:]
+	case entity.KlondikePileFoundation:
+		if move.FromIndex < 0 || move.FromIndex >= len(s.Foundations) || len(s.Foundations[move.FromIndex]) == 0 || count != 1 {
+			return fmt.Errorf("%w: only the top foundation card can move", KlondikeIllegalMoveErr)
+		}
+		f := s.Foundations[move.FromIndex]
+		cards = f[len(f)-1:]
+	case entity.KlondikePileTableau:
+		if move.FromIndex < 0 || move.FromIndex >= len(s.Tableau) {
+			return fmt.Errorf("%w: unknown tableau pile %d", KlondikeIllegalMoveErr, move.FromIndex)
+		}
+		t := s.Tableau[move.FromIndex]
+		if count > len(t.Cards)-t.FaceDown {
+			return fmt.Errorf("%w: only face up cards can move", KlondikeIllegalMoveErr)
+		}
+		cards = t.Cards[len(t.Cards)-count:]
+	default:
+		return fmt.Errorf("%w: can't move from %q", KlondikeIllegalMoveErr, move.From)
+	}
+	cards = append([]entity.Card{}, cards...)
+
+	switch move.To {
+	case entity.KlondikePileFoundation:
+		if len(cards) != 1 {
+			return fmt.Errorf("%w: one card at a time goes to a foundation", KlondikeIllegalMoveErr)
+		}
+		idx := klondikeSuitIndex(cards[0])
+		if move.From == entity.KlondikePileFoundation || klondikeRank(cards[0]) != len(s.Foundations[idx])+1 {
+			return fmt.Errorf("%w: %s doesn't go to its foundation now", KlondikeIllegalMoveErr, cards[0].Code)
+		}
+		s.Foundations[idx] = append(s.Foundations[idx], cards[0])
+	case entity.KlondikePileTableau:
+		if move.ToIndex < 0 || move.ToIndex >= len(s.Tableau) || (move.From == entity.KlondikePileTableau && move.ToIndex == move.FromIndex) {
+			return fmt.Errorf("%w: unknown tableau pile %d", KlondikeIllegalMoveErr, move.ToIndex)
+		}
+		if !klondikeBuilds(s.Tableau[move.ToIndex], cards[0]) {
+			return fmt.Errorf("%w: %s can't go on tableau pile %d", KlondikeIllegalMoveErr, cards[0].Code, move.ToIndex)
+		}
+		s.Tableau[move.ToIndex].Cards = append(s.Tableau[move.ToIndex].Cards, cards...)
+	default:
+		return fmt.Errorf("%w: can't move to %q", KlondikeIllegalMoveErr, move.To)
+	}
+
+	switch move.From {
+	case entity.KlondikePileWaste:
+		s.Waste = s.Waste[:len(s.Waste)-1]
+	case entity.KlondikePileFoundation:
+		s.Foundations[move.FromIndex] = s.Foundations[move.FromIndex][:len(s.Foundations[move.FromIndex])-1]
+	case entity.KlondikePileTableau:
+		t := &s.Tableau[move.FromIndex]
+		t.Cards = t.Cards[:len(t.Cards)-count]
+		// The card left on top is turned up.
+		if t.FaceDown >= len(t.Cards) && t.FaceDown > 0 {
+			t.FaceDown = len(t.Cards) - 1
+		}
+	}
+
+	return nil
+}
+
+// klondikeBuilds tells whether the card can be put on the
+// pile: a king on an empty pile, or one rank lower and of
+// the other color than the top card.
+func klondikeBuilds(pile entity.KlondikeStack, card entity.Card) bool {
+	if len(pile.Cards) == 0 {
+		return klondikeRank(card) == 13
+	}
+	top := pile.Cards[len(pile.Cards)-1]
+	return klondikeRank(card) == klondikeRank(top)-1 && isRedCard(card) != isRedCard(top)
+}
+
+func klondikeWon(s entity.KlondikeState) bool {
+	for _, f := range s.Foundations {
+		if len(f) != 13 {
+			return false
+		}
+	}
+	return true
+}
+
+func cloneKlondikeState(s entity.KlondikeState) entity.KlondikeState {
+	c := entity.KlondikeState{
+		Stock:       append([]entity.Card(nil), s.Stock...),
+		Waste:       append([]entity.Card(nil), s.Waste...),
+		Foundations: make([][]entity.Card, len(s.Foundations)),
+		Tableau:     make([]entity.KlondikeStack, len(s.Tableau)),
+		Moves:       s.Moves,
+	}
+	for i, f := range s.Foundations {
+		c.Foundations[i] = append([]entity.Card(nil), f...)
+	}
+	for i, t := range s.Tableau {
+		c.Tableau[i] = entity.KlondikeStack{
+			Cards:    append([]entity.Card(nil), t.Cards...),
+			FaceDown: t.FaceDown,
+		}
+	}
+	return c
+}
+
+// klondikeRank returns the rank of a card from 1 to 13,
+// with aces low.
+func klondikeRank(c entity.Card) int {
+	if r := pokerRank(c); r != 14 {
+		return r
+	}
+	return 1
+}
+
+func klondikeSuitIndex(c entity.Card) int {
+	for i, s := range klondikeSuits {
+		if s == c.Suit {
+			return i
+		}
+	}
+	return 0
+}
+
+func isRedCard(c entity.Card) bool {
+	return c.Suit == "DIAMONDS" || c.Suit == "HEARTS"
+}
@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+const (
+	// KlondikeWinnable means the solver found a winning line.
+	KlondikeWinnable = "WINNABLE"
+	// KlondikeUnwinnable means the solver tried every line and none wins.
+	KlondikeUnwinnable = "UNWINNABLE"
+	// KlondikeUnknown means the solver ran out of budget.
+	KlondikeUnknown = "UNKNOWN"
+)
+
+// KlondikeBudget bounds a solver run.
+type KlondikeBudget struct {
+	MaxStates int
+	Timeout   time.Duration
+}
+
+// KlondikeSolution is the outcome of a solver run. Moves
+// is the winning line when the deal is winnable.
+type KlondikeSolution struct {
+	Seed      int64                 `json:"seed"`
+	DrawCount int                   `json:"draw_count"`
+	Result    string                `json:"result"`
+	Moves     []entity.KlondikeMove `json:"moves,omitempty"`
+	States    int                   `json:"states"`
+}
+
+type klondikeSolver struct {
+	drawCount int
+	maxStates int
+	deadline  time.Time
+	seen      map[string]bool
+	path      []entity.KlondikeMove
+	aborted   bool
+}
+
+// solveKlondike searches depth first for a winning line,
+// skipping layouts it has already seen.
+func solveKlondike(state entity.KlondikeState, drawCount int, budget KlondikeBudget) KlondikeSolution {
+	s := &klondikeSolver{
+		drawCount: drawCount,
+		maxStates: budget.MaxStates,
+		seen:      make(map[string]bool),
+	}
+	if budget.Timeout > 0 {
+		s.deadline = time.Now().Add(budget.Timeout)
+	}
+
+	solution := KlondikeSolution{Result: KlondikeUnwinnable}
+	switch {
+	case s.search(state):
+		solution.Result = KlondikeWinnable
+		solution.Moves = s.path
+	case s.aborted:
+		solution.Result = KlondikeUnknown
+	}
+	solution.States = len(s.seen)
+
+	return solution
+}
+
+func (s *klondikeSolver) search(state entity.KlondikeState) bool {
+	if klondikeWon(state) {
+		return true
+	}
+	if s.maxStates > 0 && len(s.seen) >= s.maxStates {
+		s.aborted = true
+		return false
+	}
+	// Checking the clock on every state is too slow.
+	if !s.deadline.IsZero() && len(s.seen)%1024 == 0 && time.Now().After(s.deadline) {
+		s.aborted = true
+		return false
+	}
+
+	key := klondikeKey(state)
+	if s.seen[key] {
+		return false
+	}
+	s.seen[key] = true
+
+	for _, move := range klondikeCandidates(state) {
+		next, err := applyKlondikeMove(state, s.drawCount, move)
+		if err != nil {
+			continue
+		}
+		s.path = append(s.path, move)
+		if s.search(next) {
+			return true
+		}
+		s.path = s.path[:len(s.path)-1]
+		if s.aborted {
+			return false
+		}
+	}
+
+	return false
+}
+
+// klondikeCandidates lists the moves worth trying, the most
+// promising first. A safe foundation move is played alone:
+// no card of the other color can need it anymore.
+func klondikeCandidates(state entity.KlondikeState) []entity.KlondikeMove {
+	var moves []entity.KlondikeMove
+
+	// Cards to the foundations.
+	if len(state.Waste) > 0 {
+		card := state.Waste[len(state.Waste)-1]
+		if klondikeToFoundation(state, card) {
+			move := entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileWaste, To: entity.KlondikePileFoundation}
+			if klondikeSafe(state, card) {
+				return []entity.KlondikeMove{move}
+			}
+			moves = append(moves, move)
+		}
+	}
+	for i, t := range state.Tableau {
+		if len(t.Cards) == 0 {
+			continue
+		}
+		card := t.Cards[len(t.Cards)-1]
+		if klondikeToFoundation(state, card) {
+			move := entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileTableau, FromIndex: i, To: entity.KlondikePileFoundation}
+			if klondikeSafe(state, card) {
+				return []entity.KlondikeMove{move}
+			}
+			moves = append(moves, move)
+		}
+	}
+
+	// Whole face up runs between tableau piles, the ones
+	// turning a card up first.
+	var reveals, shifts []entity.KlondikeMove
+	for i, t := range state.Tableau {
+		up := len(t.Cards) - t.FaceDown
+		if up == 0 {
+			continue
+		}
+		for j, dst := range state.Tableau {
+			if i == j || !klondikeBuilds(dst, t.Cards[t.FaceDown]) {
+				continue
+			}
+			// A king already at the bottom has nowhere better to be.
+			if t.FaceDown == 0 && len(dst.Cards) == 0 {
+				continue
+			}
+			move := entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileTableau, FromIndex: i, To: entity.KlondikePileTableau, ToIndex: j, Count: up}
+			if t.FaceDown > 0 {
+				reveals = append(reveals, move)
+			} else {
+				shifts = append(shifts, move)
+			}
+		}
+	}
+	moves = append(moves, reveals...)
+
+	if len(state.Waste) > 0 {
+		card := state.Waste[len(state.Waste)-1]
+		for j, dst := range state.Tableau {
+			if klondikeBuilds(dst, card) {
+				moves = append(moves, entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileWaste, To: entity.KlondikePileTableau, ToIndex: j})
+			}
+		}
+	}
+	moves = append(moves, shifts...)
+
+	// Part of a run only moves when it frees a card
+	// for the foundations.
+	for i, t := range state.Tableau {
+		for count := 1; count < len(t.Cards)-t.FaceDown; count++ {
+			under := t.Cards[len(t.Cards)-count-1]
+			if !klondikeToFoundation(state, under) {
+				continue
+			}
+			for j, dst := range state.Tableau {
+				if i != j && klondikeBuilds(dst, t.Cards[len(t.Cards)-count]) {
+					moves = append(moves, entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileTableau, FromIndex: i, To: entity.KlondikePileTableau, ToIndex: j, Count: count})
+				}
+			}
+		}
+	}
+
+	for i, f := range state.Foundations {
+		if len(f) == 0 {
+			continue
+		}
+		for j, dst := range state.Tableau {
+			if klondikeBuilds(dst, f[len(f)-1]) {
+				moves = append(moves, entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileFoundation, FromIndex: i, To: entity.KlondikePileTableau, ToIndex: j})
+			}
+		}
+	}
+
+	if len(state.Stock) > 0 || len(state.Waste) > 0 {
+		moves = append(moves, entity.KlondikeMove{Action: entity.KlondikeActionDraw})
+	}
+
+	return moves
+}
+
+func klondikeToFoundation(state entity.KlondikeState, card entity.Card) bool {
+	return klondikeRank(card) == len(state.Foundations[klondikeSuitIndex(card)])+1
+}
+
+// klondikeSafe tells whether both cards of the other color
+// that could be built on the card are already home.
+func klondikeSafe(state entity.KlondikeState, card entity.Card) bool {
+	rank := klondikeRank(card)
+	if rank <= 2 {
+		return true
+	}
+	for i, suit := range klondikeSuits {
+		if isRedCard(entity.Card{Suit: suit}) != isRedCard(card) && len(state.Foundations[i]) < rank-1 {
+			return false
+		}
+	}
+	return true
+}
+
+// klondikeKey identifies a layout. Tableau piles are sorted
+// since swapping two of them changes nothing.
+func klondikeKey(state entity.KlondikeState) string {
+	var b strings.Builder
+	for _, c := range state.Stock {
+		b.WriteString(c.Code)
+	}
+	b.WriteByte('|')
+	for _, c := range state.Waste {
+		b.WriteString(c.Code)
+	}
+	b.WriteByte('|')
+	for _, f := range state.Foundations {
+		b.WriteString(strconv.Itoa(len(f)))
+		b.WriteByte(',')
+	}
+
+	piles := make([]string, len(state.Tableau))
+	for i, t := range state.Tableau {
+		var p strings.Builder
+		p.WriteString(strconv.Itoa(t.FaceDown))
+		for _, c := range t.Cards {
+			p.WriteString(c.Code)
+		}
+		piles[i] = p.String()
+	}
+	sort.Strings(piles)
+	b.WriteByte('|')
+	b.WriteString(strings.Join(piles, "/"))
+
+	return b.String()
+}
@@ -0,0 +1,418 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+// klondikeFoundations returns foundations holding every
+// card of each suit up to the given rank.
+func klondikeFoundations(ranks ...int) [][]entity.Card {
+	values := []string{"A", "2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K"}
+	foundations := make([][]entity.Card, len(klondikeSuits))
+	for i, suit := range klondikeSuits {
+		for r := 0; r < ranks[i]; r++ {
+			foundations[i] = append(foundations[i], cardByCode(values[r]+suit[:1]))
+		}
+	}
+	return foundations
+}
+
+func TestKlondike_New(t *testing.T) {
+	codes := klondikeCodes(42)
+	shoe := &stubShoe{codes: codes}
+	k := NewKlondikeManager(shoe, repo.NewKlondikeGame(), KlondikeBudget{})
+
+	game, err := k.New(context.Background(), 42, 3)
+	if err != nil {
+		t.Fatalf("Klondike.New() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(shoe.deleted, []string{game.DeckID}); diff != "" {
+		t.Errorf("Klondike.New() | deleted decks (-got +want):\n%s", diff)
+	}
+
+	for i, pile := range game.Tableau {
+		if len(pile.Cards) != i+1 || pile.FaceDown != i {
+			t.Errorf("Klondike.New() | pile %d has %d cards and %d face down", i, len(pile.Cards), pile.FaceDown)
+		}
+	}
+	if len(game.Stock) != 24 || game.Stock[len(game.Stock)-1].Code != codes[28] {
+		t.Errorf("Klondike.New() | got stock %v, want 24 cards with %s on top", game.Stock, codes[28])
+	}
+
+	if diff := cmp.Diff(klondikeCodes(42), codes); diff != "" {
+		t.Errorf("klondikeCodes() | same seed dealt another order (-got +want):\n%s", diff)
+	}
+
+	if _, err := k.New(context.Background(), 42, 2); !errors.Is(err, KlondikeInvalidOptionsErr) {
+		t.Errorf("Klondike.New() | got error %v, want %v", err, KlondikeInvalidOptionsErr)
+	}
+}
+
+func TestKlondike_MoveUndo(t *testing.T) {
+	store := repo.NewKlondikeGame()
+	k := NewKlondikeManager(&stubShoe{}, store, KlondikeBudget{})
+
+	start := entity.KlondikeState{
+		Stock:       cardsByCodes("2S"),
+		Waste:       cardsByCodes("AS"),
+		Foundations: make([][]entity.Card, 4),
+		Tableau: []entity.KlondikeStack{
+			{Cards: cardsByCodes("3D 2C"), FaceDown: 1},
+			{},
+		},
+	}
+	store.Save(entity.KlondikeGame{ID: "id", DrawCount: 1, KlondikeState: start})
+
+	game, err := k.Move(context.Background(), "id", entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileWaste, To: entity.KlondikePileFoundation})
+	if err != nil {
+		t.Fatalf("Klondike.Move() | got error %v, want nil", err)
+	}
+	if len(game.Foundations[0]) != 1 || len(game.Waste) != 0 || game.Moves != 1 {
+		t.Errorf("Klondike.Move() | got foundations %v and waste %v", game.Foundations, game.Waste)
+	}
+
+	_, err = k.Move(context.Background(), "id", entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileTableau, To: entity.KlondikePileTableau, ToIndex: 1})
+	if !errors.Is(err, KlondikeIllegalMoveErr) {
+		t.Errorf("Klondike.Move() | got error %v, want %v", err, KlondikeIllegalMoveErr)
+	}
+
+	game, err = k.Undo(context.Background(), "id")
+	if err != nil {
+		t.Fatalf("Klondike.Undo() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(game.KlondikeState, start); diff != "" {
+		t.Errorf("Klondike.Undo() | (-got +want):\n%s", diff)
+	}
+
+	if _, err := k.Undo(context.Background(), "id"); !errors.Is(err, KlondikeIllegalMoveErr) {
+		t.Errorf("Klondike.Undo() | got error %v, want %v", err, KlondikeIllegalMoveErr)
+	}
+	if _, err := k.Undo(context.Background(), "other"); !errors.Is(err, KlondikeGameNotFoundErr) {
+		t.Errorf("Klondike.Undo() | got error %v, want %v", err, KlondikeGameNotFoundErr)
+	}
+}
+
+func TestApplyKlondikeMove(t *testing.T) {
+	state := entity.KlondikeState{
+		Stock:       cardsByCodes("4C 3C 2C"),
+		Waste:       cardsByCodes("KH"),
+		Foundations: klondikeFoundations(1, 0, 0, 0),
+		Tableau: []entity.KlondikeStack{
+			{Cards: cardsByCodes("9C 8D 7S"), FaceDown: 1},
+			{Cards: cardsByCodes("9S"), FaceDown: 0},
+			{},
+			{Cards: cardsByCodes("8C"), FaceDown: 0},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		move    entity.KlondikeMove
+		check   func(s entity.KlondikeState) bool
+		wantErr error
+	}{
+		{
+			name: "Draw",
+			move: entity.KlondikeMove{Action: entity.KlondikeActionDraw},
+			check: func(s entity.KlondikeState) bool {
+				return len(s.Stock) == 0 && s.Waste[len(s.Waste)-1].Code == "4C"
+			},
+		},
+		{
+			name: "Run Turns Card Up",
+			move: entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileTableau, FromIndex: 0, To: entity.KlondikePileTableau, ToIndex: 1, Count: 2},
+			check: func(s entity.KlondikeState) bool {
+				return len(s.Tableau[1].Cards) == 3 && s.Tableau[0].FaceDown == 0
+			},
+		},
+		{
+			name: "King To Empty Pile",
+			move: entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileWaste, To: entity.KlondikePileTableau, ToIndex: 2},
+			check: func(s entity.KlondikeState) bool {
+				return len(s.Waste) == 0 && s.Tableau[2].Cards[0].Code == "KH"
+			},
+		},
+		{
+			name:    "Face Down Card",
+			move:    entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileTableau, FromIndex: 0, To: entity.KlondikePileTableau, ToIndex: 2, Count: 3},
+			wantErr: KlondikeIllegalMoveErr,
+		},
+		{
+			name:    "Same Color",
+			move:    entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileTableau, FromIndex: 0, To: entity.KlondikePileTableau, ToIndex: 3},
+			wantErr: KlondikeIllegalMoveErr,
+		},
+		{
+			name:    "Foundation Out Of Order",
+			move:    entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileTableau, FromIndex: 0, To: entity.KlondikePileFoundation},
+			wantErr: KlondikeIllegalMoveErr,
+		},
+		{
+			name:    "Unknown Pile",
+			move:    entity.KlondikeMove{Action: entity.KlondikeActionMove, From: entity.KlondikePileTableau, FromIndex: 7, To: entity.KlondikePileFoundation},
+			wantErr: KlondikeIllegalMoveErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyKlondikeMove(state, 3, tt.move)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("applyKlondikeMove() | got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.check != nil && !tt.check(got) {
+				t.Errorf("applyKlondikeMove() | got state %+v", got)
+			}
+		})
+	}
+
+	if len(state.Stock) != 3 || len(state.Tableau[1].Cards) != 1 {
+		t.Errorf("applyKlondikeMove() | changed the given state")
+	}
+}
+
+func TestSolveKlondike(t *testing.T) {
+	budget := KlondikeBudget{MaxStates: 100000, Timeout: 10 * time.Second}
+
+	tests := []struct {
+		name   string
+		state  entity.KlondikeState
+		budget KlondikeBudget
+		want   string
+	}{
+		{
+			name: "Near Win",
+			state: entity.KlondikeState{
+				Stock:       cardsByCodes("KD"),
+				Foundations: klondikeFoundations(12, 12, 13, 11),
+				Tableau: []entity.KlondikeStack{
+					{Cards: cardsByCodes("KH KS QH"), FaceDown: 2},
+				},
+			},
+			budget: budget,
+			want:   KlondikeWinnable,
+		},
+		{
+			name: "Ace Buried Under Two",
+			state: entity.KlondikeState{
+				Stock:       cardsByCodes("KS QS JS 10S 9S 8S 7S 6S 5S 4S 3S"),
+				Foundations: klondikeFoundations(0, 13, 13, 13),
+				Tableau: []entity.KlondikeStack{
+					{Cards: cardsByCodes("AS 2S"), FaceDown: 1},
+					{},
+				},
+			},
+			budget: budget,
+			want:   KlondikeUnwinnable,
+		},
+		{
+			name:   "Out Of Budget",
+			state:  dealKlondike(cardsByCodes(strings.Join(klondikeCodes(9), " "))),
+			budget: KlondikeBudget{MaxStates: 10},
+			want:   KlondikeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := solveKlondike(tt.state, 1, tt.budget)
+			if got.Result != tt.want {
+				t.Fatalf("solveKlondike() | got %s, want %s", got.Result, tt.want)
+			}
+
+			state := tt.state
+			for _, move := range got.Moves {
+				next, err := applyKlondikeMove(state, 1, move)
+				if err != nil {
+					t.Fatalf("solveKlondike() | move %+v: %v", move, err)
+				}
+				state = next
+			}
+			if won := klondikeWon(state); won != (tt.want == KlondikeWinnable) {
+				t.Errorf("solveKlondike() | moves end with won %t", won)
+			}
+		})
+	}
+}
+
+func TestKlondike_Solve(t *testing.T) {
+	k := NewKlondikeManager(&stubShoe{}, repo.NewKlondikeGame(), KlondikeBudget{MaxStates: 100000})
+
+	got, err := k.Solve(1, 1)
+	if err != nil {
+		t.Fatalf("Klondike.Solve() | got error %v, want nil", err)
+	}
+	if got.Result != KlondikeWinnable || got.Seed != 1 || got.DrawCount != 1 {
+		t.Fatalf("Klondike.Solve() | got %s for seed %d", got.Result, got.Seed)
+	}
+
+	state := dealKlondike(cardsByCodes(strings.Join(klondikeCodes(1), " ")))
+	for _, move := range got.Moves {
+		if state, err = applyKlondikeMove(state, 1, move); err != nil {
+			t.Fatalf("Klondike.Solve() | move %+v: %v", move, err)
+		}
+	}
+	if !klondikeWon(state) {
+		t.Errorf("Klondike.Solve() | winning line doesn't win")
+	}
+}
+
+func TestKlondike_DailySeed(t *testing.T) {
+	day := time.Date(2024, time.March, 9, 15, 0, 0, 0, time.UTC)
+	var tries int
+
+	k := NewKlondikeManager(&stubShoe{}, repo.NewKlondikeGame(), KlondikeBudget{})
+	k.solver = func(entity.KlondikeState, int, KlondikeBudget) KlondikeSolution {
+		tries++
+		if tries < 3 {
+			return KlondikeSolution{Result: KlondikeUnknown}
+		}
+		return KlondikeSolution{Result: KlondikeWinnable}
+	}
+
+	got, err := k.DailySeed(context.Background(), day, 3)
+	if err != nil {
+		t.Fatalf("Klondike.DailySeed() | got error %v, want nil", err)
+	}
+	if want := int64(20240309002); got.Seed != want {
+		t.Errorf("Klondike.DailySeed() | got seed %d, want %d", got.Seed, want)
+	}
+
+	// The deal of the day is kept.
+	if got, err := k.DailySeed(context.Background(), day.Add(time.Hour), 3); err != nil || got.Seed != 20240309002 || tries != 3 {
+		t.Errorf("Klondike.DailySeed() | got seed %d and error %v after %d solves, want the kept deal", got.Seed, err, tries)
+	}
+
+	k.solver = func(entity.KlondikeState, int, KlondikeBudget) KlondikeSolution {
+		return KlondikeSolution{Result: KlondikeUnwinnable}
+	}
+	if _, err := k.DailySeed(context.Background(), day.AddDate(0, 0, 1), 3); !errors.Is(err, KlondikeNoDailyDealErr) {
+		t.Errorf("Klondike.DailySeed() | got error %v, want %v", err, KlondikeNoDailyDealErr)
+	}
+}
+
+func TestKlondike_DailySeed_Concurrent(t *testing.T) {
+	day := time.Date(2024, time.March, 9, 15, 0, 0, 0, time.UTC)
+	var solves int32
+	release := make(chan struct{})
+
+	k := NewKlondikeManager(&stubShoe{}, repo.NewKlondikeGame(), KlondikeBudget{})
+	k.solver = func(entity.KlondikeState, int, KlondikeBudget) KlondikeSolution {
+		atomic.AddInt32(&solves, 1)
+		<-release
+		return KlondikeSolution{Result: KlondikeWinnable}
+	}
+
+	var wg sync.WaitGroup
+	seeds := make([]int64, 8)
+	for i := range seeds {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			solution, err := k.DailySeed(context.Background(), day, 1)
+			if err != nil {
+				t.Errorf("Klondike.DailySeed() | got error %v, want nil", err)
+			}
+			seeds[i] = solution.Seed
+		}(i)
+	}
+	for atomic.LoadInt32(&solves) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&solves); got != 1 {
+		t.Errorf("Klondike.DailySeed() | got %d solves, want 1", got)
+	}
+	for _, seed := range seeds {
+		if seed != 20240309000 {
+			t.Errorf("Klondike.DailySeed() | got seed %d, want 20240309000", seed)
+		}
+	}
+}
+
+func TestKlondike_DailySeed_Context(t *testing.T) {
+	day := time.Date(2024, time.March, 9, 15, 0, 0, 0, time.UTC)
+	k := NewKlondikeManager(&stubShoe{}, repo.NewKlondikeGame(), KlondikeBudget{Timeout: 2 * time.Hour})
+
+	// Each solve ends by the deadline of the search.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	k.solver = func(_ entity.KlondikeState, _ int, budget KlondikeBudget) KlondikeSolution {
+		if budget.Timeout <= 0 || budget.Timeout > time.Hour {
+			t.Errorf("Klondike.DailySeed() | got solve timeout %v, want at most 1h", budget.Timeout)
+		}
+		return KlondikeSolution{Result: KlondikeWinnable}
+	}
+	if _, err := k.DailySeed(ctx, day, 1); err != nil {
+		t.Fatalf("Klondike.DailySeed() | got error %v, want nil", err)
+	}
+
+	// A canceled search stops, and isn't kept: a call
+	// waiting on it searches again.
+	var solves int32
+	started, release := make(chan struct{}), make(chan struct{})
+	k.solver = func(entity.KlondikeState, int, KlondikeBudget) KlondikeSolution {
+		if atomic.AddInt32(&solves, 1) == 1 {
+			close(started)
+			<-release
+			return KlondikeSolution{Result: KlondikeUnknown}
+		}
+		return KlondikeSolution{Result: KlondikeWinnable}
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := k.DailySeed(canceled, day, 3)
+		first <- err
+	}()
+	<-started
+	second := make(chan error)
+	go func() {
+		_, err := k.DailySeed(context.Background(), day, 3)
+		second <- err
+	}()
+	cancel()
+	close(release)
+
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("Klondike.DailySeed() | got error %v, want %v", err, context.Canceled)
+	}
+	if err := <-second; err != nil {
+		t.Errorf("Klondike.DailySeed() | got error %v after another search was canceled, want nil", err)
+	}
+	if got := atomic.LoadInt32(&solves); got != 2 {
+		t.Errorf("Klondike.DailySeed() | got %d solves, want 2", got)
+	}
+}
+
+func TestKlondike_Solve_Busy(t *testing.T) {
+	k := NewKlondikeManager(&stubShoe{}, repo.NewKlondikeGame(), KlondikeBudget{})
+	k.solving = make(chan struct{}, 1)
+	k.solver = func(entity.KlondikeState, int, KlondikeBudget) KlondikeSolution {
+		return KlondikeSolution{Result: KlondikeWinnable}
+	}
+
+	k.solving <- struct{}{}
+	if _, err := k.Solve(1, 1); !errors.Is(err, KlondikeSolverBusyErr) {
+		t.Errorf("Klondike.Solve() | got error %v, want %v", err, KlondikeSolverBusyErr)
+	}
+	if _, err := k.DailySeed(context.Background(), time.Now(), 1); !errors.Is(err, KlondikeSolverBusyErr) {
+		t.Errorf("Klondike.DailySeed() | got error %v, want %v", err, KlondikeSolverBusyErr)
+	}
+
+	<-k.solving
+	if _, err := k.Solve(1, 1); err != nil {
+		t.Errorf("Klondike.Solve() | got error %v, want nil", err)
+	}
+}
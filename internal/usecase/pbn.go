@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// BridgeInvalidPBNErr happens when a PBN file can't be read.
+var BridgeInvalidPBNErr = errors.New("invalid PBN")
+
+// pbnRanks are the PBN rank characters, aces high.
+const pbnRanks = "23456789TJQKA"
+
+var pbnTag = regexp.MustCompile(`^\[(\w+)\s+"(.*)"\]$`)
+
+// FormatPBN writes deals in Portable Bridge Notation, one
+// game per deal separated by a blank line.
+func FormatPBN(deals []entity.BridgeDeal) string {
+	var b strings.Builder
+	for i, deal := range deals {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[Board \"%d\"]\n", deal.Board)
+		fmt.Fprintf(&b, "[Dealer \"%s\"]\n", deal.Dealer)
+		fmt.Fprintf(&b, "[Vulnerable \"%s\"]\n", deal.Vulnerable)
+		fmt.Fprintf(&b, "[Deal \"%s\"]\n", formatPBNDeal(deal))
+	}
+	return b.String()
+}
+
+// formatPBNDeal writes the hands clockwise from the dealer.
+func formatPBNDeal(deal entity.BridgeDeal) string {
+	d := bridgeSeatIndex(deal.Dealer)
+
+	hands := make([]string, len(entity.BridgeSeats))
+	for i := range hands {
+		seat := entity.BridgeSeats[(d+i)%len(entity.BridgeSeats)]
+
+		suits := make([]string, len(bridgeSuits))
+		hand := append([]entity.Card{}, deal.Hands[seat]...)
+		sortBridgeHand(hand)
+		for _, c := range hand {
+			suits[bridgeSuitIndex(c)] += string(pbnRanks[pokerRank(c)-2])
+		}
+		hands[i] = strings.Join(suits, ".")
+	}
+
+	return deal.Dealer + ":" + strings.Join(hands, " ")
+}
+
+// ParsePBN reads the deals of a PBN file. Games without
+// a Deal tag are skipped; missing Dealer and Vulnerable
+// tags follow from the board number.
+func ParsePBN(pbn string) ([]entity.BridgeDeal, error) {
+	var (
+		deals []entity.BridgeDeal
+		tags  = make(map[string]string)
+	)
+
+	flush := func() error {
+		defer func() { tags = make(map[string]string) }()
+		if tags["Deal"] == "" {
+			return nil
+		}
+
+		deal, err := parsePBNGame(tags, len(deals)+1)
+		if err != nil {
+			return err
+		}
+		deals = append(deals, deal)
+		return nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(pbn))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(line, "%"), strings.HasPrefix(line, ";"), strings.HasPrefix(line, "{"):
+			// Comments and commentary.
+		default:
+			if m := pbnTag.FindStringSubmatch(line); m != nil {
+				tags[m[1]] = m[2]
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", BridgeInvalidPBNErr, err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	if len(deals) == 0 {
+		return nil, fmt.Errorf("%w: no deal found", BridgeInvalidPBNErr)
+	}
+
+	return deals, nil
+}
+
+func parsePBNGame(tags map[string]string, position int) (entity.BridgeDeal, error) {
+	board := position
+	if v := tags["Board"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return entity.BridgeDeal{}, fmt.Errorf("%w: board %q", BridgeInvalidPBNErr, v)
+		}
+		board = n
+	}
+
+	deal := entity.BridgeDeal{Board: board}
+	deal.Dealer, deal.Vulnerable = bridgeBoard(board)
+
+	if v := tags["Dealer"]; v != "" {
+		if bridgeSeatIndex(v) < 0 {
+			return entity.BridgeDeal{}, fmt.Errorf("%w: dealer %q", BridgeInvalidPBNErr, v)
+		}
+		deal.Dealer = v
+	}
+	if v := tags["Vulnerable"]; v != "" {
+		switch v {
+		case entity.BridgeVulnerableNone, "Love", "-":
+			deal.Vulnerable = entity.BridgeVulnerableNone
+		case entity.BridgeVulnerableNS, entity.BridgeVulnerableEW:
+			deal.Vulnerable = v
+		case entity.BridgeVulnerableAll, "Both":
+			deal.Vulnerable = entity.BridgeVulnerableAll
+		default:
+			return entity.BridgeDeal{}, fmt.Errorf("%w: vulnerable %q", BridgeInvalidPBNErr, v)
+		}
+	}
+
+	hands, err := parsePBNDeal(tags["Deal"])
+	if err != nil {
+		return entity.BridgeDeal{}, err
+	}
+	deal.Hands = hands
+
+	return deal, nil
+}
+
+// parsePBNDeal reads a deal such as "N:AKQ.JT9.876.5432 ...",
+// where the hands go clockwise from the given seat.
+func parsePBNDeal(s string) (map[string][]entity.Card, error) {
+	first, rest, ok := strings.Cut(s, ":")
+	start := bridgeSeatIndex(strings.ToUpper(first))
+	if !ok || start < 0 {
+		return nil, fmt.Errorf("%w: deal %q has no first seat", BridgeInvalidPBNErr, s)
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) != len(entity.BridgeSeats) {
+		return nil, fmt.Errorf("%w: deal %q has %d hands, want %d", BridgeInvalidPBNErr, s, len(fields), len(entity.BridgeSeats))
+	}
+
+	seen := make(map[string]bool)
+	hands := make(map[string][]entity.Card, len(entity.BridgeSeats))
+	for i, field := range fields {
+		seat := entity.BridgeSeats[(start+i)%len(entity.BridgeSeats)]
+
+		suits := strings.Split(field, ".")
+		if len(suits) != len(bridgeSuits) {
+			return nil, fmt.Errorf("%w: hand %q has %d suits, want %d", BridgeInvalidPBNErr, field, len(suits), len(bridgeSuits))
+		}
+		for si, ranks := range suits {
+			if ranks == "-" {
+				continue
+			}
+			for _, r := range strings.ToUpper(ranks) {
+				card, ok := pbnCard(r, bridgeSuits[si])
+				if !ok {
+					return nil, fmt.Errorf("%w: unknown rank %q in hand %q", BridgeInvalidPBNErr, r, field)
+				}
+				if seen[card.Code] {
+					return nil, fmt.Errorf("%w: %s is dealt twice", BridgeInvalidPBNErr, card.Code)
+				}
+				seen[card.Code] = true
+				hands[seat] = append(hands[seat], card)
+			}
+		}
+
+		if len(hands[seat]) != bridgeHandSize {
+			return nil, fmt.Errorf("%w: seat %s has %d cards, want %d", BridgeInvalidPBNErr, seat, len(hands[seat]), bridgeHandSize)
+		}
+		sortBridgeHand(hands[seat])
+	}
+
+	return hands, nil
+}
+
+func pbnCard(rank rune, suit string) (entity.Card, bool) {
+	r := strings.IndexRune(pbnRanks, rank)
+	if r < 0 {
+		return entity.Card{}, false
+	}
+	for _, c := range entity.DefaultCards {
+		if c.Suit == suit && pokerRank(c) == r+2 {
+			return c, true
+		}
+	}
+	return entity.Card{}, false
+}
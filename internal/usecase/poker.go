@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+const (
+	pokerHighCard = iota
+	pokerPair
+	pokerTwoPair
+	pokerThreeOfAKind
+	pokerStraight
+	pokerFlush
+	pokerFullHouse
+	pokerFourOfAKind
+	pokerStraightFlush
+)
+
+// pokerHandNames maps a hand category to its name.
+var pokerHandNames = []string{
+	pokerHighCard:      "HIGH_CARD",
+	pokerPair:          "PAIR",
+	pokerTwoPair:       "TWO_PAIR",
+	pokerThreeOfAKind:  "THREE_OF_A_KIND",
+	pokerStraight:      "STRAIGHT",
+	pokerFlush:         "FLUSH",
+	pokerFullHouse:     "FULL_HOUSE",
+	pokerFourOfAKind:   "FOUR_OF_A_KIND",
+	pokerStraightFlush: "STRAIGHT_FLUSH",
+}
+
+// PokerHand is the evaluation of the best five card poker
+// hand. A higher Score always beats a lower one.
+type PokerHand struct {
+	Score int
+	Name  string
+}
+
+// EvaluatePokerHand finds the best five card poker hand
+// among five to seven cards.
+func EvaluatePokerHand(cards []entity.Card) PokerHand {
+	best := -1
+	combo := make([]entity.Card, 5)
+
+	var choose func(start, depth int)
+	choose = func(start, depth int) {
+		if depth == 5 {
+			if score := scoreFiveCards(combo); score > best {
+				best = score
+			}
+			return
+		}
+		for i := start; i <= len(cards)-(5-depth); i++ {
+			combo[depth] = cards[i]
+			choose(i+1, depth+1)
+		}
+	}
+	choose(0, 0)
+
+	if best < 0 {
+		return PokerHand{}
+	}
+
+	return PokerHand{
+		Score: best,
+		Name:  pokerHandNames[best/pokerCategoryWeight],
+	}
+}
+
+// pokerCategoryWeight is 15^5, enough room for five ranks
+// encoded in base 15 below the hand category.
+const pokerCategoryWeight = 15 * 15 * 15 * 15 * 15
+
+func scoreFiveCards(cards []entity.Card) int {
+	counts := map[int]int{}
+	flush := true
+	for i, c := range cards {
+		counts[pokerRank(c)]++
+		if i > 0 && c.Suit != cards[0].Suit {
+			flush = false
+		}
+	}
+
+	// Distinct ranks ordered by how many times they
+	// appear and then by rank, which is also the order
+	// they are compared in.
+	ranks := make([]int, 0, len(counts))
+	for r := range counts {
+		ranks = append(ranks, r)
+	}
+	sort.Slice(ranks, func(i, j int) bool {
+		if counts[ranks[i]] != counts[ranks[j]] {
+			return counts[ranks[i]] > counts[ranks[j]]
+		}
+		return ranks[i] > ranks[j]
+	})
+
+	straight := false
+	if len(ranks) == 5 {
+		switch {
+		case ranks[0]-ranks[4] == 4:
+			straight = true
+		case ranks[0] == 14 && ranks[1] == 5:
+			// The wheel, A-2-3-4-5, plays the ace low.
+			straight = true
+			ranks = []int{5, 4, 3, 2, 1}
+		}
+	}
+
+	var category int
+	switch {
+	case straight && flush:
+		category = pokerStraightFlush
+	case counts[ranks[0]] == 4:
+		category = pokerFourOfAKind
+	case counts[ranks[0]] == 3 && counts[ranks[1]] == 2:
+		category = pokerFullHouse
+	case flush:
+		category = pokerFlush
+	case straight:
+		category = pokerStraight
+	case counts[ranks[0]] == 3:
+		category = pokerThreeOfAKind
+	case counts[ranks[0]] == 2 && counts[ranks[1]] == 2:
+		category = pokerTwoPair
+	case counts[ranks[0]] == 2:
+		category = pokerPair
+	default:
+		category = pokerHighCard
+	}
+
+	score := 0
+	for i := 0; i < 5; i++ {
+		score *= 15
+		if i < len(ranks) {
+			score += ranks[i]
+		}
+	}
+
+	return category*pokerCategoryWeight + score
+}
+
+// pokerRank returns the rank of a card from 2 to 14,
+// with aces high.
+func pokerRank(c entity.Card) int {
+	switch c.Value {
+	case "ACE":
+		return 14
+	case "KING":
+		return 13
+	case "QUEEN":
+		return 12
+	case "JACK":
+		return 11
+	}
+
+	v, _ := strconv.Atoi(c.Value)
+	return v
+}
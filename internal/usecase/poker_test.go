@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func cardsByCodes(codes string) []entity.Card {
+	var cards []entity.Card
+	for _, c := range strings.Fields(codes) {
+		cards = append(cards, cardByCode(c))
+	}
+	return cards
+}
+
+func TestEvaluatePokerHand(t *testing.T) {
+	tests := []struct {
+		name     string
+		cards    string
+		wantName string
+	}{
+		{name: "Straight Flush", cards: "9S 10S JS QS KS 2D 3C", wantName: "STRAIGHT_FLUSH"},
+		{name: "Four Of A Kind", cards: "9S 9D 9C 9H KS 2D 3C", wantName: "FOUR_OF_A_KIND"},
+		{name: "Full House", cards: "9S 9D 9C KH KS 2D 3C", wantName: "FULL_HOUSE"},
+		{name: "Flush", cards: "2S 5S 9S JS KS 2D 3C", wantName: "FLUSH"},
+		{name: "Straight", cards: "5S 6D 7C 8H 9S 2D 2C", wantName: "STRAIGHT"},
+		{name: "Wheel", cards: "AS 2D 3C 4H 5S KD KC", wantName: "STRAIGHT"},
+		{name: "Three Of A Kind", cards: "9S 9D 9C 2H KS 4D 7C", wantName: "THREE_OF_A_KIND"},
+		{name: "Two Pair", cards: "9S 9D KC KH 2S 4D 7C", wantName: "TWO_PAIR"},
+		{name: "Pair", cards: "9S 9D KC QH 2S 4D 7C", wantName: "PAIR"},
+		{name: "High Card", cards: "9S 10D KC QH 2S 4D 7C", wantName: "HIGH_CARD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EvaluatePokerHand(cardsByCodes(tt.cards))
+			if got.Name != tt.wantName {
+				t.Errorf("EvaluatePokerHand() | got %s, want %s", got.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestEvaluatePokerHand_Compare(t *testing.T) {
+	tests := []struct {
+		name   string
+		better string
+		worse  string
+	}{
+		{name: "Category Wins", better: "2S 2D 3C 3H 4S", worse: "AS AD KC QH JS"},
+		{name: "Pair Kicker", better: "AS AD KC 4H 3S", worse: "AC AH QC 4D 3D"},
+		{name: "Two Pair Top Pair", better: "KS KD 2C 2H 3S", worse: "QS QD JC JH AS"},
+		{name: "Wheel Is Lowest Straight", better: "2S 3D 4C 5H 6S", worse: "AS 2D 3C 4H 5S"},
+		{name: "Full House Trips First", better: "3S 3D 3C 2H 2S", worse: "2D 2C 2H AS AD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			better := EvaluatePokerHand(cardsByCodes(tt.better))
+			worse := EvaluatePokerHand(cardsByCodes(tt.worse))
+			if better.Score <= worse.Score {
+				t.Errorf("EvaluatePokerHand() | %s (%d) should beat %s (%d)", tt.better, better.Score, tt.worse, worse.Score)
+			}
+		})
+	}
+
+	split := EvaluatePokerHand(cardsByCodes("AS KD QC JH 9S"))
+	same := EvaluatePokerHand(cardsByCodes("AD KC QH JS 9D"))
+	if split.Score != same.Score {
+		t.Errorf("EvaluatePokerHand() | equal hands got scores %d and %d", split.Score, same.Score)
+	}
+}
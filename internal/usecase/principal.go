@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx made by p.
+func WithPrincipal(ctx context.Context, p entity.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns who ctx is made by, if it was
+// authenticated.
+func PrincipalFrom(ctx context.Context) (entity.Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(entity.Principal)
+	return p, ok
+}
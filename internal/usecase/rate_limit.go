@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// RateLimitBudget is a share of the requests callers are
+// held to a rate limit for.
+type RateLimitBudget string
+
+// Budgets of the rate limits.
+const (
+	// RateLimitCreate is the decks a caller creates.
+	RateLimitCreate RateLimitBudget = "create"
+	// RateLimitDraw is the draws, deals and returns a
+	// caller makes, from any deck.
+	RateLimitDraw RateLimitBudget = "draw"
+	// RateLimitDeckDraw is the draws, deals and returns
+	// made from a deck, by any caller.
+	RateLimitDeckDraw RateLimitBudget = "deck_draw"
+)
+
+// RateLimitOptions are the limits of each budget. A limit
+// with zero PerMinute leaves its budget unlimited, and
+// one with zero Burst holds a minute of requests.
+type RateLimitOptions struct {
+	Create   entity.RateLimit
+	Draw     entity.RateLimit
+	DeckDraw entity.RateLimit
+}
+
+// RateLimits is a use case to hold callers to the rate
+// limits of the budgets, with a token bucket for each
+// budget and key.
+type RateLimits struct {
+	store RateLimitRepo
+	opts  RateLimitOptions
+}
+
+// NewRateLimits creates a new RateLimits keeping its
+// buckets in store.
+func NewRateLimits(store RateLimitRepo, opts RateLimitOptions) *RateLimits {
+	return &RateLimits{store: store, opts: opts}
+}
+
+// Take takes a request of key out of budget. Budgets
+// without a limit allow every request, with a zero
+// status Limit. So does a store that fails, rather than
+// turning every caller away; the failure is logged.
+func (r *RateLimits) Take(ctx context.Context, budget RateLimitBudget, key string) entity.RateLimitStatus {
+	var limit entity.RateLimit
+	switch budget {
+	case RateLimitCreate:
+		limit = r.opts.Create
+	case RateLimitDraw:
+		limit = r.opts.Draw
+	case RateLimitDeckDraw:
+		limit = r.opts.DeckDraw
+	}
+	if limit.PerMinute <= 0 {
+		return entity.RateLimitStatus{Allowed: true}
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = limit.PerMinute
+	}
+
+	status, err := r.store.Take(ctx, string(budget)+":"+key, limit, time.Now())
+	if err != nil {
+		slog.WarnContext(ctx, "rate limit: taking a token", slog.String("budget", string(budget)), slog.Any("err", err))
+		return entity.RateLimitStatus{Allowed: true}
+	}
+	return status
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+type failingRateLimitRepo struct{}
+
+func (failingRateLimitRepo) Take(context.Context, string, entity.RateLimit, time.Time) (entity.RateLimitStatus, error) {
+	return entity.RateLimitStatus{}, errors.New("store down")
+}
+
+func TestRateLimits_Take(t *testing.T) {
+	ctx := context.Background()
+	limits := NewRateLimits(repo.NewRateLimit(), RateLimitOptions{
+		Create: entity.RateLimit{PerMinute: 2},
+		Draw:   entity.RateLimit{PerMinute: 60, Burst: 1},
+	})
+
+	// Create holds a minute of requests, with no burst set.
+	for i, want := range []bool{true, true, false} {
+		if got := limits.Take(ctx, RateLimitCreate, "alice"); got.Allowed != want || got.Limit != 2 {
+			t.Errorf("Take() | create %d got allowed %t of %d, want %t of 2", i, got.Allowed, got.Limit, want)
+		}
+	}
+
+	// Budgets and keys have buckets of their own.
+	if got := limits.Take(ctx, RateLimitDraw, "alice"); !got.Allowed {
+		t.Error("Take() | got alice's draw refused after her creates, want allowed")
+	}
+	if got := limits.Take(ctx, RateLimitCreate, "bob"); !got.Allowed {
+		t.Error("Take() | got bob's create refused after alice's, want allowed")
+	}
+
+	// DeckDraw has no limit.
+	for i := 0; i < 10; i++ {
+		if got := limits.Take(ctx, RateLimitDeckDraw, "deck"); !got.Allowed || got.Limit != 0 {
+			t.Fatalf("Take() | got deck draw %+v, want allowed without a limit", got)
+		}
+	}
+}
+
+func TestRateLimits_Take_StoreFails(t *testing.T) {
+	limits := NewRateLimits(failingRateLimitRepo{}, RateLimitOptions{Create: entity.RateLimit{PerMinute: 1}})
+
+	got := limits.Take(context.Background(), RateLimitCreate, "alice")
+	if got != (entity.RateLimitStatus{Allowed: true}) {
+		t.Errorf("Take() | got %+v, want allowed without a limit", got)
+	}
+}
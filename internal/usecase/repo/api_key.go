@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+var (
+	// APIKeyNotFoundErr happens when an API key is not found
+	// in the repo.
+	APIKeyNotFoundErr = errors.New("api key not found")
+	// APIKeyExistsErr happens when an API key is saved under
+	// a name already taken.
+	APIKeyExistsErr = errors.New("api key already exists")
+	// APIKeyConflictErr happens when the keys keep changing
+	// under a change until it runs out of retries.
+	APIKeyConflictErr = errors.New("api keys changed concurrently")
+)
+
+// APIKey repo, keeping the keys in memory until the
+// application stops. It's safe for concurrent use.
+type APIKey struct {
+	mu     sync.RWMutex
+	keys   map[string]entity.APIKey
+	hashes map[string]string
+}
+
+// NewAPIKey creates a new APIKey.
+func NewAPIKey() *APIKey {
+	return &APIKey{keys: make(map[string]entity.APIKey), hashes: make(map[string]string)}
+}
+
+// Save adds a key to the store, unless its name is taken.
+func (a *APIKey) Save(ctx context.Context, key entity.APIKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.keys[key.Name]; ok {
+		return fmt.Errorf("%w with name %s", APIKeyExistsErr, key.Name)
+	}
+	a.keys[key.Name] = key
+	a.hashes[key.Hash] = key.Name
+	return nil
+}
+
+// Get retrieves a key from the hash of its secret.
+func (a *APIKey) Get(ctx context.Context, hash string) (entity.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return entity.APIKey{}, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	name, ok := a.hashes[hash]
+	if !ok {
+		return entity.APIKey{}, APIKeyNotFoundErr
+	}
+	return a.keys[name], nil
+}
+
+// All returns every key in the store, by name.
+func (a *APIKey) All(ctx context.Context) ([]entity.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	keys := make([]entity.APIKey, 0, len(a.keys))
+	for _, key := range a.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys, nil
+}
+
+// Delete removes a key from the store by its name.
+func (a *APIKey) Delete(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key, ok := a.keys[name]
+	if !ok {
+		return fmt.Errorf("%w with name %s", APIKeyNotFoundErr, name)
+	}
+	delete(a.keys, name)
+	delete(a.hashes, key.Hash)
+	return nil
+}
+
+// apiKeyRecord is how the bolt and redis stores keep an
+// API key, hash included.
+type apiKeyRecord struct {
+	Name    string    `json:"name"`
+	Hash    string    `json:"hash"`
+	Admin   bool      `json:"admin"`
+	Tenant  string    `json:"tenant,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+func encodeAPIKey(key entity.APIKey) ([]byte, error) {
+	return json.Marshal(apiKeyRecord(key))
+}
+
+func decodeAPIKey(data []byte) (entity.APIKey, error) {
+	var r apiKeyRecord
+	if err := json.Unmarshal(data, &r); err != nil {
+		return entity.APIKey{}, fmt.Errorf("decoding api key: %w", err)
+	}
+	return entity.APIKey(r), nil
+}
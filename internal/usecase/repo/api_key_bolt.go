@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// BoltAPIKey is an API key store on bbolt, keeping each
+// key under its name and the names under the hashes.
+type BoltAPIKey struct {
+	db *bbolt.DB
+}
+
+// NewBoltAPIKey creates a new BoltAPIKey.
+func NewBoltAPIKey(db *bbolt.DB) *BoltAPIKey {
+	return &BoltAPIKey{db: db}
+}
+
+// Save adds a key to the store, unless its name is taken.
+func (b *BoltAPIKey) Save(ctx context.Context, key entity.APIKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	v, err := encodeAPIKey(key)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		keys := tx.Bucket(boltAPIKeysBucket)
+		if keys.Get([]byte(key.Name)) != nil {
+			return fmt.Errorf("%w with name %s", APIKeyExistsErr, key.Name)
+		}
+		if err := keys.Put([]byte(key.Name), v); err != nil {
+			return err
+		}
+		return tx.Bucket(boltAPIHashesBucket).Put([]byte(key.Hash), []byte(key.Name))
+	})
+}
+
+// Get retrieves a key from the hash of its secret.
+func (b *BoltAPIKey) Get(ctx context.Context, hash string) (entity.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return entity.APIKey{}, err
+	}
+
+	var key entity.APIKey
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		name := tx.Bucket(boltAPIHashesBucket).Get([]byte(hash))
+		if name == nil {
+			return APIKeyNotFoundErr
+		}
+		v := tx.Bucket(boltAPIKeysBucket).Get(name)
+		if v == nil {
+			return APIKeyNotFoundErr
+		}
+		var err error
+		key, err = decodeAPIKey(v)
+		return err
+	})
+	return key, err
+}
+
+// All returns every key in the store, by name.
+func (b *BoltAPIKey) All(ctx context.Context) ([]entity.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var keys []entity.APIKey
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(boltAPIKeysBucket).ForEach(func(_, v []byte) error {
+			key, err := decodeAPIKey(v)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, key)
+			return nil
+		})
+	})
+	return keys, err
+}
+
+// Delete removes a key from the store by its name.
+func (b *BoltAPIKey) Delete(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		keys := tx.Bucket(boltAPIKeysBucket)
+		v := keys.Get([]byte(name))
+		if v == nil {
+			return fmt.Errorf("%w with name %s", APIKeyNotFoundErr, name)
+		}
+		key, err := decodeAPIKey(v)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltAPIHashesBucket).Delete([]byte(key.Hash)); err != nil {
+			return err
+		}
+		return keys.Delete([]byte(name))
+	})
+}
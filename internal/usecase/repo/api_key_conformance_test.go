@@ -0,0 +1,60 @@
+package repo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+	"github.com/lualfe/card-game/internal/usecase/repo/repotest"
+)
+
+func TestAPIKey_Conformance(t *testing.T) {
+	repotest.APIKeyRepo(t, func(t *testing.T) usecase.APIKeyRepo {
+		return repo.NewAPIKey()
+	})
+}
+
+func TestBoltAPIKey_Conformance(t *testing.T) {
+	repotest.APIKeyRepo(t, func(t *testing.T) usecase.APIKeyRepo {
+		db, err := repo.OpenBolt(filepath.Join(t.TempDir(), "decks.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		return repo.NewBoltAPIKey(db)
+	})
+}
+
+func TestRedisAPIKey_Conformance(t *testing.T) {
+	repotest.APIKeyRepo(t, func(t *testing.T) usecase.APIKeyRepo {
+		client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+		t.Cleanup(func() { client.Close() })
+		return repo.NewRedisAPIKey(client)
+	})
+}
+
+// TestPostgresAPIKey_Conformance needs DECK_POSTGRES_TEST_DSN
+// and wipes the API keys of that database.
+func TestPostgresAPIKey_Conformance(t *testing.T) {
+	dsn := os.Getenv("DECK_POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("DECK_POSTGRES_TEST_DSN not set")
+	}
+
+	repotest.APIKeyRepo(t, func(t *testing.T) usecase.APIKeyRepo {
+		db, err := repo.OpenPostgres(dsn, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		if _, err := db.Exec("TRUNCATE api_keys"); err != nil {
+			t.Fatal(err)
+		}
+		return repo.NewPostgresAPIKey(db)
+	})
+}
@@ -0,0 +1,100 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// postgresUniqueViolation is the code of the errors of
+// inserting a row whose key is taken.
+const postgresUniqueViolation = "23505"
+
+// PostgresAPIKey is an API key store on PostgreSQL.
+type PostgresAPIKey struct {
+	db *sql.DB
+}
+
+// NewPostgresAPIKey creates a new PostgresAPIKey.
+func NewPostgresAPIKey(db *sql.DB) *PostgresAPIKey {
+	return &PostgresAPIKey{db: db}
+}
+
+// Save adds a key to the store, unless its name is taken.
+func (p *PostgresAPIKey) Save(ctx context.Context, key entity.APIKey) error {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	_, err := p.db.ExecContext(ctx, "INSERT INTO api_keys (name, hash, admin, tenant, created_at) VALUES ($1, $2, $3, $4, $5)",
+		key.Name, key.Hash, key.Admin, key.Tenant, key.Created)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == postgresUniqueViolation {
+		return fmt.Errorf("%w with name %s", APIKeyExistsErr, key.Name)
+	}
+	return err
+}
+
+// Get retrieves a key from the hash of its secret.
+func (p *PostgresAPIKey) Get(ctx context.Context, hash string) (entity.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	key := entity.APIKey{Hash: hash}
+	err := p.db.QueryRowContext(ctx, "SELECT name, admin, tenant, created_at FROM api_keys WHERE hash = $1", hash).
+		Scan(&key.Name, &key.Admin, &key.Tenant, &key.Created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.APIKey{}, APIKeyNotFoundErr
+		}
+		return entity.APIKey{}, err
+	}
+	key.Created = key.Created.UTC()
+	return key, nil
+}
+
+// All returns every key in the store, by name.
+func (p *PostgresAPIKey) All(ctx context.Context) ([]entity.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, "SELECT name, hash, admin, tenant, created_at FROM api_keys ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []entity.APIKey
+	for rows.Next() {
+		var key entity.APIKey
+		if err := rows.Scan(&key.Name, &key.Hash, &key.Admin, &key.Tenant, &key.Created); err != nil {
+			return keys, err
+		}
+		key.Created = key.Created.UTC()
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// Delete removes a key from the store by its name.
+func (p *PostgresAPIKey) Delete(ctx context.Context, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	res, err := p.db.ExecContext(ctx, "DELETE FROM api_keys WHERE name = $1", name)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w with name %s", APIKeyNotFoundErr, name)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+const (
+	redisAPIKeysKey      = "api_keys"
+	redisAPIKeyHashesKey = "api_key_hashes"
+)
+
+// RedisAPIKey is an API key store on Redis, shared by every
+// instance of the application. Keys are kept in a hash by
+// name, and their names in a hash by key hash.
+type RedisAPIKey struct {
+	client redis.UniversalClient
+}
+
+// NewRedisAPIKey creates a new RedisAPIKey.
+func NewRedisAPIKey(client redis.UniversalClient) *RedisAPIKey {
+	return &RedisAPIKey{client: client}
+}
+
+// Save adds a key to the store, unless its name is taken.
+// The names are watched, so two instances saving the same
+// name don't both succeed.
+func (r *RedisAPIKey) Save(ctx context.Context, key entity.APIKey) error {
+	v, err := encodeAPIKey(key)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < redisUpdateRetries; i++ {
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			taken, err := tx.HExists(ctx, redisAPIKeysKey, key.Name).Result()
+			if err != nil {
+				return err
+			}
+			if taken {
+				return fmt.Errorf("%w with name %s", APIKeyExistsErr, key.Name)
+			}
+
+			_, err = tx.TxPipelined(ctx, func(p redis.Pipeliner) error {
+				p.HSet(ctx, redisAPIKeysKey, key.Name, v)
+				p.HSet(ctx, redisAPIKeyHashesKey, key.Hash, key.Name)
+				return nil
+			})
+			return err
+		}, redisAPIKeysKey)
+		if errors.Is(err, redis.TxFailedErr) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			continue
+		}
+		return err
+	}
+
+	return fmt.Errorf("%w: api key %s", APIKeyConflictErr, key.Name)
+}
+
+// Get retrieves a key from the hash of its secret.
+func (r *RedisAPIKey) Get(ctx context.Context, hash string) (entity.APIKey, error) {
+	name, err := r.client.HGet(ctx, redisAPIKeyHashesKey, hash).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return entity.APIKey{}, APIKeyNotFoundErr
+		}
+		return entity.APIKey{}, err
+	}
+	v, err := r.client.HGet(ctx, redisAPIKeysKey, name).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return entity.APIKey{}, APIKeyNotFoundErr
+		}
+		return entity.APIKey{}, err
+	}
+	return decodeAPIKey(v)
+}
+
+// All returns every key in the store.
+func (r *RedisAPIKey) All(ctx context.Context) ([]entity.APIKey, error) {
+	values, err := r.client.HVals(ctx, redisAPIKeysKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []entity.APIKey
+	for _, v := range values {
+		key, err := decodeAPIKey([]byte(v))
+		if err != nil {
+			return keys, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Delete removes a key from the store by its name.
+func (r *RedisAPIKey) Delete(ctx context.Context, name string) error {
+	for i := 0; i < redisUpdateRetries; i++ {
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			v, err := tx.HGet(ctx, redisAPIKeysKey, name).Bytes()
+			if err != nil {
+				if errors.Is(err, redis.Nil) {
+					return fmt.Errorf("%w with name %s", APIKeyNotFoundErr, name)
+				}
+				return err
+			}
+			key, err := decodeAPIKey(v)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.TxPipelined(ctx, func(p redis.Pipeliner) error {
+				p.HDel(ctx, redisAPIKeysKey, name)
+				p.HDel(ctx, redisAPIKeyHashesKey, key.Hash)
+				return nil
+			})
+			return err
+		}, redisAPIKeysKey)
+		if errors.Is(err, redis.TxFailedErr) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			continue
+		}
+		return err
+	}
+
+	return fmt.Errorf("%w: api key %s", APIKeyConflictErr, name)
+}
@@ -0,0 +1,150 @@
+package repo
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// AuditFile is an audit sink writing one JSON record
+// per line to an append-only file. The file is read once
+// when it's opened, to find where the last record of each
+// deck starts; Last reads only that line.
+type AuditFile struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+	// size is the offset the next record is written at.
+	size int64
+	// last is the offset of the last record of each deck.
+	last map[string]int64
+}
+
+// NewAuditFile opens the audit file at path, creating it
+// when it doesn't exist.
+func NewAuditFile(path string) (*AuditFile, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit file: %w", err)
+	}
+
+	a := &AuditFile{path: path, file: f, last: make(map[string]int64)}
+	if err := a.index(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// index finds the offset of the last record of each deck
+// and the end of the file.
+func (a *AuditFile) index() error {
+	r := bufio.NewReader(io.NewSectionReader(a.file, 0, math.MaxInt64))
+	for line := 1; ; line++ {
+		b, err := r.ReadBytes('\n')
+		if len(b) > 0 {
+			var record struct {
+				DeckID string `json:"deck_id"`
+			}
+			if err := json.Unmarshal(b, &record); err != nil {
+				return fmt.Errorf("reading audit file line %d: %w", line, err)
+			}
+			a.last[record.DeckID] = a.size
+			a.size += int64(len(b))
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading audit file: %w", err)
+		}
+	}
+}
+
+// Append writes a record and syncs it to disk.
+func (a *AuditFile) Append(record entity.AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	n, err := a.file.Write(append(line, '\n'))
+	offset := a.size
+	a.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("writing audit file: %w", err)
+	}
+	a.last[record.DeckID] = offset
+	return a.file.Sync()
+}
+
+// Last returns the last record of a deck.
+func (a *AuditFile) Last(deckID string) (entity.AuditRecord, bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	offset, ok := a.last[deckID]
+	if !ok {
+		return entity.AuditRecord{}, false, nil
+	}
+
+	line, err := bufio.NewReader(io.NewSectionReader(a.file, offset, a.size-offset)).ReadBytes('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return entity.AuditRecord{}, false, fmt.Errorf("reading audit file: %w", err)
+	}
+	var record entity.AuditRecord
+	if err := json.Unmarshal(line, &record); err != nil {
+		return entity.AuditRecord{}, false, fmt.Errorf("reading audit file at %d: %w", offset, err)
+	}
+	return record, true, nil
+}
+
+// Records reads every record in the order they were written.
+func (a *AuditFile) Records() ([]entity.AuditRecord, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return ReadAuditFile(a.path)
+}
+
+// Close closes the file.
+func (a *AuditFile) Close() error {
+	return a.file.Close()
+}
+
+// ReadAuditFile reads the records of an audit file
+// without opening it for writing.
+func ReadAuditFile(path string) ([]entity.AuditRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit file: %w", err)
+	}
+	defer f.Close()
+
+	var records []entity.AuditRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var r entity.AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("reading audit file line %d: %w", line, err)
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit file: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	// Registers the sqlite3 driver.
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+const auditSQLiteSchema = `
+CREATE TABLE IF NOT EXISTS audit_records (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	deck_id   TEXT    NOT NULL,
+	seq       INTEGER NOT NULL,
+	version   INTEGER NOT NULL,
+	type      TEXT    NOT NULL,
+	time      TEXT    NOT NULL,
+	cards     TEXT    NOT NULL,
+	player_id TEXT    NOT NULL,
+	prev_hash TEXT    NOT NULL,
+	hash      TEXT    NOT NULL,
+	UNIQUE (deck_id, seq)
+)`
+
+const auditSQLiteColumns = "deck_id, seq, version, type, time, cards, player_id, prev_hash, hash"
+
+// AuditSQLite is an audit sink writing records to a
+// SQLite database.
+type AuditSQLite struct {
+	db *sql.DB
+}
+
+// NewAuditSQLite opens the SQLite database at path,
+// creating the audit table when it doesn't exist.
+func NewAuditSQLite(path string) (*AuditSQLite, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit database: %w", err)
+	}
+	// SQLite allows a single writer.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(auditSQLiteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating audit table: %w", err)
+	}
+
+	return &AuditSQLite{db: db}, nil
+}
+
+// Append inserts a record.
+func (a *AuditSQLite) Append(record entity.AuditRecord) error {
+	_, err := a.db.Exec(
+		"INSERT INTO audit_records ("+auditSQLiteColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		record.DeckID,
+		record.Seq,
+		record.Version,
+		record.Type,
+		record.Time.UTC().Format(time.RFC3339Nano),
+		strings.Join(record.Cards, ","),
+		record.PlayerID,
+		record.PrevHash,
+		record.Hash,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting audit record: %w", err)
+	}
+	return nil
+}
+
+// Last returns the last record of a deck.
+func (a *AuditSQLite) Last(deckID string) (entity.AuditRecord, bool, error) {
+	row := a.db.QueryRow("SELECT "+auditSQLiteColumns+" FROM audit_records WHERE deck_id = ? ORDER BY seq DESC LIMIT 1", deckID)
+
+	record, err := scanAuditRecord(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.AuditRecord{}, false, nil
+	}
+	if err != nil {
+		return entity.AuditRecord{}, false, err
+	}
+	return record, true, nil
+}
+
+// Records reads every record in the order they were written.
+func (a *AuditSQLite) Records() ([]entity.AuditRecord, error) {
+	rows, err := a.db.Query("SELECT " + auditSQLiteColumns + " FROM audit_records ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("reading audit records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []entity.AuditRecord
+	for rows.Next() {
+		record, err := scanAuditRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// Close closes the database.
+func (a *AuditSQLite) Close() error {
+	return a.db.Close()
+}
+
+func scanAuditRecord(row interface{ Scan(...any) error }) (entity.AuditRecord, error) {
+	var (
+		r           entity.AuditRecord
+		when, cards string
+	)
+	if err := row.Scan(&r.DeckID, &r.Seq, &r.Version, &r.Type, &when, &cards, &r.PlayerID, &r.PrevHash, &r.Hash); err != nil {
+		return entity.AuditRecord{}, err
+	}
+
+	var err error
+	if r.Time, err = time.Parse(time.RFC3339Nano, when); err != nil {
+		return entity.AuditRecord{}, fmt.Errorf("reading audit record time: %w", err)
+	}
+	r.Cards = []string{}
+	if cards != "" {
+		r.Cards = strings.Split(cards, ",")
+	}
+
+	return r, nil
+}
@@ -0,0 +1,102 @@
+package repo
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+type auditSink interface {
+	Append(record entity.AuditRecord) error
+	Last(deckID string) (entity.AuditRecord, bool, error)
+	Records() ([]entity.AuditRecord, error)
+	Close() error
+}
+
+func TestAuditSinks(t *testing.T) {
+	when := time.Date(2024, time.March, 9, 15, 4, 5, 123456789, time.UTC)
+	records := []entity.AuditRecord{
+		{DeckID: "a", Seq: 1, Version: 1, Type: entity.DeckEventCreated, Time: when, Cards: []string{"AS", "2S"}, Hash: "h1"},
+		{DeckID: "b", Seq: 1, Version: 1, Type: entity.DeckEventCreated, Time: when, Cards: []string{"KH"}, Hash: "h2"},
+		{DeckID: "a", Seq: 2, Version: 2, Type: entity.DeckEventMoved, Time: when, Cards: []string{"AS"}, PlayerID: "p", PrevHash: "h1", Hash: "h3"},
+		{DeckID: "a", Seq: 3, Version: 4, Type: entity.DeckEventShuffled, Time: when, Cards: []string{}, PrevHash: "h3", Hash: "h4"},
+	}
+
+	tests := []struct {
+		name string
+		open func(dir string) (auditSink, error)
+	}{
+		{
+			name: "File",
+			open: func(dir string) (auditSink, error) {
+				return NewAuditFile(filepath.Join(dir, "audit.log"))
+			},
+		},
+		{
+			name: "SQLite",
+			open: func(dir string) (auditSink, error) {
+				return NewAuditSQLite(filepath.Join(dir, "audit.db"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			sink, err := tt.open(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range records {
+				if err := sink.Append(r); err != nil {
+					t.Fatalf("Append() | got error %v, want nil", err)
+				}
+			}
+			sink.Close()
+
+			// Records survive opening the sink again.
+			sink, err = tt.open(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sink.Close()
+
+			got, err := sink.Records()
+			if err != nil {
+				t.Fatalf("Records() | got error %v, want nil", err)
+			}
+			if diff := cmp.Diff(got, records); diff != "" {
+				t.Errorf("Records() | (-got +want):\n%s", diff)
+			}
+
+			last, ok, err := sink.Last("a")
+			if err != nil || !ok {
+				t.Fatalf("Last() | got %t and error %v", ok, err)
+			}
+			if diff := cmp.Diff(last, records[3]); diff != "" {
+				t.Errorf("Last() | (-got +want):\n%s", diff)
+			}
+
+			if _, ok, _ := sink.Last("c"); ok {
+				t.Errorf("Last() | found a record for an unknown deck")
+			}
+
+			// Records appended after opening it again are the last.
+			next := entity.AuditRecord{DeckID: "b", Seq: 2, Version: 2, Type: entity.DeckEventDrawn, Time: when, Cards: []string{"KH"}, PrevHash: "h2", Hash: "h5"}
+			if err := sink.Append(next); err != nil {
+				t.Fatalf("Append() | got error %v, want nil", err)
+			}
+			last, ok, err = sink.Last("b")
+			if err != nil || !ok {
+				t.Fatalf("Last() | got %t and error %v", ok, err)
+			}
+			if diff := cmp.Diff(last, next); diff != "" {
+				t.Errorf("Last() | (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// BlackjackTableNotFoundErr happens when a blackjack table
+// is not found in the repo.
+var BlackjackTableNotFoundErr = errors.New("blackjack table not found")
+
+// BlackjackTable repo, keeping each table within its
+// tenant. It's safe for concurrent use.
+type BlackjackTable struct {
+	tables games[entity.BlackjackTable]
+}
+
+// NewBlackjackTable creates a new BlackjackTable.
+func NewBlackjackTable() *BlackjackTable {
+	return &BlackjackTable{tables: newGames[entity.BlackjackTable]()}
+}
+
+// Save saves a blackjack table to the store, within its tenant.
+func (b *BlackjackTable) Save(table entity.BlackjackTable) {
+	b.tables.save(gameKey{table.Tenant, table.ID}, table)
+}
+
+// Get retrieves a blackjack table of tenant from its ID.
+func (b *BlackjackTable) Get(tenant, id string) (entity.BlackjackTable, error) {
+	table, ok := b.tables.get(gameKey{tenant, id})
+	if !ok {
+		return entity.BlackjackTable{}, fmt.Errorf("%w with ID %s", BlackjackTableNotFoundErr, id)
+	}
+	return table, nil
+}
+
+// Update runs fn on a blackjack table of tenant and saves
+// it when fn succeeds. The updates of a table run one at
+// a time.
+func (b *BlackjackTable) Update(tenant, id string, fn func(table *entity.BlackjackTable) error) (entity.BlackjackTable, error) {
+	table, ok, err := b.tables.update(gameKey{tenant, id}, fn)
+	if !ok {
+		return entity.BlackjackTable{}, fmt.Errorf("%w with ID %s", BlackjackTableNotFoundErr, id)
+	}
+	return table, err
+}
@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func TestBlackjackTable_SaveGet(t *testing.T) {
+	want := entity.BlackjackTable{
+		ID:       "id",
+		Tenant:   "acme",
+		ShoeID:   "shoe",
+		Decks:    6,
+		Bankroll: 100,
+		Status:   entity.BlackjackStatusBetting,
+	}
+
+	store := NewBlackjackTable()
+	store.Save(want)
+
+	got, err := store.Get("acme", want.ID)
+	if err != nil {
+		t.Fatalf("BlackjackTable.Get() | got error %v, want nil", err)
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("BlackjackTable.Get() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestBlackjackTable_Get_Error(t *testing.T) {
+	store := NewBlackjackTable()
+	_, err := store.Get("", "id")
+	if !errors.Is(err, BlackjackTableNotFoundErr) {
+		t.Errorf("BlackjackTable.Get() | got error %v, want %v", err, BlackjackTableNotFoundErr)
+	}
+
+	// A table of another tenant isn't found either.
+	store.Save(entity.BlackjackTable{ID: "id", Tenant: "acme"})
+	if _, err := store.Get("", "id"); !errors.Is(err, BlackjackTableNotFoundErr) {
+		t.Errorf("BlackjackTable.Get() | got error %v for another tenant, want %v", err, BlackjackTableNotFoundErr)
+	}
+}
+
+func TestBlackjackTable_Update(t *testing.T) {
+	store := NewBlackjackTable()
+	store.Save(entity.BlackjackTable{ID: "id", Tenant: "acme"})
+
+	// Updates of the same table run one at a time, so none
+	// of them is lost.
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := store.Update("acme", "id", func(table *entity.BlackjackTable) error {
+				table.Bankroll++
+				return nil
+			}); err != nil {
+				t.Errorf("BlackjackTable.Update() | got error %v, want nil", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, _ := store.Get("acme", "id"); got.Bankroll != 50 {
+		t.Errorf("BlackjackTable.Update() | got bankroll %d, want 50", got.Bankroll)
+	}
+
+	failed := errors.New("failed")
+	if _, err := store.Update("acme", "id", func(table *entity.BlackjackTable) error {
+		table.Bankroll = 0
+		return failed
+	}); !errors.Is(err, failed) {
+		t.Errorf("BlackjackTable.Update() | got error %v, want %v", err, failed)
+	}
+	if got, _ := store.Get("acme", "id"); got.Bankroll != 50 {
+		t.Errorf("BlackjackTable.Update() | got bankroll %d after a failed update, want 50", got.Bankroll)
+	}
+
+	if _, err := store.Update("", "id", func(*entity.BlackjackTable) error { return nil }); !errors.Is(err, BlackjackTableNotFoundErr) {
+		t.Errorf("BlackjackTable.Update() | got error %v for another tenant, want %v", err, BlackjackTableNotFoundErr)
+	}
+	if len(store.tables.locks) != 0 {
+		t.Errorf("BlackjackTable.Update() | got %d locks left, want 0", len(store.tables.locks))
+	}
+}
@@ -0,0 +1,38 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// BridgeDealNotFoundErr happens when a bridge deal
+// is not found in the repo.
+var BridgeDealNotFoundErr = errors.New("bridge deal not found")
+
+// BridgeDeal repo, keeping each deal within its tenant.
+// It's safe for concurrent use. Deals aren't changed once
+// dealt, so there is no Update.
+type BridgeDeal struct {
+	deals games[entity.BridgeDeal]
+}
+
+// NewBridgeDeal creates a new BridgeDeal.
+func NewBridgeDeal() *BridgeDeal {
+	return &BridgeDeal{deals: newGames[entity.BridgeDeal]()}
+}
+
+// Save saves a bridge deal to the store, within its tenant.
+func (b *BridgeDeal) Save(deal entity.BridgeDeal) {
+	b.deals.save(gameKey{deal.Tenant, deal.ID}, deal)
+}
+
+// Get retrieves a bridge deal of tenant from its ID.
+func (b *BridgeDeal) Get(tenant, id string) (entity.BridgeDeal, error) {
+	deal, ok := b.deals.get(gameKey{tenant, id})
+	if !ok {
+		return entity.BridgeDeal{}, fmt.Errorf("%w with ID %s", BridgeDealNotFoundErr, id)
+	}
+	return deal, nil
+}
@@ -0,0 +1,47 @@
+package repo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func TestBridgeDeal_SaveGet(t *testing.T) {
+	want := entity.BridgeDeal{
+		ID:         "id",
+		Tenant:     "acme",
+		Board:      3,
+		Dealer:     entity.BridgeSouth,
+		Vulnerable: entity.BridgeVulnerableEW,
+		Hands:      map[string][]entity.Card{entity.BridgeNorth: entity.DefaultCards[:13]},
+	}
+
+	store := NewBridgeDeal()
+	store.Save(want)
+
+	got, err := store.Get("acme", want.ID)
+	if err != nil {
+		t.Fatalf("BridgeDeal.Get() | got error %v, want nil", err)
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("BridgeDeal.Get() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestBridgeDeal_Get_Error(t *testing.T) {
+	store := NewBridgeDeal()
+	_, err := store.Get("", "id")
+	if !errors.Is(err, BridgeDealNotFoundErr) {
+		t.Errorf("BridgeDeal.Get() | got error %v, want %v", err, BridgeDealNotFoundErr)
+	}
+
+	// A deal of another tenant isn't found either.
+	store.Save(entity.BridgeDeal{ID: "id", Tenant: "acme"})
+	if _, err := store.Get("", "id"); !errors.Is(err, BridgeDealNotFoundErr) {
+		t.Errorf("BridgeDeal.Get() | got error %v for another tenant, want %v", err, BridgeDealNotFoundErr)
+	}
+}
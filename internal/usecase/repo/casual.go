@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// CasualGameNotFoundErr happens when a casual game
+// is not found in the repo.
+var CasualGameNotFoundErr = errors.New("casual game not found")
+
+// CasualGame repo, keeping each game within its tenant.
+// It's safe for concurrent use.
+type CasualGame struct {
+	games games[entity.CasualGame]
+}
+
+// NewCasualGame creates a new CasualGame.
+func NewCasualGame() *CasualGame {
+	return &CasualGame{games: newGames[entity.CasualGame]()}
+}
+
+// Save saves a casual game to the store, within its tenant.
+func (c *CasualGame) Save(game entity.CasualGame) {
+	c.games.save(gameKey{game.Tenant, game.ID}, game)
+}
+
+// Get retrieves a casual game of tenant from its ID.
+func (c *CasualGame) Get(tenant, id string) (entity.CasualGame, error) {
+	game, ok := c.games.get(gameKey{tenant, id})
+	if !ok {
+		return entity.CasualGame{}, fmt.Errorf("%w with ID %s", CasualGameNotFoundErr, id)
+	}
+	return game, nil
+}
+
+// Update runs fn on a casual game of tenant and saves it
+// when fn succeeds. The updates of a game run one at a
+// time.
+func (c *CasualGame) Update(tenant, id string, fn func(game *entity.CasualGame) error) (entity.CasualGame, error) {
+	game, ok, err := c.games.update(gameKey{tenant, id}, fn)
+	if !ok {
+		return entity.CasualGame{}, fmt.Errorf("%w with ID %s", CasualGameNotFoundErr, id)
+	}
+	return game, err
+}
@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func TestCasualGame_SaveGet(t *testing.T) {
+	want := entity.CasualGame{
+		ID:      "id",
+		Tenant:  "acme",
+		Kind:    entity.CasualGameWar,
+		Players: []entity.CasualPlayer{{ID: "a"}, {ID: "b"}},
+	}
+
+	store := NewCasualGame()
+	store.Save(want)
+
+	got, err := store.Get("acme", want.ID)
+	if err != nil {
+		t.Fatalf("CasualGame.Get() | got error %v, want nil", err)
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("CasualGame.Get() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestCasualGame_Get_Error(t *testing.T) {
+	store := NewCasualGame()
+	_, err := store.Get("", "id")
+	if !errors.Is(err, CasualGameNotFoundErr) {
+		t.Errorf("CasualGame.Get() | got error %v, want %v", err, CasualGameNotFoundErr)
+	}
+
+	// A game of another tenant isn't found either.
+	store.Save(entity.CasualGame{ID: "id", Tenant: "acme"})
+	if _, err := store.Get("", "id"); !errors.Is(err, CasualGameNotFoundErr) {
+		t.Errorf("CasualGame.Get() | got error %v for another tenant, want %v", err, CasualGameNotFoundErr)
+	}
+}
@@ -1,8 +1,10 @@
 package repo
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/lualfe/card-game/internal/entity"
 )
@@ -11,19 +13,156 @@ import (
 // in the repo.
 var DeckNotFoundErr = errors.New("deck not found")
 
-// Deck repo.
-type Deck map[string]entity.Deck
+// deckKey finds a deck within its tenant.
+type deckKey struct {
+	tenant string
+	id     string
+}
+
+// Deck repo. It's safe for concurrent use. Calls only
+// check the context before touching the map. The events
+// of the decks are kept in the DeckEvents Events returns.
+type Deck struct {
+	mu     sync.RWMutex
+	decks  map[deckKey]entity.Deck
+	counts map[string]int
+	events *DeckEvents
+}
+
+// NewDeck creates a new Deck.
+func NewDeck() *Deck {
+	return &Deck{
+		decks:  make(map[deckKey]entity.Deck),
+		counts: make(map[string]int),
+		events: NewDeckEvents(),
+	}
+}
+
+// Events returns the event store the decks write their
+// events to.
+func (d *Deck) Events() *DeckEvents {
+	return d.events
+}
+
+// Save saves a deck to the store, within its tenant, and
+// appends its events.
+func (d *Deck) Save(ctx context.Context, deck entity.Deck, events ...entity.DeckEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.save(deck, events)
+	return nil
+}
+
+// save saves a deck and appends its events. d.mu must be
+// held.
+func (d *Deck) save(deck entity.Deck, events []entity.DeckEvent) {
+	key := deckKey{deck.Tenant, deck.ID}
+	if _, ok := d.decks[key]; !ok {
+		d.counts[deck.Tenant]++
+	}
+	d.decks[key] = deck
+	d.events.append(events...)
+}
+
+// Get retrieves a deck of tenant from its ID.
+func (d *Deck) Get(ctx context.Context, tenant, id string) (entity.Deck, error) {
+	if err := ctx.Err(); err != nil {
+		return entity.Deck{}, err
+	}
 
-// Save saves a deck to the store.
-func (d Deck) Save(deck entity.Deck) {
-	d[deck.ID] = deck
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.get(tenant, id)
 }
 
-// Get retrieves a deck from its ID.
-func (d Deck) Get(id string) (entity.Deck, error) {
-	deck, ok := d[id]
+func (d *Deck) get(tenant, id string) (entity.Deck, error) {
+	deck, ok := d.decks[deckKey{tenant, id}]
 	if !ok {
 		return entity.Deck{}, fmt.Errorf("%w with ID %s", DeckNotFoundErr, id)
 	}
 	return deck, nil
 }
+
+// Update changes a deck of tenant with fn and saves it
+// with the events fn returns, unless fn fails. The store
+// is locked while fn runs.
+func (d *Deck) Update(ctx context.Context, tenant, id string, fn func(deck *entity.Deck) ([]entity.DeckEvent, error)) (entity.Deck, error) {
+	if err := ctx.Err(); err != nil {
+		return entity.Deck{}, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	deck, err := d.get(tenant, id)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	events, err := fn(&deck)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	deck.Tenant = tenant
+	d.decks[deckKey{tenant, id}] = deck
+	d.events.append(events...)
+	return deck, nil
+}
+
+// All returns every deck in the store, of every tenant.
+func (d *Deck) All(ctx context.Context) ([]entity.Deck, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	decks := make([]entity.Deck, 0, len(d.decks))
+	for _, deck := range d.decks {
+		decks = append(decks, deck)
+	}
+	return decks, nil
+}
+
+// Count returns how many decks of tenant the store holds.
+func (d *Deck) Count(ctx context.Context, tenant string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.counts[tenant], nil
+}
+
+// Delete removes a deck of tenant from the store.
+func (d *Deck) Delete(ctx context.Context, tenant, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.get(tenant, id); err != nil {
+		return err
+	}
+	d.remove(tenant, id)
+	return nil
+}
+
+// remove removes a deck the store holds. d.mu must be
+// held.
+func (d *Deck) remove(tenant, id string) {
+	delete(d.decks, deckKey{tenant, id})
+	if d.counts[tenant]--; d.counts[tenant] == 0 {
+		delete(d.counts, tenant)
+	}
+}
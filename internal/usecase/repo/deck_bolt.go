@@ -0,0 +1,334 @@
+package repo
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+var (
+	boltDecksBucket     = []byte("decks")
+	boltEventsBucket    = []byte("deck_events")
+	boltAPIKeysBucket   = []byte("api_keys")
+	boltAPIHashesBucket = []byte("api_key_hashes")
+	// boltTenantDecksBucket holds a bucket of decks for
+	// each tenant but the default one.
+	boltTenantDecksBucket = []byte("tenant_decks")
+	boltTenantsBucket     = []byte("tenants")
+)
+
+// OpenBolt opens the bbolt database at path, creating it
+// and its buckets when they don't exist.
+func OpenBolt(path string) (*bbolt.DB, error) {
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening bolt database: %w", err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, b := range [][]byte{boltDecksBucket, boltEventsBucket, boltAPIKeysBucket, boltAPIHashesBucket, boltTenantDecksBucket, boltTenantsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating bolt buckets: %w", err)
+	}
+
+	return db, nil
+}
+
+// BoltDeck is a deck store on bbolt, keeping each deck
+// under its ID in a compact binary form. Decks of the
+// default tenant are in the decks bucket, those of the
+// other tenants in a bucket per tenant, so a lookup can't
+// reach another tenant's decks. bbolt can't stop a
+// transaction half way, so calls only check the context
+// before starting one.
+type BoltDeck struct {
+	db *bbolt.DB
+}
+
+// NewBoltDeck creates a new BoltDeck.
+func NewBoltDeck(db *bbolt.DB) *BoltDeck {
+	return &BoltDeck{db: db}
+}
+
+// Save saves a deck to the store, within its tenant, and
+// appends its events in the same transaction.
+func (b *BoltDeck) Save(ctx context.Context, deck entity.Deck, events ...entity.DeckEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		if err := putBoltDeck(tx, deck); err != nil {
+			return err
+		}
+		return putBoltDeckEvents(tx, events...)
+	})
+}
+
+// Get retrieves a deck of tenant from its ID.
+func (b *BoltDeck) Get(ctx context.Context, tenant, id string) (entity.Deck, error) {
+	if err := ctx.Err(); err != nil {
+		return entity.Deck{}, err
+	}
+
+	var deck entity.Deck
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		deck, err = getBoltDeck(tx, tenant, id)
+		return err
+	})
+	return deck, err
+}
+
+// Update changes a deck of tenant with fn and saves it,
+// with the events fn returns, in a single transaction, so
+// concurrent updates never see the same state.
+func (b *BoltDeck) Update(ctx context.Context, tenant, id string, fn func(deck *entity.Deck) ([]entity.DeckEvent, error)) (entity.Deck, error) {
+	if err := ctx.Err(); err != nil {
+		return entity.Deck{}, err
+	}
+
+	var deck entity.Deck
+	err := b.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		if deck, err = getBoltDeck(tx, tenant, id); err != nil {
+			return err
+		}
+		events, err := fn(&deck)
+		if err != nil {
+			return err
+		}
+		deck.Tenant = tenant
+		if err := putBoltDeck(tx, deck); err != nil {
+			return err
+		}
+		return putBoltDeckEvents(tx, events...)
+	})
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	return deck, nil
+}
+
+// All returns every deck in the store, of every tenant.
+func (b *BoltDeck) All(ctx context.Context) ([]entity.Deck, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var decks []entity.Deck
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		all := func(tenant string, bucket *bbolt.Bucket) error {
+			return bucket.ForEach(func(k, v []byte) error {
+				deck, err := decodeDeck(string(k), v)
+				if err != nil {
+					return err
+				}
+				deck.Tenant = tenant
+				decks = append(decks, deck)
+				return nil
+			})
+		}
+
+		if err := all("", tx.Bucket(boltDecksBucket)); err != nil {
+			return err
+		}
+		tenants := tx.Bucket(boltTenantDecksBucket)
+		return tenants.ForEach(func(k, _ []byte) error {
+			return all(string(k), tenants.Bucket(k))
+		})
+	})
+	return decks, err
+}
+
+// Count returns how many decks of tenant the store holds.
+func (b *BoltDeck) Count(ctx context.Context, tenant string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	var n int
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		if bucket := boltDeckBucket(tx, tenant); bucket != nil {
+			n = bucket.Stats().KeyN
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Delete removes a deck of tenant from the store.
+func (b *BoltDeck) Delete(ctx context.Context, tenant, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		if _, err := getBoltDeck(tx, tenant, id); err != nil {
+			return err
+		}
+		return boltDeckBucket(tx, tenant).Delete([]byte(id))
+	})
+}
+
+// Ping checks the database can be read.
+func (b *BoltDeck) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket(boltDecksBucket) == nil {
+			return fmt.Errorf("bolt: no %s bucket", boltDecksBucket)
+		}
+		return nil
+	})
+}
+
+// boltDeckBucket returns the bucket of the decks of
+// tenant, nil when the tenant never had one.
+func boltDeckBucket(tx *bbolt.Tx, tenant string) *bbolt.Bucket {
+	if tenant == "" {
+		return tx.Bucket(boltDecksBucket)
+	}
+	return tx.Bucket(boltTenantDecksBucket).Bucket([]byte(tenant))
+}
+
+func getBoltDeck(tx *bbolt.Tx, tenant, id string) (entity.Deck, error) {
+	var v []byte
+	if bucket := boltDeckBucket(tx, tenant); bucket != nil {
+		v = bucket.Get([]byte(id))
+	}
+	if v == nil {
+		return entity.Deck{}, fmt.Errorf("%w with ID %s", DeckNotFoundErr, id)
+	}
+	deck, err := decodeDeck(id, v)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	deck.Tenant = tenant
+	return deck, nil
+}
+
+func putBoltDeck(tx *bbolt.Tx, deck entity.Deck) error {
+	v, err := encodeDeck(deck)
+	if err != nil {
+		return err
+	}
+
+	bucket := tx.Bucket(boltDecksBucket)
+	if deck.Tenant != "" {
+		if bucket, err = tx.Bucket(boltTenantDecksBucket).CreateBucketIfNotExists([]byte(deck.Tenant)); err != nil {
+			return err
+		}
+	}
+	return bucket.Put([]byte(deck.ID), v)
+}
+
+// BoltDeckEvents is a deck event store on bbolt, keeping
+// the events of each deck in a bucket keyed by version.
+type BoltDeckEvents struct {
+	db *bbolt.DB
+}
+
+// NewBoltDeckEvents creates a new BoltDeckEvents.
+func NewBoltDeckEvents(db *bbolt.DB) *BoltDeckEvents {
+	return &BoltDeckEvents{db: db}
+}
+
+// Append adds an event at the end of its deck stream.
+func (b *BoltDeckEvents) Append(ctx context.Context, event entity.DeckEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		return putBoltDeckEvents(tx, event)
+	})
+}
+
+func putBoltDeckEvents(tx *bbolt.Tx, events ...entity.DeckEvent) error {
+	for _, e := range events {
+		stream, err := tx.Bucket(boltEventsBucket).CreateBucketIfNotExists([]byte(e.DeckID))
+		if err != nil {
+			return err
+		}
+		v, err := encodeDeckEvent(e)
+		if err != nil {
+			return err
+		}
+		if err := stream.Put(boltVersionKey(e.Version), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Events retrieves the events of a deck, oldest first.
+func (b *BoltDeckEvents) Events(ctx context.Context, deckID string) ([]entity.DeckEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var events []entity.DeckEvent
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		stream := tx.Bucket(boltEventsBucket).Bucket([]byte(deckID))
+		if stream == nil {
+			return fmt.Errorf("%w with ID %s", DeckEventsNotFoundErr, deckID)
+		}
+		var err error
+		events, err = boltDeckEvents(deckID, stream)
+		return err
+	})
+	return events, err
+}
+
+// All returns the events of every deck, each deck
+// stream oldest first.
+func (b *BoltDeckEvents) All(ctx context.Context) ([]entity.DeckEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var events []entity.DeckEvent
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(boltEventsBucket).ForEach(func(k, _ []byte) error {
+			stream, err := boltDeckEvents(string(k), tx.Bucket(boltEventsBucket).Bucket(k))
+			if err != nil {
+				return err
+			}
+			events = append(events, stream...)
+			return nil
+		})
+	})
+	return events, err
+}
+
+func boltDeckEvents(deckID string, stream *bbolt.Bucket) ([]entity.DeckEvent, error) {
+	var events []entity.DeckEvent
+	err := stream.ForEach(func(k, v []byte) error {
+		e, err := decodeDeckEvent(deckID, int(binary.BigEndian.Uint64(k)), v)
+		if err != nil {
+			return err
+		}
+		events = append(events, e)
+		return nil
+	})
+	return events, err
+}
+
+// boltVersionKey keeps the events in version order, as
+// bbolt sorts keys bytewise.
+func boltVersionKey(version int) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(version))
+	return k
+}
@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func TestBoltDeck(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "decks.db")
+	db, err := OpenBolt(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := testDeckSnapshot(time.Date(2024, time.March, 9, 15, 4, 5, 0, time.UTC))
+	decks, events := NewBoltDeck(db), NewBoltDeckEvents(db)
+	for _, d := range snapshot.Decks {
+		if err := decks.Save(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, e := range snapshot.Events {
+		if err := events.Append(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	// Decks and events survive opening the database again.
+	db, err = OpenBolt(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	decks, events = NewBoltDeck(db), NewBoltDeckEvents(db)
+
+	got, err := decks.Get(ctx, "", "id")
+	if err != nil {
+		t.Fatalf("BoltDeck.Get() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(got, snapshot.Decks[0]); diff != "" {
+		t.Errorf("BoltDeck.Get() | (-got +want):\n%s", diff)
+	}
+	all, err := decks.All(ctx)
+	if err != nil {
+		t.Fatalf("BoltDeck.All() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(all, snapshot.Decks); diff != "" {
+		t.Errorf("BoltDeck.All() | (-got +want):\n%s", diff)
+	}
+
+	gotEvents, err := events.Events(ctx, "id")
+	if err != nil {
+		t.Fatalf("BoltDeckEvents.Events() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(gotEvents, snapshot.Events); diff != "" {
+		t.Errorf("BoltDeckEvents.Events() | (-got +want):\n%s", diff)
+	}
+	allEvents, err := events.All(ctx)
+	if err != nil {
+		t.Fatalf("BoltDeckEvents.All() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(allEvents, snapshot.Events); diff != "" {
+		t.Errorf("BoltDeckEvents.All() | (-got +want):\n%s", diff)
+	}
+
+	if _, err := decks.Get(ctx, "", "other"); !errors.Is(err, DeckNotFoundErr) {
+		t.Errorf("BoltDeck.Get() | got error %v, want %v", err, DeckNotFoundErr)
+	}
+	if _, err := events.Events(ctx, "other"); !errors.Is(err, DeckEventsNotFoundErr) {
+		t.Errorf("BoltDeckEvents.Events() | got error %v, want %v", err, DeckEventsNotFoundErr)
+	}
+}
+
+func TestBoltDeck_Update(t *testing.T) {
+	ctx := context.Background()
+	db, err := OpenBolt(filepath.Join(t.TempDir(), "decks.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	decks := NewBoltDeck(db)
+	full := append([]entity.Card{}, entity.DefaultCards...)
+	if err := decks.Save(ctx, entity.Deck{ID: "id", Remaining: len(full), Cards: full}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent draws never get the same card.
+	drawn := make(chan entity.Card, len(full))
+	var wg sync.WaitGroup
+	for i := 0; i < len(full); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := decks.Update(ctx, "", "id", func(deck *entity.Deck) ([]entity.DeckEvent, error) {
+				drawn <- deck.Cards[0]
+				deck.Cards = deck.Cards[1:]
+				deck.Remaining = len(deck.Cards)
+				return nil, nil
+			})
+			if err != nil {
+				t.Errorf("BoltDeck.Update() | got error %v, want nil", err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(drawn)
+
+	seen := make(map[string]bool)
+	for c := range drawn {
+		if seen[c.Code] {
+			t.Errorf("BoltDeck.Update() | %s drawn twice", c.Code)
+		}
+		seen[c.Code] = true
+	}
+	if got, _ := decks.Get(ctx, "", "id"); got.Remaining != 0 || got.Cards == nil {
+		t.Errorf("BoltDeck.Update() | got %d cards left (%v), want an empty deck", got.Remaining, got.Cards)
+	}
+
+	// A failing update leaves the deck untouched.
+	fnErr := errors.New("fail")
+	if _, err := decks.Update(ctx, "", "id", func(deck *entity.Deck) ([]entity.DeckEvent, error) {
+		deck.Shuffled = true
+		return nil, fnErr
+	}); !errors.Is(err, fnErr) {
+		t.Errorf("BoltDeck.Update() | got error %v, want %v", err, fnErr)
+	}
+	if got, _ := decks.Get(ctx, "", "id"); got.Shuffled {
+		t.Error("BoltDeck.Update() | failed update was saved")
+	}
+}
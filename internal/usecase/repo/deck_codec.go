@@ -0,0 +1,244 @@
+package repo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// DeckCodecErr happens when a deck or event can't be
+// encoded to or decoded from its compact form.
+var DeckCodecErr = errors.New("invalid deck encoding")
+
+// cardIndexes maps each card code to its position in
+// entity.DefaultCards, which is how cards are encoded.
+var cardIndexes = func() map[string]byte {
+	indexes := make(map[string]byte, len(entity.DefaultCards))
+	for i, c := range entity.DefaultCards {
+		indexes[c.Code] = byte(i)
+	}
+	return indexes
+}()
+
+// deckEventTypes are the event types by their encoded byte.
+var deckEventTypes = []string{
+	entity.DeckEventCreated,
+	entity.DeckEventShuffled,
+	entity.DeckEventDrawn,
+	entity.DeckEventReturned,
+	entity.DeckEventMoved,
+}
+
+// encodeDeck writes a deck as a flags byte, its version,
+// its cards, its hands and its owner. The ID is the key
+// it's kept under and Remaining is the number of cards.
+func encodeDeck(deck entity.Deck) ([]byte, error) {
+	var e deckEncoder
+	var flags byte
+	if deck.Shuffled {
+		flags = 1
+	}
+	e.buf = append(e.buf, flags)
+	e.uvarint(uint64(deck.Version))
+	e.cards(deck.Cards)
+	e.uvarint(uint64(len(deck.Hands)))
+	for _, h := range deck.Hands {
+		e.string(h.PlayerID)
+		e.string(h.Token)
+		e.cards(h.Cards)
+	}
+	e.string(deck.Owner)
+	return e.buf, e.err
+}
+
+func decodeDeck(id string, data []byte) (entity.Deck, error) {
+	d := deckDecoder{buf: data}
+	deck := entity.Deck{
+		ID:       id,
+		Shuffled: d.byte() == 1,
+		Version:  int(d.uvarint()),
+		Cards:    d.cards(),
+	}
+	deck.Remaining = len(deck.Cards)
+	for n := d.uvarint(); n > 0 && d.err == nil; n-- {
+		deck.Hands = append(deck.Hands, entity.Hand{
+			PlayerID: d.string(),
+			Token:    d.string(),
+			Cards:    d.cards(),
+		})
+	}
+	// Decks kept before they had owners end with their hands.
+	if d.more() {
+		deck.Owner = d.string()
+	}
+	if d.err != nil {
+		return entity.Deck{}, fmt.Errorf("deck %s: %w", id, d.err)
+	}
+	return deck, nil
+}
+
+// encodeDeckEvent writes an event as its type byte, its
+// time, its cards, player, token, owner, actor and tenant.
+// The deck ID and the version are the keys it's kept under.
+func encodeDeckEvent(event entity.DeckEvent) ([]byte, error) {
+	var e deckEncoder
+	t := -1
+	for i, typ := range deckEventTypes {
+		if typ == event.Type {
+			t = i
+		}
+	}
+	if t < 0 {
+		return nil, fmt.Errorf("%w: event type %s", DeckCodecErr, event.Type)
+	}
+	e.buf = append(e.buf, byte(t))
+	e.varint(event.Time.UnixNano())
+	e.cards(event.Cards)
+	e.string(event.PlayerID)
+	e.string(event.Token)
+	e.string(event.Owner)
+	e.string(event.Actor)
+	e.string(event.Tenant)
+	return e.buf, e.err
+}
+
+func decodeDeckEvent(deckID string, version int, data []byte) (entity.DeckEvent, error) {
+	d := deckDecoder{buf: data}
+	event := entity.DeckEvent{DeckID: deckID, Version: version}
+	if t := int(d.byte()); t < len(deckEventTypes) {
+		event.Type = deckEventTypes[t]
+	} else {
+		d.fail()
+	}
+	event.Time = time.Unix(0, d.varint()).UTC()
+	event.Cards = d.cards()
+	event.PlayerID = d.string()
+	event.Token = d.string()
+	if d.more() {
+		event.Owner = d.string()
+	}
+	if d.more() {
+		event.Actor = d.string()
+	}
+	if d.more() {
+		event.Tenant = d.string()
+	}
+	if d.err != nil {
+		return entity.DeckEvent{}, fmt.Errorf("deck %s version %d: %w", deckID, version, d.err)
+	}
+	return event, nil
+}
+
+type deckEncoder struct {
+	buf []byte
+	err error
+}
+
+func (e *deckEncoder) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	e.buf = append(e.buf, b[:binary.PutUvarint(b[:], v)]...)
+}
+
+func (e *deckEncoder) varint(v int64) {
+	var b [binary.MaxVarintLen64]byte
+	e.buf = append(e.buf, b[:binary.PutVarint(b[:], v)]...)
+}
+
+func (e *deckEncoder) string(s string) {
+	e.uvarint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *deckEncoder) cards(cards []entity.Card) {
+	e.uvarint(uint64(len(cards)))
+	for _, c := range cards {
+		i, ok := cardIndexes[c.Code]
+		if !ok && e.err == nil {
+			e.err = fmt.Errorf("%w: card %s", DeckCodecErr, c.Code)
+		}
+		e.buf = append(e.buf, i)
+	}
+}
+
+// deckDecoder reads what deckEncoder wrote, keeping the
+// first error and returning zero values after it.
+type deckDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *deckDecoder) fail() {
+	if d.err == nil {
+		d.err = DeckCodecErr
+	}
+	d.buf = nil
+}
+
+// more tells whether there's anything left to read.
+func (d *deckDecoder) more() bool {
+	return len(d.buf) > 0
+}
+
+func (d *deckDecoder) byte() byte {
+	if len(d.buf) < 1 {
+		d.fail()
+		return 0
+	}
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b
+}
+
+func (d *deckDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *deckDecoder) varint() int64 {
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *deckDecoder) bytes() []byte {
+	n := d.uvarint()
+	if uint64(len(d.buf)) < n {
+		d.fail()
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *deckDecoder) string() string {
+	return string(d.bytes())
+}
+
+func (d *deckDecoder) cards() []entity.Card {
+	indexes := d.bytes()
+	if d.err != nil {
+		return nil
+	}
+	cards := make([]entity.Card, len(indexes))
+	for i, c := range indexes {
+		if int(c) >= len(entity.DefaultCards) {
+			d.fail()
+			return nil
+		}
+		cards[i] = entity.DefaultCards[c]
+	}
+	return cards
+}
@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func TestEncodeDeck(t *testing.T) {
+	deck := entity.Deck{
+		ID:        "id",
+		Shuffled:  true,
+		Remaining: len(entity.DefaultCards),
+		Cards:     entity.DefaultCards,
+		Version:   2,
+	}
+
+	data, err := encodeDeck(deck)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Flags, version, card count, one byte per card, hand
+	// count and owner length.
+	if want := 5 + len(entity.DefaultCards); len(data) != want {
+		t.Errorf("encodeDeck() | got %d bytes, want %d", len(data), want)
+	}
+
+	// Decks kept before they had owners still decode.
+	old, err := decodeDeck("id", data[:len(data)-1])
+	if err != nil {
+		t.Fatalf("decodeDeck() | got error %v on a deck without owner, want nil", err)
+	}
+	if old.Version != deck.Version || old.Remaining != deck.Remaining || old.Owner != "" {
+		t.Errorf("decodeDeck() | got version %d, %d cards, owner %q, want %d, %d, \"\"", old.Version, old.Remaining, old.Owner, deck.Version, deck.Remaining)
+	}
+
+	if _, err := encodeDeck(entity.Deck{Cards: []entity.Card{{Code: "ZZ"}}}); !errors.Is(err, DeckCodecErr) {
+		t.Errorf("encodeDeck() | got error %v, want %v", err, DeckCodecErr)
+	}
+	if _, err := decodeDeck("id", data[:10]); !errors.Is(err, DeckCodecErr) {
+		t.Errorf("decodeDeck() | got error %v, want %v", err, DeckCodecErr)
+	}
+}
+
+func TestEncodeDeckEvent(t *testing.T) {
+	event := entity.DeckEvent{
+		DeckID:  "id",
+		Version: 3,
+		Type:    entity.DeckEventDrawn,
+		Time:    time.Unix(0, 42).UTC(),
+		Cards:   entity.DefaultCards[:2],
+		Actor:   "alice",
+		Tenant:  "acme",
+	}
+
+	data, err := encodeDeckEvent(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decodeDeckEvent("id", 3, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Actor != event.Actor || got.Tenant != event.Tenant || got.Type != event.Type || len(got.Cards) != 2 {
+		t.Errorf("decodeDeckEvent() | got %+v, want %+v", got, event)
+	}
+
+	// Events kept before they had actors and tenants still
+	// decode.
+	old, err := decodeDeckEvent("id", 3, data[:len(data)-len(event.Actor)-len(event.Tenant)-2])
+	if err != nil {
+		t.Fatalf("decodeDeckEvent() | got error %v on an event without actor, want nil", err)
+	}
+	if old.Actor != "" || old.Tenant != "" || old.Type != event.Type {
+		t.Errorf("decodeDeckEvent() | got type %s, actor %q, tenant %q, want %s, \"\", \"\"", old.Type, old.Actor, old.Tenant, event.Type)
+	}
+}
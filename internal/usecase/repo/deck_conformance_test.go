@@ -0,0 +1,73 @@
+package repo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+	"github.com/lualfe/card-game/internal/usecase/repo/repotest"
+)
+
+func TestDeck_Conformance(t *testing.T) {
+	repotest.DeckRepo(t, func(t *testing.T) (usecase.DeckRepo, usecase.DeckEventRepo) {
+		store := repo.NewDeck()
+		return store, store.Events()
+	})
+}
+
+func TestWALDeck_Conformance(t *testing.T) {
+	repotest.DeckRepo(t, func(t *testing.T) (usecase.DeckRepo, usecase.DeckEventRepo) {
+		wal, err := repo.OpenDeckWAL(t.TempDir(), repo.WALSyncNever, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { wal.Close() })
+		store := repo.NewDeck()
+		return repo.NewWALDeck(store, wal), store.Events()
+	})
+}
+
+func TestBoltDeck_Conformance(t *testing.T) {
+	repotest.DeckRepo(t, func(t *testing.T) (usecase.DeckRepo, usecase.DeckEventRepo) {
+		db, err := repo.OpenBolt(filepath.Join(t.TempDir(), "decks.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		return repo.NewBoltDeck(db), repo.NewBoltDeckEvents(db)
+	})
+}
+
+func TestRedisDeck_Conformance(t *testing.T) {
+	repotest.DeckRepo(t, func(t *testing.T) (usecase.DeckRepo, usecase.DeckEventRepo) {
+		client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+		t.Cleanup(func() { client.Close() })
+		return repo.NewRedisDeck(client), repo.NewRedisDeckEvents(client)
+	})
+}
+
+// TestPostgresDeck_Conformance needs DECK_POSTGRES_TEST_DSN
+// and wipes the decks of that database.
+func TestPostgresDeck_Conformance(t *testing.T) {
+	dsn := os.Getenv("DECK_POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("DECK_POSTGRES_TEST_DSN not set")
+	}
+
+	repotest.DeckRepo(t, func(t *testing.T) (usecase.DeckRepo, usecase.DeckEventRepo) {
+		db, err := repo.OpenPostgres(dsn, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		if _, err := db.Exec("TRUNCATE decks, deck_events"); err != nil {
+			t.Fatal(err)
+		}
+		return repo.NewPostgresDeck(db), repo.NewPostgresDeckEvents(db)
+	})
+}
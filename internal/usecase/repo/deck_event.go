@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// DeckEventsNotFoundErr happens when a deck has no
+// events in the repo.
+var DeckEventsNotFoundErr = errors.New("deck events not found")
+
+// DeckEvents repo. Events are only ever appended. It's
+// safe for concurrent use.
+type DeckEvents struct {
+	mu      sync.RWMutex
+	streams map[string][]entity.DeckEvent
+}
+
+// NewDeckEvents creates a new DeckEvents.
+func NewDeckEvents() *DeckEvents {
+	return &DeckEvents{streams: make(map[string][]entity.DeckEvent)}
+}
+
+// Append adds an event at the end of its deck stream.
+func (d *DeckEvents) Append(ctx context.Context, event entity.DeckEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d.append(event)
+	return nil
+}
+
+func (d *DeckEvents) append(events ...entity.DeckEvent) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, e := range events {
+		d.streams[e.DeckID] = append(d.streams[e.DeckID], e)
+	}
+}
+
+// Events retrieves the events of a deck, oldest first.
+func (d *DeckEvents) Events(_ context.Context, deckID string) ([]entity.DeckEvent, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	events, ok := d.streams[deckID]
+	if !ok {
+		return nil, fmt.Errorf("%w with ID %s", DeckEventsNotFoundErr, deckID)
+	}
+	return append([]entity.DeckEvent{}, events...), nil
+}
+
+// All returns the events of every deck, each deck
+// stream oldest first.
+func (d *DeckEvents) All(context.Context) ([]entity.DeckEvent, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var events []entity.DeckEvent
+	for _, stream := range d.streams {
+		events = append(events, stream...)
+	}
+	return events, nil
+}
+
+// version returns the version of the last event of a deck,
+// or zero without events.
+func (d *DeckEvents) version(deckID string) int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	stream := d.streams[deckID]
+	if len(stream) == 0 {
+		return 0
+	}
+	return stream[len(stream)-1].Version
+}
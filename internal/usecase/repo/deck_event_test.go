@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func TestDeckEvents_AppendEvents(t *testing.T) {
+	ctx := context.Background()
+	want := []entity.DeckEvent{
+		{DeckID: "id", Version: 1, Type: entity.DeckEventCreated, Cards: entity.DefaultCards[:2]},
+		{DeckID: "id", Version: 2, Type: entity.DeckEventDrawn, Cards: entity.DefaultCards[:1]},
+	}
+
+	store := NewDeckEvents()
+	for _, e := range want {
+		if err := store.Append(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Append(ctx, entity.DeckEvent{DeckID: "other", Version: 1, Type: entity.DeckEventCreated}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.Events(ctx, "id")
+	if err != nil {
+		t.Fatalf("DeckEvents.Events() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("DeckEvents.Events() | (-got +want):\n%s", diff)
+	}
+
+	// Changing the returned events doesn't change the stream.
+	got[0].Type = entity.DeckEventShuffled
+	if again, _ := store.Events(ctx, "id"); again[0].Type != entity.DeckEventCreated {
+		t.Errorf("DeckEvents.Events() | stream changed through returned events")
+	}
+}
+
+func TestDeckEvents_Events_Error(t *testing.T) {
+	store := NewDeckEvents()
+	_, err := store.Events(context.Background(), "id")
+	if !errors.Is(err, DeckEventsNotFoundErr) {
+		t.Errorf("DeckEvents.Events() | got error %v, want %v", err, DeckEventsNotFoundErr)
+	}
+}
@@ -0,0 +1,377 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// Registers the postgres driver.
+	_ "github.com/lib/pq"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+//go:embed migrations/postgres/*.sql
+var postgresMigrations embed.FS
+
+// postgresMigrationLock is the advisory lock instances
+// take so only one of them migrates at a time.
+const postgresMigrationLock = 7340121
+
+// postgresTimeout bounds every query, on top of the
+// deadline of the caller's context.
+const postgresTimeout = 5 * time.Second
+
+// OpenPostgres connects to the database at dsn with a pool
+// of at most maxConns connections and applies the
+// migrations it's missing.
+func OpenPostgres(dsn string, maxConns int) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening postgres database: %w", err)
+	}
+	db.SetMaxOpenConns(maxConns)
+	db.SetMaxIdleConns(maxConns)
+	db.SetConnMaxIdleTime(5 * time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connecting to postgres: %w", err)
+	}
+	if err := migratePostgres(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// migratePostgres applies, in order and in a single
+// transaction, the migrations not yet recorded in
+// schema_migrations. A migration's version is the number
+// its file name starts with.
+func migratePostgres(ctx context.Context, db *sql.DB) error {
+	files, err := fs.Glob(postgresMigrations, "migrations/postgres/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migrating postgres: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", postgresMigrationLock); err != nil {
+		return fmt.Errorf("migrating postgres: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version    INTEGER     PRIMARY KEY,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`); err != nil {
+		return fmt.Errorf("migrating postgres: %w", err)
+	}
+
+	for _, file := range files {
+		name := file[strings.LastIndex(file, "/")+1:]
+		version, err := strconv.Atoi(strings.SplitN(name, "_", 2)[0])
+		if err != nil {
+			return fmt.Errorf("migrating postgres: %s has no version", name)
+		}
+
+		var applied bool
+		err = tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", version).Scan(&applied)
+		if err != nil {
+			return fmt.Errorf("migrating postgres: %w", err)
+		}
+		if applied {
+			continue
+		}
+
+		migration, err := postgresMigrations.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, string(migration)); err != nil {
+			return fmt.Errorf("migrating postgres: %s: %w", name, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", version); err != nil {
+			return fmt.Errorf("migrating postgres: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// PostgresDeck is a deck store on PostgreSQL, keeping
+// each deck in its compact form under its tenant and ID.
+type PostgresDeck struct {
+	db *sql.DB
+}
+
+// NewPostgresDeck creates a new PostgresDeck.
+func NewPostgresDeck(db *sql.DB) *PostgresDeck {
+	return &PostgresDeck{db: db}
+}
+
+// Save saves a deck to the store, within its tenant, and
+// appends its events in the same transaction.
+func (p *PostgresDeck) Save(ctx context.Context, deck entity.Deck, events ...entity.DeckEvent) error {
+	data, err := encodeDeck(deck)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO decks (tenant, id, version, data) VALUES ($1, $2, $3, $4)
+ON CONFLICT (tenant, id) DO UPDATE SET version = EXCLUDED.version, data = EXCLUDED.data, updated_at = now()`,
+		deck.Tenant, deck.ID, deck.Version, data)
+	if err != nil {
+		return err
+	}
+	if err := insertPostgresDeckEvents(ctx, tx, events...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Get retrieves a deck of tenant from its ID.
+func (p *PostgresDeck) Get(ctx context.Context, tenant, id string) (entity.Deck, error) {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	return getPostgresDeck(ctx, p.db, tenant, id, "")
+}
+
+type postgresQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type postgresExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func getPostgresDeck(ctx context.Context, q postgresQueryer, tenant, id, lock string) (entity.Deck, error) {
+	var data []byte
+	err := q.QueryRowContext(ctx, "SELECT data FROM decks WHERE tenant = $1 AND id = $2 "+lock, tenant, id).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Deck{}, fmt.Errorf("%w with ID %s", DeckNotFoundErr, id)
+		}
+		return entity.Deck{}, err
+	}
+	deck, err := decodeDeck(id, data)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	deck.Tenant = tenant
+	return deck, nil
+}
+
+// Update changes a deck of tenant with fn and saves it,
+// with the events fn returns, in a single transaction,
+// holding the deck row lock so concurrent updates wait for
+// each other.
+func (p *PostgresDeck) Update(ctx context.Context, tenant, id string, fn func(deck *entity.Deck) ([]entity.DeckEvent, error)) (entity.Deck, error) {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	defer tx.Rollback()
+
+	deck, err := getPostgresDeck(ctx, tx, tenant, id, "FOR UPDATE")
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	events, err := fn(&deck)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	deck.Tenant = tenant
+	data, err := encodeDeck(deck)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE decks SET version = $3, data = $4, updated_at = now() WHERE tenant = $1 AND id = $2", tenant, id, deck.Version, data)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	if err := insertPostgresDeckEvents(ctx, tx, events...); err != nil {
+		return entity.Deck{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return entity.Deck{}, err
+	}
+
+	return deck, nil
+}
+
+// All returns every deck in the store, of every tenant.
+func (p *PostgresDeck) All(ctx context.Context) ([]entity.Deck, error) {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, "SELECT tenant, id, data FROM decks ORDER BY tenant, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var decks []entity.Deck
+	for rows.Next() {
+		var (
+			tenant, id string
+			data       []byte
+		)
+		if err := rows.Scan(&tenant, &id, &data); err != nil {
+			return decks, err
+		}
+		deck, err := decodeDeck(id, data)
+		if err != nil {
+			return decks, err
+		}
+		deck.Tenant = tenant
+		decks = append(decks, deck)
+	}
+	return decks, rows.Err()
+}
+
+// Count returns how many decks of tenant the store holds.
+func (p *PostgresDeck) Count(ctx context.Context, tenant string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	var n int
+	err := p.db.QueryRowContext(ctx, "SELECT count(*) FROM decks WHERE tenant = $1", tenant).Scan(&n)
+	return n, err
+}
+
+// Delete removes a deck of tenant from the store.
+func (p *PostgresDeck) Delete(ctx context.Context, tenant, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	res, err := p.db.ExecContext(ctx, "DELETE FROM decks WHERE tenant = $1 AND id = $2", tenant, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w with ID %s", DeckNotFoundErr, id)
+	}
+	return nil
+}
+
+// Ping checks the database answers.
+func (p *PostgresDeck) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	return p.db.PingContext(ctx)
+}
+
+// PostgresDeckEvents is a deck event store on PostgreSQL.
+type PostgresDeckEvents struct {
+	db *sql.DB
+}
+
+// NewPostgresDeckEvents creates a new PostgresDeckEvents.
+func NewPostgresDeckEvents(db *sql.DB) *PostgresDeckEvents {
+	return &PostgresDeckEvents{db: db}
+}
+
+// Append adds an event at the end of its deck stream.
+func (p *PostgresDeckEvents) Append(ctx context.Context, event entity.DeckEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	return insertPostgresDeckEvents(ctx, p.db, event)
+}
+
+func insertPostgresDeckEvents(ctx context.Context, e postgresExecer, events ...entity.DeckEvent) error {
+	for _, event := range events {
+		data, err := encodeDeckEvent(event)
+		if err != nil {
+			return err
+		}
+		_, err = e.ExecContext(ctx, "INSERT INTO deck_events (deck_id, version, data) VALUES ($1, $2, $3)", event.DeckID, event.Version, data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Events retrieves the events of a deck, oldest first.
+func (p *PostgresDeckEvents) Events(ctx context.Context, deckID string) ([]entity.DeckEvent, error) {
+	events, err := p.query(ctx, "WHERE deck_id = $1", deckID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w with ID %s", DeckEventsNotFoundErr, deckID)
+	}
+	return events, nil
+}
+
+// All returns the events of every deck, each deck
+// stream oldest first.
+func (p *PostgresDeckEvents) All(ctx context.Context) ([]entity.DeckEvent, error) {
+	return p.query(ctx, "")
+}
+
+func (p *PostgresDeckEvents) query(ctx context.Context, where string, args ...interface{}) ([]entity.DeckEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, "SELECT deck_id, version, data FROM deck_events "+where+" ORDER BY deck_id, version", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []entity.DeckEvent
+	for rows.Next() {
+		var (
+			deckID  string
+			version int
+			data    []byte
+		)
+		if err := rows.Scan(&deckID, &version, &data); err != nil {
+			return events, err
+		}
+		e, err := decodeDeckEvent(deckID, version, data)
+		if err != nil {
+			return events, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,97 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func TestPostgresDeck(t *testing.T) {
+	dsn := os.Getenv("DECK_POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("DECK_POSTGRES_TEST_DSN not set")
+	}
+
+	// Migrations apply once, however many times it's opened.
+	for i := 0; i < 2; i++ {
+		db, err := OpenPostgres(dsn, 4)
+		if err != nil {
+			t.Fatalf("OpenPostgres() | got error %v, want nil", err)
+		}
+		db.Close()
+	}
+
+	db, err := OpenPostgres(dsn, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("TRUNCATE decks, deck_events"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Events", func(t *testing.T) {
+		ctx := context.Background()
+		events := NewPostgresDeckEvents(db)
+		snapshot := testDeckSnapshot(time.Date(2024, time.March, 9, 15, 4, 5, 0, time.UTC))
+		for _, e := range snapshot.Events {
+			if err := events.Append(ctx, e); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got, err := events.Events(ctx, "id")
+		if err != nil {
+			t.Fatalf("PostgresDeckEvents.Events() | got error %v, want nil", err)
+		}
+		if diff := cmp.Diff(got, snapshot.Events); diff != "" {
+			t.Errorf("PostgresDeckEvents.Events() | (-got +want):\n%s", diff)
+		}
+		if _, err := events.Events(ctx, "other"); !errors.Is(err, DeckEventsNotFoundErr) {
+			t.Errorf("PostgresDeckEvents.Events() | got error %v, want %v", err, DeckEventsNotFoundErr)
+		}
+	})
+
+	t.Run("Concurrent Draws", func(t *testing.T) {
+		decks := NewPostgresDeck(db)
+		full := append([]entity.Card{}, entity.DefaultCards...)
+		if err := decks.Save(context.Background(), entity.Deck{ID: "draws", Remaining: len(full), Cards: full}); err != nil {
+			t.Fatal(err)
+		}
+
+		drawn := make(chan entity.Card, len(full))
+		var wg sync.WaitGroup
+		for i := 0; i < len(full); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := decks.Update(context.Background(), "", "draws", func(deck *entity.Deck) ([]entity.DeckEvent, error) {
+					drawn <- deck.Cards[0]
+					deck.Cards = deck.Cards[1:]
+					deck.Remaining = len(deck.Cards)
+					return nil, nil
+				})
+				if err != nil {
+					t.Errorf("PostgresDeck.Update() | got error %v, want nil", err)
+				}
+			}()
+		}
+		wg.Wait()
+		close(drawn)
+
+		seen := make(map[string]bool)
+		for c := range drawn {
+			if seen[c.Code] {
+				t.Errorf("PostgresDeck.Update() | %s drawn twice", c.Code)
+			}
+			seen[c.Code] = true
+		}
+	})
+}
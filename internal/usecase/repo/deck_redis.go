@@ -0,0 +1,309 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// DeckConflictErr happens when a deck keeps changing
+// under an update until it runs out of retries.
+var DeckConflictErr = errors.New("deck changed concurrently")
+
+const (
+	redisDeckTenantsKey  = "deck_tenants"
+	redisEventDeckIDsKey = "deck_events"
+
+	// redisUpdateRetries bounds how many times an update is
+	// retried when another instance changes the deck first.
+	redisUpdateRetries = 50
+)
+
+// redisDeckKey is where a deck of tenant is kept. Decks of
+// the default tenant keep the keys they had before there
+// were tenants.
+func redisDeckKey(tenant, id string) string {
+	if tenant == "" {
+		return "deck:" + id
+	}
+	return "tenant:" + tenant + ":deck:" + id
+}
+
+// redisDeckIDsKey is the set of the deck IDs of tenant.
+func redisDeckIDsKey(tenant string) string {
+	if tenant == "" {
+		return "decks"
+	}
+	return "tenant:" + tenant + ":decks"
+}
+
+// redisDeckEventsKey is the hash of the events of a deck,
+// each under its version.
+func redisDeckEventsKey(deckID string) string {
+	return "deck:" + deckID + ":event_versions"
+}
+
+// RedisDeck is a deck store on Redis, shared by every
+// instance of the application. Decks are kept in their
+// compact form under deck:<id>, or tenant:<tenant>:deck:<id>
+// for the decks of a tenant, and their IDs in a set per
+// tenant. The tenants with decks are kept in a set too.
+type RedisDeck struct {
+	client redis.UniversalClient
+}
+
+// NewRedisDeck creates a new RedisDeck.
+func NewRedisDeck(client redis.UniversalClient) *RedisDeck {
+	return &RedisDeck{client: client}
+}
+
+// Save saves a deck to the store, within its tenant, and
+// appends its events in the same transaction.
+func (r *RedisDeck) Save(ctx context.Context, deck entity.Deck, events ...entity.DeckEvent) error {
+	v, err := encodeDeck(deck)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.Set(ctx, redisDeckKey(deck.Tenant, deck.ID), v, 0)
+		p.SAdd(ctx, redisDeckIDsKey(deck.Tenant), deck.ID)
+		if deck.Tenant != "" {
+			p.SAdd(ctx, redisDeckTenantsKey, deck.Tenant)
+		}
+		return queueRedisDeckEvents(ctx, p, events...)
+	})
+	return err
+}
+
+// Get retrieves a deck of tenant from its ID.
+func (r *RedisDeck) Get(ctx context.Context, tenant, id string) (entity.Deck, error) {
+	return getRedisDeck(ctx, r.client, tenant, id)
+}
+
+type redisGetter interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+}
+
+func getRedisDeck(ctx context.Context, client redisGetter, tenant, id string) (entity.Deck, error) {
+	v, err := client.Get(ctx, redisDeckKey(tenant, id)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return entity.Deck{}, fmt.Errorf("%w with ID %s", DeckNotFoundErr, id)
+		}
+		return entity.Deck{}, err
+	}
+	deck, err := decodeDeck(id, v)
+	if err != nil {
+		return entity.Deck{}, err
+	}
+	deck.Tenant = tenant
+	return deck, nil
+}
+
+// Update changes a deck of tenant with fn and saves it with
+// the events fn returns, in one transaction, watching the
+// deck so a change from another instance in between makes
+// the update start over.
+func (r *RedisDeck) Update(ctx context.Context, tenant, id string, fn func(deck *entity.Deck) ([]entity.DeckEvent, error)) (entity.Deck, error) {
+	key := redisDeckKey(tenant, id)
+
+	for i := 0; i < redisUpdateRetries; i++ {
+		var deck entity.Deck
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			var err error
+			if deck, err = getRedisDeck(ctx, tx, tenant, id); err != nil {
+				return err
+			}
+			events, err := fn(&deck)
+			if err != nil {
+				return err
+			}
+			deck.Tenant = tenant
+			v, err := encodeDeck(deck)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.TxPipelined(ctx, func(p redis.Pipeliner) error {
+				p.Set(ctx, key, v, 0)
+				return queueRedisDeckEvents(ctx, p, events...)
+			})
+			return err
+		}, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			if err := ctx.Err(); err != nil {
+				return entity.Deck{}, err
+			}
+			continue
+		}
+		if err != nil {
+			return entity.Deck{}, err
+		}
+		return deck, nil
+	}
+
+	return entity.Deck{}, fmt.Errorf("%w: deck %s", DeckConflictErr, id)
+}
+
+// All returns every deck in the store, of every tenant.
+func (r *RedisDeck) All(ctx context.Context) ([]entity.Deck, error) {
+	tenants, err := r.client.SMembers(ctx, redisDeckTenantsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var decks []entity.Deck
+	for _, tenant := range append([]string{""}, tenants...) {
+		d, err := r.tenantDecks(ctx, tenant)
+		decks = append(decks, d...)
+		if err != nil {
+			return decks, err
+		}
+	}
+	return decks, nil
+}
+
+func (r *RedisDeck) tenantDecks(ctx context.Context, tenant string) ([]entity.Deck, error) {
+	ids, err := r.client.SMembers(ctx, redisDeckIDsKey(tenant)).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = redisDeckKey(tenant, id)
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var decks []entity.Deck
+	for i, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		deck, err := decodeDeck(ids[i], []byte(s))
+		if err != nil {
+			return decks, err
+		}
+		deck.Tenant = tenant
+		decks = append(decks, deck)
+	}
+	return decks, nil
+}
+
+// Count returns how many decks of tenant the store holds.
+func (r *RedisDeck) Count(ctx context.Context, tenant string) (int, error) {
+	n, err := r.client.SCard(ctx, redisDeckIDsKey(tenant)).Result()
+	return int(n), err
+}
+
+// Delete removes a deck of tenant from the store.
+func (r *RedisDeck) Delete(ctx context.Context, tenant, id string) error {
+	var del *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		del = p.Del(ctx, redisDeckKey(tenant, id))
+		p.SRem(ctx, redisDeckIDsKey(tenant), id)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if del.Val() == 0 {
+		return fmt.Errorf("%w with ID %s", DeckNotFoundErr, id)
+	}
+	return nil
+}
+
+// Ping checks the server answers.
+func (r *RedisDeck) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// queueRedisDeckEvents queues the writes of events, each
+// under its version, on a transaction pipeline.
+func queueRedisDeckEvents(ctx context.Context, p redis.Pipeliner, events ...entity.DeckEvent) error {
+	for _, e := range events {
+		v, err := encodeDeckEvent(e)
+		if err != nil {
+			return err
+		}
+		p.HSet(ctx, redisDeckEventsKey(e.DeckID), strconv.Itoa(e.Version), v)
+		p.SAdd(ctx, redisEventDeckIDsKey, e.DeckID)
+	}
+	return nil
+}
+
+// RedisDeckEvents is a deck event store on Redis, keeping
+// the events of each deck in a hash keyed by version.
+type RedisDeckEvents struct {
+	client redis.UniversalClient
+}
+
+// NewRedisDeckEvents creates a new RedisDeckEvents.
+func NewRedisDeckEvents(client redis.UniversalClient) *RedisDeckEvents {
+	return &RedisDeckEvents{client: client}
+}
+
+// Append adds an event at the end of its deck stream.
+func (r *RedisDeckEvents) Append(ctx context.Context, event entity.DeckEvent) error {
+	_, err := r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		return queueRedisDeckEvents(ctx, p, event)
+	})
+	return err
+}
+
+// Events retrieves the events of a deck, oldest first.
+func (r *RedisDeckEvents) Events(ctx context.Context, deckID string) ([]entity.DeckEvent, error) {
+	versions, err := r.client.HGetAll(ctx, redisDeckEventsKey(deckID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]entity.DeckEvent, 0, len(versions))
+	for k, v := range versions {
+		version, err := strconv.Atoi(k)
+		if err != nil {
+			return nil, fmt.Errorf("%w: deck %s event version %q", DeckCodecErr, deckID, k)
+		}
+		e, err := decodeDeckEvent(deckID, version, []byte(v))
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w with ID %s", DeckEventsNotFoundErr, deckID)
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Version < events[j].Version })
+	return events, nil
+}
+
+// All returns the events of every deck, each deck
+// stream oldest first.
+func (r *RedisDeckEvents) All(ctx context.Context) ([]entity.DeckEvent, error) {
+	ids, err := r.client.SMembers(ctx, redisEventDeckIDsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []entity.DeckEvent
+	for _, id := range ids {
+		stream, err := r.Events(ctx, id)
+		if err != nil {
+			return events, err
+		}
+		events = append(events, stream...)
+	}
+	return events, nil
+}
@@ -0,0 +1,137 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func TestRedisDeck(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+
+	// Two instances, each with its own client.
+	one := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	two := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer one.Close()
+	defer two.Close()
+
+	snapshot := testDeckSnapshot(time.Date(2024, time.March, 9, 15, 4, 5, 0, time.UTC))
+	for _, d := range snapshot.Decks {
+		if err := NewRedisDeck(one).Save(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, e := range snapshot.Events {
+		if err := NewRedisDeckEvents(one).Append(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	decks, events := NewRedisDeck(two), NewRedisDeckEvents(two)
+
+	got, err := decks.Get(ctx, "", "id")
+	if err != nil {
+		t.Fatalf("RedisDeck.Get() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(got, snapshot.Decks[0]); diff != "" {
+		t.Errorf("RedisDeck.Get() | (-got +want):\n%s", diff)
+	}
+	all, err := decks.All(ctx)
+	if err != nil {
+		t.Fatalf("RedisDeck.All() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(all, snapshot.Decks); diff != "" {
+		t.Errorf("RedisDeck.All() | (-got +want):\n%s", diff)
+	}
+
+	gotEvents, err := events.Events(ctx, "id")
+	if err != nil {
+		t.Fatalf("RedisDeckEvents.Events() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(gotEvents, snapshot.Events); diff != "" {
+		t.Errorf("RedisDeckEvents.Events() | (-got +want):\n%s", diff)
+	}
+	allEvents, err := events.All(ctx)
+	if err != nil {
+		t.Fatalf("RedisDeckEvents.All() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(allEvents, snapshot.Events); diff != "" {
+		t.Errorf("RedisDeckEvents.All() | (-got +want):\n%s", diff)
+	}
+
+	if _, err := decks.Get(ctx, "", "other"); !errors.Is(err, DeckNotFoundErr) {
+		t.Errorf("RedisDeck.Get() | got error %v, want %v", err, DeckNotFoundErr)
+	}
+	if _, err := events.Events(ctx, "other"); !errors.Is(err, DeckEventsNotFoundErr) {
+		t.Errorf("RedisDeckEvents.Events() | got error %v, want %v", err, DeckEventsNotFoundErr)
+	}
+	if _, err := decks.Update(ctx, "", "other", func(*entity.Deck) ([]entity.DeckEvent, error) { return nil, nil }); !errors.Is(err, DeckNotFoundErr) {
+		t.Errorf("RedisDeck.Update() | got error %v, want %v", err, DeckNotFoundErr)
+	}
+}
+
+func TestRedisDeck_Update(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+
+	full := append([]entity.Card{}, entity.DefaultCards[:20]...)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer client.Close()
+	if err := NewRedisDeck(client).Save(ctx, entity.Deck{ID: "id", Remaining: len(full), Cards: full}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Instances drawing at once never get the same card.
+	var (
+		mu    sync.Mutex
+		drawn []string
+		wg    sync.WaitGroup
+	)
+	for i := 0; i < len(full); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+			defer client.Close()
+
+			var card entity.Card
+			_, err := NewRedisDeck(client).Update(ctx, "", "id", func(deck *entity.Deck) ([]entity.DeckEvent, error) {
+				card = deck.Cards[0]
+				deck.Cards = deck.Cards[1:]
+				deck.Remaining = len(deck.Cards)
+				return nil, nil
+			})
+			if err != nil {
+				t.Errorf("RedisDeck.Update() | got error %v, want nil", err)
+				return
+			}
+			mu.Lock()
+			drawn = append(drawn, card.Code)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	var want []string
+	for _, c := range full {
+		want = append(want, c.Code)
+	}
+	sort.Strings(drawn)
+	sort.Strings(want)
+	if diff := cmp.Diff(drawn, want); diff != "" {
+		t.Errorf("RedisDeck.Update() | drawn cards (-got +want):\n%s", diff)
+	}
+	if got, _ := NewRedisDeck(client).Get(ctx, "", "id"); got.Remaining != 0 {
+		t.Errorf("RedisDeck.Update() | got %d cards left, want 0", got.Remaining)
+	}
+}
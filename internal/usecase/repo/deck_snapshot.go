@@ -0,0 +1,174 @@
+package repo
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// deckSnapshotFormat is the version of the snapshot file
+// layout, bumped on every incompatible change.
+const deckSnapshotFormat = 1
+
+var (
+	// DeckSnapshotNotFoundErr happens when no snapshot was written yet.
+	DeckSnapshotNotFoundErr = errors.New("deck snapshot not found")
+	// DeckSnapshotFormatErr happens when a snapshot was written in an unknown format.
+	DeckSnapshotFormatErr = errors.New("unknown deck snapshot format")
+)
+
+// DeckSnapshotFile keeps the deck store snapshot in a
+// gzip compressed JSON file. A new snapshot is written
+// next to the file and renamed over it, so a crash while
+// writing leaves the previous snapshot in place.
+type DeckSnapshotFile struct {
+	path string
+
+	// writer wraps the writes to the file, letting tests
+	// fail them half way.
+	writer func(io.Writer) io.Writer
+}
+
+// NewDeckSnapshotFile creates a new DeckSnapshotFile.
+func NewDeckSnapshotFile(path string) *DeckSnapshotFile {
+	return &DeckSnapshotFile{path: path}
+}
+
+// snapshotHand and snapshotEvent keep the player tokens,
+// which the entities leave out of their JSON.
+type snapshotHand struct {
+	PlayerID string        `json:"player_id"`
+	Token    string        `json:"token"`
+	Cards    []entity.Card `json:"cards"`
+}
+
+type snapshotDeck struct {
+	entity.Deck
+	Hands []snapshotHand `json:"hands,omitempty"`
+}
+
+type snapshotEvent struct {
+	entity.DeckEvent
+	Token string `json:"token,omitempty"`
+}
+
+type snapshotFile struct {
+	Format int             `json:"format"`
+	Taken  time.Time       `json:"taken"`
+	Decks  []snapshotDeck  `json:"decks"`
+	Events []snapshotEvent `json:"events"`
+}
+
+// Write replaces the snapshot file with a new snapshot.
+func (s *DeckSnapshotFile) Write(snapshot entity.DeckSnapshot) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating deck snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := s.encode(tmp, snapshot); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing deck snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing deck snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing deck snapshot: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("replacing deck snapshot: %w", err)
+	}
+
+	// The rename only survives a crash once the directory is synced.
+	if err := syncDir(filepath.Dir(s.path)); err != nil {
+		return fmt.Errorf("replacing deck snapshot: %w", err)
+	}
+	return nil
+}
+
+func (s *DeckSnapshotFile) encode(f *os.File, snapshot entity.DeckSnapshot) error {
+	var w io.Writer = f
+	if s.writer != nil {
+		w = s.writer(f)
+	}
+
+	file := snapshotFile{
+		Format: deckSnapshotFormat,
+		Taken:  snapshot.Taken,
+		Decks:  make([]snapshotDeck, len(snapshot.Decks)),
+		Events: make([]snapshotEvent, len(snapshot.Events)),
+	}
+	for i, d := range snapshot.Decks {
+		file.Decks[i].Deck = d
+		for _, h := range d.Hands {
+			file.Decks[i].Hands = append(file.Decks[i].Hands, snapshotHand(h))
+		}
+	}
+	for i, e := range snapshot.Events {
+		file.Events[i] = snapshotEvent{DeckEvent: e, Token: e.Token}
+	}
+
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(file); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Read loads the snapshot file.
+func (s *DeckSnapshotFile) Read() (entity.DeckSnapshot, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return entity.DeckSnapshot{}, fmt.Errorf("%w at %s", DeckSnapshotNotFoundErr, s.path)
+		}
+		return entity.DeckSnapshot{}, fmt.Errorf("opening deck snapshot: %w", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return entity.DeckSnapshot{}, fmt.Errorf("reading deck snapshot: %w", err)
+	}
+	var file snapshotFile
+	if err := json.NewDecoder(zr).Decode(&file); err != nil {
+		return entity.DeckSnapshot{}, fmt.Errorf("reading deck snapshot: %w", err)
+	}
+	// Reading to the end checks the gzip checksum.
+	if _, err := io.Copy(io.Discard, zr); err != nil {
+		return entity.DeckSnapshot{}, fmt.Errorf("reading deck snapshot: %w", err)
+	}
+	if file.Format != deckSnapshotFormat {
+		return entity.DeckSnapshot{}, fmt.Errorf("%w %d", DeckSnapshotFormatErr, file.Format)
+	}
+
+	snapshot := entity.DeckSnapshot{
+		Taken:  file.Taken,
+		Decks:  make([]entity.Deck, len(file.Decks)),
+		Events: make([]entity.DeckEvent, len(file.Events)),
+	}
+	for i, d := range file.Decks {
+		snapshot.Decks[i] = d.Deck
+		snapshot.Decks[i].Hands = nil
+		for _, h := range d.Hands {
+			snapshot.Decks[i].Hands = append(snapshot.Decks[i].Hands, entity.Hand(h))
+		}
+	}
+	for i, e := range file.Events {
+		snapshot.Events[i] = e.DeckEvent
+		snapshot.Events[i].Token = e.Token
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// crashWriter fails once n bytes were written.
+type crashWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *crashWriter) Write(p []byte) (int, error) {
+	if len(p) > c.n {
+		written, _ := c.w.Write(p[:c.n])
+		c.n = 0
+		return written, errors.New("crash")
+	}
+	c.n -= len(p)
+	return c.w.Write(p)
+}
+
+func testDeckSnapshot(taken time.Time) entity.DeckSnapshot {
+	return entity.DeckSnapshot{
+		Taken: taken,
+		Decks: []entity.Deck{{
+			ID:        "id",
+			Remaining: 1,
+			Cards:     entity.DefaultCards[2:3],
+			Hands:     []entity.Hand{{PlayerID: "p", Token: "token", Cards: entity.DefaultCards[:2]}},
+			Version:   2,
+			Owner:     "key",
+		}},
+		Events: []entity.DeckEvent{
+			{DeckID: "id", Version: 1, Type: entity.DeckEventCreated, Time: taken, Cards: entity.DefaultCards[:3], Owner: "key"},
+			{DeckID: "id", Version: 2, Type: entity.DeckEventMoved, Time: taken, Cards: entity.DefaultCards[:2], PlayerID: "p", Token: "token"},
+		},
+	}
+}
+
+func TestDeckSnapshotFile_WriteRead(t *testing.T) {
+	store := NewDeckSnapshotFile(filepath.Join(t.TempDir(), "decks.snapshot"))
+	want := testDeckSnapshot(time.Date(2024, time.March, 9, 15, 4, 5, 0, time.UTC))
+
+	if err := store.Write(want); err != nil {
+		t.Fatalf("DeckSnapshotFile.Write() | got error %v, want nil", err)
+	}
+
+	got, err := store.Read()
+	if err != nil {
+		t.Fatalf("DeckSnapshotFile.Read() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("DeckSnapshotFile.Read() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestDeckSnapshotFile_Read_NotFound(t *testing.T) {
+	store := NewDeckSnapshotFile(filepath.Join(t.TempDir(), "decks.snapshot"))
+	if _, err := store.Read(); !errors.Is(err, DeckSnapshotNotFoundErr) {
+		t.Errorf("DeckSnapshotFile.Read() | got error %v, want %v", err, DeckSnapshotNotFoundErr)
+	}
+}
+
+func TestDeckSnapshotFile_Write_Crash(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "decks.snapshot")
+	store := NewDeckSnapshotFile(path)
+
+	previous := testDeckSnapshot(time.Date(2024, time.March, 9, 15, 4, 5, 0, time.UTC))
+	if err := store.Write(previous); err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash half way through the next snapshot...
+	store.writer = func(w io.Writer) io.Writer { return &crashWriter{w: w, n: 20} }
+	next := testDeckSnapshot(previous.Taken.Add(time.Minute))
+	if err := store.Write(next); err == nil {
+		t.Fatal("DeckSnapshotFile.Write() | got nil error, want the crash")
+	}
+	// ...or a process killed before renaming, leaving its temp file.
+	if err := os.WriteFile(path+".123.tmp", []byte("partial"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewDeckSnapshotFile(path).Read()
+	if err != nil {
+		t.Fatalf("DeckSnapshotFile.Read() | got error %v, want nil", err)
+	}
+	if diff := cmp.Diff(got, previous); diff != "" {
+		t.Errorf("DeckSnapshotFile.Read() | (-got +want):\n%s", diff)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("DeckSnapshotFile.Write() | got %d files, want the snapshot and the killed temp file", len(entries))
+	}
+}
+
+func TestDeckSnapshotFile_Read_Format(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "decks.snapshot")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := gzip.NewWriter(f)
+	json.NewEncoder(zw).Encode(snapshotFile{Format: deckSnapshotFormat + 1})
+	zw.Close()
+	f.Close()
+
+	if _, err := NewDeckSnapshotFile(path).Read(); !errors.Is(err, DeckSnapshotFormatErr) {
+		t.Errorf("DeckSnapshotFile.Read() | got error %v, want %v", err, DeckSnapshotFormatErr)
+	}
+}
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"context"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -25,10 +26,12 @@ func TestDeck_Save(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			deckStore := make(Deck)
-			deckStore.Save(tt.ent)
+			deckStore := NewDeck()
+			if err := deckStore.Save(context.Background(), tt.ent); err != nil {
+				t.Fatalf("Deck.Save() | got error %v, want nil", err)
+			}
 
-			got, ok := deckStore[tt.ent.ID]
+			got, ok := deckStore.decks[deckKey{id: tt.ent.ID}]
 			if !ok {
 				t.Fatalf("Deck.Save() | saved deck not found in the store")
 			}
@@ -57,9 +60,9 @@ func TestDeck_Get(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			deckStore := make(Deck)
-			deckStore[tt.want.ID] = tt.want
-			got, err := deckStore.Get(tt.want.ID)
+			deckStore := NewDeck()
+			deckStore.decks[deckKey{id: tt.want.ID}] = tt.want
+			got, err := deckStore.Get(context.Background(), "", tt.want.ID)
 			if err != nil {
 				t.Errorf("Deck.Get() | got error %v, want nil", err)
 			}
@@ -72,8 +75,8 @@ func TestDeck_Get(t *testing.T) {
 }
 
 func TestDeck_Get_Error(t *testing.T) {
-	deckStore := make(Deck)
-	_, err := deckStore.Get("id")
+	deckStore := NewDeck()
+	_, err := deckStore.Get(context.Background(), "", "id")
 	if err == nil {
 		t.Errorf("Deck.Get() | got error %v, want nil", err)
 	}
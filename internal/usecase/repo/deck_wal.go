@@ -0,0 +1,456 @@
+package repo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// Sync policies of the deck WAL. Every record reaches the
+// kernel before the store returns, which survives the
+// process being killed; the policy decides how much can be
+// lost when the machine itself goes down.
+const (
+	// WALSyncAlways syncs every record to disk.
+	WALSyncAlways = "always"
+	// WALSyncInterval syncs the records written in the last interval.
+	WALSyncInterval = "interval"
+	// WALSyncNever leaves syncing to the operating system.
+	WALSyncNever = "never"
+)
+
+var (
+	// DeckWALPolicyErr happens when the WAL is opened with an unknown sync policy.
+	DeckWALPolicyErr = errors.New("unknown wal sync policy")
+	// DeckWALCorruptErr happens when a WAL record other than the last of a segment can't be read.
+	DeckWALCorruptErr = errors.New("corrupt deck wal")
+)
+
+const walSegmentExt = ".wal"
+
+// DeckWAL is an append-only log of the writes to the
+// in-memory deck stores, split in numbered segment files.
+// Each line holds the CRC of its record, so a record torn
+// by a crash is told apart from a valid one.
+type DeckWAL struct {
+	dir    string
+	policy string
+
+	mu      sync.Mutex
+	file    *os.File
+	segment int
+	dirty   bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// walRecord is a deck save, along with the events it
+// appends, or a deck delete. A delete names the deck and
+// its tenant.
+type walRecord struct {
+	Deck   *snapshotDeck    `json:"deck,omitempty"`
+	Events []*snapshotEvent `json:"events,omitempty"`
+	Delete string           `json:"delete,omitempty"`
+	Tenant string           `json:"tenant,omitempty"`
+}
+
+// OpenDeckWAL starts a new segment in dir for the records
+// to come. Existing segments are left for ReplayDeckWAL.
+// The interval is only used by WALSyncInterval.
+func OpenDeckWAL(dir, policy string, interval time.Duration) (*DeckWAL, error) {
+	switch policy {
+	case WALSyncAlways, WALSyncNever:
+	case WALSyncInterval:
+		if interval <= 0 {
+			return nil, fmt.Errorf("%w: %s needs a positive interval", DeckWALPolicyErr, policy)
+		}
+	default:
+		return nil, fmt.Errorf("%w %q", DeckWALPolicyErr, policy)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating deck wal: %w", err)
+	}
+	segments, err := walSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &DeckWAL{dir: dir, policy: policy}
+	if len(segments) > 0 {
+		w.segment = segments[len(segments)-1]
+	}
+	if err := w.next(); err != nil {
+		return nil, err
+	}
+
+	if policy == WALSyncInterval {
+		w.stop = make(chan struct{})
+		w.done = make(chan struct{})
+		go w.syncEvery(interval)
+	}
+
+	return w, nil
+}
+
+// next closes the current segment, if any, and starts the
+// following one.
+func (w *DeckWAL) next() error {
+	if w.file != nil {
+		if err := w.file.Sync(); err != nil {
+			return fmt.Errorf("syncing deck wal: %w", err)
+		}
+		if err := w.file.Close(); err != nil {
+			return fmt.Errorf("closing deck wal segment: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(walSegmentPath(w.dir, w.segment+1), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("creating deck wal segment: %w", err)
+	}
+	w.file = f
+	w.segment++
+	w.dirty = false
+
+	return syncDir(w.dir)
+}
+
+func (w *DeckWAL) syncEvery(interval time.Duration) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if w.dirty {
+				if err := w.file.Sync(); err != nil {
+					slog.Error("deck wal: syncing", slog.Any("err", err))
+				} else {
+					w.dirty = false
+				}
+			}
+			w.mu.Unlock()
+		}
+	}
+}
+
+func (w *DeckWAL) append(record walRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line := fmt.Sprintf("%08x %s\n", crc32.ChecksumIEEE(data), data)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := io.WriteString(w.file, line); err != nil {
+		return fmt.Errorf("writing deck wal: %w", err)
+	}
+	if w.policy == WALSyncAlways {
+		if err := w.file.Sync(); err != nil {
+			return fmt.Errorf("syncing deck wal: %w", err)
+		}
+		return nil
+	}
+	w.dirty = true
+
+	return nil
+}
+
+// Rotate starts a new segment and returns its number.
+// Every record written before lives in older segments.
+func (w *DeckWAL) Rotate() (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.next(); err != nil {
+		return 0, err
+	}
+	return w.segment, nil
+}
+
+// Compact removes the segments older than the given one,
+// once their records are kept by a snapshot.
+func (w *DeckWAL) Compact(segment int) error {
+	segments, err := walSegments(w.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range segments {
+		if s >= segment {
+			break
+		}
+		if err := os.Remove(walSegmentPath(w.dir, s)); err != nil {
+			return fmt.Errorf("compacting deck wal: %w", err)
+		}
+	}
+	return syncDir(w.dir)
+}
+
+// Close syncs and closes the current segment.
+func (w *DeckWAL) Close() error {
+	if w.stop != nil {
+		close(w.stop)
+		<-w.done
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.file.Sync(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("syncing deck wal: %w", err)
+	}
+	return w.file.Close()
+}
+
+// ReplayDeckWAL applies the records of every segment in
+// dir to the stores, oldest first, and returns how many it
+// read. Events already in the stores, as restored from a
+// snapshot, are skipped. A torn last record of a segment
+// is the write a crash cut short and is dropped.
+func ReplayDeckWAL(dir string, decks *Deck) (int, error) {
+	segments, err := walSegments(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var n int
+	for _, s := range segments {
+		records, err := readWALSegment(walSegmentPath(dir, s))
+		if err != nil {
+			return n, err
+		}
+
+		for _, r := range records {
+			switch {
+			case r.Deck != nil:
+				deck := r.Deck.Deck
+				deck.Hands = nil
+				for _, h := range r.Deck.Hands {
+					deck.Hands = append(deck.Hands, entity.Hand(h))
+				}
+				if err := decks.Save(context.Background(), deck); err != nil {
+					return n, err
+				}
+				replayWALEvents(decks.events, r.Events...)
+			case r.Delete != "":
+				err := decks.Delete(context.Background(), r.Tenant, r.Delete)
+				if err != nil && !errors.Is(err, DeckNotFoundErr) {
+					return n, err
+				}
+			}
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// replayWALEvents appends the events events doesn't have yet.
+func replayWALEvents(events *DeckEvents, records ...*snapshotEvent) {
+	for _, r := range records {
+		e := r.DeckEvent
+		e.Token = r.Token
+		if events.version(e.DeckID) < e.Version {
+			events.append(e)
+		}
+	}
+}
+
+func readWALSegment(path string) ([]walRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening deck wal segment: %w", err)
+	}
+	defer f.Close()
+
+	var lines [][]byte
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// A line without its newline was cut short.
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading deck wal segment: %w", err)
+		}
+		lines = append(lines, line)
+	}
+
+	var records []walRecord
+	for i, line := range lines {
+		record, ok := decodeWALLine(line)
+		if !ok {
+			if i == len(lines)-1 {
+				break
+			}
+			return nil, fmt.Errorf("%w: %s line %d", DeckWALCorruptErr, filepath.Base(path), i+1)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func decodeWALLine(line []byte) (walRecord, bool) {
+	sum, data, ok := bytes.Cut(bytes.TrimSuffix(line, []byte("\n")), []byte(" "))
+	if !ok {
+		return walRecord{}, false
+	}
+	want, err := strconv.ParseUint(string(sum), 16, 32)
+	if err != nil || uint32(want) != crc32.ChecksumIEEE(data) {
+		return walRecord{}, false
+	}
+
+	var record walRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return walRecord{}, false
+	}
+	return record, true
+}
+
+// walSegments returns the segment numbers in dir, in order.
+func walSegments(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("listing deck wal: %w", err)
+	}
+
+	var segments []int
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasSuffix(name, walSegmentExt) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(name, walSegmentExt))
+		if err != nil {
+			continue
+		}
+		segments = append(segments, n)
+	}
+	sort.Ints(segments)
+
+	return segments, nil
+}
+
+func walSegmentPath(dir string, segment int) string {
+	return filepath.Join(dir, fmt.Sprintf("%08d%s", segment, walSegmentExt))
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// WALDeck is the in-memory deck store, logging every save
+// to a WAL before applying it. A write is logged and
+// applied while the store is locked, so a snapshot, which
+// reads the store, sees every write logged before it, and
+// the writes are logged in the order they're applied.
+type WALDeck struct {
+	*Deck
+	wal *DeckWAL
+}
+
+// NewWALDeck creates a new WALDeck.
+func NewWALDeck(store *Deck, wal *DeckWAL) *WALDeck {
+	return &WALDeck{Deck: store, wal: wal}
+}
+
+// Save logs the deck and its events, then saves them to
+// the store. A deck that can't be logged isn't saved.
+func (d *WALDeck) Save(ctx context.Context, deck entity.Deck, events ...entity.DeckEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.Deck.mu.Lock()
+	defer d.Deck.mu.Unlock()
+
+	if err := d.log(deck, events); err != nil {
+		return fmt.Errorf("deck wal: deck %s: %w", deck.ID, err)
+	}
+	d.Deck.save(deck, events)
+	return nil
+}
+
+// Update changes a deck with fn, then logs and saves it
+// with its events while the store is locked.
+func (d *WALDeck) Update(ctx context.Context, tenant, id string, fn func(deck *entity.Deck) ([]entity.DeckEvent, error)) (entity.Deck, error) {
+	return d.Deck.Update(ctx, tenant, id, func(deck *entity.Deck) ([]entity.DeckEvent, error) {
+		events, err := fn(deck)
+		if err != nil {
+			return nil, err
+		}
+		if err := d.log(*deck, events); err != nil {
+			return nil, fmt.Errorf("deck wal: deck %s: %w", deck.ID, err)
+		}
+		return events, nil
+	})
+}
+
+// Delete logs the delete, then removes the deck from the
+// store.
+func (d *WALDeck) Delete(ctx context.Context, tenant, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.Deck.mu.Lock()
+	defer d.Deck.mu.Unlock()
+
+	if _, err := d.Deck.get(tenant, id); err != nil {
+		return err
+	}
+	if err := d.wal.append(walRecord{Delete: id, Tenant: tenant}); err != nil {
+		return fmt.Errorf("deck wal: deck %s: %w", id, err)
+	}
+	d.Deck.remove(tenant, id)
+	return nil
+}
+
+// log writes the deck and its events as a single record,
+// so a torn write loses both.
+func (d *WALDeck) log(deck entity.Deck, events []entity.DeckEvent) error {
+	record := walRecord{Deck: &snapshotDeck{Deck: deck}}
+	for _, h := range deck.Hands {
+		record.Deck.Hands = append(record.Deck.Hands, snapshotHand(h))
+	}
+	for _, e := range events {
+		record.Events = append(record.Events, &snapshotEvent{DeckEvent: e, Token: e.Token})
+	}
+	return d.wal.append(record)
+}
@@ -0,0 +1,320 @@
+package repo
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// walTestWrites writes the deck of testDeckSnapshot
+// through the WAL store, in a save per event.
+func walTestWrites(t *testing.T, wal *DeckWAL) *Deck {
+	t.Helper()
+
+	decks := NewDeck()
+	walDecks := NewWALDeck(decks, wal)
+
+	snapshot := testDeckSnapshot(time.Date(2024, time.March, 9, 15, 4, 5, 0, time.UTC))
+	for _, e := range snapshot.Events {
+		if err := walDecks.Save(context.Background(), snapshot.Decks[0], e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return decks
+}
+
+// walTestEvents returns the events of the deck of
+// testDeckSnapshot in events.
+func walTestEvents(t *testing.T, events *DeckEvents) []entity.DeckEvent {
+	t.Helper()
+
+	got, err := events.Events(context.Background(), "id")
+	if err != nil {
+		t.Fatalf("DeckEvents.Events() | got error %v, want nil", err)
+	}
+	return got
+}
+
+func TestDeckWAL_Replay(t *testing.T) {
+	for _, policy := range []string{WALSyncAlways, WALSyncInterval, WALSyncNever} {
+		t.Run(policy, func(t *testing.T) {
+			dir := t.TempDir()
+			wal, err := OpenDeckWAL(dir, policy, time.Millisecond)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := walTestWrites(t, wal)
+			wal.Close()
+
+			decks := NewDeck()
+			n, err := ReplayDeckWAL(dir, decks)
+			if err != nil {
+				t.Fatalf("ReplayDeckWAL() | got error %v, want nil", err)
+			}
+			if n != 2 {
+				t.Errorf("ReplayDeckWAL() | got %d records, want 2", n)
+			}
+			got, _ := decks.All(context.Background())
+			wantDecks, _ := want.All(context.Background())
+			if diff := cmp.Diff(got, wantDecks); diff != "" {
+				t.Errorf("ReplayDeckWAL() | decks (-got +want):\n%s", diff)
+			}
+			if diff := cmp.Diff(walTestEvents(t, decks.Events()), walTestEvents(t, want.Events())); diff != "" {
+				t.Errorf("ReplayDeckWAL() | events (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestDeckWAL_Killed runs the writes in a child process
+// killed before it closes the WAL.
+func TestDeckWAL_Killed(t *testing.T) {
+	if dir := os.Getenv("DECK_WAL_TEST_DIR"); dir != "" {
+		wal, err := OpenDeckWAL(dir, os.Getenv("DECK_WAL_TEST_POLICY"), time.Hour)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		walTestWrites(t, wal)
+		fmt.Println("written")
+		time.Sleep(time.Minute)
+		return
+	}
+
+	for _, policy := range []string{WALSyncAlways, WALSyncInterval, WALSyncNever} {
+		t.Run(policy, func(t *testing.T) {
+			dir := t.TempDir()
+
+			cmd := exec.Command(os.Args[0], "-test.run=^TestDeckWAL_Killed$")
+			cmd.Env = append(os.Environ(), "DECK_WAL_TEST_DIR="+dir, "DECK_WAL_TEST_POLICY="+policy)
+			out, err := cmd.StdoutPipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := cmd.Start(); err != nil {
+				t.Fatal(err)
+			}
+			line, err := bufio.NewReader(out).ReadString('\n')
+			if err != nil || line != "written\n" {
+				cmd.Process.Kill()
+				t.Fatalf("child | got %q and error %v", line, err)
+			}
+			cmd.Process.Kill()
+			cmd.Wait()
+
+			decks := NewDeck()
+			if _, err := ReplayDeckWAL(dir, decks); err != nil {
+				t.Fatalf("ReplayDeckWAL() | got error %v, want nil", err)
+			}
+			want := testDeckSnapshot(time.Time{})
+			if got, _ := decks.Get(context.Background(), "", "id"); got.Version != want.Decks[0].Version || got.Hands[0].Token != "token" {
+				t.Errorf("ReplayDeckWAL() | got deck %+v, want %+v", got, want.Decks[0])
+			}
+			if got, _ := decks.Events().Events(context.Background(), "id"); len(got) != len(want.Events) {
+				t.Errorf("ReplayDeckWAL() | got %d events, want %d", len(got), len(want.Events))
+			}
+		})
+	}
+}
+
+func TestDeckWAL_Replay_Torn(t *testing.T) {
+	dir := t.TempDir()
+	wal, err := OpenDeckWAL(dir, WALSyncAlways, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	walTestWrites(t, wal)
+	wal.Close()
+
+	segment := walSegmentPath(dir, 1)
+	f, err := os.OpenFile(segment, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`00000000 {"deck":{"deck_id":"torn"`)
+	f.Close()
+
+	n, err := ReplayDeckWAL(dir, NewDeck())
+	if err != nil || n != 2 {
+		t.Fatalf("ReplayDeckWAL() | got %d records and error %v, want 2 and nil", n, err)
+	}
+
+	// A bad record followed by others isn't a torn write.
+	data, _ := os.ReadFile(segment)
+	data[0] = 'x'
+	os.WriteFile(segment, data, 0o600)
+	if _, err := ReplayDeckWAL(dir, NewDeck()); !errors.Is(err, DeckWALCorruptErr) {
+		t.Errorf("ReplayDeckWAL() | got error %v, want %v", err, DeckWALCorruptErr)
+	}
+}
+
+func TestDeckWAL_Replay_Delete(t *testing.T) {
+	dir := t.TempDir()
+	wal, err := OpenDeckWAL(dir, WALSyncNever, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decks := walTestWrites(t, wal)
+	if err := NewWALDeck(decks, wal).Delete(context.Background(), "", "id"); err != nil {
+		t.Fatalf("WALDeck.Delete() | got error %v, want nil", err)
+	}
+	wal.Close()
+
+	replayed := NewDeck()
+	if _, err := ReplayDeckWAL(dir, replayed); err != nil {
+		t.Fatalf("ReplayDeckWAL() | got error %v, want nil", err)
+	}
+	if _, err := replayed.Get(context.Background(), "", "id"); !errors.Is(err, DeckNotFoundErr) {
+		t.Errorf("ReplayDeckWAL() | got error %v for the deleted deck, want %v", err, DeckNotFoundErr)
+	}
+}
+
+func TestDeckWAL_RotateCompact(t *testing.T) {
+	dir := t.TempDir()
+	wal, err := OpenDeckWAL(dir, WALSyncNever, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decks := walTestWrites(t, wal)
+
+	segment, err := wal.Rotate()
+	if err != nil {
+		t.Fatalf("DeckWAL.Rotate() | got error %v, want nil", err)
+	}
+	_, err = NewWALDeck(decks, wal).Update(context.Background(), "", "id", func(deck *entity.Deck) ([]entity.DeckEvent, error) {
+		deck.Version++
+		return []entity.DeckEvent{{DeckID: "id", Version: deck.Version, Type: entity.DeckEventShuffled}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := wal.Compact(segment); err != nil {
+		t.Fatalf("DeckWAL.Compact() | got error %v, want nil", err)
+	}
+	wal.Close()
+
+	if _, err := os.Stat(walSegmentPath(dir, 1)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("DeckWAL.Compact() | got error %v for the old segment, want it removed", err)
+	}
+
+	// The snapshot taken after the rotation already has the
+	// first events; replaying the rest doesn't repeat them.
+	want := walTestEvents(t, decks.Events())
+	restored := NewDeck()
+	if err := restored.Save(context.Background(), entity.Deck{ID: "id"}, want[:2]...); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReplayDeckWAL(dir, restored); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(walTestEvents(t, restored.Events()), want); diff != "" {
+		t.Errorf("ReplayDeckWAL() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestWALDeck_SnapshotWhileWriting(t *testing.T) {
+	dir := t.TempDir()
+	wal, err := OpenDeckWAL(dir, WALSyncNever, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decks := NewDeck()
+	walDecks := NewWALDeck(decks, wal)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				id := fmt.Sprintf("%d-%d", w, i)
+				if err := walDecks.Save(context.Background(), entity.Deck{ID: id, Remaining: i}); err != nil {
+					t.Error(err)
+					return
+				}
+				if i%2 == 0 {
+					if err := walDecks.Delete(context.Background(), "", id); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}
+		}(w)
+	}
+
+	// Snapshots are taken as Take does while the writes go
+	// on: rotate, read the store, compact.
+	var snapshot []entity.Deck
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		segment, err := wal.Rotate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snapshot, err = decks.All(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if err := wal.Compact(segment); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wal.Close()
+
+	// The last snapshot and what is left of the WAL give
+	// back every write.
+	restored := NewDeck()
+	for _, d := range snapshot {
+		if err := restored.Save(context.Background(), d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := ReplayDeckWAL(dir, restored); err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := decks.All(context.Background())
+	got, _ := restored.All(context.Background())
+	byID := cmpopts.SortSlices(func(a, b entity.Deck) bool { return a.ID < b.ID })
+	if diff := cmp.Diff(got, want, byID); diff != "" {
+		t.Errorf("ReplayDeckWAL() | restored decks (-got +want):\n%s", diff)
+	}
+	if len(want) != 100 {
+		t.Errorf("WALDeck | got %d decks, want 100", len(want))
+	}
+}
+
+func TestOpenDeckWAL_Policy(t *testing.T) {
+	for _, tt := range []struct {
+		policy   string
+		interval time.Duration
+	}{
+		{policy: "sometimes"},
+		{policy: WALSyncInterval},
+	} {
+		if _, err := OpenDeckWAL(filepath.Join(t.TempDir(), "wal"), tt.policy, tt.interval); !errors.Is(err, DeckWALPolicyErr) {
+			t.Errorf("OpenDeckWAL(%q) | got error %v, want %v", tt.policy, err, DeckWALPolicyErr)
+		}
+	}
+}
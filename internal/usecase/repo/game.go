@@ -0,0 +1,90 @@
+package repo
+
+import "sync"
+
+// gameKey finds a game within its tenant, so the games of
+// a tenant aren't found by the others.
+type gameKey struct {
+	tenant string
+	id     string
+}
+
+// games keeps the games of one kind in memory. It's safe
+// for concurrent use, and update runs the changes of each
+// game one at a time, leaving the other games free.
+type games[T any] struct {
+	mu    sync.RWMutex
+	games map[gameKey]T
+	locks map[gameKey]*gameLock
+}
+
+// gameLock is held by the update of a game, and counts
+// the updates holding or waiting for it, so it is dropped
+// once none is left.
+type gameLock struct {
+	sync.Mutex
+	refs int
+}
+
+func newGames[T any]() games[T] {
+	return games[T]{
+		games: make(map[gameKey]T),
+		locks: make(map[gameKey]*gameLock),
+	}
+}
+
+func (g *games[T]) save(key gameKey, game T) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.games[key] = game
+}
+
+func (g *games[T]) get(key gameKey) (T, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	game, ok := g.games[key]
+	return game, ok
+}
+
+// update runs fn on the game of key and saves it when fn
+// succeeds. Updates of the same game wait for each other,
+// so none is lost. It reports whether the game was found.
+func (g *games[T]) update(key gameKey, fn func(game *T) error) (T, bool, error) {
+	unlock := g.lock(key)
+	defer unlock()
+
+	game, ok := g.get(key)
+	if !ok {
+		return game, false, nil
+	}
+	if err := fn(&game); err != nil {
+		var zero T
+		return zero, true, err
+	}
+	g.save(key, game)
+	return game, true, nil
+}
+
+func (g *games[T]) lock(key gameKey) func() {
+	g.mu.Lock()
+	l, ok := g.locks[key]
+	if !ok {
+		l = &gameLock{}
+		g.locks[key] = l
+	}
+	l.refs++
+	g.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		g.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(g.locks, key)
+		}
+		g.mu.Unlock()
+	}
+}
@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// HoldemTableNotFoundErr happens when a Hold'em table
+// is not found in the repo.
+var HoldemTableNotFoundErr = errors.New("holdem table not found")
+
+// HoldemTable repo, keeping each table within its tenant.
+// It's safe for concurrent use.
+type HoldemTable struct {
+	tables games[entity.HoldemTable]
+}
+
+// NewHoldemTable creates a new HoldemTable.
+func NewHoldemTable() *HoldemTable {
+	return &HoldemTable{tables: newGames[entity.HoldemTable]()}
+}
+
+// Save saves a Hold'em table to the store, within its tenant.
+func (h *HoldemTable) Save(table entity.HoldemTable) {
+	h.tables.save(gameKey{table.Tenant, table.ID}, table)
+}
+
+// Get retrieves a Hold'em table of tenant from its ID.
+func (h *HoldemTable) Get(tenant, id string) (entity.HoldemTable, error) {
+	table, ok := h.tables.get(gameKey{tenant, id})
+	if !ok {
+		return entity.HoldemTable{}, fmt.Errorf("%w with ID %s", HoldemTableNotFoundErr, id)
+	}
+	return table, nil
+}
+
+// Update runs fn on a Hold'em table of tenant and saves
+// it when fn succeeds. The updates of a table run one at
+// a time.
+func (h *HoldemTable) Update(tenant, id string, fn func(table *entity.HoldemTable) error) (entity.HoldemTable, error) {
+	table, ok, err := h.tables.update(gameKey{tenant, id}, fn)
+	if !ok {
+		return entity.HoldemTable{}, fmt.Errorf("%w with ID %s", HoldemTableNotFoundErr, id)
+	}
+	return table, err
+}
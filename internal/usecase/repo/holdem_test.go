@@ -0,0 +1,47 @@
+package repo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func TestHoldemTable_SaveGet(t *testing.T) {
+	want := entity.HoldemTable{
+		ID:         "id",
+		Tenant:     "acme",
+		SmallBlind: 1,
+		BigBlind:   2,
+		Street:     entity.HoldemStreetWaiting,
+		Seats:      []entity.HoldemSeat{{PlayerID: "p1", Stack: 100}},
+	}
+
+	store := NewHoldemTable()
+	store.Save(want)
+
+	got, err := store.Get("acme", want.ID)
+	if err != nil {
+		t.Fatalf("HoldemTable.Get() | got error %v, want nil", err)
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("HoldemTable.Get() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestHoldemTable_Get_Error(t *testing.T) {
+	store := NewHoldemTable()
+	_, err := store.Get("", "id")
+	if !errors.Is(err, HoldemTableNotFoundErr) {
+		t.Errorf("HoldemTable.Get() | got error %v, want %v", err, HoldemTableNotFoundErr)
+	}
+
+	// A table of another tenant isn't found either.
+	store.Save(entity.HoldemTable{ID: "id", Tenant: "acme"})
+	if _, err := store.Get("", "id"); !errors.Is(err, HoldemTableNotFoundErr) {
+		t.Errorf("HoldemTable.Get() | got error %v for another tenant, want %v", err, HoldemTableNotFoundErr)
+	}
+}
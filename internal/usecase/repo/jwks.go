@@ -0,0 +1,169 @@
+package repo
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jwksMinReload is how long a JWKS waits before loading
+// its keys again to find an unknown key ID, so tokens
+// signed with made up IDs can't hammer the source.
+const jwksMinReload = time.Minute
+
+// jwksMaxSize bounds the key sets read, as a URL could
+// send anything.
+const jwksMaxSize = 1 << 20
+
+var (
+	// JWKNotFoundErr happens when no key of the set has the ID a token was signed with.
+	JWKNotFoundErr = errors.New("signing key not found")
+	// JWKSErr happens when a key set can't be read or parsed.
+	JWKSErr = errors.New("invalid jwks")
+)
+
+// JWKS keeps the RSA public keys of a JSON Web Key Set,
+// read from a file or an http(s) URL. The keys are loaded
+// again every refresh, and when a token names a key the
+// set doesn't have, so keys rotated in by the issuer are
+// picked up.
+type JWKS struct {
+	source  string
+	client  *http.Client
+	refresh time.Duration
+
+	mu     sync.Mutex
+	keys   map[string]crypto.PublicKey
+	loaded time.Time
+}
+
+// NewJWKS creates a new JWKS reading source, a path or a
+// URL fetched with client. A refresh of 0 keeps the keys
+// until one is missing.
+func NewJWKS(source string, client *http.Client, refresh time.Duration) *JWKS {
+	return &JWKS{source: source, client: client, refresh: refresh}
+}
+
+// Key returns the key with ID kid. A token without key ID
+// is checked against the key of a set holding only one.
+func (j *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	since := time.Since(j.loaded)
+	key, ok := j.find(kid)
+	if j.keys == nil || (j.refresh > 0 && since >= j.refresh) || (!ok && since >= jwksMinReload) {
+		if err := j.load(ctx); err != nil {
+			if j.keys == nil {
+				return nil, err
+			}
+			// The keys already loaded are still good.
+			slog.Warn("jwks: reloading keys", slog.String("source", j.source), slog.Any("err", err))
+		}
+		key, ok = j.find(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w with id %q", JWKNotFoundErr, kid)
+	}
+	return key, nil
+}
+
+func (j *JWKS) find(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(j.keys) == 1 {
+		for _, k := range j.keys {
+			return k, true
+		}
+	}
+	k, ok := j.keys[kid]
+	return k, ok
+}
+
+func (j *JWKS) load(ctx context.Context) error {
+	// A failed load waits as long as a good one to be
+	// tried again.
+	j.loaded = time.Now()
+
+	data, err := j.read(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: reading %s: %v", JWKSErr, j.source, err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", JWKSErr, j.source, err)
+	}
+	j.keys = keys
+	return nil
+}
+
+func (j *JWKS) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(j.source, "http://") && !strings.HasPrefix(j.source, "https://") {
+		return os.ReadFile(j.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, jwksMaxSize))
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// parseJWKS returns the RSA signing keys of a key set by
+// ID. Keys of other types or meant for encryption are
+// left out.
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: modulus: %v", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: exponent: %v", k.Kid, err)
+		}
+		exp := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exp.IsInt64() || exp.Int64() < 3 || exp.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("key %q: not an RSA public key", k.Kid)
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}
+	}
+	return keys, nil
+}
@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestJWKS_URL(t *testing.T) {
+	k1, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		set      atomic.Value
+		requests atomic.Int32
+	)
+	set.Store(testJWKS(map[string]*rsa.PublicKey{"k1": &k1.PublicKey}))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, set.Load())
+	}))
+	defer srv.Close()
+
+	jwks := NewJWKS(srv.URL, srv.Client(), 0)
+	ctx := context.Background()
+
+	key, err := jwks.Key(ctx, "k1")
+	if err != nil {
+		t.Fatalf("JWKS.Key() | got error %v, want nil", err)
+	}
+	if !key.(*rsa.PublicKey).Equal(&k1.PublicKey) {
+		t.Error("JWKS.Key() | got another key than k1")
+	}
+	if key, err := jwks.Key(ctx, ""); err != nil || !key.(*rsa.PublicKey).Equal(&k1.PublicKey) {
+		t.Errorf("JWKS.Key() | got error %v without key ID, want the only key", err)
+	}
+
+	// The issuer rotates in k2, but the set was just loaded:
+	// unknown IDs don't reload it at once.
+	set.Store(testJWKS(map[string]*rsa.PublicKey{"k1": &k1.PublicKey, "k2": &k2.PublicKey}))
+	if _, err := jwks.Key(ctx, "k2"); !errors.Is(err, JWKNotFoundErr) {
+		t.Errorf("JWKS.Key() | got error %v, want %v", err, JWKNotFoundErr)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("JWKS.Key() | got %d requests, want 1", got)
+	}
+
+	jwks.loaded = jwks.loaded.Add(-jwksMinReload)
+	key, err = jwks.Key(ctx, "k2")
+	if err != nil {
+		t.Fatalf("JWKS.Key() | got error %v for a rotated key, want nil", err)
+	}
+	if !key.(*rsa.PublicKey).Equal(&k2.PublicKey) {
+		t.Error("JWKS.Key() | got another key than k2")
+	}
+}
+
+func TestJWKS_Invalid(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"keys": [{"kty": "RSA", "kid": "k1", "n": "!!", "e": "AQAB"}]}`)
+	}))
+	defer srv.Close()
+
+	if _, err := NewJWKS(srv.URL, srv.Client(), 0).Key(context.Background(), "k1"); !errors.Is(err, JWKSErr) {
+		t.Errorf("JWKS.Key() | got error %v, want %v", err, JWKSErr)
+	}
+}
+
+func testJWKS(keys map[string]*rsa.PublicKey) string {
+	s := `{"keys": [{"kty": "EC", "kid": "ec", "crv": "P-256"}`
+	for kid, k := range keys {
+		s += fmt.Sprintf(`, {"kty": "RSA", "kid": %q, "use": "sig", "n": %q, "e": %q}`, kid,
+			base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()))
+	}
+	return s + "]}"
+}
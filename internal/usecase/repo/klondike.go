@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// KlondikeGameNotFoundErr happens when a Klondike game
+// is not found in the repo.
+var KlondikeGameNotFoundErr = errors.New("klondike game not found")
+
+// KlondikeGame repo, keeping each game within its tenant.
+// It's safe for concurrent use.
+type KlondikeGame struct {
+	games games[entity.KlondikeGame]
+}
+
+// NewKlondikeGame creates a new KlondikeGame.
+func NewKlondikeGame() *KlondikeGame {
+	return &KlondikeGame{games: newGames[entity.KlondikeGame]()}
+}
+
+// Save saves a Klondike game to the store, within its tenant.
+func (k *KlondikeGame) Save(game entity.KlondikeGame) {
+	k.games.save(gameKey{game.Tenant, game.ID}, game)
+}
+
+// Get retrieves a Klondike game of tenant from its ID.
+func (k *KlondikeGame) Get(tenant, id string) (entity.KlondikeGame, error) {
+	game, ok := k.games.get(gameKey{tenant, id})
+	if !ok {
+		return entity.KlondikeGame{}, fmt.Errorf("%w with ID %s", KlondikeGameNotFoundErr, id)
+	}
+	return game, nil
+}
+
+// Update runs fn on a Klondike game of tenant and saves
+// it when fn succeeds. The updates of a game run one at a
+// time.
+func (k *KlondikeGame) Update(tenant, id string, fn func(game *entity.KlondikeGame) error) (entity.KlondikeGame, error) {
+	game, ok, err := k.games.update(gameKey{tenant, id}, fn)
+	if !ok {
+		return entity.KlondikeGame{}, fmt.Errorf("%w with ID %s", KlondikeGameNotFoundErr, id)
+	}
+	return game, err
+}
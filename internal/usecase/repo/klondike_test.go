@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func TestKlondikeGame_SaveGet(t *testing.T) {
+	want := entity.KlondikeGame{
+		ID:        "id",
+		Tenant:    "acme",
+		Seed:      42,
+		DrawCount: 3,
+		KlondikeState: entity.KlondikeState{
+			Tableau: []entity.KlondikeStack{{Cards: entity.DefaultCards[:2], FaceDown: 1}},
+		},
+	}
+
+	store := NewKlondikeGame()
+	store.Save(want)
+
+	got, err := store.Get("acme", want.ID)
+	if err != nil {
+		t.Fatalf("KlondikeGame.Get() | got error %v, want nil", err)
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("KlondikeGame.Get() | (-got +want):\n%s", diff)
+	}
+}
+
+func TestKlondikeGame_Get_Error(t *testing.T) {
+	store := NewKlondikeGame()
+	_, err := store.Get("", "id")
+	if !errors.Is(err, KlondikeGameNotFoundErr) {
+		t.Errorf("KlondikeGame.Get() | got error %v, want %v", err, KlondikeGameNotFoundErr)
+	}
+
+	// A game of another tenant isn't found either.
+	store.Save(entity.KlondikeGame{ID: "id", Tenant: "acme"})
+	if _, err := store.Get("", "id"); !errors.Is(err, KlondikeGameNotFoundErr) {
+		t.Errorf("KlondikeGame.Get() | got error %v for another tenant, want %v", err, KlondikeGameNotFoundErr)
+	}
+}
@@ -0,0 +1,14 @@
+-- Decks and their events, each kept in the compact deck encoding.
+CREATE TABLE decks (
+	id         TEXT        PRIMARY KEY,
+	version    INTEGER     NOT NULL,
+	data       BYTEA       NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE deck_events (
+	deck_id TEXT    NOT NULL,
+	version INTEGER NOT NULL,
+	data    BYTEA   NOT NULL,
+	PRIMARY KEY (deck_id, version)
+);
@@ -0,0 +1,7 @@
+-- API keys, kept by the SHA-256 of their secret.
+CREATE TABLE api_keys (
+	name       TEXT        PRIMARY KEY,
+	hash       TEXT        NOT NULL UNIQUE,
+	admin      BOOLEAN     NOT NULL DEFAULT false,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,16 @@
+-- Tenants, and the tenant of every deck and API key. Rows
+-- kept before there were tenants belong to the default
+-- one, named ''.
+CREATE TABLE tenants (
+	name                TEXT        PRIMARY KEY,
+	max_decks           INTEGER     NOT NULL DEFAULT 0,
+	max_cards           INTEGER     NOT NULL DEFAULT 0,
+	requests_per_minute INTEGER     NOT NULL DEFAULT 0,
+	created_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE decks ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
+ALTER TABLE decks DROP CONSTRAINT decks_pkey;
+ALTER TABLE decks ADD PRIMARY KEY (tenant, id);
+
+ALTER TABLE api_keys ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
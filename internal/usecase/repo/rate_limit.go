@@ -0,0 +1,113 @@
+package repo
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// rateLimitSweepInterval is the least time between two
+// sweeps of the full buckets out of the memory store.
+const rateLimitSweepInterval = time.Minute
+
+// RateLimit repo, keeping token buckets in memory, so each
+// instance of the application holds callers to their
+// limits on its own. It's safe for concurrent use.
+type RateLimit struct {
+	mu      sync.Mutex
+	buckets map[string]*rateBucket
+	swept   time.Time
+}
+
+// rateBucket is a token bucket as it was at, and when it
+// is full again, so it can be dropped.
+type rateBucket struct {
+	tokens float64
+	at     time.Time
+	full   time.Time
+}
+
+// NewRateLimit creates a new RateLimit.
+func NewRateLimit() *RateLimit {
+	return &RateLimit{buckets: make(map[string]*rateBucket)}
+}
+
+// Take takes a request out of the bucket of key at now, if
+// it holds one. Buckets start full, and are dropped once
+// full again, as they'd start over the same way.
+func (r *RateLimit) Take(ctx context.Context, key string, limit entity.RateLimit, now time.Time) (entity.RateLimitStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return entity.RateLimitStatus{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now.Sub(r.swept) >= rateLimitSweepInterval {
+		for k, b := range r.buckets {
+			if !now.Before(b.full) {
+				delete(r.buckets, k)
+			}
+		}
+		r.swept = now
+	}
+
+	b, ok := r.buckets[key]
+	if !ok {
+		b = &rateBucket{tokens: float64(limit.Burst), at: now}
+		r.buckets[key] = b
+	}
+	tokens, allowed := takeToken(refill(b.tokens, now.Sub(b.at), limit))
+	status := rateLimitStatus(tokens, allowed, limit)
+	// Requests can come in a bit out of order; the bucket
+	// never goes back in time, so it isn't refilled twice.
+	if now.After(b.at) {
+		b.at = now
+	}
+	b.tokens, b.full = tokens, b.at.Add(status.Reset)
+	return status, nil
+}
+
+// refill returns the tokens of a bucket holding tokens
+// after elapsed, which never go over its burst.
+func refill(tokens float64, elapsed time.Duration, limit entity.RateLimit) float64 {
+	if elapsed > 0 {
+		tokens += elapsed.Seconds() * float64(limit.PerMinute) / 60
+	}
+	return math.Min(tokens, float64(limit.Burst))
+}
+
+// takeToken takes a token out of a bucket holding tokens,
+// if there's a whole one, returning the tokens left. The
+// Redis store does the same in Lua.
+func takeToken(tokens float64) (float64, bool) {
+	if tokens >= 1 {
+		return tokens - 1, true
+	}
+	return tokens, false
+}
+
+// rateLimitStatus returns the status of a request to a
+// bucket left holding tokens.
+func rateLimitStatus(tokens float64, allowed bool, limit entity.RateLimit) entity.RateLimitStatus {
+	perSecond := float64(limit.PerMinute) / 60
+	status := entity.RateLimitStatus{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(tokens),
+		Reset:     seconds((float64(limit.Burst) - tokens) / perSecond),
+	}
+	if !allowed {
+		status.RetryAfter = seconds((1 - tokens) / perSecond)
+	}
+	return status
+}
+
+// seconds returns s seconds as a duration, rounded up to
+// the millisecond.
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s*1000)) * time.Millisecond
+}
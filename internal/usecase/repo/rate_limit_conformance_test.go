@@ -0,0 +1,26 @@
+package repo_test
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+	"github.com/lualfe/card-game/internal/usecase/repo/repotest"
+)
+
+func TestRateLimit_Conformance(t *testing.T) {
+	repotest.RateLimitRepo(t, func(t *testing.T) usecase.RateLimitRepo {
+		return repo.NewRateLimit()
+	})
+}
+
+func TestRedisRateLimit_Conformance(t *testing.T) {
+	repotest.RateLimitRepo(t, func(t *testing.T) usecase.RateLimitRepo {
+		client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+		t.Cleanup(func() { client.Close() })
+		return repo.NewRedisRateLimit(client)
+	})
+}
@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// redisTakeToken refills the bucket of KEYS[1] and takes a
+// token out of it, as refill and takeToken do, returning
+// whether it did and the tokens left as a string, as Redis
+// would cut a number down to an integer. ARGV holds the
+// limit per minute, the burst and the time in
+// milliseconds. Buckets expire once full again.
+var redisTakeToken = redis.NewScript(`
+local perMs = tonumber(ARGV[1]) / 60000
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local tokens, at = burst, now
+local b = redis.call('HMGET', KEYS[1], 'tokens', 'at')
+if b[1] then
+	at = math.max(now, tonumber(b[2]))
+	tokens = math.min(burst, tonumber(b[1]) + math.max(0, now - tonumber(b[2])) * perMs)
+end
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'at', at)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / perMs) + 1)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisRateLimit is a rate limit store on Redis, sharing
+// the token buckets between every instance of the
+// application. Each bucket is a hash taken from by a
+// script, so requests racing for the last token of a
+// bucket don't retry, and don't both get it.
+type RedisRateLimit struct {
+	client redis.UniversalClient
+}
+
+// NewRedisRateLimit creates a new RedisRateLimit.
+func NewRedisRateLimit(client redis.UniversalClient) *RedisRateLimit {
+	return &RedisRateLimit{client: client}
+}
+
+// Take takes a request out of the bucket of key at now, if
+// it holds one.
+func (r *RedisRateLimit) Take(ctx context.Context, key string, limit entity.RateLimit, now time.Time) (entity.RateLimitStatus, error) {
+	v, err := redisTakeToken.Run(ctx, r.client, []string{"rate_limit:" + key}, limit.PerMinute, limit.Burst, now.UnixMilli()).Slice()
+	if err != nil {
+		return entity.RateLimitStatus{}, err
+	}
+	if len(v) != 2 {
+		return entity.RateLimitStatus{}, fmt.Errorf("rate limit script returned %d values, want 2", len(v))
+	}
+	allowed, _ := v[0].(int64)
+	left, _ := v[1].(string)
+	tokens, err := strconv.ParseFloat(left, 64)
+	if err != nil {
+		return entity.RateLimitStatus{}, fmt.Errorf("rate limit script returned tokens %q: %w", left, err)
+	}
+	return rateLimitStatus(tokens, allowed == 1, limit), nil
+}
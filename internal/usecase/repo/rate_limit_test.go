@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+func TestRateLimit_Sweep(t *testing.T) {
+	ctx := context.Background()
+	store := NewRateLimit()
+	limit := entity.RateLimit{PerMinute: 60, Burst: 3}
+	start := time.Date(2024, time.March, 9, 15, 4, 5, 0, time.UTC)
+
+	// alice is full again a second later, bob two minutes
+	// later, after the sweep.
+	if _, err := store.Take(ctx, "alice", limit, start); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := store.Take(ctx, "bob", entity.RateLimit{PerMinute: 1, Burst: 10}, start); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.Take(ctx, "carol", limit, start.Add(rateLimitSweepInterval)); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]bool{"alice": false, "bob": true, "carol": true} {
+		if _, got := store.buckets[key]; got != want {
+			t.Errorf("Take() | got bucket of %s kept %t, want %t", key, got, want)
+		}
+	}
+}
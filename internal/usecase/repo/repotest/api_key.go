@@ -0,0 +1,136 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+// APIKeyRepo runs the API key store conformance tests.
+// open must return an empty store each time it's called.
+func APIKeyRepo(t *testing.T, open func(t *testing.T) usecase.APIKeyRepo) {
+	t.Run("Save Get", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+		want := apiKey("ci", true)
+		if err := store.Save(ctx, want); err != nil {
+			t.Fatalf("Save() | got error %v, want nil", err)
+		}
+
+		got, err := store.Get(ctx, want.Hash)
+		if err != nil {
+			t.Fatalf("Get() | got error %v, want nil", err)
+		}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf("Get() | (-got +want):\n%s", diff)
+		}
+		if _, err := store.Get(ctx, "missing"); !errors.Is(err, repo.APIKeyNotFoundErr) {
+			t.Errorf("Get() | got error %v, want %v", err, repo.APIKeyNotFoundErr)
+		}
+	})
+
+	t.Run("Name Taken", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+		key := apiKey("ci", false)
+		if err := store.Save(ctx, key); err != nil {
+			t.Fatal(err)
+		}
+
+		again := apiKey("ci", true)
+		again.Hash = "other"
+		if err := store.Save(ctx, again); !errors.Is(err, repo.APIKeyExistsErr) {
+			t.Errorf("Save() | got error %v, want %v", err, repo.APIKeyExistsErr)
+		}
+		if _, err := store.Get(ctx, again.Hash); !errors.Is(err, repo.APIKeyNotFoundErr) {
+			t.Errorf("Get() | got error %v for the refused key, want %v", err, repo.APIKeyNotFoundErr)
+		}
+	})
+
+	t.Run("Concurrent Names", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+
+		const savers = 8
+		var (
+			wg    sync.WaitGroup
+			mu    sync.Mutex
+			saved int
+		)
+		for i := 0; i < savers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				key := apiKey("ci", false)
+				key.Hash = fmt.Sprintf("hash-%d", i)
+				if err := store.Save(ctx, key); err == nil {
+					mu.Lock()
+					saved++
+					mu.Unlock()
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		if saved != 1 {
+			t.Errorf("Save() | saved the same name %d times, want 1", saved)
+		}
+	})
+
+	t.Run("All Delete", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+		for _, name := range []string{"b", "a", "c"} {
+			if err := store.Save(ctx, apiKey(name, false)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := store.Delete(ctx, "b"); err != nil {
+			t.Fatalf("Delete() | got error %v, want nil", err)
+		}
+		if err := store.Delete(ctx, "b"); !errors.Is(err, repo.APIKeyNotFoundErr) {
+			t.Errorf("Delete() | got error %v deleting twice, want %v", err, repo.APIKeyNotFoundErr)
+		}
+		if _, err := store.Get(ctx, apiKey("b", false).Hash); !errors.Is(err, repo.APIKeyNotFoundErr) {
+			t.Errorf("Get() | got error %v after deleting, want %v", err, repo.APIKeyNotFoundErr)
+		}
+
+		keys, err := store.All(ctx)
+		if err != nil {
+			t.Fatalf("All() | got error %v, want nil", err)
+		}
+		var got []string
+		for _, k := range keys {
+			got = append(got, k.Name)
+		}
+		sort.Strings(got)
+		if diff := cmp.Diff(got, []string{"a", "c"}); diff != "" {
+			t.Errorf("All() | (-got +want):\n%s", diff)
+		}
+
+		// The name of a deleted key can be used again.
+		if err := store.Save(ctx, apiKey("b", true)); err != nil {
+			t.Errorf("Save() | got error %v reusing a deleted name, want nil", err)
+		}
+	})
+}
+
+func apiKey(name string, admin bool) entity.APIKey {
+	return entity.APIKey{
+		Name:    name,
+		Hash:    "hash-of-" + name,
+		Admin:   admin,
+		Created: time.Date(2024, time.March, 9, 15, 4, 5, 0, time.UTC),
+	}
+}
@@ -0,0 +1,550 @@
+// Package repotest holds the tests every store behind the
+// usecase repo interfaces must pass, whatever it's built on.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+// DeckRepo runs the deck store conformance tests. open
+// must return an empty store each time it's called, along
+// with the event store its decks write their events to.
+func DeckRepo(t *testing.T, open func(t *testing.T) (usecase.DeckRepo, usecase.DeckEventRepo)) {
+	t.Run("Save Get", func(t *testing.T) {
+		ctx := context.Background()
+		store, _ := open(t)
+		want := dealtDeck()
+		save(t, store, want)
+
+		got, err := store.Get(ctx, "", want.ID)
+		if err != nil {
+			t.Fatalf("Get() | got error %v, want nil", err)
+		}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf("Get() | (-got +want):\n%s", diff)
+		}
+	})
+
+	t.Run("Save Replaces", func(t *testing.T) {
+		ctx := context.Background()
+		store, _ := open(t)
+		deck := dealtDeck()
+		save(t, store, deck)
+
+		deck.Cards = deck.Cards[1:]
+		deck.Remaining = len(deck.Cards)
+		deck.Version++
+		save(t, store, deck)
+
+		if got, _ := store.Get(ctx, "", deck.ID); !cmp.Equal(got, deck) {
+			t.Errorf("Get() | got version %d with %d cards, want %d with %d", got.Version, got.Remaining, deck.Version, deck.Remaining)
+		}
+	})
+
+	t.Run("Card Order", func(t *testing.T) {
+		ctx := context.Background()
+		store, _ := open(t)
+		custom := entity.Deck{
+			ID:        "custom",
+			Remaining: 3,
+			Cards:     []entity.Card{entity.DefaultCards[51], entity.DefaultCards[0], entity.DefaultCards[51]},
+			Version:   1,
+		}
+		save(t, store, custom)
+
+		got, err := store.Get(ctx, "", custom.ID)
+		if err != nil {
+			t.Fatalf("Get() | got error %v, want nil", err)
+		}
+		if diff := cmp.Diff(got.Cards, custom.Cards); diff != "" {
+			t.Errorf("Get() | cards (-got +want):\n%s", diff)
+		}
+	})
+
+	t.Run("Large Deck", func(t *testing.T) {
+		ctx := context.Background()
+		store, _ := open(t)
+		shoe := shoeDeck(8)
+		save(t, store, shoe)
+
+		got, err := store.Get(ctx, "", shoe.ID)
+		if err != nil {
+			t.Fatalf("Get() | got error %v, want nil", err)
+		}
+		if diff := cmp.Diff(got, shoe); diff != "" {
+			t.Errorf("Get() | (-got +want):\n%s", diff)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		ctx := context.Background()
+		store, _ := open(t)
+
+		if _, err := store.Get(ctx, "", "missing"); !errors.Is(err, repo.DeckNotFoundErr) {
+			t.Errorf("Get() | got error %v, want %v", err, repo.DeckNotFoundErr)
+		}
+		_, err := store.Update(ctx, "", "missing", func(*entity.Deck) ([]entity.DeckEvent, error) {
+			t.Error("Update() | fn called for a missing deck")
+			return nil, nil
+		})
+		if !errors.Is(err, repo.DeckNotFoundErr) {
+			t.Errorf("Update() | got error %v, want %v", err, repo.DeckNotFoundErr)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		ctx := context.Background()
+		store, _ := open(t)
+		deck := dealtDeck()
+		save(t, store, deck)
+
+		got, err := store.Update(ctx, "", deck.ID, func(d *entity.Deck) ([]entity.DeckEvent, error) {
+			d.Cards = d.Cards[2:]
+			d.Remaining = len(d.Cards)
+			d.Version++
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatalf("Update() | got error %v, want nil", err)
+		}
+
+		want := deck
+		want.Cards = deck.Cards[2:]
+		want.Remaining = len(want.Cards)
+		want.Version++
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf("Update() | (-got +want):\n%s", diff)
+		}
+		if stored, _ := store.Get(ctx, "", deck.ID); !cmp.Equal(stored, want) {
+			t.Errorf("Update() | stored deck differs from the returned one")
+		}
+	})
+
+	t.Run("Update Error", func(t *testing.T) {
+		ctx := context.Background()
+		store, _ := open(t)
+		deck := dealtDeck()
+		save(t, store, deck)
+
+		fnErr := errors.New("fail")
+		_, err := store.Update(ctx, "", deck.ID, func(d *entity.Deck) ([]entity.DeckEvent, error) {
+			d.Cards = nil
+			d.Version = 99
+			return nil, fnErr
+		})
+		if !errors.Is(err, fnErr) {
+			t.Errorf("Update() | got error %v, want %v", err, fnErr)
+		}
+		if got, _ := store.Get(ctx, "", deck.ID); !cmp.Equal(got, deck) {
+			t.Errorf("Update() | failed update changed the deck")
+		}
+	})
+
+	t.Run("Events", func(t *testing.T) {
+		ctx := context.Background()
+		store, events := open(t)
+		deck := dealtDeck()
+		deck.Version = 2
+		created := []entity.DeckEvent{
+			deckEvent(deck.ID, 1, entity.DeckEventCreated),
+			deckEvent(deck.ID, 2, entity.DeckEventShuffled),
+		}
+		if err := store.Save(ctx, deck, created...); err != nil {
+			t.Fatalf("Save() | got error %v, want nil", err)
+		}
+
+		drawn := deckEvent(deck.ID, 3, entity.DeckEventDrawn)
+		_, err := store.Update(ctx, "", deck.ID, func(d *entity.Deck) ([]entity.DeckEvent, error) {
+			d.Version++
+			return []entity.DeckEvent{drawn}, nil
+		})
+		if err != nil {
+			t.Fatalf("Update() | got error %v, want nil", err)
+		}
+
+		// A failed update keeps none of its events.
+		_, err = store.Update(ctx, "", deck.ID, func(d *entity.Deck) ([]entity.DeckEvent, error) {
+			d.Version++
+			return []entity.DeckEvent{deckEvent(deck.ID, 4, entity.DeckEventDrawn)}, errors.New("fail")
+		})
+		if err == nil {
+			t.Fatal("Update() | got error nil, want fn's")
+		}
+
+		got, err := events.Events(ctx, deck.ID)
+		if err != nil {
+			t.Fatalf("Events() | got error %v, want nil", err)
+		}
+		if diff := cmp.Diff(got, append(created, drawn)); diff != "" {
+			t.Errorf("Events() | (-got +want):\n%s", diff)
+		}
+	})
+
+	t.Run("Concurrent Events", func(t *testing.T) {
+		ctx := context.Background()
+		store, events := open(t)
+		deck := dealtDeck()
+		deck.Version = 1
+		if err := store.Save(ctx, deck, deckEvent(deck.ID, 1, entity.DeckEventCreated)); err != nil {
+			t.Fatalf("Save() | got error %v, want nil", err)
+		}
+
+		// Every update writes the event of the version it
+		// moves the deck to, so each version is written once
+		// and the deck ends at the last one.
+		const workers, updates = 8, 3
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < updates; i++ {
+					_, err := store.Update(ctx, "", deck.ID, func(d *entity.Deck) ([]entity.DeckEvent, error) {
+						d.Cards = d.Cards[1:]
+						d.Remaining = len(d.Cards)
+						d.Version++
+						return []entity.DeckEvent{deckEvent(d.ID, d.Version, entity.DeckEventDrawn)}, nil
+					})
+					if err != nil {
+						t.Errorf("Update() | got error %v, want nil", err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		got, err := events.Events(ctx, deck.ID)
+		if err != nil {
+			t.Fatalf("Events() | got error %v, want nil", err)
+		}
+		var versions []int
+		for _, e := range got {
+			versions = append(versions, e.Version)
+		}
+		var want []int
+		for v := 1; v <= 1+workers*updates; v++ {
+			want = append(want, v)
+		}
+		if diff := cmp.Diff(versions, want); diff != "" {
+			t.Errorf("Events() | versions (-got +want):\n%s", diff)
+		}
+		if stored, _ := store.Get(ctx, "", deck.ID); stored.Version != want[len(want)-1] {
+			t.Errorf("Get() | got version %d, want %d", stored.Version, want[len(want)-1])
+		}
+	})
+
+	t.Run("All", func(t *testing.T) {
+		ctx := context.Background()
+		store, _ := open(t)
+		var want []string
+		for i := 0; i < 3; i++ {
+			deck := dealtDeck()
+			deck.ID = fmt.Sprintf("deck-%d", i)
+			save(t, store, deck)
+			want = append(want, deck.ID)
+		}
+
+		decks, err := store.All(ctx)
+		if err != nil {
+			t.Fatalf("All() | got error %v, want nil", err)
+		}
+		if n, err := store.Count(ctx, ""); err != nil || n != len(want) {
+			t.Errorf("Count() | got %d and error %v, want %d and nil", n, err, len(want))
+		}
+		var got []string
+		for _, d := range decks {
+			got = append(got, d.ID)
+		}
+		sort.Strings(got)
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf("All() | (-got +want):\n%s", diff)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		ctx := context.Background()
+		store, _ := open(t)
+		deck := dealtDeck()
+		save(t, store, deck)
+		kept := dealtDeck()
+		kept.ID = "kept"
+		save(t, store, kept)
+
+		if err := store.Delete(ctx, "", deck.ID); err != nil {
+			t.Fatalf("Delete() | got error %v, want nil", err)
+		}
+		if _, err := store.Get(ctx, "", deck.ID); !errors.Is(err, repo.DeckNotFoundErr) {
+			t.Errorf("Get() | got error %v after deleting, want %v", err, repo.DeckNotFoundErr)
+		}
+		if err := store.Delete(ctx, "", deck.ID); !errors.Is(err, repo.DeckNotFoundErr) {
+			t.Errorf("Delete() | got error %v deleting twice, want %v", err, repo.DeckNotFoundErr)
+		}
+
+		decks, err := store.All(ctx)
+		if err != nil {
+			t.Fatalf("All() | got error %v, want nil", err)
+		}
+		if diff := cmp.Diff(decks, []entity.Deck{kept}); diff != "" {
+			t.Errorf("All() | (-got +want):\n%s", diff)
+		}
+		if n, _ := store.Count(ctx, ""); n != 1 {
+			t.Errorf("Count() | got %d decks after deleting, want 1", n)
+		}
+	})
+
+	t.Run("Tenants", func(t *testing.T) {
+		ctx := context.Background()
+		store, _ := open(t)
+		deck := dealtDeck()
+		save(t, store, deck)
+		acme := dealtDeck()
+		acme.Tenant = "acme"
+		acme.Cards = acme.Cards[1:]
+		acme.Remaining = len(acme.Cards)
+		save(t, store, acme)
+
+		// Both tenants keep a deck with the same ID, and
+		// each only sees its own.
+		if got, _ := store.Get(ctx, "", deck.ID); !cmp.Equal(got, deck) {
+			t.Errorf("Get() | got %d cards for the default tenant, want %d", got.Remaining, deck.Remaining)
+		}
+		if got, _ := store.Get(ctx, "acme", deck.ID); !cmp.Equal(got, acme) {
+			t.Errorf("Get() | got %d cards for acme, want %d", got.Remaining, acme.Remaining)
+		}
+		if _, err := store.Get(ctx, "other", deck.ID); !errors.Is(err, repo.DeckNotFoundErr) {
+			t.Errorf("Get() | got error %v from another tenant, want %v", err, repo.DeckNotFoundErr)
+		}
+		_, err := store.Update(ctx, "other", deck.ID, func(*entity.Deck) ([]entity.DeckEvent, error) {
+			t.Error("Update() | fn called for another tenant's deck")
+			return nil, nil
+		})
+		if !errors.Is(err, repo.DeckNotFoundErr) {
+			t.Errorf("Update() | got error %v from another tenant, want %v", err, repo.DeckNotFoundErr)
+		}
+		if err := store.Delete(ctx, "other", deck.ID); !errors.Is(err, repo.DeckNotFoundErr) {
+			t.Errorf("Delete() | got error %v from another tenant, want %v", err, repo.DeckNotFoundErr)
+		}
+		for tenant, want := range map[string]int{"": 1, "acme": 1, "other": 0} {
+			if n, err := store.Count(ctx, tenant); err != nil || n != want {
+				t.Errorf("Count() | got %d and error %v for %q, want %d and nil", n, err, tenant, want)
+			}
+		}
+
+		// All returns the decks of every tenant, each with
+		// its tenant.
+		decks, err := store.All(ctx)
+		if err != nil {
+			t.Fatalf("All() | got error %v, want nil", err)
+		}
+		sort.Slice(decks, func(i, j int) bool { return decks[i].Tenant < decks[j].Tenant })
+		if diff := cmp.Diff(decks, []entity.Deck{deck, acme}); diff != "" {
+			t.Errorf("All() | (-got +want):\n%s", diff)
+		}
+
+		if err := store.Delete(ctx, "acme", deck.ID); err != nil {
+			t.Fatalf("Delete() | got error %v, want nil", err)
+		}
+		decks, err = store.All(ctx)
+		if err != nil {
+			t.Fatalf("All() | got error %v, want nil", err)
+		}
+		if diff := cmp.Diff(decks, []entity.Deck{deck}); diff != "" {
+			t.Errorf("All() | deleting acme's deck changed the default tenant's (-got +want):\n%s", diff)
+		}
+	})
+
+	t.Run("Concurrent Updates", func(t *testing.T) {
+		ctx := context.Background()
+		store, _ := open(t)
+		deck := dealtDeck()
+		save(t, store, deck)
+
+		const workers, draws = 16, 3
+		var (
+			mu    sync.Mutex
+			drawn []int
+			wg    sync.WaitGroup
+		)
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < draws; i++ {
+					var card int
+					_, err := store.Update(ctx, "", deck.ID, func(d *entity.Deck) ([]entity.DeckEvent, error) {
+						card = len(d.Cards)
+						d.Cards = d.Cards[1:]
+						d.Remaining = len(d.Cards)
+						return nil, nil
+					})
+					if err != nil {
+						t.Errorf("Update() | got error %v, want nil", err)
+						return
+					}
+					mu.Lock()
+					drawn = append(drawn, card)
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		// Each update saw a deck one card shorter than another.
+		sort.Ints(drawn)
+		for i, n := range drawn {
+			if want := deck.Remaining - len(drawn) + 1 + i; n != want {
+				t.Fatalf("Update() | updates saw decks of %v cards, want each size once", drawn)
+			}
+		}
+		if got, _ := store.Get(ctx, "", deck.ID); got.Remaining != deck.Remaining-workers*draws {
+			t.Errorf("Update() | got %d cards left, want %d", got.Remaining, deck.Remaining-workers*draws)
+		}
+	})
+
+	t.Run("Context Canceled", func(t *testing.T) {
+		store, _ := open(t)
+		deck := dealtDeck()
+		save(t, store, deck)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		changed := deck
+		changed.ID = "changed"
+		if err := store.Save(ctx, changed); !errors.Is(err, context.Canceled) {
+			t.Errorf("Save() | got error %v, want %v", err, context.Canceled)
+		}
+		if _, err := store.Get(ctx, "", deck.ID); !errors.Is(err, context.Canceled) {
+			t.Errorf("Get() | got error %v, want %v", err, context.Canceled)
+		}
+		_, err := store.Update(ctx, "", deck.ID, func(d *entity.Deck) ([]entity.DeckEvent, error) {
+			d.Cards = nil
+			return nil, nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Update() | got error %v, want %v", err, context.Canceled)
+		}
+		if _, err := store.All(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("All() | got error %v, want %v", err, context.Canceled)
+		}
+		if _, err := store.Count(ctx, ""); !errors.Is(err, context.Canceled) {
+			t.Errorf("Count() | got error %v, want %v", err, context.Canceled)
+		}
+		if err := store.Delete(ctx, "", deck.ID); !errors.Is(err, context.Canceled) {
+			t.Errorf("Delete() | got error %v, want %v", err, context.Canceled)
+		}
+
+		decks, err := store.All(context.Background())
+		if err != nil {
+			t.Fatalf("All() | got error %v, want nil", err)
+		}
+		if diff := cmp.Diff(decks, []entity.Deck{deck}); diff != "" {
+			t.Errorf("All() | canceled calls changed the store (-got +want):\n%s", diff)
+		}
+	})
+
+	t.Run("Concurrent Saves", func(t *testing.T) {
+		ctx := context.Background()
+		store, _ := open(t)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 16; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				deck := dealtDeck()
+				deck.ID = fmt.Sprintf("deck-%d", i)
+				if err := store.Save(ctx, deck); err != nil {
+					t.Errorf("Save() | got error %v, want nil", err)
+					return
+				}
+				if _, err := store.Get(ctx, "", deck.ID); err != nil {
+					t.Errorf("Get() | got error %v after saving", err)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		decks, err := store.All(ctx)
+		if err != nil {
+			t.Fatalf("All() | got error %v, want nil", err)
+		}
+		if len(decks) != 16 {
+			t.Errorf("All() | got %d decks, want 16", len(decks))
+		}
+	})
+}
+
+func save(t *testing.T, store usecase.DeckRepo, deck entity.Deck) {
+	t.Helper()
+	if err := store.Save(context.Background(), deck); err != nil {
+		t.Fatalf("Save() | got error %v, want nil", err)
+	}
+}
+
+// deckEvent is an event of a deck, at a time every store
+// keeps exactly.
+func deckEvent(deckID string, version int, typ string) entity.DeckEvent {
+	return entity.DeckEvent{
+		DeckID:  deckID,
+		Version: version,
+		Type:    typ,
+		Time:    time.Date(2024, time.March, 9, 15, 4, 5, 0, time.UTC),
+		Cards:   entity.DefaultCards[:1],
+	}
+}
+
+// dealtDeck is a shuffled deck with two hands dealt.
+func dealtDeck() entity.Deck {
+	cards := append([]entity.Card{}, entity.DefaultCards...)
+	rand.New(rand.NewSource(1)).Shuffle(len(cards), func(i, j int) {
+		cards[i], cards[j] = cards[j], cards[i]
+	})
+
+	return entity.Deck{
+		ID:        "dealt",
+		Shuffled:  true,
+		Remaining: len(cards) - 4,
+		Cards:     cards[4:],
+		Hands: []entity.Hand{
+			{PlayerID: "alice", Token: "alice-token", Cards: cards[:2]},
+			{PlayerID: "bob", Token: "bob-token", Cards: cards[2:4]},
+		},
+		Version: 4,
+		Owner:   "alice-key",
+	}
+}
+
+// shoeDeck is a shuffled shoe of several decks, where
+// every card shows up once per deck.
+func shoeDeck(decks int) entity.Deck {
+	var cards []entity.Card
+	for i := 0; i < decks; i++ {
+		cards = append(cards, entity.DefaultCards...)
+	}
+	rand.New(rand.NewSource(2)).Shuffle(len(cards), func(i, j int) {
+		cards[i], cards[j] = cards[j], cards[i]
+	})
+
+	return entity.Deck{
+		ID:        "shoe",
+		Shuffled:  true,
+		Remaining: len(cards),
+		Cards:     cards,
+		Version:   2,
+	}
+}
@@ -0,0 +1,126 @@
+package repotest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+)
+
+// RateLimitRepo runs the rate limit store conformance
+// tests. open must return an empty store each time it's
+// called.
+func RateLimitRepo(t *testing.T, open func(t *testing.T) usecase.RateLimitRepo) {
+	// A token a second, up to 3.
+	limit := entity.RateLimit{PerMinute: 60, Burst: 3}
+	start := time.Date(2024, time.March, 9, 15, 4, 5, 0, time.UTC)
+
+	t.Run("Burst", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+
+		var got []entity.RateLimitStatus
+		for i := 0; i < 4; i++ {
+			status, err := store.Take(ctx, "alice", limit, start)
+			if err != nil {
+				t.Fatalf("Take() | got error %v, want nil", err)
+			}
+			got = append(got, status)
+		}
+		want := []entity.RateLimitStatus{
+			{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second},
+			{Allowed: true, Limit: 3, Remaining: 1, Reset: 2 * time.Second},
+			{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second},
+			{Allowed: false, Limit: 3, Remaining: 0, Reset: 3 * time.Second, RetryAfter: time.Second},
+		}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf("Take() | (-got +want):\n%s", diff)
+		}
+	})
+
+	t.Run("Refill", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+		for i := 0; i < 3; i++ {
+			if _, err := store.Take(ctx, "alice", limit, start); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		status, err := store.Take(ctx, "alice", limit, start.Add(1500*time.Millisecond))
+		if err != nil {
+			t.Fatalf("Take() | got error %v, want nil", err)
+		}
+		want := entity.RateLimitStatus{Allowed: true, Limit: 3, Remaining: 0, Reset: 2500 * time.Millisecond}
+		if diff := cmp.Diff(status, want); diff != "" {
+			t.Errorf("Take() | (-got +want):\n%s", diff)
+		}
+
+		status, _ = store.Take(ctx, "alice", limit, start.Add(1500*time.Millisecond))
+		if status.Allowed || status.RetryAfter != 500*time.Millisecond {
+			t.Errorf("Take() | got allowed %t retry after %v, want refused for 500ms", status.Allowed, status.RetryAfter)
+		}
+
+		// The bucket never holds more than its burst.
+		status, _ = store.Take(ctx, "alice", limit, start.Add(time.Hour))
+		if status.Remaining != 2 {
+			t.Errorf("Take() | got %d remaining after an hour, want 2", status.Remaining)
+		}
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+		for i := 0; i < 3; i++ {
+			if _, err := store.Take(ctx, "alice", limit, start); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		status, err := store.Take(ctx, "bob", limit, start)
+		if err != nil {
+			t.Fatalf("Take() | got error %v, want nil", err)
+		}
+		if !status.Allowed || status.Remaining != 2 {
+			t.Errorf("Take() | got allowed %t with %d remaining, want bob's own full bucket", status.Allowed, status.Remaining)
+		}
+	})
+
+	t.Run("Concurrent Takes", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+
+		const takers = 20
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			allowed int
+		)
+		for i := 0; i < takers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				status, err := store.Take(ctx, "alice", limit, start)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if status.Allowed {
+					mu.Lock()
+					allowed++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if allowed != limit.Burst {
+			t.Errorf("Take() | allowed %d of %d concurrent requests, want %d", allowed, takers, limit.Burst)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+// TenantRepo runs the tenant store conformance tests. open
+// must return an empty store each time it's called.
+func TenantRepo(t *testing.T, open func(t *testing.T) usecase.TenantRepo) {
+	t.Run("Save Get", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+		want := tenant("acme", 10)
+		if err := store.Save(ctx, want); err != nil {
+			t.Fatalf("Save() | got error %v, want nil", err)
+		}
+
+		got, err := store.Get(ctx, want.Name)
+		if err != nil {
+			t.Fatalf("Get() | got error %v, want nil", err)
+		}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf("Get() | (-got +want):\n%s", diff)
+		}
+		if _, err := store.Get(ctx, "missing"); !errors.Is(err, repo.TenantNotFoundErr) {
+			t.Errorf("Get() | got error %v, want %v", err, repo.TenantNotFoundErr)
+		}
+	})
+
+	t.Run("Name Taken", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+		if err := store.Save(ctx, tenant("acme", 10)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := store.Save(ctx, tenant("acme", 20)); !errors.Is(err, repo.TenantExistsErr) {
+			t.Errorf("Save() | got error %v, want %v", err, repo.TenantExistsErr)
+		}
+		if got, _ := store.Get(ctx, "acme"); got.Limits.MaxDecks != 10 {
+			t.Errorf("Get() | got %d max decks, want the first tenant's 10", got.Limits.MaxDecks)
+		}
+	})
+
+	t.Run("Concurrent Names", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+
+		const savers = 8
+		var (
+			wg    sync.WaitGroup
+			mu    sync.Mutex
+			saved int
+		)
+		for i := 0; i < savers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if err := store.Save(ctx, tenant("acme", i)); err == nil {
+					mu.Lock()
+					saved++
+					mu.Unlock()
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		if saved != 1 {
+			t.Errorf("Save() | saved the same name %d times, want 1", saved)
+		}
+	})
+
+	t.Run("All", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+		for _, name := range []string{"b", "a", "c"} {
+			if err := store.Save(ctx, tenant(name, 1)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		tenants, err := store.All(ctx)
+		if err != nil {
+			t.Fatalf("All() | got error %v, want nil", err)
+		}
+		var got []string
+		for _, tn := range tenants {
+			got = append(got, tn.Name)
+		}
+		if diff := cmp.Diff(got, []string{"a", "b", "c"}); diff != "" {
+			t.Errorf("All() | (-got +want):\n%s", diff)
+		}
+	})
+}
+
+func tenant(name string, maxDecks int) entity.Tenant {
+	return entity.Tenant{
+		Name:    name,
+		Limits:  entity.TenantLimits{MaxDecks: maxDecks, MaxCards: 104, RequestsPerMinute: 60},
+		Created: time.Date(2024, time.March, 9, 15, 4, 5, 0, time.UTC),
+	}
+}
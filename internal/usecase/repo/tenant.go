@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+var (
+	// TenantNotFoundErr happens when a tenant is not found in
+	// the repo.
+	TenantNotFoundErr = errors.New("tenant not found")
+	// TenantExistsErr happens when a tenant is saved under a
+	// name already taken.
+	TenantExistsErr = errors.New("tenant already exists")
+)
+
+// Tenant repo, keeping the tenants in memory until the
+// application stops. It's safe for concurrent use.
+type Tenant struct {
+	mu      sync.RWMutex
+	tenants map[string]entity.Tenant
+}
+
+// NewTenant creates a new Tenant.
+func NewTenant() *Tenant {
+	return &Tenant{tenants: make(map[string]entity.Tenant)}
+}
+
+// Save adds a tenant to the store, unless its name is
+// taken.
+func (t *Tenant) Save(ctx context.Context, tenant entity.Tenant) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.tenants[tenant.Name]; ok {
+		return fmt.Errorf("%w with name %s", TenantExistsErr, tenant.Name)
+	}
+	t.tenants[tenant.Name] = tenant
+	return nil
+}
+
+// Get retrieves a tenant from its name.
+func (t *Tenant) Get(ctx context.Context, name string) (entity.Tenant, error) {
+	if err := ctx.Err(); err != nil {
+		return entity.Tenant{}, err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	tenant, ok := t.tenants[name]
+	if !ok {
+		return entity.Tenant{}, fmt.Errorf("%w with name %s", TenantNotFoundErr, name)
+	}
+	return tenant, nil
+}
+
+// All returns every tenant in the store, by name.
+func (t *Tenant) All(ctx context.Context) ([]entity.Tenant, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	tenants := make([]entity.Tenant, 0, len(t.tenants))
+	for _, tenant := range t.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Name < tenants[j].Name })
+	return tenants, nil
+}
+
+func encodeTenant(tenant entity.Tenant) ([]byte, error) {
+	return json.Marshal(tenant)
+}
+
+func decodeTenant(data []byte) (entity.Tenant, error) {
+	var tenant entity.Tenant
+	if err := json.Unmarshal(data, &tenant); err != nil {
+		return entity.Tenant{}, fmt.Errorf("decoding tenant: %w", err)
+	}
+	return tenant, nil
+}
@@ -0,0 +1,80 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// BoltTenant is a tenant store on bbolt, keeping each
+// tenant under its name.
+type BoltTenant struct {
+	db *bbolt.DB
+}
+
+// NewBoltTenant creates a new BoltTenant.
+func NewBoltTenant(db *bbolt.DB) *BoltTenant {
+	return &BoltTenant{db: db}
+}
+
+// Save adds a tenant to the store, unless its name is
+// taken.
+func (b *BoltTenant) Save(ctx context.Context, tenant entity.Tenant) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	v, err := encodeTenant(tenant)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		tenants := tx.Bucket(boltTenantsBucket)
+		if tenants.Get([]byte(tenant.Name)) != nil {
+			return fmt.Errorf("%w with name %s", TenantExistsErr, tenant.Name)
+		}
+		return tenants.Put([]byte(tenant.Name), v)
+	})
+}
+
+// Get retrieves a tenant from its name.
+func (b *BoltTenant) Get(ctx context.Context, name string) (entity.Tenant, error) {
+	if err := ctx.Err(); err != nil {
+		return entity.Tenant{}, err
+	}
+
+	var tenant entity.Tenant
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket(boltTenantsBucket).Get([]byte(name))
+		if v == nil {
+			return fmt.Errorf("%w with name %s", TenantNotFoundErr, name)
+		}
+		var err error
+		tenant, err = decodeTenant(v)
+		return err
+	})
+	return tenant, err
+}
+
+// All returns every tenant in the store, by name.
+func (b *BoltTenant) All(ctx context.Context) ([]entity.Tenant, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var tenants []entity.Tenant
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(boltTenantsBucket).ForEach(func(_, v []byte) error {
+			tenant, err := decodeTenant(v)
+			if err != nil {
+				return err
+			}
+			tenants = append(tenants, tenant)
+			return nil
+		})
+	})
+	return tenants, err
+}
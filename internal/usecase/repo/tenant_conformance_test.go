@@ -0,0 +1,60 @@
+package repo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/lualfe/card-game/internal/usecase"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+	"github.com/lualfe/card-game/internal/usecase/repo/repotest"
+)
+
+func TestTenant_Conformance(t *testing.T) {
+	repotest.TenantRepo(t, func(t *testing.T) usecase.TenantRepo {
+		return repo.NewTenant()
+	})
+}
+
+func TestBoltTenant_Conformance(t *testing.T) {
+	repotest.TenantRepo(t, func(t *testing.T) usecase.TenantRepo {
+		db, err := repo.OpenBolt(filepath.Join(t.TempDir(), "decks.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		return repo.NewBoltTenant(db)
+	})
+}
+
+func TestRedisTenant_Conformance(t *testing.T) {
+	repotest.TenantRepo(t, func(t *testing.T) usecase.TenantRepo {
+		client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+		t.Cleanup(func() { client.Close() })
+		return repo.NewRedisTenant(client)
+	})
+}
+
+// TestPostgresTenant_Conformance needs DECK_POSTGRES_TEST_DSN
+// and wipes the tenants of that database.
+func TestPostgresTenant_Conformance(t *testing.T) {
+	dsn := os.Getenv("DECK_POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("DECK_POSTGRES_TEST_DSN not set")
+	}
+
+	repotest.TenantRepo(t, func(t *testing.T) usecase.TenantRepo {
+		db, err := repo.OpenPostgres(dsn, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		if _, err := db.Exec("TRUNCATE tenants"); err != nil {
+			t.Fatal(err)
+		}
+		return repo.NewPostgresTenant(db)
+	})
+}
@@ -0,0 +1,78 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+// PostgresTenant is a tenant store on PostgreSQL.
+type PostgresTenant struct {
+	db *sql.DB
+}
+
+// NewPostgresTenant creates a new PostgresTenant.
+func NewPostgresTenant(db *sql.DB) *PostgresTenant {
+	return &PostgresTenant{db: db}
+}
+
+// Save adds a tenant to the store, unless its name is
+// taken.
+func (p *PostgresTenant) Save(ctx context.Context, tenant entity.Tenant) error {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	_, err := p.db.ExecContext(ctx, "INSERT INTO tenants (name, max_decks, max_cards, requests_per_minute, created_at) VALUES ($1, $2, $3, $4, $5)",
+		tenant.Name, tenant.Limits.MaxDecks, tenant.Limits.MaxCards, tenant.Limits.RequestsPerMinute, tenant.Created)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == postgresUniqueViolation {
+		return fmt.Errorf("%w with name %s", TenantExistsErr, tenant.Name)
+	}
+	return err
+}
+
+// Get retrieves a tenant from its name.
+func (p *PostgresTenant) Get(ctx context.Context, name string) (entity.Tenant, error) {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	tenant := entity.Tenant{Name: name}
+	err := p.db.QueryRowContext(ctx, "SELECT max_decks, max_cards, requests_per_minute, created_at FROM tenants WHERE name = $1", name).
+		Scan(&tenant.Limits.MaxDecks, &tenant.Limits.MaxCards, &tenant.Limits.RequestsPerMinute, &tenant.Created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Tenant{}, fmt.Errorf("%w with name %s", TenantNotFoundErr, name)
+		}
+		return entity.Tenant{}, err
+	}
+	tenant.Created = tenant.Created.UTC()
+	return tenant, nil
+}
+
+// All returns every tenant in the store, by name.
+func (p *PostgresTenant) All(ctx context.Context) ([]entity.Tenant, error) {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, "SELECT name, max_decks, max_cards, requests_per_minute, created_at FROM tenants ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenants []entity.Tenant
+	for rows.Next() {
+		var tenant entity.Tenant
+		if err := rows.Scan(&tenant.Name, &tenant.Limits.MaxDecks, &tenant.Limits.MaxCards, &tenant.Limits.RequestsPerMinute, &tenant.Created); err != nil {
+			return tenants, err
+		}
+		tenant.Created = tenant.Created.UTC()
+		tenants = append(tenants, tenant)
+	}
+	return tenants, rows.Err()
+}
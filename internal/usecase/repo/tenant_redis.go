@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+const redisTenantsKey = "tenants"
+
+// RedisTenant is a tenant store on Redis, shared by every
+// instance of the application. Tenants are kept in a hash
+// by name.
+type RedisTenant struct {
+	client redis.UniversalClient
+}
+
+// NewRedisTenant creates a new RedisTenant.
+func NewRedisTenant(client redis.UniversalClient) *RedisTenant {
+	return &RedisTenant{client: client}
+}
+
+// Save adds a tenant to the store, unless its name is
+// taken. HSETNX makes sure two instances saving the same
+// name don't both succeed.
+func (r *RedisTenant) Save(ctx context.Context, tenant entity.Tenant) error {
+	v, err := encodeTenant(tenant)
+	if err != nil {
+		return err
+	}
+
+	saved, err := r.client.HSetNX(ctx, redisTenantsKey, tenant.Name, v).Result()
+	if err != nil {
+		return err
+	}
+	if !saved {
+		return fmt.Errorf("%w with name %s", TenantExistsErr, tenant.Name)
+	}
+	return nil
+}
+
+// Get retrieves a tenant from its name.
+func (r *RedisTenant) Get(ctx context.Context, name string) (entity.Tenant, error) {
+	v, err := r.client.HGet(ctx, redisTenantsKey, name).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return entity.Tenant{}, fmt.Errorf("%w with name %s", TenantNotFoundErr, name)
+		}
+		return entity.Tenant{}, err
+	}
+	return decodeTenant(v)
+}
+
+// All returns every tenant in the store, by name.
+func (r *RedisTenant) All(ctx context.Context) ([]entity.Tenant, error) {
+	values, err := r.client.HVals(ctx, redisTenantsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var tenants []entity.Tenant
+	for _, v := range values {
+		tenant, err := decodeTenant([]byte(v))
+		if err != nil {
+			return tenants, err
+		}
+		tenants = append(tenants, tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Name < tenants[j].Name })
+	return tenants, nil
+}
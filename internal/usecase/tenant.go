@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+var (
+	// TenantNotFoundErr happens when a tenant can't be found in the repo.
+	TenantNotFoundErr = errors.New("tenant not found")
+	// TenantExistsErr happens when a tenant is created with a name already taken.
+	TenantExistsErr = errors.New("tenant already exists")
+	// TenantInvalidErr happens when a tenant is created with a name or limits it can't have.
+	TenantInvalidErr = errors.New("invalid tenant")
+	// TenantQuotaErr happens when a tenant would go over one of its limits.
+	TenantQuotaErr = errors.New("tenant quota exceeded")
+)
+
+// tenantName is what tenant names look like. They end up
+// in store keys, metric labels and the logs.
+var tenantName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx working in tenant.
+func WithTenant(ctx context.Context, tenant entity.Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFrom returns the tenant ctx works in, if it isn't
+// the default one.
+func TenantFrom(ctx context.Context) (entity.Tenant, bool) {
+	t, ok := ctx.Value(tenantKey{}).(entity.Tenant)
+	return t, ok
+}
+
+// Tenants is a use case to manage the tenants, and to hold
+// them to their request rate. Requests are counted by each
+// instance of the application, over fixed minutes, so a
+// tenant spread over n instances can make up to n times
+// its rate.
+type Tenants struct {
+	store TenantRepo
+	decks DeckRepo
+
+	mu      sync.Mutex
+	windows map[string]*tenantWindow
+}
+
+// tenantWindow counts the requests of a tenant in the
+// minute starting at start.
+type tenantWindow struct {
+	start time.Time
+	n     int
+}
+
+// NewTenants creates a new Tenants, counting the decks of
+// the tenants in decks.
+func NewTenants(store TenantRepo, decks DeckRepo) *Tenants {
+	return &Tenants{store: store, decks: decks, windows: make(map[string]*tenantWindow)}
+}
+
+// Create adds a tenant with limits.
+func (t *Tenants) Create(ctx context.Context, name string, limits entity.TenantLimits) (entity.Tenant, error) {
+	if !tenantName.MatchString(name) {
+		return entity.Tenant{}, fmt.Errorf("%w: name %q must be 1 to 63 lowercase letters, digits or dashes, not starting with a dash", TenantInvalidErr, name)
+	}
+	if limits.MaxDecks < 0 || limits.MaxCards < 0 || limits.RequestsPerMinute < 0 {
+		return entity.Tenant{}, fmt.Errorf("%w: limits can't be negative", TenantInvalidErr)
+	}
+
+	tenant := entity.Tenant{Name: name, Limits: limits, Created: time.Now().UTC()}
+	if err := t.store.Save(ctx, tenant); err != nil {
+		if errors.Is(err, repo.TenantExistsErr) {
+			return entity.Tenant{}, fmt.Errorf("%w with name %s", TenantExistsErr, name)
+		}
+		return entity.Tenant{}, err
+	}
+	return tenant, nil
+}
+
+// Tenant returns a tenant by name.
+func (t *Tenants) Tenant(ctx context.Context, name string) (entity.Tenant, error) {
+	tenant, err := t.store.Get(ctx, name)
+	if err != nil {
+		if errors.Is(err, repo.TenantNotFoundErr) {
+			return entity.Tenant{}, fmt.Errorf("%w with name %s", TenantNotFoundErr, name)
+		}
+		return entity.Tenant{}, err
+	}
+	return tenant, nil
+}
+
+// Tenants returns every tenant by name.
+func (t *Tenants) Tenants(ctx context.Context) ([]entity.Tenant, error) {
+	return t.store.All(ctx)
+}
+
+// Usage returns how many decks a tenant keeps, and how many
+// requests it made to this instance in the current minute.
+func (t *Tenants) Usage(ctx context.Context, name string) (entity.TenantUsage, error) {
+	tenant, err := t.Tenant(ctx, name)
+	if err != nil {
+		return entity.TenantUsage{}, err
+	}
+	n, err := t.decks.Count(ctx, name)
+	if err != nil {
+		return entity.TenantUsage{}, err
+	}
+
+	usage := entity.TenantUsage{Tenant: tenant, Decks: n}
+	t.mu.Lock()
+	if w, ok := t.windows[name]; ok && w.start.Equal(time.Now().Truncate(time.Minute)) {
+		usage.Requests = w.n
+	}
+	t.mu.Unlock()
+	return usage, nil
+}
+
+// Allow counts a request of tenant, telling if it's within
+// the tenant's rate. When it isn't, it returns how long
+// until the next minute lets the tenant in again.
+func (t *Tenants) Allow(tenant entity.Tenant) (time.Duration, bool) {
+	now := time.Now()
+	start := now.Truncate(time.Minute)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	w, ok := t.windows[tenant.Name]
+	if !ok {
+		w = &tenantWindow{}
+		t.windows[tenant.Name] = w
+	}
+	if !w.start.Equal(start) {
+		w.start, w.n = start, 0
+	}
+	if limit := tenant.Limits.RequestsPerMinute; limit > 0 && w.n >= limit {
+		return start.Add(time.Minute).Sub(now), false
+	}
+	w.n++
+	return 0, true
+}
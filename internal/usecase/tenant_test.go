@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+func TestTenants_Create(t *testing.T) {
+	tenants := NewTenants(repo.NewTenant(), repo.NewDeck())
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		tenant string
+		limits entity.TenantLimits
+		err    error
+	}{
+		{name: "Success", tenant: "acme", limits: entity.TenantLimits{MaxDecks: 10}},
+		{name: "Taken", tenant: "acme", err: TenantExistsErr},
+		{name: "Empty", tenant: "", err: TenantInvalidErr},
+		{name: "Uppercase", tenant: "Acme", err: TenantInvalidErr},
+		{name: "Leading Dash", tenant: "-acme", err: TenantInvalidErr},
+		{name: "Negative Limit", tenant: "globex", limits: entity.TenantLimits{MaxCards: -1}, err: TenantInvalidErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tenants.Create(ctx, tt.tenant, tt.limits)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Tenants.Create() | got error %v, want %v", err, tt.err)
+			}
+			if err == nil && (got.Name != tt.tenant || got.Limits != tt.limits) {
+				t.Errorf("Tenants.Create() | got %+v, want %s with %+v", got, tt.tenant, tt.limits)
+			}
+		})
+	}
+
+	if _, err := tenants.Tenant(ctx, "missing"); !errors.Is(err, TenantNotFoundErr) {
+		t.Errorf("Tenants.Tenant() | got error %v, want %v", err, TenantNotFoundErr)
+	}
+}
+
+func TestTenants_Allow(t *testing.T) {
+	decks := repo.NewDeck()
+	tenants := NewTenants(repo.NewTenant(), decks)
+	ctx := context.Background()
+	acme, err := tenants.Create(ctx, "acme", entity.TenantLimits{RequestsPerMinute: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decks.Save(ctx, entity.Deck{ID: "id", Tenant: "acme"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The minute may turn between two calls, letting a
+	// request in again: the checks hold within one.
+	start := time.Now().Truncate(time.Minute)
+	for i := 0; i < 2; i++ {
+		if _, ok := tenants.Allow(acme); !ok {
+			t.Fatalf("Tenants.Allow() | got request %d refused, want it allowed", i+1)
+		}
+	}
+	retry, ok := tenants.Allow(acme)
+	if time.Now().Truncate(time.Minute).Equal(start) {
+		if ok {
+			t.Error("Tenants.Allow() | got request 3 allowed, want it refused")
+		}
+		if retry <= 0 || retry > time.Minute {
+			t.Errorf("Tenants.Allow() | got retry after %v, want up to a minute", retry)
+		}
+
+		usage, err := tenants.Usage(ctx, "acme")
+		if err != nil {
+			t.Fatalf("Tenants.Usage() | got error %v, want nil", err)
+		}
+		if usage.Decks != 1 || usage.Requests != 2 {
+			t.Errorf("Tenants.Usage() | got %d decks and %d requests, want 1 and 2", usage.Decks, usage.Requests)
+		}
+	}
+
+	// Tenants without a rate are never refused.
+	for i := 0; i < 100; i++ {
+		if _, ok := tenants.Allow(entity.Tenant{Name: "globex"}); !ok {
+			t.Fatal("Tenants.Allow() | got a request refused without a rate")
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+// TokenInvalidErr happens when a caller's bearer token is malformed, expired or badly signed.
+var TokenInvalidErr = errors.New("invalid bearer token")
+
+// tokenLeeway is how far apart the clocks of the issuer
+// and the application can be.
+const tokenLeeway = 30 * time.Second
+
+// TokenOptions are the settings of Tokens. At least one of
+// Secret and Keys must be set.
+type TokenOptions struct {
+	// Secret checks the tokens signed with HS256.
+	Secret []byte
+	// Keys finds the public keys checking the tokens
+	// signed with RS256.
+	Keys TokenKeyRepo
+	// Issuer and Audience, when set, must be the iss and
+	// one of the aud of the tokens.
+	Issuer   string
+	Audience string
+}
+
+// Tokens is a use case to authenticate callers by JSON Web
+// Token, such as the access tokens of an OIDC provider.
+// The subject of a token is the principal, its scopes,
+// from the scope or scp claim, what it can do, and its
+// tenant claim the tenant it works in.
+type Tokens struct {
+	parser *jwt.Parser
+	secret []byte
+	keys   TokenKeyRepo
+}
+
+// NewTokens creates a new Tokens. Only the algorithms of
+// the keys set are accepted, so a token can't pick how
+// it's checked.
+func NewTokens(opts TokenOptions) *Tokens {
+	var methods []string
+	if len(opts.Secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if opts.Keys != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(tokenLeeway),
+	}
+	if opts.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.Audience))
+	}
+
+	return &Tokens{parser: jwt.NewParser(parserOpts...), secret: opts.Secret, keys: opts.Keys}
+}
+
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Scope  string      `json:"scope"`
+	Scp    tokenScopes `json:"scp"`
+	Tenant string      `json:"tenant"`
+}
+
+// tokenScopes is a scp claim, which providers send either
+// as a list or as a space separated string.
+type tokenScopes []string
+
+func (s *tokenScopes) UnmarshalJSON(data []byte) error {
+	var scope string
+	if err := json.Unmarshal(data, &scope); err == nil {
+		*s = strings.Fields(scope)
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(s))
+}
+
+// Authenticate returns who token was issued to. A token
+// needs an expiry and a subject.
+func (t *Tokens) Authenticate(ctx context.Context, token string) (entity.Principal, error) {
+	var (
+		claims tokenClaims
+		keyErr error
+	)
+	_, err := t.parser.ParseWithClaims(token, &claims, func(tok *jwt.Token) (interface{}, error) {
+		if tok.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+			return t.secret, nil
+		}
+		kid, _ := tok.Header["kid"].(string)
+		key, err := t.keys.Key(ctx, kid)
+		keyErr = err
+		return key, err
+	})
+	if err != nil {
+		// The keys couldn't be loaded: the token may well
+		// be good.
+		if keyErr != nil && !errors.Is(keyErr, repo.JWKNotFoundErr) {
+			return entity.Principal{}, fmt.Errorf("token keys: %w", keyErr)
+		}
+		return entity.Principal{}, fmt.Errorf("%w: %v", TokenInvalidErr, err)
+	}
+	if claims.Subject == "" {
+		return entity.Principal{}, fmt.Errorf("%w: no subject", TokenInvalidErr)
+	}
+
+	scopes := append(strings.Fields(claims.Scope), claims.Scp...)
+	p := entity.Principal{ID: entity.PrincipalTokenPrefix + claims.Subject, Scopes: scopes, Tenant: claims.Tenant}
+	for _, s := range scopes {
+		if s == entity.ScopeAdmin {
+			p.Admin = true
+		}
+	}
+	return p, nil
+}
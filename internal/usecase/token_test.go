@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lualfe/card-game/internal/entity"
+	"github.com/lualfe/card-game/internal/usecase/repo"
+)
+
+func TestTokens(t *testing.T) {
+	secret := []byte("test-secret")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The key set of the issuer, as an OIDC provider would
+	// publish it.
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	writeTestJWKS(t, jwksPath, map[string]*rsa.PublicKey{"k1": &rsaKey.PublicKey})
+
+	tokens := NewTokens(TokenOptions{
+		Secret:   secret,
+		Keys:     repo.NewJWKS(jwksPath, http.DefaultClient, 0),
+		Issuer:   "https://issuer.test",
+		Audience: "card-game",
+	})
+
+	claims := func(scope string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":   "alice",
+			"iss":   "https://issuer.test",
+			"aud":   "card-game",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"scope": scope,
+		}
+	}
+	with := func(c jwt.MapClaims, key string, value interface{}) jwt.MapClaims {
+		c[key] = value
+		return c
+	}
+	without := func(c jwt.MapClaims, key string) jwt.MapClaims {
+		delete(c, key)
+		return c
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  entity.Principal
+		err   error
+	}{
+		{
+			name:  "HS256",
+			token: signHS256(t, secret, claims("decks:read decks:draw")),
+			want:  entity.Principal{ID: "jwt:alice", Scopes: []string{"decks:read", "decks:draw"}},
+		},
+		{
+			name:  "RS256",
+			token: signRS256(t, rsaKey, "k1", claims("decks:create")),
+			want:  entity.Principal{ID: "jwt:alice", Scopes: []string{"decks:create"}},
+		},
+		{
+			name:  "Scp List",
+			token: signRS256(t, rsaKey, "k1", with(without(claims(""), "scope"), "scp", []string{"decks:read", "admin"})),
+			want:  entity.Principal{ID: "jwt:alice", Admin: true, Scopes: []string{"decks:read", "admin"}},
+		},
+		{
+			name:  "Tenant",
+			token: signHS256(t, secret, with(claims("decks:read"), "tenant", "acme")),
+			want:  entity.Principal{ID: "jwt:alice", Scopes: []string{"decks:read"}, Tenant: "acme"},
+		},
+		{
+			name:  "Wrong Secret",
+			token: signHS256(t, []byte("guess"), claims("decks:read")),
+			err:   TokenInvalidErr,
+		},
+		{
+			name:  "Unknown Key",
+			token: signRS256(t, otherKey, "k2", claims("decks:read")),
+			err:   TokenInvalidErr,
+		},
+		{
+			name:  "Forged Key ID",
+			token: signRS256(t, otherKey, "k1", claims("decks:read")),
+			err:   TokenInvalidErr,
+		},
+		{
+			name:  "Expired",
+			token: signHS256(t, secret, with(claims("decks:read"), "exp", time.Now().Add(-time.Hour).Unix())),
+			err:   TokenInvalidErr,
+		},
+		{
+			name:  "No Expiry",
+			token: signHS256(t, secret, without(claims("decks:read"), "exp")),
+			err:   TokenInvalidErr,
+		},
+		{
+			name:  "Wrong Issuer",
+			token: signHS256(t, secret, with(claims("decks:read"), "iss", "https://evil.test")),
+			err:   TokenInvalidErr,
+		},
+		{
+			name:  "Wrong Audience",
+			token: signHS256(t, secret, with(claims("decks:read"), "aud", "other-app")),
+			err:   TokenInvalidErr,
+		},
+		{
+			name:  "No Subject",
+			token: signHS256(t, secret, without(claims("decks:read"), "sub")),
+			err:   TokenInvalidErr,
+		},
+		{
+			name:  "Unsigned",
+			token: signNone(t, claims("decks:read")),
+			err:   TokenInvalidErr,
+		},
+		{
+			name:  "Garbage",
+			token: "not.a.token",
+			err:   TokenInvalidErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokens.Authenticate(context.Background(), tt.token)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Tokens.Authenticate() | got error %v, want %v", err, tt.err)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Tokens.Authenticate() | (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTokens_OnlyConfiguredAlgorithms(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := NewTokens(TokenOptions{Secret: []byte("test-secret")})
+
+	token := signRS256(t, rsaKey, "k1", jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+	if _, err := tokens.Authenticate(context.Background(), token); !errors.Is(err, TokenInvalidErr) {
+		t.Errorf("Tokens.Authenticate() | got error %v for an RS256 token without keys, want %v", err, TokenInvalidErr)
+	}
+}
+
+func TestTokens_KeysUnavailable(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := NewTokens(TokenOptions{Keys: repo.NewJWKS(filepath.Join(t.TempDir(), "missing.json"), http.DefaultClient, 0)})
+
+	token := signRS256(t, rsaKey, "k1", jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+	_, err = tokens.Authenticate(context.Background(), token)
+	if !errors.Is(err, repo.JWKSErr) || errors.Is(err, TokenInvalidErr) {
+		t.Errorf("Tokens.Authenticate() | got error %v, want %v and not %v", err, repo.JWKSErr, TokenInvalidErr)
+	}
+}
+
+func signHS256(t *testing.T, secret []byte, claims jwt.MapClaims) string {
+	t.Helper()
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = kid
+	s, err := tok.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func signNone(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	s, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func writeTestJWKS(t *testing.T, path string, keys map[string]*rsa.PublicKey) {
+	t.Helper()
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for kid, k := range keys {
+		set.Keys = append(set.Keys, map[string]string{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		})
+	}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
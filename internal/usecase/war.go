@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lualfe/card-game/internal/entity"
+)
+
+const (
+	// warFaceDown is how many cards each player puts
+	// face down when a war starts.
+	warFaceDown = 3
+	// maxWarRounds stops games that would loop forever. The
+	// player holding more cards wins when it is reached.
+	maxWarRounds = 5000
+)
+
+// War holds the rules of the game of War. The whole deck is
+// split between two players, who flip their top cards and
+// the higher one takes both. Ties start a war.
+type War struct {
+	deck DeckManager
+}
+
+// Players returns how many players can play.
+func (w *War) Players() (int, int) {
+	return 2, 2
+}
+
+// Deal splits the whole deck between the players.
+func (w *War) Deal(ctx context.Context, game *entity.CasualGame) error {
+	return dealHands(ctx, w.deck, game, game.Stock/len(game.Players))
+}
+
+// LegalMoves lists the moves of a player.
+func (w *War) LegalMoves(game entity.CasualGame, player string) []entity.CasualMove {
+	if game.Finished || game.Players[game.Turn].ID != player {
+		return nil
+	}
+	return []entity.CasualMove{{Player: player, Action: entity.CasualMoveFlip}}
+}
+
+// Play resolves a whole battle, including any wars.
+func (w *War) Play(ctx context.Context, game *entity.CasualGame, _ entity.CasualMove) error {
+	first, second := &game.Players[0], &game.Players[1]
+
+	var pile []entity.Card
+	winner := -1
+	wars := 0
+	for winner < 0 {
+		switch {
+		case len(first.Hand) == 0:
+			winner = 1
+			continue
+		case len(second.Hand) == 0:
+			winner = 0
+			continue
+		}
+
+		a, b := first.Hand[0], second.Hand[0]
+		first.Hand, second.Hand = first.Hand[1:], second.Hand[1:]
+		pile = append(pile, a, b)
+
+		switch ra, rb := pokerRank(a), pokerRank(b); {
+		case ra > rb:
+			winner = 0
+		case rb > ra:
+			winner = 1
+		default:
+			wars++
+			// Keep a card to flip, unless the hand is out already.
+			for i := 0; i < warFaceDown && len(first.Hand) > 1; i++ {
+				pile = append(pile, first.Hand[0])
+				first.Hand = first.Hand[1:]
+			}
+			for i := 0; i < warFaceDown && len(second.Hand) > 1; i++ {
+				pile = append(pile, second.Hand[0])
+				second.Hand = second.Hand[1:]
+			}
+		}
+	}
+
+	game.Players[winner].Hand = append(append([]entity.Card{}, game.Players[winner].Hand...), pile...)
+	game.Pile = pile
+	game.Round++
+	game.Turn = (game.Turn + 1) % len(game.Players)
+	game.Message = fmt.Sprintf("%s took %d cards", game.Players[winner].ID, len(pile))
+	if wars > 0 {
+		game.Message = fmt.Sprintf("%s won %d war(s) and took %d cards", game.Players[winner].ID, wars, len(pile))
+	}
+
+	switch {
+	case len(first.Hand) == 0:
+		game.Finished, game.Winner = true, second.ID
+	case len(second.Hand) == 0:
+		game.Finished, game.Winner = true, first.ID
+	case game.Round >= maxWarRounds:
+		game.Finished, game.Winner = true, first.ID
+		if len(second.Hand) > len(first.Hand) {
+			game.Winner = second.ID
+		}
+	}
+
+	return nil
+}
+
+// BotMove flips, the only move in War.
+func (w *War) BotMove(game entity.CasualGame, player string) (entity.CasualMove, bool) {
+	moves := w.LegalMoves(game, player)
+	if len(moves) == 0 {
+		return entity.CasualMove{}, false
+	}
+	return moves[0], true
+}